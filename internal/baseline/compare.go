@@ -0,0 +1,260 @@
+package baseline
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/color"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// Delta compares the current value of a benchmark result with its baseline value.
+//
+// Change is the relative change from the baseline (e.g. 0.1 for +10%). It is infinite
+// when the baseline value is zero and the current one is not.
+//
+// A change in the direction of a worse performance beyond the comparison threshold is a regression,
+// and a change in the direction of a better performance beyond the threshold is an improvement.
+type Delta struct {
+	Result
+
+	Baseline    float64 `json:"baseline"`
+	Change      float64 `json:"change"`
+	Regression  bool    `json:"regression"`
+	Improvement bool    `json:"improvement"`
+}
+
+// Comparison holds the result of comparing a benchmark run against a baseline [Snapshot].
+//
+// Missing lists the results found in the baseline but not in the current run,
+// and Added the results of the current run not found in the baseline.
+//
+// Environment lists the differences between the environment of the baseline and the one of the current run
+// (e.g. "cpu: X != Y"), which make the comparison unreliable.
+type Comparison struct {
+	Baseline    string   `json:"baseline"`
+	Threshold   float64  `json:"threshold"`
+	Environment []string `json:"environment,omitempty"`
+	Deltas      []Delta  `json:"deltas"`
+	Missing     []Result `json:"missing,omitempty"`
+	Added       []Result `json:"added,omitempty"`
+}
+
+// Compare a current [Snapshot] against a baseline.
+//
+// The threshold is the relative change (e.g. 0.05 for 5%) beyond which a worse result is considered a regression.
+func Compare(base, current Snapshot, threshold float64) Comparison {
+	c := Comparison{
+		Baseline:    base.Name,
+		Threshold:   threshold,
+		Environment: environmentDiff(base.Environments, current.Environments),
+	}
+
+	baseValues := make(map[model.SeriesKey]float64, len(base.Results))
+	for _, r := range base.Results {
+		baseValues[r.Key()] = r.Value
+	}
+
+	currentKeys := make(map[model.SeriesKey]struct{}, len(current.Results))
+	for _, r := range current.Results {
+		key := r.Key()
+		currentKeys[key] = struct{}{}
+
+		baseValue, ok := baseValues[key]
+		if !ok {
+			c.Added = append(c.Added, r)
+
+			continue
+		}
+
+		c.Deltas = append(c.Deltas, newDelta(r, baseValue, threshold))
+	}
+
+	for _, r := range base.Results {
+		if _, ok := currentKeys[r.Key()]; !ok {
+			c.Missing = append(c.Missing, r)
+		}
+	}
+
+	return c
+}
+
+// Regressions returns the deltas that are regressions.
+func (c Comparison) Regressions() []Delta {
+	var regressions []Delta
+
+	for _, d := range c.Deltas {
+		if d.Regression {
+			regressions = append(regressions, d)
+		}
+	}
+
+	return regressions
+}
+
+// Filter retains the deltas shown by the given [config.Changes], e.g. only regressions.
+//
+// Results missing from either side are only retained when all kinds of changes are shown.
+// Filtering is meant for display: the regressions of the original comparison are unaffected.
+func (c Comparison) Filter(changes config.Changes) Comparison {
+	if !changes.IsFiltered() {
+		return c
+	}
+
+	filtered := c
+	filtered.Deltas = nil
+	for _, d := range c.Deltas {
+		if changes.Retains(d.Change, d.Regression, d.Improvement) {
+			filtered.Deltas = append(filtered.Deltas, d)
+		}
+	}
+
+	if changes.Only != "" && changes.Only != config.ChangeAll {
+		filtered.Missing = nil
+		filtered.Added = nil
+	}
+
+	return filtered
+}
+
+// Write the [Comparison] as an aligned, human-readable table.
+func (c Comparison) Write(w io.Writer) error {
+	return c.WriteColored(w, color.Palette{})
+}
+
+// WriteColored writes the [Comparison] as an aligned table, with regressions in red and improvements in green.
+func (c Comparison) WriteColored(w io.Writer, palette color.Palette) error {
+	lines := []color.Line{
+		{Text: fmt.Sprintf("Baseline: %s (threshold: %.1f%%)", c.Baseline, c.Threshold*100), Style: palette.Bold}, //nolint:mnd // percentage
+	}
+	if len(c.Environment) > 0 {
+		lines = append(lines, color.Line{Text: "WARNING: environment differs from baseline: " + strings.Join(c.Environment, ", "), Style: palette.Yellow})
+	}
+	lines = append(lines, color.Line{}, color.Line{Text: "Benchmark\tBaseline\tCurrent\tDelta\t\tSource"})
+
+	for _, d := range c.Deltas {
+		var (
+			status string
+			style  color.Style
+		)
+		switch {
+		case d.Regression:
+			status = "REGRESSION"
+			style = palette.Red
+		case d.Improvement:
+			style = palette.Green
+		}
+		lines = append(lines, color.Line{
+			Text:  fmt.Sprintf("%s\t%.4g\t%.4g\t%s\t%s\t%s", d.Result, d.Baseline, d.Value, FormatChange(d.Change), status, d.Source),
+			Style: style,
+		})
+	}
+
+	for _, r := range c.Missing {
+		lines = append(lines, color.Line{Text: fmt.Sprintf("%s\t%.4g\t-\tmissing\t\t%s", r, r.Value, r.Source), Style: palette.Yellow})
+	}
+
+	for _, r := range c.Added {
+		lines = append(lines, color.Line{Text: fmt.Sprintf("%s\t-\t%.4g\tadded\t\t%s", r, r.Value, r.Source)})
+	}
+
+	regressions := len(c.Regressions())
+	summary := color.Line{Text: fmt.Sprintf("Regressions: %d", regressions), Style: palette.Green}
+	if regressions > 0 {
+		summary.Style = palette.Red
+	}
+	lines = append(lines, color.Line{}, summary)
+
+	return color.WriteTable(w, lines)
+}
+
+// WriteMarkdown writes the [Comparison] as a Markdown table, e.g. to be pasted into a pull request.
+func (c Comparison) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Comparison with baseline %s\n\n", c.Baseline)
+	if len(c.Environment) > 0 {
+		fmt.Fprintf(&b, "> :warning: Environment differs from baseline: %s\n\n", strings.Join(c.Environment, ", "))
+	}
+	fmt.Fprintf(&b, "Regressions: %d (threshold: %.1f%%)\n\n", len(c.Regressions()), c.Threshold*100) //nolint:mnd // percentage
+	b.WriteString("| Benchmark | Baseline | Current | Delta | | Source |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, d := range c.Deltas {
+		status := ""
+		if d.Regression {
+			status = ":warning:"
+		}
+		fmt.Fprintf(&b, "| %s | %.4g | %.4g | %s | %s | %s |\n",
+			EscapeMarkdown(d.Result.String()), d.Baseline, d.Value, FormatChange(d.Change), status, EscapeMarkdown(d.Source),
+		)
+	}
+
+	for _, r := range c.Missing {
+		fmt.Fprintf(&b, "| %s | %.4g | - | missing | | %s |\n", EscapeMarkdown(r.String()), r.Value, EscapeMarkdown(r.Source))
+	}
+
+	for _, r := range c.Added {
+		fmt.Fprintf(&b, "| %s | - | %.4g | added | | %s |\n", EscapeMarkdown(r.String()), r.Value, EscapeMarkdown(r.Source))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// environmentDiff lists the differences between the environments of the baseline and of the current run.
+//
+// Each current environment is compared to the first environment of the baseline. Unknown environments are not compared.
+func environmentDiff(base, current []string) []string {
+	if len(base) == 0 || len(current) == 0 {
+		return nil
+	}
+
+	return model.EnvironmentDiff(append([]string{base[0]}, current...))
+}
+
+// FormatChange renders a relative change as a signed percentage, e.g. "+12.5%".
+func FormatChange(change float64) string {
+	if math.IsInf(change, 0) {
+		return "n/a"
+	}
+
+	return fmt.Sprintf("%+.1f%%", change*100) //nolint:mnd // percentage
+}
+
+func newDelta(r Result, baseValue, threshold float64) Delta {
+	d := Delta{
+		Result:   r,
+		Baseline: baseValue,
+	}
+
+	switch {
+	case baseValue == 0 && r.Value == 0:
+		d.Change = 0
+	case baseValue == 0:
+		d.Change = math.Inf(1)
+		if r.Value < 0 {
+			d.Change = math.Inf(-1)
+		}
+	default:
+		d.Change = (r.Value - baseValue) / math.Abs(baseValue)
+	}
+
+	worse := d.Change
+	if r.Metric.HigherIsBetter() {
+		worse = -worse
+	}
+	d.Regression = worse > threshold
+	d.Improvement = -worse > threshold
+
+	return d
+}
+
+// EscapeMarkdown escapes the content of a Markdown table cell: pipes are escaped,
+// and multi-line labels are joined on a single line.
+func EscapeMarkdown(cell string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ").Replace(cell)
+}
@@ -0,0 +1,186 @@
+package baseline
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/color"
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestCompare(t *testing.T) {
+	base := New("main", testScenario(100, 10))
+
+	t.Run("should find no regression within threshold", func(t *testing.T) {
+		c := Compare(base, New("current", testScenario(104, 9)), 0.05)
+
+		assert.Equal(t, "main", c.Baseline)
+		require.Len(t, c.Deltas, 2)
+		assert.InDelta(t, -0.1, c.Deltas[0].Change, 1e-9)
+		assert.InDelta(t, 0.04, c.Deltas[1].Change, 1e-9)
+		assert.Empty(t, c.Regressions())
+	})
+
+	t.Run("should find regressions beyond threshold", func(t *testing.T) {
+		c := Compare(base, New("current", testScenario(120, 10)), 0.05)
+
+		regressions := c.Regressions()
+		require.Len(t, regressions, 1)
+		assert.Equal(t, "reflect", regressions[0].Version)
+		assert.InDelta(t, 100, regressions[0].Baseline, 1e-9)
+		assert.InDelta(t, 0.2, regressions[0].Change, 1e-9)
+	})
+
+	t.Run("should report missing and added results", func(t *testing.T) {
+		current := New("current", testScenario(100, 10))
+		current.Results[0].Context = "float64"
+
+		c := Compare(base, current, 0.05)
+		require.Len(t, c.Deltas, 1)
+		require.Len(t, c.Missing, 1)
+		assert.Equal(t, "int", c.Missing[0].Context)
+		require.Len(t, c.Added, 1)
+		assert.Equal(t, "float64", c.Added[0].Context)
+	})
+}
+
+func TestCompareEnvironment(t *testing.T) {
+	base := New("main", testScenario(100, 10))
+	base.Environments = []string{"go1.23.4 linux amd64 cpu: CPU A"}
+
+	t.Run("should report a different environment", func(t *testing.T) {
+		current := New("current", testScenario(100, 10))
+		current.Environments = []string{"go1.23.4 darwin arm64 cpu: CPU B"}
+
+		c := Compare(base, current, 0.05)
+		assert.Equal(t, []string{"goos: linux != darwin", "goarch: amd64 != arm64", "cpu: CPU A != CPU B"}, c.Environment)
+
+		var text, markdown bytes.Buffer
+		require.NoError(t, c.Write(&text))
+		assert.Contains(t, text.String(), "WARNING: environment differs from baseline: goos: linux != darwin, goarch: amd64 != arm64, cpu: CPU A != CPU B\n")
+		require.NoError(t, c.WriteMarkdown(&markdown))
+		assert.Contains(t, markdown.String(), "> :warning: Environment differs from baseline: goos: linux != darwin")
+	})
+
+	t.Run("should ignore the version of Go", func(t *testing.T) {
+		current := New("current", testScenario(100, 10))
+		current.Environments = []string{"go1.24.0 linux amd64 cpu: CPU A"}
+
+		assert.Empty(t, Compare(base, current, 0.05).Environment)
+	})
+
+	t.Run("should ignore unknown environments", func(t *testing.T) {
+		assert.Empty(t, Compare(base, New("current", testScenario(100, 10)), 0.05).Environment)
+	})
+}
+
+func TestComparisonFilter(t *testing.T) {
+	base := New("main", testScenario(100, 10))
+	current := New("current", testScenario(120, 9.5))
+	current.Results = append(current.Results, Result{Function: "greater", Version: "extra", Metric: config.MetricNsPerOp, Value: 1})
+	c := Compare(base, current, 0.05)
+	require.Len(t, c.Deltas, 2)
+	require.Len(t, c.Added, 1)
+
+	t.Run("should keep everything without filter", func(t *testing.T) {
+		filtered := c.Filter(config.Changes{})
+		assert.Len(t, filtered.Deltas, 2)
+		assert.Len(t, filtered.Added, 1)
+	})
+
+	t.Run("should keep only regressions", func(t *testing.T) {
+		filtered := c.Filter(config.Changes{Only: config.ChangeRegressions})
+		require.Len(t, filtered.Deltas, 1)
+		assert.EqualT(t, "reflect", filtered.Deltas[0].Version)
+		assert.Empty(t, filtered.Added)
+	})
+
+	t.Run("should keep only changes beyond a minimum", func(t *testing.T) {
+		filtered := c.Filter(config.Changes{MinChange: 10})
+		require.Len(t, filtered.Deltas, 1)
+		assert.EqualT(t, "reflect", filtered.Deltas[0].Version)
+		assert.Len(t, filtered.Added, 1)
+	})
+
+	t.Run("should leave the original comparison unchanged", func(t *testing.T) {
+		_ = c.Filter(config.Changes{Only: config.ChangeImprovements})
+		assert.Len(t, c.Deltas, 2)
+		assert.Len(t, c.Regressions(), 1)
+	})
+}
+
+func TestNewDelta(t *testing.T) {
+	t.Run("should consider a lower throughput as a regression", func(t *testing.T) {
+		d := newDelta(Result{Metric: config.MetricMBPerS, Value: 80}, 100, 0.05)
+		assert.True(t, d.Regression)
+
+		d = newDelta(Result{Metric: config.MetricMBPerS, Value: 120}, 100, 0.05)
+		assert.False(t, d.Regression)
+	})
+
+	t.Run("should compare with a zero baseline", func(t *testing.T) {
+		d := newDelta(Result{Metric: config.MetricAllocsPerOp, Value: 0}, 0, 0.05)
+		assert.Zero(t, d.Change)
+		assert.False(t, d.Regression)
+
+		d = newDelta(Result{Metric: config.MetricAllocsPerOp, Value: 1}, 0, 0.05)
+		assert.True(t, math.IsInf(d.Change, 1))
+		assert.True(t, d.Regression)
+		assert.Equal(t, "n/a", FormatChange(d.Change))
+	})
+}
+
+func TestComparisonWrite(t *testing.T) {
+	c := Compare(New("main", testScenario(100, 10)), New("current", testScenario(120, 10)), 0.05)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.Write(&buf))
+
+	output := buf.String()
+	assert.Contains(t, output, "Baseline: main (threshold: 5.0%)\n")
+	assert.Contains(t, output, "greater/reflect/int (nsPerOp)")
+	assert.Contains(t, output, "+20.0%")
+	assert.Contains(t, output, "REGRESSION")
+	assert.Contains(t, output, "Regressions: 1\n")
+}
+
+func TestComparisonWriteColored(t *testing.T) {
+	c := Compare(New("main", testScenario(100, 10)), New("current", testScenario(120, 8)), 0.05)
+	for _, d := range c.Deltas {
+		assert.EqualT(t, d.Version == "generics", d.Improvement)
+	}
+
+	var plain, colored bytes.Buffer
+	require.NoError(t, c.Write(&plain))
+	require.NoError(t, c.WriteColored(&colored, color.New(true)))
+
+	output := colored.String()
+	assert.Contains(t, output, "\x1b[31mgreater/reflect/int (nsPerOp)")
+	assert.Contains(t, output, "\x1b[32mgreater/generics/int (nsPerOp)")
+	assert.Contains(t, output, "\x1b[31mRegressions: 1\x1b[0m\n")
+
+	// colors don't break the alignment of columns
+	assert.Equal(t, plain.String(), stripANSI(output))
+}
+
+func stripANSI(s string) string {
+	return regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(s, "")
+}
+
+func TestComparisonWriteMarkdown(t *testing.T) {
+	c := Compare(New("main", testScenario(100, 10)), New("current", testScenario(120, 10)), 0.05)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.WriteMarkdown(&buf))
+
+	output := buf.String()
+	assert.Contains(t, output, "## Comparison with baseline main\n")
+	assert.Contains(t, output, "Regressions: 1 (threshold: 5.0%)\n")
+	assert.Contains(t, output, "| greater/reflect/int (nsPerOp) | 100 | 120 | +20.0% | :warning: |  |\n")
+	assert.Contains(t, output, "| greater/generics/int (nsPerOp) | 10 | 10 | +0.0% |  |  |\n")
+}
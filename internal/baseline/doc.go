@@ -0,0 +1,3 @@
+// Package baseline stores named snapshots of organized benchmark results,
+// and compares new benchmark runs against them.
+package baseline
@@ -0,0 +1,88 @@
+package baseline
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+// WriteJUnit writes the [Comparison] as a JUnit XML report, so CI dashboards display regressions as test failures.
+//
+// Each compared result is a test case, which fails on a regression.
+// Results missing from the current run or from the baseline are skipped test cases.
+func (c Comparison) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name: "benchviz baseline " + c.Baseline,
+	}
+
+	for _, d := range c.Deltas {
+		tc := junitTestCase{ClassName: d.Function, Name: d.Result.String()}
+		if d.Regression {
+			tc.Failure = &junitMessage{
+				Message: fmt.Sprintf("%s against baseline %s (%.4g -> %.4g)", FormatChange(d.Change), c.Baseline, d.Baseline, d.Value),
+				Type:    "regression",
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, r := range c.Missing {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			ClassName: r.Function,
+			Name:      r.String(),
+			Skipped:   &junitMessage{Message: "missing from the current run"},
+		})
+		suite.Skipped++
+	}
+
+	for _, r := range c.Added {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			ClassName: r.Function,
+			Name:      r.String(),
+			Skipped:   &junitMessage{Message: "not found in baseline " + c.Baseline},
+		})
+		suite.Skipped++
+	}
+	suite.Tests = len(suite.Cases)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", " ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
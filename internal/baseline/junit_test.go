@@ -0,0 +1,31 @@
+package baseline
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestComparisonWriteJUnit(t *testing.T) {
+	current := New("current", testScenario(150, 10))
+	current.Results = append(current.Results, Result{Function: "less", Metric: "nsPerOp", Value: 5})
+	c := Compare(New("main", testScenario(100, 10)), current, 0.05)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.WriteJUnit(&buf))
+
+	output := buf.String()
+	assert.Contains(t, output, xml.Header)
+	assert.Contains(t, output, `<testsuite name="benchviz baseline main" tests="3" failures="1" skipped="1">`)
+	assert.Contains(t, output, `<testcase classname="greater" name="greater/reflect/int (nsPerOp)">`)
+	assert.Contains(t, output, `<failure message="+50.0% against baseline main (100 -&gt; 150)" type="regression"></failure>`)
+	assert.Contains(t, output, `<skipped message="not found in baseline main"></skipped>`)
+
+	var decoded junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded.Suites, 1)
+	assert.Len(t, decoded.Suites[0].Cases, 3)
+}
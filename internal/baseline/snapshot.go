@@ -0,0 +1,181 @@
+package baseline
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// Snapshot holds the organized results of a benchmark run, saved under a name.
+//
+// Meta is the metadata of the run, as set on the scenario.
+// Environments are the environments in which the benchmarks were run (e.g. "linux amd64 cpu: ...").
+type Snapshot struct {
+	Name         string            `json:"name"`
+	Scenario     string            `json:"scenario,omitempty"`
+	Created      time.Time         `json:"created"`
+	Meta         map[string]string `json:"meta,omitempty"`
+	Environments []string          `json:"environments,omitempty"`
+	Results      []Result          `json:"results"`
+}
+
+// Result is the value of a metric for a benchmark, identified by its function, version and context.
+//
+// Source traces the value back to the benchmark results it is computed from (see [model.Origin]).
+type Result struct {
+	Function string            `json:"function"`
+	Version  string            `json:"version,omitempty"`
+	Context  string            `json:"context,omitempty"`
+	Metric   config.MetricName `json:"metric"`
+	Value    float64           `json:"value"`
+	Source   string            `json:"source,omitempty"`
+}
+
+// Key identifies the benchmark series of the result.
+func (r Result) Key() model.SeriesKey {
+	return model.SeriesKey{
+		Function: r.Function,
+		Version:  r.Version,
+		Context:  r.Context,
+		Metric:   r.Metric,
+	}
+}
+
+// String renders the identification of the result, e.g. "greater/reflect/int (nsPerOp)".
+func (r Result) String() string {
+	name := r.Function
+	for _, part := range []string{r.Version, r.Context} {
+		if part != "" {
+			name += "/" + part
+		}
+	}
+
+	return fmt.Sprintf("%s (%s)", name, r.Metric)
+}
+
+// New builds a [Snapshot] from the data points of a [model.Scenario].
+//
+// Points found in several categories are recorded once. Duplicate points (e.g. non-aggregated runs)
+// are recorded as their mean value.
+func New(name string, scenario *model.Scenario) Snapshot {
+	type sum struct {
+		total  float64
+		count  int
+		origin model.Origin
+	}
+
+	sums := make(map[model.SeriesKey]sum)
+
+	for _, category := range scenario.Categories {
+		// all the points for a key are found in a single category
+		categorySums := make(map[model.SeriesKey]sum)
+		for _, data := range category.Data {
+			for _, series := range data.Series {
+				for _, point := range series.Points {
+					s := categorySums[point.SeriesKey]
+					s.total += point.Value
+					s.count++
+					s.origin = s.origin.Merge(point.Origin)
+					categorySums[point.SeriesKey] = s
+				}
+			}
+		}
+
+		for key, s := range categorySums {
+			if _, ok := sums[key]; !ok {
+				sums[key] = s
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(sums))
+	for key, s := range sums {
+		results = append(results, Result{
+			Function: key.Function,
+			Version:  key.Version,
+			Context:  key.Context,
+			Metric:   key.Metric,
+			Value:    s.total / float64(s.count),
+			Source:   s.origin.String(),
+		})
+	}
+	sortResults(results)
+
+	return Snapshot{
+		Name:         name,
+		Scenario:     scenario.Name,
+		Created:      time.Now().UTC(),
+		Meta:         scenario.Meta,
+		Environments: scenario.Environments(),
+		Results:      results,
+	}
+}
+
+// Write the [Snapshot] as JSON.
+func (s Snapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+
+	return enc.Encode(s)
+}
+
+// Read a [Snapshot] previously written with [Snapshot.Write].
+func Read(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return s, fmt.Errorf("decoding baseline snapshot: %w", err)
+	}
+
+	return s, nil
+}
+
+func sortResults(results []Result) {
+	slices.SortFunc(results, func(a, b Result) int {
+		return cmp.Or(
+			cmp.Compare(a.Function, b.Function),
+			cmp.Compare(a.Version, b.Version),
+			cmp.Compare(a.Context, b.Context),
+			cmp.Compare(a.Metric, b.Metric),
+		)
+	})
+}
+
+// ReadDir reads all the snapshots stored as JSON files in a directory, ordered by creation time.
+//
+// Files that are not snapshots are skipped.
+func ReadDir(dir string) ([]Snapshot, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]Snapshot, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot: %w", err)
+		}
+
+		snapshot, err := Read(bytes.NewReader(content))
+		if err != nil || snapshot.Name == "" {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	slices.SortStableFunc(snapshots, func(a, b Snapshot) int {
+		return cmp.Or(a.Created.Compare(b.Created), cmp.Compare(a.Name, b.Name))
+	})
+
+	return snapshots, nil
+}
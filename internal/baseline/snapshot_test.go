@@ -0,0 +1,136 @@
+package baseline
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+func TestNew(t *testing.T) {
+	snapshot := New("main", testScenario(100, 10))
+
+	assert.Equal(t, "main", snapshot.Name)
+	assert.Equal(t, "test", snapshot.Scenario)
+	assert.False(t, snapshot.Created.IsZero())
+	assert.Equal(t, []Result{
+		{Function: "greater", Version: "generics", Context: "int", Metric: config.MetricNsPerOp, Value: 10},
+		{Function: "greater", Version: "reflect", Context: "int", Metric: config.MetricNsPerOp, Value: 100},
+	}, snapshot.Results)
+
+	t.Run("should record duplicate points as their mean", func(t *testing.T) {
+		scenario := testScenario(100, 10)
+		points := &scenario.Categories[0].Data[0].Series[0].Points
+		*points = append(*points, testPoint("reflect", 200))
+
+		snapshot := New("main", scenario)
+		require.Len(t, snapshot.Results, 2)
+		assert.InDelta(t, 150, snapshot.Results[1].Value, 1e-9)
+	})
+
+	t.Run("should record the source of points", func(t *testing.T) {
+		scenario := testScenario(100, 10)
+		points := scenario.Categories[0].Data[0].Series[0].Points
+		points[0].Origin = model.Origin{Files: []string{"old.txt"}, Benchmark: "BenchmarkGreater/reflect/int-8", Samples: 1}
+		duplicate := testPoint("reflect", 200)
+		duplicate.Origin = model.Origin{Files: []string{"new.txt"}, Benchmark: "BenchmarkGreater/reflect/int-8", Samples: 1}
+		scenario.Categories[0].Data[0].Series[0].Points = append(points, duplicate)
+
+		snapshot := New("main", scenario)
+		require.Len(t, snapshot.Results, 2)
+		assert.EqualT(t, "BenchmarkGreater/reflect/int-8 (old.txt, new.txt, 2 samples)", snapshot.Results[1].Source)
+	})
+
+	t.Run("should record points found in several categories once", func(t *testing.T) {
+		scenario := testScenario(100, 10)
+		scenario.Categories = append(scenario.Categories, scenario.Categories[0])
+
+		snapshot := New("main", scenario)
+		require.Len(t, snapshot.Results, 2)
+		assert.InDelta(t, 100, snapshot.Results[1].Value, 1e-9)
+	})
+}
+
+func TestSnapshotReadWrite(t *testing.T) {
+	snapshot := New("main", testScenario(100, 10))
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Write(&buf))
+
+	read, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.Name, read.Name)
+	assert.True(t, snapshot.Created.Equal(read.Created))
+	assert.Equal(t, snapshot.Results, read.Results)
+
+	_, err = Read(bytes.NewBufferString("{"))
+	require.Error(t, err)
+}
+
+func TestResultString(t *testing.T) {
+	assert.Equal(t, "greater/reflect/int (nsPerOp)", Result{Function: "greater", Version: "reflect", Context: "int", Metric: config.MetricNsPerOp}.String())
+	assert.Equal(t, "greater (nsPerOp)", Result{Function: "greater", Metric: config.MetricNsPerOp}.String())
+}
+
+func TestReadDir(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, name := range []string{"v2", "v1", "v3"} {
+		snapshot := New(name, testScenario(100, 10))
+		snapshot.Created = start.Add(time.Duration([]int{2, 1, 3}[i]) * time.Hour)
+
+		var buf bytes.Buffer
+		require.NoError(t, snapshot.Write(&buf))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".json"), buf.Bytes(), 0o600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.json"), []byte(`{"artifacts": []}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a snapshot"), 0o600))
+
+	snapshots, err := ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+	assert.Equal(t, "v1", snapshots[0].Name)
+	assert.Equal(t, "v2", snapshots[1].Name)
+	assert.Equal(t, "v3", snapshots[2].Name)
+
+	t.Run("with missing directory", func(t *testing.T) {
+		snapshots, err := ReadDir(filepath.Join(dir, "missing"))
+		require.NoError(t, err)
+		assert.Empty(t, snapshots)
+	})
+}
+
+func testScenario(reflectValue, genericsValue float64) *model.Scenario {
+	return &model.Scenario{
+		Name: "test",
+		Categories: []model.Category{
+			{
+				ID: "comparisons",
+				Data: []model.CategoryData{
+					{Series: []model.MetricSeries{{Points: []model.MetricPoint{testPoint("reflect", reflectValue)}}}},
+					{Series: []model.MetricSeries{{Points: []model.MetricPoint{testPoint("generics", genericsValue)}}}},
+				},
+			},
+		},
+	}
+}
+
+func testPoint(version string, value float64) model.MetricPoint {
+	return model.MetricPoint{
+		SeriesKey: model.SeriesKey{
+			Function: "greater",
+			Version:  version,
+			Context:  "int",
+			Metric:   config.MetricNsPerOp,
+		},
+		Value: value,
+	}
+}
@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// formatVersion is hashed into every key: bumping it invalidates all cached entries,
+// e.g. when the layout of [Entry] or the organization of benchmarks changes.
+const formatVersion = "benchviz-cache-v1"
+
+const (
+	dirPermissions  = 0o755
+	filePermissions = 0o644
+)
+
+// Entry holds the intermediate results of a run: the parsed benchmarks and the scenario organized from them.
+type Entry struct {
+	Sets     []parser.Set    `json:"sets"`
+	Scenario *model.Scenario `json:"scenario"`
+}
+
+// Cache stores entries as JSON files in a directory, one file per key.
+type Cache struct {
+	dir string
+}
+
+// New builds a [Cache] storing entries in a directory. The directory is created when the first entry is stored.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Get the entry stored for a key.
+//
+// It returns false without an error if no entry is stored for this key.
+func (c *Cache) Get(key string) (Entry, bool, error) {
+	content, err := os.ReadFile(c.file(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("reading cache entry: %w", err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return Entry{}, false, fmt.Errorf("decoding cache entry %q: %w", c.file(key), err)
+	}
+
+	return entry, true, nil
+}
+
+// Put stores the entry for a key.
+//
+// The entry is written to a temporary file first, so concurrent readers never see a partial entry.
+func (c *Cache) Put(key string, entry Entry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(c.dir, dirPermissions); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), filePermissions); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), c.file(key))
+}
+
+func (c *Cache) file(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Hasher computes the key of a cache entry from everything that determines it:
+// input files, configuration and settings.
+type Hasher struct {
+	h hash.Hash
+}
+
+// NewHasher builds a [Hasher].
+func NewHasher() *Hasher {
+	h := &Hasher{h: sha256.New()}
+	h.AddString(formatVersion)
+
+	return h
+}
+
+// AddString adds a string to the hash.
+//
+// Strings are length-prefixed, so that adding "ab", "c" and "a", "bc" produce different keys.
+func (h *Hasher) AddString(values ...string) {
+	for _, value := range values {
+		_, _ = fmt.Fprintf(h.h, "%d:%s", len(value), value)
+	}
+}
+
+// AddJSON adds the JSON representation of a value to the hash.
+func (h *Hasher) AddJSON(value any) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	h.AddString(string(content))
+
+	return nil
+}
+
+// AddFile adds the name and the content of a file to the hash.
+//
+// For a directory, all the files it contains are added, in lexical order.
+func (h *Hasher) AddFile(name string) error {
+	return filepath.WalkDir(name, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		content := sha256.New()
+		if _, err := io.Copy(content, file); err != nil {
+			return err
+		}
+		h.AddString(path, hex.EncodeToString(content.Sum(nil)))
+
+		return nil
+	})
+}
+
+// Sum returns the key, as a hex-encoded string.
+func (h *Hasher) Sum() string {
+	return hex.EncodeToString(h.h.Sum(nil))
+}
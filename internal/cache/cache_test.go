@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestCache(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "cache"))
+
+	_, ok, err := c.Get("missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	entry := Entry{
+		Sets: []parser.Set{
+			{
+				Set: parse.Set{
+					"BenchmarkSort": {{Name: "BenchmarkSort", N: 100, NsPerOp: 12.5, Measured: parse.NsPerOp}},
+				},
+				File:  "bench.txt",
+				Label: "v1",
+			},
+		},
+		Scenario: &model.Scenario{Name: "test", Categories: []model.Category{{ID: "sort"}}},
+	}
+	require.NoError(t, c.Put("key", entry))
+
+	restored, ok, err := c.Get("key")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, entry, restored)
+
+	t.Run("should report corrupted entries", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(c.file("corrupted"), []byte("{"), 0o600))
+
+		_, _, err := c.Get("corrupted")
+		require.ErrorContains(t, err, "decoding cache entry")
+	})
+}
+
+func TestHasher(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bench.txt")
+	require.NoError(t, os.WriteFile(file, []byte("BenchmarkSort 100 12.5 ns/op\n"), 0o600))
+
+	key := func(values ...string) string {
+		h := NewHasher()
+		h.AddString(values...)
+		require.NoError(t, h.AddFile(file))
+
+		return h.Sum()
+	}
+
+	first := key("a", "bc")
+	assert.EqualT(t, first, key("a", "bc"))
+	assert.NotEqual(t, first, key("ab", "c"))
+
+	t.Run("should change with the content of files", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(file, []byte("BenchmarkSort 100 13 ns/op\n"), 0o600))
+		assert.NotEqual(t, first, key("a", "bc"))
+	})
+
+	t.Run("should hash all files in a directory", func(t *testing.T) {
+		h := NewHasher()
+		require.NoError(t, h.AddFile(dir))
+		before := h.Sum()
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "other.txt"), []byte("x"), 0o600))
+		h = NewHasher()
+		require.NoError(t, h.AddFile(dir))
+		assert.NotEqual(t, before, h.Sum())
+	})
+
+	t.Run("should fail on missing files", func(t *testing.T) {
+		require.Error(t, NewHasher().AddFile(filepath.Join(dir, "missing")))
+	})
+}
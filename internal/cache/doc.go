@@ -0,0 +1,6 @@
+// Package cache stores the intermediate results of benchviz runs, keyed by a hash of their inputs.
+//
+// Repeated invocations over unchanged inputs and configuration (e.g. CI retries, or requests
+// in serve mode) restore the parsed benchmarks and the organized scenario from the cache,
+// instead of parsing and organizing inputs again.
+package cache
@@ -0,0 +1,117 @@
+package chart
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	echartsopts "github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// Layout of the annotations displayed at the top of a chart, below the title and above the series.
+const (
+	annotationTop        = 60
+	annotationLineHeight = 16
+	annotationZ          = 100
+)
+
+// Annotation is a freeform text documenting a chart.
+//
+// X optionally positions the annotation on the X axis, as a vertical mark line: a label on a category
+// X axis, or a number on a numeric X axis. Annotations without a position are displayed at the top of the chart.
+type Annotation struct {
+	Text string
+	X    string
+}
+
+// markLineAnnotations returns the series options drawing positioned annotations as mark lines.
+func (c *Chart) markLineAnnotations() []charts.SeriesOpts {
+	var seriesOpts []charts.SeriesOpts
+
+	for _, annotation := range c.Annotations {
+		if annotation.X == "" {
+			continue
+		}
+
+		var position any = annotation.X
+		if c.IsNumeric() {
+			x, err := strconv.ParseFloat(annotation.X, 64)
+			if err != nil {
+				continue
+			}
+			position = x
+		}
+
+		// the category axis is the Y axis once a bar chart is reversed
+		if c.Horizontal && !c.IsNumeric() {
+			seriesOpts = append(seriesOpts, charts.WithMarkLineNameYAxisItemOpts(echartsopts.MarkLineNameYAxisItem{Name: annotation.Text, YAxis: position}))
+
+			continue
+		}
+
+		seriesOpts = append(seriesOpts, charts.WithMarkLineNameXAxisItemOpts(echartsopts.MarkLineNameXAxisItem{Name: annotation.Text, XAxis: position}))
+	}
+
+	if len(seriesOpts) == 0 {
+		return nil
+	}
+
+	return append(seriesOpts, charts.WithMarkLineStyleOpts(echartsopts.MarkLineStyle{
+		Symbol: []string{"none", "none"},
+		Label: &echartsopts.Label{
+			Show:      echartsopts.Bool(true),
+			Formatter: "{b}",
+		},
+	}))
+}
+
+// graphicElement is an ECharts graphic component element.
+type graphicElement struct {
+	Type  string       `json:"type"`
+	Left  string       `json:"left"`
+	Top   int          `json:"top"`
+	Z     int          `json:"z"`
+	Style graphicStyle `json:"style"`
+}
+
+type graphicStyle struct {
+	Text string `json:"text"`
+	Fill string `json:"fill"`
+	Font string `json:"font"`
+}
+
+// graphicAnnotations returns the scripts displaying annotations without a position at the top of the chart.
+//
+// go-echarts doesn't support the graphic component: elements are set once the chart is initialized.
+func (c *Chart) graphicAnnotations() []string {
+	var elements []graphicElement
+
+	for _, annotation := range c.Annotations {
+		if annotation.X != "" {
+			continue
+		}
+
+		elements = append(elements, graphicElement{
+			Type: "text",
+			Left: "center",
+			Top:  annotationTop + len(elements)*annotationLineHeight,
+			Z:    annotationZ,
+			Style: graphicStyle{
+				Text: annotation.Text,
+				Fill: "#888",
+				Font: "italic 12px sans-serif",
+			},
+		})
+	}
+
+	if len(elements) == 0 {
+		return nil
+	}
+
+	graphic, err := json.Marshal(elements)
+	if err != nil {
+		return nil
+	}
+
+	return []string{"%MY_ECHARTS%.setOption({graphic: " + string(graphic) + "});"}
+}
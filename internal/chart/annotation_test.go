@@ -0,0 +1,63 @@
+package chart
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestAnnotations(t *testing.T) {
+	newChart := func(opts ...Option) *Chart {
+		chart := NewChart(append([]Option{
+			WithTitle("Annotated"),
+			WithXAxisLabels([]string{"small", "large"}),
+			WithAnnotations(
+				Annotation{Text: "go1.22, after sync.Pool change"},
+				Annotation{Text: "new allocator", X: "large"},
+			),
+		}, opts...)...)
+		chart.Series = []Series{{Name: "v1"}, {Name: "v2"}}
+
+		return chart
+	}
+
+	render := func(t *testing.T, chart *Chart) string {
+		t.Helper()
+
+		page := NewPage("Annotations")
+		page.AddChart(chart)
+
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+
+		return buf.String()
+	}
+
+	t.Run("should display annotations without position at the top of the chart", func(t *testing.T) {
+		html := render(t, newChart())
+
+		assert.Contains(t, html, `.setOption({graphic: [{"type":"text","left":"center","top":60,"z":100,"style":{"text":"go1.22, after sync.Pool change"`)
+	})
+
+	t.Run("should draw positioned annotations as a mark line, once", func(t *testing.T) {
+		html := render(t, newChart())
+
+		assert.Contains(t, html, `{"name":"new allocator","xAxis":"large"}`)
+		assert.EqualT(t, 1, bytes.Count([]byte(html), []byte(`"new allocator"`)))
+	})
+
+	t.Run("should position annotations on the category axis of horizontal charts", func(t *testing.T) {
+		html := render(t, newChart(WithHorizontal(true)))
+
+		assert.Contains(t, html, `{"name":"new allocator","yAxis":"large"}`)
+	})
+
+	t.Run("should position annotations at a number on a numeric axis", func(t *testing.T) {
+		chart := newChart(WithXAxisType(xAxisLog), WithAnnotations(Annotation{Text: "cache size", X: "4096"}))
+
+		assert.Len(t, chart.markLineAnnotations(), 2) // "large" isn't a number
+		assert.Contains(t, render(t, chart), `{"name":"cache size","xAxis":4096}`)
+	})
+}
@@ -3,6 +3,7 @@ package chart
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/fredbi/benchviz/internal/config"
 	"github.com/fredbi/benchviz/internal/model"
@@ -10,20 +11,68 @@ import (
 
 // Builder constructs charts from scenarized benchmark data.
 type Builder struct {
-	cfg      *config.Config
-	scenario *model.Scenario
-	l        *slog.Logger
+	cfg         *config.Config
+	scenario    *model.Scenario
+	concurrency int
+	screenshot  bool
+	metadata    *Metadata
+	cache       *Cache
+	cacheScope  string
+	l           *slog.Logger
+}
+
+// BuilderOption configures a chart [Builder].
+type BuilderOption func(*Builder)
+
+// WithLogger sets the logger used by the [Builder].
+//
+// By default, the builder logs with [slog.Default].
+func WithLogger(l *slog.Logger) BuilderOption {
+	return func(b *Builder) {
+		b.l = l.With(slog.String("module", "chart"))
+	}
+}
+
+// WithConcurrency sets the maximum number of charts built concurrently, by the [Builder] and
+// when rendering the [Page] it builds.
+//
+// By default, charts are built by as many goroutines as [runtime.GOMAXPROCS]. A concurrency of 1
+// builds charts sequentially.
+func WithConcurrency(n int) BuilderOption {
+	return func(b *Builder) {
+		b.concurrency = n
+	}
+}
+
+// WithScreenshot tells the [Builder] that charts are rendered for a PNG screenshot.
+//
+// Unless configured otherwise with render.animation, charts are then not animated, so that
+// screenshots are deterministic.
+func WithScreenshot(enabled bool) BuilderOption {
+	return func(b *Builder) {
+		b.screenshot = enabled
+	}
 }
 
 // New creates a new chart [Builder], given a [config.Config] and a pre-calculated [model.Scenario].
 //
 // The builder embeds a [slog.Logger] to croak about warnings and issues.
-func New(cfg *config.Config, scenario *model.Scenario) *Builder {
-	return &Builder{
+func New(cfg *config.Config, scenario *model.Scenario, opts ...BuilderOption) *Builder {
+	b := &Builder{
 		cfg:      cfg,
 		scenario: scenario,
 		l:        slog.Default().With(slog.String("module", "chart")),
 	}
+
+	for _, apply := range opts {
+		apply(b)
+	}
+
+	if b.cache != nil && b.cacheScope != "" {
+		b.cache = b.cache.scope(b.cacheScope)
+	}
+
+	return b
 }
 
 // defaultPageTitle is the HTML <title> used when neither render.title nor the
@@ -31,23 +80,114 @@ func New(cfg *config.Config, scenario *model.Scenario) *Builder {
 const defaultPageTitle = "Benchmark results"
 
 // BuildPage creates a page with all charts for all metrics and categories.
+//
+// Charts are built concurrently (see [WithConcurrency]): the order of charts on the page
+// is the order of categories and metrics in the scenario.
+//
+// With render.overview, summary charts come first (one per metric), with the geometric mean of each version.
+// With render.maxBars, categories with too many bars are split into several charts.
+// With [WithCache], the charts of categories unchanged since the previous page are reused.
 func (b *Builder) BuildPage() *Page {
 	page := NewPage(b.pageTitle())
+	page.concurrency = b.concurrency
+	page.Metadata = b.pageMetadata()
+
+	type job struct {
+		categoryID string // the ID of the paginated category
+		category   model.Category
+		metrics    []config.Metric
+	}
 
+	var (
+		jobs   []job
+		cached = make(map[string][]*Chart)
+		prints = make(map[string]string)
+	)
 	for _, category := range b.scenario.Categories {
-		for _, metric := range category.Metrics() {
-			chart := b.buildChartForMetric(category, metric)
-			if chart == nil {
-				b.l.Warn("empty chart skipped", slog.String("category_id", category.ID))
+		if b.cache != nil {
+			fingerprint := categoryFingerprint(category)
+			if charts, ok := b.cache.get(category, fingerprint); ok {
+				cached[category.ID] = charts
 
 				continue
 			}
+			prints[category.ID] = fingerprint
+		}
+
+		for _, part := range paginate(category, b.cfg.Render.MaxBars) {
+			metrics := part.Metrics()
+			if part.MetricLayout.IsCombined() && len(metrics) > 1 {
+				jobs = append(jobs, job{categoryID: category.ID, category: part, metrics: metrics})
 
+				continue
+			}
+
+			for _, metric := range metrics {
+				jobs = append(jobs, job{categoryID: category.ID, category: part, metrics: []config.Metric{metric}})
+			}
+		}
+	}
+
+	built := Concurrently(b.concurrency, jobs, func(j job) *Chart {
+		switch {
+		case len(j.metrics) == 1:
+			return b.buildChartForMetric(j.category, j.metrics[0])
+		case j.category.MetricLayout == config.MetricLayoutDualAxis:
+			return b.buildDualAxisChart(j.category, j.metrics[0], j.metrics[1])
+		default:
+			return b.buildSelectorChart(j.category, j.metrics)
+		}
+	})
+
+	if b.cfg.Render.Overview {
+		for _, chart := range b.buildOverview() {
+			page.AddChart(chart)
+			b.l.Info("added overview chart", slog.String("title", chart.Title))
+		}
+	}
+
+	// charts built for each category, in the order of jobs
+	rebuilt := make(map[string]cacheEntry, len(prints))
+	for i, chart := range built {
+		categoryID := jobs[i].categoryID
+		entry, ok := rebuilt[categoryID]
+		if !ok {
+			entry.fingerprint = prints[categoryID]
+		}
+		if chart == nil {
+			b.l.Warn("empty chart skipped", slog.String("category_id", jobs[i].category.ID))
+		} else {
+			entry.charts = append(entry.charts, chart)
+			b.l.Info("added chart", slog.String("category_id", jobs[i].category.ID))
+		}
+		rebuilt[categoryID] = entry
+	}
+
+	for _, category := range b.scenario.Categories {
+		if charts, ok := cached[category.ID]; ok {
+			for _, chart := range charts {
+				page.AddChart(chart)
+			}
+			b.l.Debug("reused cached charts", slog.String("category_id", category.ID))
+
+			continue
+		}
+
+		for _, chart := range rebuilt[category.ID].charts {
 			page.AddChart(chart)
-			b.l.Info("added chart", slog.String("category_id", category.ID))
 		}
 	}
 
+	if b.cache != nil {
+		for id, entry := range rebuilt {
+			if entry.fingerprint == "" {
+				delete(rebuilt, id)
+			}
+		}
+		b.cache.put(b.scenario, rebuilt)
+	}
+
+	page.tableOfContents = b.cfg.Render.HasTableOfContents(len(page.Charts))
 	b.l.Info("added charts", slog.Int("charts", len(page.Charts)))
 
 	return page
@@ -73,48 +213,187 @@ func (b *Builder) buildChartForMetric(category model.Category, metric config.Met
 		return nil
 	}
 
-	showLegend := b.cfg.Render.Legend != config.LegendPositionNone
-	title := category.TitleWithPlaceHolders(metric)
-	yAxis := metric.Title + " (" + metric.Axis + ")"
+	opts := append(b.categoryOptions(category),
+		WithTitle(category.TitleWithPlaceHolders(metric)),
+		WithYAxisLabel(axisLabel(metric)),
+	)
 
-	opts := []Option{
-		WithTitle(title),
+	if category.Difference.IsEnabled() {
+		return b.buildDifferenceChart(category, metric, opts)
+	}
+
+	chart := NewChart(append(opts, groupOptions(category)...)...)
+	b.addMetricSeries(chart, category, metric)
+
+	return chart
+}
+
+// categoryOptions returns the chart options of a category, common to the charts of all its metrics.
+func (b *Builder) categoryOptions(category model.Category) []Option {
+	opts := append(b.renderOptions(),
 		WithXAxisLabels(category.Labels()),
-		WithYAxisLabel(yAxis),
-		WithSubtitle(category.Environment),
-		WithLegend(showLegend),
-		WithLegendPosition(string(b.cfg.Render.Legend)),
-		WithHorizontal(b.cfg.Render.Orientation == config.OrientationHorizontal),
-		WithLabelFontSize(b.cfg.Render.LabelFontSize),
+		WithSubtitle(subtitle(category)),
+	)
+
+	if category.XAxis != "" {
+		opts = append(opts, WithXAxisType(string(category.XAxis)))
 	}
 
-	if b.cfg.Render.Theme != "" {
-		opts = append(opts, WithTheme(b.cfg.Render.Theme))
+	if category.AxisLabels.Width > 0 {
+		opts = append(opts, WithLabelWidth(category.AxisLabels.Width, category.AxisLabels.Overflow == config.LabelOverflowWrap))
 	}
 
-	if w, h := b.chartSize(); w != "" {
-		opts = append(opts, WithSize(w, h))
+	if abbreviations := abbreviatedLabels(category); len(abbreviations) > 0 {
+		opts = append(opts, WithLabelAbbreviations(abbreviations))
+	}
+
+	for _, annotation := range category.Annotations {
+		opts = append(opts, WithAnnotations(Annotation{Text: annotation.Text, X: annotation.X}))
+	}
+
+	return opts
+}
+
+// abbreviatedLabels returns the abbreviations of the x-axis labels of a category, by label.
+//
+// Labels left unchanged by the abbreviation rules of the category are omitted.
+func abbreviatedLabels(category model.Category) map[string]string {
+	if len(category.AxisLabels.Abbreviations) == 0 {
+		return nil
+	}
+
+	abbreviations := make(map[string]string)
+	for _, label := range category.Labels() {
+		if short := category.AxisLabels.Abbreviate(label); short != label {
+			abbreviations[label] = short
+		}
 	}
 
-	chart := NewChart(opts...)
+	return abbreviations
+}
+
+// groupOptions returns the chart options highlighting the function groups of a category.
+func groupOptions(category model.Category) []Option {
+	groups := category.Groups()
+	opts := make([]Option, 0, len(groups))
+	for _, group := range groups {
+		opts = append(opts, WithGroups(Group{Title: group.Title, From: group.From, To: group.To}))
+	}
+
+	return opts
+}
+
+// axisLabel returns the label of the value axis of a metric, e.g. "Benchmark Timings (ns/op)".
+func axisLabel(metric config.Metric) string {
+	return metric.Title + " (" + metric.Axis + ")"
+}
 
+// addMetricSeries adds the series of a metric of a category to a chart.
+func (b *Builder) addMetricSeries(chart *Chart, category model.Category, metric config.Metric) {
 	for _, data := range category.Data { // iterate the series in a category
 		for _, series := range data.Series { // each category, iterate over series
 			if series.Metric != metric.ID {
 				continue
 			}
 
-			chart.AddSeries(series)
+			if chart.IsNumeric() {
+				b.addNumericSeries(chart, category, series)
+			} else {
+				chart.AddSeries(series)
+			}
 
 			b.l.Info("added series",
 				slog.String("category_id", category.ID),
 				slog.String("metric_id", data.Metric.ID.String()),
 				slog.String("version_id", data.Version.ID),
+				slog.String("context_id", data.Context.ID),
 			)
 		}
 	}
+}
 
-	return chart
+// addNumericSeries adds a series to a chart with a numeric X axis, positioning points at the value of their context.
+//
+// A line is drawn for each function: when the series spans several functions, lines are named after the function.
+// Lines are annotated with the complexity estimated for the function, if any.
+func (b *Builder) addNumericSeries(chart *Chart, category model.Category, series model.MetricSeries) {
+	var functions []string
+	byFunction := make(map[string][]model.MetricPoint)
+	for _, point := range series.Points {
+		if _, ok := byFunction[point.Function]; !ok {
+			functions = append(functions, point.Function)
+		}
+		byFunction[point.Function] = append(byFunction[point.Function], point)
+	}
+
+	for _, function := range functions {
+		name := series.Title
+		if len(functions) > 1 {
+			title := function
+			if def, ok := b.cfg.GetFunction(function); ok {
+				title = def.Title
+			}
+			name = title + " " + series.Title
+		}
+
+		if fit, ok := category.FitFor(function, series.Title, series.Metric); ok {
+			name += " ~ " + string(fit.Complexity)
+		}
+
+		chart.AddNumericSeries(name, byFunction[function], b.contextValue)
+	}
+}
+
+// contextValue returns the numeric value declared by the context of a point.
+func (b *Builder) contextValue(point model.MetricPoint) (float64, bool) {
+	context, ok := b.cfg.GetContext(point.Context)
+	if !ok || context.Value == nil {
+		return 0, false
+	}
+
+	return *context.Value, true
+}
+
+// renderOptions returns the chart options common to all the charts of a page, from the render config.
+func (b *Builder) renderOptions() []Option {
+	render := b.cfg.Render
+
+	opts := []Option{
+		WithLegend(render.Legend != config.LegendPositionNone),
+		WithLegendPosition(string(render.Legend)),
+		WithHorizontal(render.Orientation == config.OrientationHorizontal),
+		WithLabelFontSize(render.LabelFontSize),
+		WithBarHeight(render.BarHeight),
+		WithAnimation(render.IsAnimated(b.screenshot)),
+		WithPalette(paletteColors(render.Palette)),
+		WithPatterns(render.Patterns),
+		WithLines(render.Chart == config.ChartTypeLine),
+		WithLogScale(render.Scale == config.ScaleLog),
+	}
+
+	if render.Theme != "" {
+		opts = append(opts, WithTheme(render.Theme))
+	}
+
+	if w, h := b.chartSize(); w != "" {
+		opts = append(opts, WithSize(w, h))
+	}
+
+	return opts
+}
+
+// subtitle composes the chart subtitle from the environment and the duration of the benchmark runs.
+func subtitle(category model.Category) string {
+	if category.RunDuration <= 0 {
+		return category.Environment
+	}
+
+	runDuration := "run duration: " + category.RunDuration.Round(time.Millisecond).String()
+	if category.Environment == "" {
+		return runDuration
+	}
+
+	return category.Environment + " (" + runDuration + ")"
 }
 
 // Nominal page dimensions used to derive per-chart canvas sizes from the layout config.
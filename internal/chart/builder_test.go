@@ -0,0 +1,164 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+var discard = slog.New(slog.DiscardHandler)
+
+func TestBuildPageConcurrently(t *testing.T) {
+	cfg := &config.Config{}
+	scenario := largeScenario(40, 3, 10)
+
+	sequential := New(cfg, scenario, WithConcurrency(1), WithLogger(discard)).BuildPage()
+	require.Len(t, sequential.Charts, 80)
+
+	t.Run("should keep the order of categories and metrics", func(t *testing.T) {
+		for range 5 {
+			page := New(cfg, scenario, WithConcurrency(8), WithLogger(discard)).BuildPage()
+			require.Len(t, page.Charts, len(sequential.Charts))
+
+			for i, chart := range page.Charts {
+				assert.EqualT(t, sequential.Charts[i].Title, chart.Title)
+			}
+		}
+	})
+
+	t.Run("should render charts in order", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, sequential.Render(&buf))
+
+		previous := -1
+		for _, chart := range sequential.Charts {
+			position := bytes.Index(buf.Bytes(), []byte(`"text":"`+chart.Title+`"`))
+			require.Positive(t, position, chart.Title)
+			assert.Greater(t, position, previous)
+			previous = position
+		}
+	})
+}
+
+func TestConcurrently(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	for _, limit := range []int{0, 1, 4, 1000} {
+		squares := Concurrently(limit, items, func(i int) int { return i * i })
+		require.Len(t, squares, len(items))
+		for i, square := range squares {
+			assert.EqualT(t, i*i, square)
+		}
+	}
+
+	assert.Empty(t, Concurrently(4, []int{}, func(i int) int { return i }))
+}
+
+func BenchmarkBuildPage(b *testing.B) {
+	cfg := &config.Config{}
+	scenario := largeScenario(60, 4, 20)
+
+	for _, concurrency := range []int{1, 0} {
+		name := "sequential"
+		if concurrency != 1 {
+			name = "concurrent"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				page := New(cfg, scenario, WithConcurrency(concurrency), WithLogger(discard)).BuildPage()
+				if err := page.Render(io.Discard); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// largeScenario builds a scenario with 2 metrics per category, and several versions compared over several contexts.
+func largeScenario(categories, versions, contexts int) *model.Scenario {
+	metrics := []config.Metric{
+		{ID: config.MetricNsPerOp, Title: "Timings", Axis: "ns/op"},
+		{ID: config.MetricAllocsPerOp, Title: "Allocations", Axis: "allocs/op"},
+	}
+
+	scenario := &model.Scenario{Name: "large"}
+	for c := range categories {
+		category := model.Category{
+			ID:    fmt.Sprintf("category-%d", c),
+			Title: fmt.Sprintf("Category %d ({metric})", c),
+		}
+
+		for _, metric := range metrics {
+			for v := range versions {
+				version := fmt.Sprintf("v%d", v)
+				series := model.MetricSeries{
+					SeriesKey: model.SeriesKey{Function: category.ID, Version: version, Metric: metric.ID},
+					Title:     version,
+				}
+
+				for x := range contexts {
+					context := fmt.Sprintf("ctx%d", x)
+					series.Points = append(series.Points, model.MetricPoint{
+						SeriesKey: model.SeriesKey{Function: category.ID, Version: version, Context: context, Metric: metric.ID},
+						Name:      category.ID + " - " + context + " - " + version,
+						Label:     context,
+						Value:     float64(c*1000 + v*100 + x),
+					})
+				}
+
+				category.Data = append(category.Data, model.CategoryData{
+					Version: config.Version{Object: config.Object{ID: version, Title: version}},
+					Metric:  metric,
+					Series:  []model.MetricSeries{series},
+				})
+			}
+		}
+
+		scenario.Categories = append(scenario.Categories, category)
+	}
+
+	return scenario
+}
+
+func TestBuildPageForScreenshot(t *testing.T) {
+	scenario := largeScenario(1, 2, 3)
+
+	render := func(t *testing.T, cfg *config.Config, opts ...BuilderOption) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		page := New(cfg, scenario, append(opts, WithLogger(discard))...).BuildPage()
+		require.NoError(t, page.Render(&buf))
+
+		return buf.String()
+	}
+
+	t.Run("should animate charts by default", func(t *testing.T) {
+		assert.Contains(t, render(t, &config.Config{}), `"animation":true`)
+	})
+
+	t.Run("should not animate charts for a screenshot", func(t *testing.T) {
+		html := render(t, &config.Config{}, WithScreenshot(true))
+		assert.Contains(t, html, `"animation":false`)
+		assert.NotContains(t, html, `"animation":true`)
+	})
+
+	t.Run("should honor the configured animation", func(t *testing.T) {
+		cfg := &config.Config{Render: config.Rendering{Animation: config.AnimationOn}}
+		assert.Contains(t, render(t, cfg, WithScreenshot(true)), `"animation":true`)
+	})
+}
@@ -0,0 +1,120 @@
+package chart
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// Cache keeps the charts built for each category, so that a [Builder] only rebuilds the charts
+// of the categories that changed since the previous page (see [WithCache]).
+//
+// A category is unchanged when its organized data is the same: categories fed by input files that
+// did not change are not rebuilt. A [Cache] assumes that the configuration and the options of
+// the builders sharing it stay the same: builders with different configurations use distinct scopes
+// (see [WithCacheScope]).
+//
+// A [Cache] is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	scopes  map[string]*Cache
+}
+
+type cacheEntry struct {
+	fingerprint string
+	charts      []*Chart
+}
+
+// NewCache builds an empty chart [Cache].
+func NewCache() *Cache {
+	return &Cache{
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// WithCache reuses the charts of unchanged categories from a [Cache], and stores the charts it builds.
+//
+// By default, all charts are built again for every page.
+func WithCache(cache *Cache) BuilderOption {
+	return func(b *Builder) {
+		b.cache = cache
+	}
+}
+
+// WithCacheScope caches charts in a scope of the [Cache] set with [WithCache], e.g. the name of a project
+// on a dashboard combining several projects.
+//
+// Builders of different scopes share a cache without evicting the charts of each other.
+func WithCacheScope(scope string) BuilderOption {
+	return func(b *Builder) {
+		b.cacheScope = scope
+	}
+}
+
+// scope returns the cache of a scope, created on first use.
+func (c *Cache) scope(name string) *Cache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.scopes == nil {
+		c.scopes = make(map[string]*Cache)
+	}
+
+	scoped, ok := c.scopes[name]
+	if !ok {
+		scoped = NewCache()
+		c.scopes[name] = scoped
+	}
+
+	return scoped
+}
+
+// get returns the charts cached for a category, if the category is unchanged.
+func (c *Cache) get(category model.Category, fingerprint string) ([]*Chart, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[category.ID]
+	if !ok || entry.fingerprint != fingerprint {
+		return nil, false
+	}
+
+	return entry.charts, true
+}
+
+// put stores the charts of a category, and forgets categories which are no longer in the scenario.
+func (c *Cache) put(scenario *model.Scenario, built map[string]cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make(map[string]cacheEntry, len(scenario.Categories))
+	for _, category := range scenario.Categories {
+		if entry, ok := built[category.ID]; ok {
+			entries[category.ID] = entry
+
+			continue
+		}
+
+		if entry, ok := c.entries[category.ID]; ok {
+			entries[category.ID] = entry
+		}
+	}
+	c.entries = entries
+}
+
+// categoryFingerprint identifies the organized data of a category.
+//
+// It returns an empty string when the category can't be fingerprinted: its charts are then never cached.
+func categoryFingerprint(category model.Category) string {
+	content, err := json.Marshal(category)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,60 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache()
+	cfg := &config.Config{}
+
+	scenario := largeScenario(2, 2, 3)
+	first := New(cfg, scenario, WithLogger(discard), WithCache(cache)).BuildPage()
+	require.Len(t, first.Charts, 4)
+
+	t.Run("should reuse the charts of unchanged categories", func(t *testing.T) {
+		page := New(cfg, largeScenario(2, 2, 3), WithLogger(discard), WithCache(cache)).BuildPage()
+		require.Len(t, page.Charts, 4)
+
+		for i, chart := range page.Charts {
+			assert.True(t, chart == first.Charts[i])
+		}
+	})
+
+	t.Run("should rebuild the charts of changed categories only", func(t *testing.T) {
+		changed := largeScenario(2, 2, 3)
+		changed.Categories[1].Data[0].Series[0].Points[0].Value = 42
+
+		page := New(cfg, changed, WithLogger(discard), WithCache(cache)).BuildPage()
+		require.Len(t, page.Charts, 4)
+
+		assert.True(t, page.Charts[0] == first.Charts[0])
+		assert.True(t, page.Charts[1] == first.Charts[1])
+		assert.False(t, page.Charts[2] == first.Charts[2])
+		assert.False(t, page.Charts[3] == first.Charts[3])
+		assert.EqualT(t, 42.0, page.Charts[2].Series[0].Data[0].Value)
+	})
+
+	t.Run("should forget removed categories", func(t *testing.T) {
+		page := New(cfg, largeScenario(1, 2, 3), WithLogger(discard), WithCache(cache)).BuildPage()
+		require.Len(t, page.Charts, 2)
+		assert.Len(t, cache.entries, 1)
+	})
+
+	t.Run("should not evict the charts of other scopes", func(t *testing.T) {
+		shared := NewCache()
+		first := New(cfg, largeScenario(2, 2, 3), WithLogger(discard), WithCache(shared), WithCacheScope("first")).BuildPage()
+		New(cfg, largeScenario(1, 2, 3), WithLogger(discard), WithCache(shared), WithCacheScope("second")).BuildPage()
+
+		page := New(cfg, largeScenario(2, 2, 3), WithLogger(discard), WithCache(shared), WithCacheScope("first")).BuildPage()
+		require.Len(t, page.Charts, 4)
+		for i, chart := range page.Charts {
+			assert.True(t, chart == first.Charts[i])
+		}
+	})
+}
@@ -1,9 +1,18 @@
 package chart
 
 import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
 	"github.com/fredbi/benchviz/internal/model"
 	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
 	echartsopts "github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/render"
+	"github.com/go-echarts/go-echarts/v2/types"
 )
 
 const (
@@ -12,17 +21,42 @@ const (
 	axisNameGap     = 32
 )
 
+// Sizing of horizontal bar charts, grown with the number of bars.
+const (
+	defaultChartHeight = 500 // go-echarts default canvas height, in px
+	defaultBarHeight   = 20  // minimum height of a bar, in px
+	gridMargins        = 200 // top and bottom margins of the grid, in px
+)
+
+// Types of X axis.
+const (
+	xAxisCategory = "category"
+	xAxisValue    = "value"
+	xAxisLog      = "log"
+)
+
 // Series represents a named data series in a chart.
+//
+// Bar charts hold Data, charts with a numeric X axis hold Points, as [x, y] pairs.
+// Origins trace each data point back to its source, in the same order.
+//
+// On a chart with a second Y axis, YAxisIndex is 1 for the series plotted against it.
 type Series struct {
-	Name string
-	Data []echartsopts.BarData
+	Name       string
+	Data       []echartsopts.BarData
+	Points     []echartsopts.LineData
+	Origins    []model.Origin
+	YAxisIndex int
 }
 
-// Chart represents a benchmark bar chart.
+// Chart represents a benchmark chart: a bar chart, or a line chart when the X axis is numeric.
+//
+// A bar chart may hold several Views (e.g. one per metric), selected with buttons (see [Chart.AddView]).
 type Chart struct {
 	options
 
 	Series []Series
+	Views  []View
 }
 
 // NewChart creates a new chart with the given title and y-axis label.
@@ -35,7 +69,9 @@ func NewChart(opts ...Option) *Chart {
 // AddSeries adds a named data series to the chart.
 func (c *Chart) AddSeries(series model.MetricSeries) {
 	data := make([]echartsopts.BarData, 0, len(series.Points))
+	origins := make([]model.Origin, 0, len(series.Points))
 	for _, point := range series.Points {
+		origins = append(origins, point.Origin)
 		data = append(data, echartsopts.BarData{
 			Name:  point.Label,
 			Value: point.Value,
@@ -47,13 +83,221 @@ func (c *Chart) AddSeries(series model.MetricSeries) {
 			*/
 		})
 	}
-	c.Series = append(c.Series, Series{Name: series.Title, Data: data})
+	c.Series = append(c.Series, Series{Name: series.Title, Data: data, Origins: origins})
+}
+
+// AddNumericSeries adds a named series of points, positioned on a numeric X axis.
+//
+// Points are sorted by their position. Points without a position are skipped.
+func (c *Chart) AddNumericSeries(name string, points []model.MetricPoint, position func(model.MetricPoint) (float64, bool)) {
+	type xy struct {
+		label  string
+		x, y   float64
+		origin model.Origin
+	}
+
+	positioned := make([]xy, 0, len(points))
+	for _, point := range points {
+		x, ok := position(point)
+		if !ok {
+			continue
+		}
+		positioned = append(positioned, xy{label: point.Label, x: x, y: point.Value, origin: point.Origin})
+	}
+	slices.SortStableFunc(positioned, func(a, b xy) int {
+		return cmp.Compare(a.x, b.x)
+	})
+
+	data := make([]echartsopts.LineData, 0, len(positioned))
+	origins := make([]model.Origin, 0, len(positioned))
+	for _, point := range positioned {
+		data = append(data, echartsopts.LineData{
+			Name:  point.label,
+			Value: []float64{point.x, point.y},
+		})
+		origins = append(origins, point.origin)
+	}
+	c.Series = append(c.Series, Series{Name: name, Points: data, Origins: origins})
+}
+
+// IsNumeric reports whether the chart has a numeric X axis, and renders as a line chart.
+func (c *Chart) IsNumeric() bool {
+	return c.XAxisType == xAxisValue || c.XAxisType == xAxisLog
+}
+
+// builtChart is an ECharts chart built from a [Chart].
+type builtChart interface {
+	components.Charter
+
+	RenderSnippet() render.ChartSnippet
+}
+
+// build creates the ECharts chart: a line chart for a numeric X axis, a bar chart otherwise.
+func (c *Chart) build() builtChart {
+	if c.IsNumeric() {
+		return c.BuildLine()
+	}
+
+	return c.Build()
+}
+
+// chartID returns the DOM identifier of a built chart.
+func chartID(chart builtChart) string {
+	switch built := chart.(type) {
+	case *charts.Bar:
+		return built.ChartID
+	case *charts.Line:
+		return built.ChartID
+	default:
+		return ""
+	}
 }
 
 // Build creates the ECharts bar chart from the accumulated configuration.
 func (c *Chart) Build() *charts.Bar {
 	bar := charts.NewBar()
+	xAxisOpts, yAxisOpts := c.setAxes()
+
+	// Apply global options
+	bar.SetGlobalOptions(c.globalOptions(xAxisOpts, yAxisOpts, echartsopts.Tooltip{
+		Show:    echartsopts.Bool(true),
+		Trigger: "axis",
+		AxisPointer: &echartsopts.AxisPointer{
+			Type: "shadow",
+		},
+	})...)
+
+	// Set categories
+	bar.SetXAxis(c.XAxisLabels)
+
+	if c.SecondYAxisLabel != "" {
+		bar.ExtendYAxis(c.secondYAxis())
+	}
+
+	// Add all series
+	for i, s := range c.Series {
+		seriesOpts := c.seriesOptions(i)
+		if s.YAxisIndex > 0 {
+			seriesOpts = append(seriesOpts, charts.WithBarChartOpts(echartsopts.BarChart{YAxisIndex: s.YAxisIndex}))
+		}
+
+		bar.AddSeries(s.Name, s.Data, seriesOpts...)
+		bar.MultiSeries[len(bar.MultiSeries)-1].Type = c.seriesType()
+	}
+	bar.AddJSFuncs(c.graphicAnnotations()...)
+	bar.AddJSFuncs(c.originTooltips()...)
+	bar.AddJSFuncs(c.viewSelector()...)
+
+	if c.Horizontal {
+		return bar.XYReversal()
+	}
+
+	return bar
+}
+
+// seriesType returns the ECharts type of the series of a bar chart: bars, unless drawn as lines (see [WithLines]).
+func (c *Chart) seriesType() string {
+	if c.Lines {
+		return types.ChartLine
+	}
+
+	return types.ChartBar
+}
+
+// valueAxisType returns the type of the value axis: linear, unless logarithmic (see [WithLogScale]).
+func (c *Chart) valueAxisType() string {
+	if c.LogScale {
+		return xAxisLog
+	}
+
+	return xAxisValue
+}
+
+// autoHeight returns the height of a horizontal bar chart, grown so that each bar gets at least
+// the configured bar height. The configured height is returned when it is enough.
+//
+// Vertical bar charts and line charts keep the configured height.
+func (c *Chart) autoHeight() string {
+	if !c.Horizontal || c.IsNumeric() {
+		return c.Height
+	}
+
+	barHeight := c.BarHeight
+	if barHeight == 0 {
+		barHeight = defaultBarHeight
+	}
+
+	height := defaultChartHeight
+	if c.Height != "" {
+		configured, err := strconv.Atoi(strings.TrimSuffix(c.Height, "px"))
+		if err != nil {
+			return c.Height // e.g. a percentage
+		}
+		height = configured
+	}
+
+	bars := len(c.XAxisLabels) * max(1, len(c.Series))
+	if needed := bars*barHeight + gridMargins; needed > height {
+		return strconv.Itoa(needed) + "px"
+	}
+
+	return c.Height
+}
+
+// seriesOptions returns the ECharts options of the i-th series of a bar chart.
+//
+// Mark lines (the zero line and positioned annotations) and mark areas (function groups) are drawn once, with the first series.
+func (c *Chart) seriesOptions(i int) []charts.SeriesOpts {
+	seriesOpts := c.paletteOptions(i)
+	if i > 0 {
+		return seriesOpts
+	}
+
+	seriesOpts = append(seriesOpts, c.markLineAnnotations()...)
+	seriesOpts = append(seriesOpts, c.markAreaGroups()...)
+	if !c.ZeroLine {
+		return seriesOpts
+	}
+
+	// the value axis is the X axis once the chart is reversed
+	if c.Horizontal {
+		return append(seriesOpts, charts.WithMarkLineNameXAxisItemOpts(echartsopts.MarkLineNameXAxisItem{Name: "0", XAxis: 0}))
+	}
+
+	return append(seriesOpts, charts.WithMarkLineNameYAxisItemOpts(echartsopts.MarkLineNameYAxisItem{Name: "0", YAxis: 0}))
+}
+
+// BuildLine creates the ECharts line chart from the accumulated configuration, for a numeric X axis.
+//
+// Line charts are always rendered vertically.
+func (c *Chart) BuildLine() *charts.Line {
+	line := charts.NewLine()
+	xAxisOpts, yAxisOpts := c.setNumericAxes()
+
+	line.SetGlobalOptions(c.globalOptions(xAxisOpts, yAxisOpts, echartsopts.Tooltip{
+		Show:    echartsopts.Bool(true),
+		Trigger: "item",
+	})...)
+
+	for i, s := range c.Series {
+		seriesOpts := []charts.SeriesOpts{charts.WithLineChartOpts(echartsopts.LineChart{
+			ShowSymbol: echartsopts.Bool(true),
+		})}
+		seriesOpts = append(seriesOpts, c.paletteOptions(i)...)
+		if i == 0 {
+			seriesOpts = append(seriesOpts, c.markLineAnnotations()...)
+		}
 
+		line.AddSeries(s.Name, s.Points, seriesOpts...)
+	}
+	line.AddJSFuncs(c.graphicAnnotations()...)
+	line.AddJSFuncs(c.originTooltips()...)
+
+	return line
+}
+
+// globalOptions returns the ECharts options shared by bar and line charts.
+func (c *Chart) globalOptions(xAxisOpts echartsopts.XAxis, yAxisOpts echartsopts.YAxis, tooltipOpts echartsopts.Tooltip) []charts.GlobalOpts {
 	// Title options
 	titleOpts := echartsopts.Title{
 		Title: c.Title,
@@ -74,8 +318,6 @@ func (c *Chart) Build() *charts.Bar {
 		legendOpts.X, legendOpts.Y = legendXY(c.LegendPosition)
 	}
 
-	xAxisOpts, yAxisOpts := c.setAxes()
-
 	// Grid options
 	gridOpts := echartsopts.Grid{
 		Bottom: "100",
@@ -92,12 +334,11 @@ func (c *Chart) Build() *charts.Bar {
 		},
 	}
 
-	// Apply global options
-	bar.SetGlobalOptions(
+	return []charts.GlobalOpts{
 		charts.WithInitializationOpts(echartsopts.Initialization{
 			Theme:  c.Theme,
 			Width:  c.Width,
-			Height: c.Height,
+			Height: c.autoHeight(),
 		}),
 		charts.WithToolboxOpts(toolboxOpts),
 		charts.WithTitleOpts(titleOpts),
@@ -105,28 +346,35 @@ func (c *Chart) Build() *charts.Bar {
 		charts.WithGridOpts(gridOpts),
 		charts.WithXAxisOpts(xAxisOpts),
 		charts.WithYAxisOpts(yAxisOpts),
-		charts.WithTooltipOpts(echartsopts.Tooltip{
-			Show:    echartsopts.Bool(true),
-			Trigger: "axis",
-			AxisPointer: &echartsopts.AxisPointer{
-				Type: "shadow",
+		charts.WithTooltipOpts(tooltipOpts),
+		charts.WithAnimation(c.Animation),
+		// describe the chart to screen readers
+		charts.WithAriaOpts(&echartsopts.Aria{
+			Enabled: echartsopts.Bool(true),
+			Label: &echartsopts.AriaLabel{
+				Enabled: echartsopts.Bool(true),
+			},
+			Decal: &echartsopts.AriaDecal{
+				Show: echartsopts.Bool(c.Patterns),
 			},
 		}),
-	)
-
-	// Set categories
-	bar.SetXAxis(c.XAxisLabels)
-
-	// Add all series
-	for _, s := range c.Series {
-		bar.AddSeries(s.Name, s.Data)
 	}
+}
 
-	if c.Horizontal {
-		return bar.XYReversal()
+// paletteOptions returns the options coloring the i-th series after the palette of the chart, if any.
+//
+// go-echarts only renders a color palette for the "white" theme: series are colored one by one instead.
+func (c *Chart) paletteOptions(i int) []charts.SeriesOpts {
+	if len(c.Palette) == 0 {
+		return nil
 	}
 
-	return bar
+	color := c.Palette[i%len(c.Palette)]
+
+	return []charts.SeriesOpts{
+		charts.WithItemStyleOpts(echartsopts.ItemStyle{Color: color}),
+		charts.WithLineStyleOpts(echartsopts.LineStyle{Color: color}),
+	}
 }
 
 // legendXY maps a legend position string to echarts X and Y alignment values.
@@ -160,17 +408,69 @@ func (c *Chart) workloadAxisLabel() *echartsopts.AxisLabel {
 		label.FontSize = c.LabelFontSize
 	}
 
+	if c.LabelWidth > 0 || len(c.Abbreviations) > 0 {
+		label.Formatter = c.labelFormatter()
+	}
+
 	return label
 }
 
+// labelFormatter returns the formatter of the workload axis tick labels, abbreviating labels and then
+// shortening labels longer than the label width.
+//
+// Labels are shortened by the formatter, so that tooltips still display full labels.
+func (c *Chart) labelFormatter() types.FuncStr {
+	abbreviate := ""
+	if len(c.Abbreviations) > 0 {
+		abbreviations, _ := json.Marshal(c.Abbreviations) // a map of strings is always marshaled
+		abbreviate = `value = ` + string(abbreviations) + `[value] || value;`
+	}
+
+	if c.LabelWidth <= 0 {
+		return echartsopts.FuncOpts(`function (value) {` + abbreviate + `return value;}`)
+	}
+
+	width := strconv.Itoa(c.LabelWidth)
+
+	if c.LabelWrap {
+		return echartsopts.FuncOpts(`function (value) {` + abbreviate +
+			`return value.split("\n").map(function (line) {` +
+			`var lines = [], current = "";` +
+			`line.split(" ").forEach(function (word) {` +
+			`if (current && (current + " " + word).length > ` + width + `) { lines.push(current); current = word; }` +
+			` else { current = current ? current + " " + word : word; }` +
+			`});` +
+			`lines.push(current);` +
+			`return lines.join("\n");` +
+			`}).join("\n");` +
+			`}`)
+	}
+
+	return echartsopts.FuncOpts(`function (value) {` + abbreviate +
+		`return value.split("\n").map(function (line) {` +
+		`return line.length > ` + width + ` ? line.slice(0, Math.max(` + width + ` - 1, 1)) + "\u2026" : line;` +
+		`}).join("\n");` +
+		`}`)
+}
+
+// valueAxisFormatter returns the formatter of the value axis tick labels.
+//
+// Integer values are rendered as is, other values with 3 significant digits, so that small values
+// (e.g. on a log or a percent axis) are not all rendered as 0.
+func valueAxisFormatter() types.FuncStr {
+	return echartsopts.FuncOpts(`function (value, index) {` +
+		`return Number.isInteger(value) ? value.toString() : Number(value.toPrecision(3)).toString();` +
+		`}`)
+}
+
 func (c *Chart) setAxes() (echartsopts.XAxis, echartsopts.YAxis) {
 	const (
 		workload     = "Workload"
-		xType        = "category"
-		yType        = "value"
+		xType        = xAxisCategory
 		axisPosition = "bottom"
 	)
-	valueFormatter := echartsopts.FuncOpts("function (value,index) { return value.toFixed(0).toString();}")
+	yType := c.valueAxisType()
+	valueFormatter := valueAxisFormatter()
 
 	if !c.Horizontal {
 		// X-axis options
@@ -223,3 +523,27 @@ func (c *Chart) setAxes() (echartsopts.XAxis, echartsopts.YAxis) {
 
 	return xAxisOpts, yAxisOpts
 }
+
+// setNumericAxes builds the axes of a line chart, with contexts positioned at their numeric value on the X axis.
+func (c *Chart) setNumericAxes() (echartsopts.XAxis, echartsopts.YAxis) {
+	const workload = "Workload"
+	valueFormatter := valueAxisFormatter()
+
+	xAxisOpts := echartsopts.XAxis{
+		Name:         workload,
+		Type:         c.XAxisType,
+		NameLocation: "end",
+		Scale:        echartsopts.Bool(true),
+	}
+
+	yAxisOpts := echartsopts.YAxis{
+		Name:  c.YAxisLabel,
+		Type:  c.valueAxisType(),
+		Scale: echartsopts.Bool(true),
+		AxisLabel: &echartsopts.AxisLabel{
+			Formatter: valueFormatter,
+		},
+	}
+
+	return xAxisOpts, yAxisOpts
+}
@@ -6,8 +6,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/fredbi/benchviz/internal/complexity"
 	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
 	"github.com/fredbi/benchviz/internal/organizer"
 	"github.com/fredbi/benchviz/internal/parser"
 
@@ -58,6 +61,42 @@ func TestSmokeRenderFromTestdata(t *testing.T) {
 	t.Log(buf.String())
 }
 
+func TestRenderChartTypeAndScale(t *testing.T) {
+	render := func(t *testing.T, chartType config.ChartType, scale config.Scale) string {
+		t.Helper()
+
+		cfg := mustLoadConfig(t, smokeConfig())
+		cfg.Render.Chart = chartType
+		cfg.Render.Scale = scale
+
+		p := parser.New(cfg, parser.WithParseJSON(true))
+		require.NoError(t, p.ParseFiles(parserTestdataPath("sample_generics.json")))
+		scenario, err := organizer.New(cfg).Scenarize(p.Sets())
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, New(cfg, scenario, WithLogger(discard)).BuildPage().Render(&buf))
+
+		return buf.String()
+	}
+
+	t.Run("should draw bars on a linear axis by default", func(t *testing.T) {
+		html := render(t, config.ChartTypeBar, config.ScaleAuto)
+
+		assert.Contains(t, html, `"type":"bar"`)
+		assert.NotContains(t, html, `"type":"line"`)
+		assert.NotContains(t, html, `"type":"log"`)
+	})
+
+	t.Run("should draw lines on a log axis", func(t *testing.T) {
+		html := render(t, config.ChartTypeLine, config.ScaleLog)
+
+		assert.Contains(t, html, `"type":"line"`)
+		assert.NotContains(t, html, `"type":"bar"`)
+		assert.Contains(t, html, `"type":"log"`)
+	})
+}
+
 // TestSmokeRenderTextFormat tests with plain text benchmark output.
 func TestSmokeRenderTextFormat(t *testing.T) {
 	cfg := mustLoadConfig(t, smokeConfigText())
@@ -88,6 +127,108 @@ func TestWithTitleAndSubtitle(t *testing.T) {
 	assert.Equal(t, "My Subtitle", c.Subtitle)
 }
 
+func TestSubtitle(t *testing.T) {
+	assert.Equal(t, "linux amd64", subtitle(model.Category{Environment: "linux amd64"}))
+	assert.Equal(t, "linux amd64 (run duration: 1m2.5s)", subtitle(model.Category{
+		Environment: "linux amd64",
+		RunDuration: time.Minute + 2500*time.Millisecond,
+	}))
+	assert.Equal(t, "run duration: 2s", subtitle(model.Category{RunDuration: 2 * time.Second}))
+}
+
+func TestBuildNumericXAxis(t *testing.T) {
+	cfg := mustLoadConfig(t, `
+metrics:
+  - id: nsPerOp
+    axis: ns/op
+functions:
+  - id: encode
+  - id: decode
+contexts:
+  - id: large
+    value: 1000
+  - id: small
+    value: 10
+  - id: unknown
+versions:
+  - id: v1
+categories:
+  - id: sizes
+    xAxis: log
+    includes:
+      contexts: [large, small]
+      metrics: [nsPerOp]
+`)
+	metric, ok := cfg.GetMetric(config.MetricNsPerOp)
+	require.True(t, ok)
+
+	series := model.MetricSeries{
+		SeriesKey: model.SeriesKey{Version: "v1", Metric: metric.ID},
+		Title:     "V1",
+	}
+	for _, function := range []string{"encode", "decode"} {
+		for _, context := range []string{"large", "small", "unknown"} {
+			series.Points = append(series.Points, model.MetricPoint{
+				SeriesKey: model.SeriesKey{Function: function, Version: "v1", Context: context, Metric: metric.ID},
+				Label:     context,
+				Value:     float64(len(context)),
+			})
+		}
+	}
+
+	scenario := &model.Scenario{Categories: []model.Category{{
+		ID:    "sizes",
+		Title: "Sizes",
+		XAxis: config.XAxisLog,
+		Data:  []model.CategoryData{{Metric: metric, Series: []model.MetricSeries{series}}},
+	}}}
+
+	page := New(cfg, scenario, WithLogger(discard)).BuildPage()
+	require.Len(t, page.Charts, 1)
+
+	chart := page.Charts[0]
+	require.True(t, chart.IsNumeric())
+
+	t.Run("should draw a line per function, sorted by context value", func(t *testing.T) {
+		require.Len(t, chart.Series, 2)
+		assert.EqualT(t, "Encode V1", chart.Series[0].Name)
+		assert.EqualT(t, "Decode V1", chart.Series[1].Name)
+
+		// the context without a value is skipped
+		points := chart.Series[0].Points
+		require.Len(t, points, 2)
+		assert.Equal(t, []float64{10, 5}, points[0].Value)
+		assert.Equal(t, []float64{1000, 5}, points[1].Value)
+		assert.EqualT(t, "small", points[0].Name)
+	})
+
+	t.Run("should annotate lines with the estimated complexity", func(t *testing.T) {
+		withFit := *scenario
+		withFit.Categories = []model.Category{scenario.Categories[0]}
+		withFit.Categories[0].Fits = []model.Fit{
+			{Result: complexity.Result{Complexity: complexity.ON}, Function: "decode", Series: "V1", Metric: metric.ID},
+		}
+
+		annotated := New(cfg, &withFit, WithLogger(discard)).BuildPage()
+		require.Len(t, annotated.Charts, 1)
+		require.Len(t, annotated.Charts[0].Series, 2)
+		assert.EqualT(t, "Encode V1", annotated.Charts[0].Series[0].Name)
+		assert.EqualT(t, "Decode V1 ~ O(n)", annotated.Charts[0].Series[1].Name)
+	})
+
+	t.Run("should render a line chart with a log X axis", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+
+		html := buf.String()
+		assert.Contains(t, html, `"type":"line"`)
+		assert.Contains(t, html, `"type":"log"`)
+		assert.NotContains(t, html, `"type":"bar"`)
+		assert.Contains(t, html, `value.toPrecision(3)`)
+		assert.NotContains(t, html, `value.toFixed(0)`)
+	})
+}
+
 func TestRenderEmptyPage(t *testing.T) {
 	page := NewPage("Empty")
 
@@ -220,3 +361,89 @@ categories:
       metrics: [nsPerOp, allocsPerOp]
 `
 }
+
+func TestLabelWidth(t *testing.T) {
+	render := func(t *testing.T, chart *Chart) string {
+		t.Helper()
+
+		page := NewPage("Labels")
+		page.AddChart(chart)
+
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+
+		return buf.String()
+	}
+
+	t.Run("should leave labels at their full length by default", func(t *testing.T) {
+		chart := NewChart(WithXAxisLabels([]string{"a rather long label"}))
+
+		assert.Empty(t, chart.workloadAxisLabel().Formatter)
+	})
+
+	t.Run("should truncate long labels", func(t *testing.T) {
+		chart := NewChart(WithXAxisLabels([]string{"a rather long label"}), WithLabelWidth(12, false))
+
+		formatter := string(chart.workloadAxisLabel().Formatter)
+		assert.Contains(t, formatter, `line.length > 12 ? line.slice(0, Math.max(12 - 1, 1)) + "\u2026"`)
+		assert.Contains(t, render(t, chart), `"formatter":function (value) {`)
+	})
+
+	t.Run("should wrap long labels", func(t *testing.T) {
+		chart := NewChart(WithXAxisLabels([]string{"a rather long label"}), WithLabelWidth(12, true), WithHorizontal(true))
+
+		formatter := string(chart.workloadAxisLabel().Formatter)
+		assert.Contains(t, formatter, `(current + " " + word).length > 12`)
+		assert.Contains(t, render(t, chart), `"formatter":function (value) {`)
+	})
+
+	t.Run("should abbreviate labels on the axis only", func(t *testing.T) {
+		chart := NewChart(WithXAxisLabels([]string{"Greater Int"}), WithLabelAbbreviations(map[string]string{"Greater Int": "Gt Int"}))
+
+		formatter := string(chart.workloadAxisLabel().Formatter)
+		assert.Contains(t, formatter, `value = {"Greater Int":"Gt Int"}[value] || value;`)
+		assert.Contains(t, render(t, chart), `"data":["Greater Int"]`)
+	})
+}
+
+func TestAutoHeight(t *testing.T) {
+	labels := func(n int) []string {
+		all := make([]string, n)
+		for i := range all {
+			all[i] = "label"
+		}
+
+		return all
+	}
+
+	newChart := func(opts ...Option) *Chart {
+		chart := NewChart(opts...)
+		chart.Series = []Series{{Name: "v1"}, {Name: "v2"}}
+
+		return chart
+	}
+
+	t.Run("should keep the configured height of vertical charts", func(t *testing.T) {
+		assert.Empty(t, newChart(WithXAxisLabels(labels(50))).autoHeight())
+	})
+
+	t.Run("should keep the configured height of horizontal charts with a few bars", func(t *testing.T) {
+		assert.Empty(t, newChart(WithHorizontal(true), WithXAxisLabels(labels(5))).autoHeight())
+		assert.EqualT(t, "400px", newChart(WithHorizontal(true), WithXAxisLabels(labels(5)), WithSize("900px", "400px")).autoHeight())
+	})
+
+	t.Run("should grow horizontal charts with many bars", func(t *testing.T) {
+		// 50 labels x 2 series x 20px + 200px of margins
+		assert.EqualT(t, "2200px", newChart(WithHorizontal(true), WithXAxisLabels(labels(50))).autoHeight())
+		assert.EqualT(t, "1200px", newChart(WithHorizontal(true), WithXAxisLabels(labels(50)), WithBarHeight(10)).autoHeight())
+	})
+
+	t.Run("should render the grown height", func(t *testing.T) {
+		page := NewPage("Tall")
+		page.AddChart(newChart(WithHorizontal(true), WithXAxisLabels(labels(50))))
+
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+		assert.Contains(t, buf.String(), "height:2200px")
+	})
+}
@@ -0,0 +1,45 @@
+package chart
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Concurrently applies fn to all items, with at most limit goroutines running at the same time.
+//
+// Results are returned in the order of items, regardless of the order of completion.
+// A limit lower than 1 defaults to [runtime.GOMAXPROCS].
+func Concurrently[T, R any](limit int, items []T, fn func(T) R) []R {
+	results := make([]R, len(items))
+	if limit < 1 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	limit = min(limit, len(items))
+
+	if limit <= 1 {
+		for i, item := range items {
+			results[i] = fn(item)
+		}
+
+		return results
+	}
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+
+	for range limit {
+		wg.Go(func() {
+			for i := range next {
+				results[i] = fn(items[i])
+			}
+		})
+	}
+
+	for i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,125 @@
+package chart
+
+import (
+	"log/slog"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	echartsopts "github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// Colors of the bars of a difference chart.
+const (
+	colorBetter = "#3ba272"
+	colorWorse  = "#ee6666"
+)
+
+// buildDifferenceChart creates a chart of the relative change between two versions, for one metric and one category.
+//
+// Each benchmark measured for both versions is a signed percentage bar: (target - base) / base.
+// Bars are colored after whether the change is an improvement, given the metric (see [changeColors]).
+//
+// Changes may be filtered by the config (see [config.Changes]): on a difference chart, any change for the worse
+// is a regression, and any change for the better an improvement.
+func (b *Builder) buildDifferenceChart(category model.Category, metric config.Metric, opts []Option) *Chart {
+	diff := category.Difference
+
+	var keys []model.SeriesKey
+	labels := make(map[model.SeriesKey]string)
+	base := make(map[model.SeriesKey]float64)
+	target := make(map[model.SeriesKey]float64)
+
+	for _, data := range category.Data {
+		for _, series := range data.Series {
+			if series.Metric != metric.ID {
+				continue
+			}
+
+			for _, point := range series.Points {
+				key := category.XKey(point)
+
+				switch point.Version {
+				case diff.Base:
+					if _, seen := base[key]; !seen {
+						keys = append(keys, key)
+						labels[key] = point.Label
+						base[key] = point.Value
+					}
+				case diff.Target:
+					if _, seen := target[key]; !seen {
+						target[key] = point.Value
+					}
+				}
+			}
+		}
+	}
+
+	var (
+		xLabels  []string
+		bars     []echartsopts.BarData
+		filtered int
+	)
+	better, worse := changeColors(b.cfg.Render.Palette)
+
+	for _, key := range keys {
+		from := base[key]
+		to, ok := target[key]
+		if !ok || from == 0 {
+			continue
+		}
+
+		change := (to - from) / from * 100 //nolint:mnd // percent
+		improvement := change != 0 && (change > 0) == metric.ID.HigherIsBetter()
+		if !b.cfg.Changes.Retains(change/100, change != 0 && !improvement, improvement) { //nolint:mnd // percent
+			filtered++
+
+			continue
+		}
+
+		color := worse
+		if (change > 0) == metric.ID.HigherIsBetter() {
+			color = better
+		}
+
+		xLabels = append(xLabels, labels[key])
+		bars = append(bars, echartsopts.BarData{
+			Name:      labels[key],
+			Value:     change,
+			ItemStyle: &echartsopts.ItemStyle{Color: color},
+		})
+	}
+
+	if len(bars) == 0 && filtered > 0 {
+		b.l.Info("no change to show for a difference",
+			slog.String("category_id", category.ID),
+			slog.Int("filtered", filtered),
+		)
+
+		return nil
+	}
+
+	if len(bars) == 0 {
+		b.l.Warn("no benchmark measured for both versions of a difference",
+			slog.String("category_id", category.ID),
+			slog.String("base", diff.Base),
+			slog.String("target", diff.Target),
+		)
+
+		return nil
+	}
+
+	name := b.versionTitle(diff.Target) + " vs " + b.versionTitle(diff.Base)
+	opts = append(opts,
+		WithXAxisLabels(xLabels),
+		WithYAxisLabel(metric.Title+" (% change)"),
+		WithZeroLine(true),
+		// changes are signed: they are drawn as bars on a linear axis
+		WithLines(false),
+		WithLogScale(false),
+	)
+
+	chart := NewChart(opts...)
+	chart.Series = append(chart.Series, Series{Name: name, Data: bars})
+
+	return chart
+}
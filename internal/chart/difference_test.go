@@ -0,0 +1,99 @@
+package chart
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+func TestBuildDifferenceChart(t *testing.T) {
+	cfg := mustLoadConfig(t, `
+metrics:
+  - id: nsPerOp
+    title: Timings
+versions:
+  - id: reflect
+  - id: generics
+categories:
+  - id: refactoring
+    difference:
+      base: reflect
+      target: generics
+    includes:
+      metrics: [nsPerOp]
+`)
+	metric, ok := cfg.GetMetric(config.MetricNsPerOp)
+	require.True(t, ok)
+
+	series := func(version string, values map[string]float64, functions ...string) model.CategoryData {
+		s := model.MetricSeries{SeriesKey: model.SeriesKey{Version: version, Metric: metric.ID}, Title: version}
+		for _, function := range functions {
+			s.Points = append(s.Points, model.MetricPoint{
+				SeriesKey: model.SeriesKey{Function: function, Version: version, Metric: metric.ID},
+				Label:     function,
+				Value:     values[function],
+			})
+		}
+
+		return model.CategoryData{Metric: metric, Series: []model.MetricSeries{s}}
+	}
+
+	category := model.Category{
+		ID:         "refactoring",
+		Title:      "Refactoring",
+		Difference: cfg.Categories[0].Difference,
+		Data: []model.CategoryData{
+			series("reflect", map[string]float64{"encode": 100, "decode": 200, "only": 10}, "encode", "decode", "only"),
+			series("generics", map[string]float64{"encode": 50, "decode": 300}, "encode", "decode"),
+		},
+	}
+
+	page := New(cfg, &model.Scenario{Categories: []model.Category{category}}, WithLogger(discard)).BuildPage()
+	require.Len(t, page.Charts, 1)
+
+	chart := page.Charts[0]
+	assert.Equal(t, []string{"encode", "decode"}, chart.XAxisLabels)
+	assert.EqualT(t, "Timings (% change)", chart.YAxisLabel)
+	assert.True(t, chart.ZeroLine)
+
+	require.Len(t, chart.Series, 1)
+	assert.EqualT(t, "Generics vs Reflect", chart.Series[0].Name)
+
+	bars := chart.Series[0].Data
+	require.Len(t, bars, 2)
+	assert.InDelta(t, -50, bars[0].Value, 1e-9)
+	assert.EqualT(t, colorBetter, bars[0].ItemStyle.Color)
+	assert.InDelta(t, 50, bars[1].Value, 1e-9)
+	assert.EqualT(t, colorWorse, bars[1].ItemStyle.Color)
+
+	t.Run("should render a zero line", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+		assert.Contains(t, buf.String(), `"markLine"`)
+	})
+
+	t.Run("should skip a chart without common benchmarks", func(t *testing.T) {
+		alone := category
+		alone.Data = alone.Data[:1]
+
+		page := New(cfg, &model.Scenario{Categories: []model.Category{alone}}, WithLogger(discard)).BuildPage()
+		assert.Empty(t, page.Charts)
+	})
+	t.Run("should only show the changes retained by config", func(t *testing.T) {
+		filtered := *cfg
+		filtered.Changes = config.Changes{Only: config.ChangeRegressions}
+
+		page := New(&filtered, &model.Scenario{Categories: []model.Category{category}}, WithLogger(discard)).BuildPage()
+		require.Len(t, page.Charts, 1)
+		assert.Equal(t, []string{"decode"}, page.Charts[0].XAxisLabels)
+
+		filtered.Changes = config.Changes{MinChange: 60}
+		page = New(&filtered, &model.Scenario{Categories: []model.Category{category}}, WithLogger(discard)).BuildPage()
+		assert.Empty(t, page.Charts)
+	})
+}
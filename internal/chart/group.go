@@ -0,0 +1,77 @@
+package chart
+
+import (
+	"github.com/go-echarts/go-echarts/v2/charts"
+	echartsopts "github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// Opacity of the shaded areas behind function groups: groups alternate between two shades.
+const (
+	groupOpacity    float32 = 0.08
+	groupOpacityAlt float32 = 0.03
+)
+
+// Group is a run of contiguous positions on the category axis, highlighted under a title
+// (e.g. the functions of an API area).
+//
+// From and To are the indices of the first and last positions of the group.
+type Group struct {
+	Title    string
+	From, To int
+}
+
+// groupArea is an edge of a mark area on the category axis.
+//
+// go-echarts serializes the Y axis of a mark area with a wrong key: edges are serialized here.
+type groupArea struct {
+	Name      string                 `json:"name,omitempty"`
+	XAxis     any                    `json:"xAxis,omitempty"`
+	YAxis     any                    `json:"yAxis,omitempty"`
+	ItemStyle *echartsopts.ItemStyle `json:"itemStyle,omitempty"`
+}
+
+// markAreaGroups returns the series options drawing function groups as shaded mark areas, labeled with their title.
+//
+// Groups are only drawn on a category axis.
+func (c *Chart) markAreaGroups() []charts.SeriesOpts {
+	if len(c.Groups) == 0 || c.IsNumeric() {
+		return nil
+	}
+
+	data := make([]any, 0, len(c.Groups))
+	for i, group := range c.Groups {
+		opacity := groupOpacity
+		if i%2 == 1 {
+			opacity = groupOpacityAlt
+		}
+
+		from := groupArea{Name: group.Title, ItemStyle: &echartsopts.ItemStyle{Color: "#888", Opacity: echartsopts.Float(opacity)}}
+		to := groupArea{}
+
+		// the category axis is the Y axis once a bar chart is reversed
+		if c.Horizontal {
+			from.YAxis, to.YAxis = group.From, group.To
+		} else {
+			from.XAxis, to.XAxis = group.From, group.To
+		}
+
+		data = append(data, []groupArea{from, to})
+	}
+
+	position := "insideTop"
+	if c.Horizontal {
+		position = "insideLeft"
+	}
+
+	return []charts.SeriesOpts{func(s *charts.SingleSeries) {
+		if s.MarkAreas == nil {
+			s.MarkAreas = &echartsopts.MarkAreas{}
+		}
+		s.MarkAreas.Data = append(s.MarkAreas.Data, data...)
+		s.MarkAreas.Label = &echartsopts.Label{
+			Show:     echartsopts.Bool(true),
+			Position: position,
+			Color:    "#666",
+		}
+	}}
+}
@@ -0,0 +1,53 @@
+package chart
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestGroups(t *testing.T) {
+	newChart := func(opts ...Option) *Chart {
+		chart := NewChart(append([]Option{
+			WithTitle("Grouped"),
+			WithXAxisLabels([]string{"Greater", "Less", "Contains", "Len"}),
+			WithGroups(Group{Title: "Comparisons", From: 0, To: 1}, Group{Title: "Collections", From: 2, To: 3}),
+		}, opts...)...)
+		chart.Series = []Series{{Name: "v1"}, {Name: "v2"}}
+
+		return chart
+	}
+
+	render := func(t *testing.T, chart *Chart) string {
+		t.Helper()
+
+		page := NewPage("Groups")
+		page.AddChart(chart)
+
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+
+		return buf.String()
+	}
+
+	t.Run("should draw groups as mark areas, once", func(t *testing.T) {
+		html := render(t, newChart())
+
+		assert.Contains(t, html, `[{"name":"Comparisons","xAxis":0,"itemStyle":{"color":"#888","opacity":0.08}},{"xAxis":1}]`)
+		assert.Contains(t, html, `[{"name":"Collections","xAxis":2,"itemStyle":{"color":"#888","opacity":0.03}},{"xAxis":3}]`)
+		assert.EqualT(t, 1, bytes.Count([]byte(html), []byte(`"Comparisons"`)))
+	})
+
+	t.Run("should position groups on the category axis of horizontal charts", func(t *testing.T) {
+		html := render(t, newChart(WithHorizontal(true)))
+
+		assert.Contains(t, html, `{"name":"Comparisons","yAxis":0,`)
+		assert.Contains(t, html, `"position":"insideLeft"`)
+	})
+
+	t.Run("should not draw groups on a numeric axis", func(t *testing.T) {
+		assert.Empty(t, newChart(WithXAxisType(xAxisLog)).markAreaGroups())
+	})
+}
@@ -0,0 +1,167 @@
+package chart
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	echartsopts "github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// buildDualAxisChart creates a single chart for two metrics of a category, each plotted against its own Y axis:
+// the first metric on the left, the second on the right.
+//
+// Series are suffixed with the unit of their metric, so that legends tell them apart.
+func (b *Builder) buildDualAxisChart(category model.Category, first, second config.Metric) *Chart {
+	if len(category.Data) == 0 {
+		return nil
+	}
+
+	opts := append(b.categoryOptions(category),
+		WithTitle(category.TitleWithMetrics([]config.Metric{first, second})),
+		WithYAxisLabel(axisLabel(first)),
+		WithSecondYAxisLabel(axisLabel(second)),
+	)
+
+	chart := NewChart(append(opts, groupOptions(category)...)...)
+	b.addMetricSeries(chart, category, first)
+	onFirstAxis := len(chart.Series)
+	b.addMetricSeries(chart, category, second)
+
+	for i := range chart.Series {
+		metric := first
+		if i >= onFirstAxis {
+			metric = second
+			chart.Series[i].YAxisIndex = 1
+		}
+		chart.Series[i].Name += " (" + metric.Axis + ")"
+	}
+
+	return chart
+}
+
+// buildSelectorChart creates a single chart for several metrics of a category, with buttons to select the metric shown.
+//
+// Each metric is a [View] of the chart, which initially shows the first metric.
+func (b *Builder) buildSelectorChart(category model.Category, metrics []config.Metric) *Chart {
+	var selector *Chart
+
+	for _, metric := range metrics {
+		chart := b.buildChartForMetric(category, metric)
+		if chart == nil {
+			continue
+		}
+
+		if selector == nil {
+			selector = chart
+		}
+		selector.AddView(metric.Title, chart)
+	}
+
+	return selector
+}
+
+// View is an alternative content of a bar chart, selected with a button: its title,
+// the label of its value axis and its series.
+type View struct {
+	Button     string
+	Title      string
+	YAxisLabel string
+	Series     []Series
+}
+
+// AddView adds the content of a chart (possibly this one) as a view of this chart, selected with a button.
+//
+// Views are only rendered on bar charts with at least two views. Screenshots only capture the first view.
+func (c *Chart) AddView(button string, chart *Chart) {
+	c.Views = append(c.Views, View{
+		Button:     button,
+		Title:      chart.Title,
+		YAxisLabel: chart.YAxisLabel,
+		Series:     chart.Series,
+	})
+}
+
+// secondYAxis returns the options of the second Y axis of a bar chart, on the right.
+func (c *Chart) secondYAxis() echartsopts.YAxis {
+	_, yAxisOpts := c.setAxes()
+	yAxisOpts.Name = c.SecondYAxisLabel
+	yAxisOpts.Position = "right"
+
+	return yAxisOpts
+}
+
+// viewSelector returns the scripts inserting a group of buttons above the chart, one for each of its views.
+//
+// Selecting a view replaces the title, the label of the value axis, the series and the origins of the data points
+// shown by the chart. Series are rendered like the series of the chart (e.g. with the same colors and mark lines).
+func (c *Chart) viewSelector() []string {
+	if len(c.Views) < 2 { //nolint:mnd // nothing to select
+		return nil
+	}
+
+	type view struct {
+		Button  string                `json:"button"`
+		Title   string                `json:"title"`
+		Axis    string                `json:"axis"`
+		Series  []charts.SingleSeries `json:"series"`
+		Origins [][]string            `json:"origins,omitempty"`
+	}
+
+	views := make([]view, 0, len(c.Views))
+	for _, v := range c.Views {
+		series := make([]charts.SingleSeries, 0, len(v.Series))
+		for i, s := range v.Series {
+			single := charts.SingleSeries{Name: s.Name, Type: c.seriesType(), Data: s.Data}
+			single.ConfigureSeriesOpts(c.seriesOptions(i)...)
+			series = append(series, single)
+		}
+
+		origins, known := originTexts(v.Series)
+		if !known {
+			origins = nil
+		}
+
+		views = append(views, view{Button: v.Button, Title: v.Title, Axis: v.YAxisLabel, Series: series, Origins: origins})
+	}
+
+	data, err := json.Marshal(views)
+	if err != nil {
+		return nil
+	}
+
+	return []string{
+		"(function (chart, views, horizontal) {" +
+			`var dom = chart.getDom(), bar = document.createElement("div");` +
+			`bar.setAttribute("role", "group");` +
+			`bar.setAttribute("aria-label", "Select a metric");` +
+			`bar.style.cssText = "display:flex;gap:0.5em;justify-content:center;margin:0.5em 0;font-family:sans-serif";` +
+			`var buttons = views.map(function (view, i) {` +
+			`var button = document.createElement("button");` +
+			`button.type = "button";` +
+			`button.textContent = view.button;` +
+			`button.onclick = function () { select(i); };` +
+			`bar.appendChild(button);` +
+			`return button;` +
+			`});` +
+			`var pressed = function (i) {` +
+			`buttons.forEach(function (button, j) {` +
+			`button.setAttribute("aria-pressed", String(i === j));` +
+			`button.style.fontWeight = i === j ? "bold" : "normal";` +
+			`});` +
+			`};` +
+			`var select = function (i) {` +
+			`var view = views[i], option = {title: {text: view.title}, series: view.series};` +
+			`option[horizontal ? "xAxis" : "yAxis"] = {name: view.axis};` +
+			// origins are reset when a view has none, not to show the origins of another metric
+			`option.tooltip = {formatter: (function (origins) { return ` + originTooltipFormatter + `; })(view.origins || [])};` +
+			`chart.setOption(option, {replaceMerge: ["series"]});` +
+			`pressed(i);` +
+			`};` +
+			`dom.parentNode.insertBefore(bar, dom);` +
+			`pressed(0);` +
+			"})(%MY_ECHARTS%, " + string(data) + ", " + strconv.FormatBool(c.Horizontal) + ");",
+	}
+}
@@ -0,0 +1,68 @@
+package chart
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestMetricLayout(t *testing.T) {
+	build := func(t *testing.T, layout config.MetricLayout) *Page {
+		t.Helper()
+
+		scenario := largeScenario(1, 2, 3)
+		scenario.Categories[0].MetricLayout = layout
+
+		return New(&config.Config{}, scenario, WithLogger(discard)).BuildPage()
+	}
+
+	t.Run("should split metrics by default", func(t *testing.T) {
+		page := build(t, "")
+		require.Len(t, page.Charts, 2)
+		assert.EqualT(t, "Category 0 (Timings)", page.Charts[0].Title)
+		assert.EqualT(t, "Category 0 (Allocations)", page.Charts[1].Title)
+	})
+
+	t.Run("should chart two metrics on a dual axis", func(t *testing.T) {
+		page := build(t, config.MetricLayoutDualAxis)
+		require.Len(t, page.Charts, 1)
+
+		chart := page.Charts[0]
+		assert.EqualT(t, "Category 0 (Timings / Allocations)", chart.Title)
+		assert.EqualT(t, "Allocations (allocs/op)", chart.SecondYAxisLabel)
+		require.Len(t, chart.Series, 4)
+		assert.EqualT(t, "v0 (ns/op)", chart.Series[0].Name)
+		assert.EqualT(t, 0, chart.Series[1].YAxisIndex)
+		assert.EqualT(t, "v0 (allocs/op)", chart.Series[2].Name)
+		assert.EqualT(t, 1, chart.Series[3].YAxisIndex)
+
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+		assert.Contains(t, buf.String(), `"yAxisIndex":1`)
+		assert.Contains(t, buf.String(), `"name":"Allocations (allocs/op)"`)
+	})
+
+	t.Run("should chart metrics with a selector", func(t *testing.T) {
+		page := build(t, config.MetricLayoutSelector)
+		require.Len(t, page.Charts, 1)
+
+		chart := page.Charts[0]
+		assert.EqualT(t, "Category 0 (Timings)", chart.Title)
+		require.Len(t, chart.Views, 2)
+		assert.EqualT(t, "Allocations", chart.Views[1].Button)
+		assert.EqualT(t, "Category 0 (Allocations)", chart.Views[1].Title)
+		assert.Len(t, chart.Views[1].Series, 2)
+
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+		html := buf.String()
+		assert.Contains(t, html, `"button":"Allocations"`)
+		assert.Contains(t, html, `replaceMerge`)
+		assert.Contains(t, html, `(view.origins || [])`) // origins are reset when switching views
+		assert.NotContains(t, html, "%MY_ECHARTS%")
+	})
+}
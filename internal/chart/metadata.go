@@ -0,0 +1,85 @@
+package chart
+
+import (
+	"html/template"
+	"slices"
+	"time"
+)
+
+// Metadata describes the run that produced a page, so that an archived page is self-describing.
+//
+// It is rendered as a collapsible section at the top of the page (see [WithMetadata]).
+// Empty fields are omitted.
+type Metadata struct {
+	Config       string            // name of the configuration, or the config file
+	Inputs       []string          // input benchmark files
+	Environments []string          // environments of the benchmarks, found in the scenario when not set
+	Generated    time.Time         // time the page was generated
+	Version      string            // version of benchviz
+	Commit       string            // git commit of the benchmarked code
+	Meta         map[string]string // arbitrary metadata of the run, found in the scenario when not set
+}
+
+// metadataTemplate renders the metadata of a page as a collapsed section.
+var metadataTemplate = template.Must(template.New("metadata").Parse(`
+<details id="benchviz-metadata" style="margin:0.5em 1em;font-family:sans-serif;font-size:13px;color:#555">
+  <summary style="cursor:pointer">Run metadata</summary>
+  <dl style="display:grid;grid-template-columns:max-content auto;gap:0.25em 1em;margin:0.5em 0">
+  {{- with .Config }}
+    <dt>Configuration</dt><dd style="margin:0">{{ . }}</dd>
+  {{- end }}
+  {{- with .Inputs }}
+    <dt>Inputs</dt><dd style="margin:0">{{ range $i, $input := . }}{{ if $i }}, {{ end }}<code>{{ $input }}</code>{{ end }}</dd>
+  {{- end }}
+  {{- with .Environments }}
+    <dt>Environments</dt><dd style="margin:0">{{ range $i, $env := . }}{{ if $i }}<br>{{ end }}{{ $env }}{{ end }}</dd>
+  {{- end }}
+  {{- if not .Generated.IsZero }}
+    <dt>Generated</dt><dd style="margin:0"><time datetime="{{ .Generated.UTC.Format "2006-01-02T15:04:05Z07:00" }}">{{ .Generated.Format "2006-01-02 15:04:05 MST" }}</time></dd>
+  {{- end }}
+  {{- with .Version }}
+    <dt>benchviz</dt><dd style="margin:0">{{ . }}</dd>
+  {{- end }}
+  {{- with .Commit }}
+    <dt>Commit</dt><dd style="margin:0"><code>{{ . }}</code></dd>
+  {{- end }}
+  {{- range $key, $value := .Meta }}
+    <dt>{{ $key }}</dt><dd style="margin:0">{{ $value }}</dd>
+  {{- end }}
+  </dl>
+</details>
+`))
+
+// WithMetadata embeds the metadata of the run in the pages built by the [Builder].
+//
+// By default, pages carry no metadata.
+func WithMetadata(metadata Metadata) BuilderOption {
+	return func(b *Builder) {
+		b.metadata = &metadata
+	}
+}
+
+// pageMetadata returns the metadata of a page, with the environments and the metadata of the run
+// found in the scenario, if not set.
+func (b *Builder) pageMetadata() *Metadata {
+	if b.metadata == nil {
+		return nil
+	}
+
+	metadata := *b.metadata
+	if len(metadata.Meta) == 0 {
+		metadata.Meta = b.scenario.Meta
+	}
+
+	if len(metadata.Environments) > 0 {
+		return &metadata
+	}
+
+	for _, category := range b.scenario.Categories {
+		if category.Environment != "" && !slices.Contains(metadata.Environments, category.Environment) {
+			metadata.Environments = append(metadata.Environments, category.Environment)
+		}
+	}
+
+	return &metadata
+}
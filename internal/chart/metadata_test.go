@@ -0,0 +1,61 @@
+package chart
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestMetadata(t *testing.T) {
+	scenario := largeScenario(1, 2, 3)
+	scenario.Categories[0].Environment = "linux/amd64 <cpu>"
+	scenario.Meta = map[string]string{"go": "go1.26", "feature": "on"}
+
+	t.Run("should not render metadata by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, New(&config.Config{}, scenario, WithLogger(discard)).BuildPage().Render(&buf))
+
+		assert.NotContains(t, buf.String(), "benchviz-metadata")
+	})
+
+	t.Run("should render metadata at the top of the page, with the environments of the scenario", func(t *testing.T) {
+		metadata := Metadata{
+			Config:    "bench",
+			Inputs:    []string{"a.txt", "b.txt"},
+			Generated: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+			Version:   "v1.2.3",
+			Commit:    "abc1234",
+		}
+
+		page := New(&config.Config{}, scenario, WithLogger(discard), WithMetadata(metadata)).BuildPage()
+		require.NotNil(t, page.Metadata)
+		assert.Equal(t, []string{"linux/amd64 <cpu>"}, page.Metadata.Environments)
+
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+		html := buf.String()
+
+		details := strings.Index(html, `<details id="benchviz-metadata"`)
+		require.Positive(t, details)
+		assert.Less(t, strings.Index(html, "<body>"), details)
+		assert.Less(t, details, strings.Index(html, `class="container"`))
+
+		for _, expected := range []string{
+			"<dd style=\"margin:0\">bench</dd>",
+			"<code>a.txt</code>, <code>b.txt</code>",
+			"linux/amd64 &lt;cpu&gt;",
+			`<time datetime="2026-10-16T12:00:00Z">2026-10-16 12:00:00 UTC</time>`,
+			"v1.2.3",
+			"<code>abc1234</code>",
+			"<dt>feature</dt><dd style=\"margin:0\">on</dd>\n    <dt>go</dt>",
+		} {
+			assert.StringContainsT(t, html, expected)
+		}
+	})
+}
@@ -20,17 +20,31 @@ const (
 type Option func(*options)
 
 type options struct {
-	Title          string
-	Subtitle       string
-	XAxisLabels    []string
-	YAxisLabel     string
-	Theme          string
-	Width          string
-	Height         string
-	ShowLegend     bool
-	LegendPosition string
-	Horizontal     bool
-	LabelFontSize  int
+	Title            string
+	Subtitle         string
+	XAxisLabels      []string
+	YAxisLabel       string
+	SecondYAxisLabel string
+	Theme            string
+	Width            string
+	Height           string
+	ShowLegend       bool
+	LegendPosition   string
+	Horizontal       bool
+	LabelFontSize    int
+	LabelWidth       int
+	LabelWrap        bool
+	Abbreviations    map[string]string
+	BarHeight        int
+	XAxisType        string
+	ZeroLine         bool
+	Annotations      []Annotation
+	Groups           []Group
+	Animation        bool
+	Palette          []string
+	Patterns         bool
+	Lines            bool
+	LogScale         bool
 }
 
 // WithTitle sets the chart title.
@@ -75,6 +89,14 @@ func WithXAxisLabels(xlabels []string) Option {
 	}
 }
 
+// WithSecondYAxisLabel adds a second Y axis on the right of a bar chart, with this label,
+// for the series plotted against it (see [Series]).
+func WithSecondYAxisLabel(ylabel string) Option {
+	return func(c *options) {
+		c.SecondYAxisLabel = ylabel
+	}
+}
+
 // WithLegendPosition sets the legend position (e.g. "bottom", "top", "left", "right").
 func WithLegendPosition(pos string) Option {
 	return func(c *options) {
@@ -105,10 +127,106 @@ func WithLabelFontSize(size int) Option {
 	}
 }
 
+// WithLabelWidth limits the width (in characters) of the workload axis tick labels:
+// longer labels are wrapped on several lines between words, or else truncated with an ellipsis.
+// A zero width leaves labels at their full length.
+func WithLabelWidth(width int, wrap bool) Option {
+	return func(c *options) {
+		c.LabelWidth = width
+		c.LabelWrap = wrap
+	}
+}
+
+// WithLabelAbbreviations abbreviates the workload axis tick labels: labels found in abbreviations
+// are displayed with their abbreviated form, before they are shortened to the label width.
+//
+// Tooltips and annotations still refer to the full labels.
+func WithLabelAbbreviations(abbreviations map[string]string) Option {
+	return func(c *options) {
+		c.Abbreviations = abbreviations
+	}
+}
+
+// WithBarHeight sets the minimum height (px) of each bar on horizontal bar charts:
+// charts with many bars grow taller than their configured height (see [Chart.Build]).
+// A zero value uses a default height.
+func WithBarHeight(height int) Option {
+	return func(c *options) {
+		c.BarHeight = height
+	}
+}
+
+// WithXAxisType sets the type of the X axis: "category" (the default) renders a bar chart,
+// "value" or "log" render a line chart with points positioned at their numeric value (see [Chart.AddNumericSeries]).
+func WithXAxisType(xType string) Option {
+	return func(c *options) {
+		c.XAxisType = xType
+	}
+}
+
+// WithZeroLine draws a line at zero on the value axis, e.g. for charts of signed changes.
+func WithZeroLine(enabled bool) Option {
+	return func(c *options) {
+		c.ZeroLine = enabled
+	}
+}
+
+// WithAnnotations documents the chart with freeform texts.
+func WithAnnotations(annotations ...Annotation) Option {
+	return func(c *options) {
+		c.Annotations = append(c.Annotations, annotations...)
+	}
+}
+
+// WithGroups highlights groups of contiguous positions on the category axis, under their title.
+func WithGroups(groups ...Group) Option {
+	return func(c *options) {
+		c.Groups = append(c.Groups, groups...)
+	}
+}
+
+// WithAnimation enables or disables the animation of the chart when it is drawn.
+//
+// Animations are enabled by default.
+func WithAnimation(enabled bool) Option {
+	return func(c *options) {
+		c.Animation = enabled
+	}
+}
+
+// WithPalette overrides the colors of the theme for the series of the chart.
+func WithPalette(colors []string) Option {
+	return func(c *options) {
+		c.Palette = colors
+	}
+}
+
+// WithPatterns distinguishes the series of the chart with decal patterns (e.g. hatching), in addition to colors.
+func WithPatterns(enabled bool) Option {
+	return func(c *options) {
+		c.Patterns = enabled
+	}
+}
+
+// WithLines draws the series of bar charts as lines, still positioned on the categories of the workload axis.
+func WithLines(enabled bool) Option {
+	return func(c *options) {
+		c.Lines = enabled
+	}
+}
+
+// WithLogScale uses a logarithmic value axis, e.g. when values span several orders of magnitude.
+func WithLogScale(enabled bool) Option {
+	return func(c *options) {
+		c.LogScale = enabled
+	}
+}
+
 func optionsWithDefaults(opts []Option) options {
 	o := options{
 		Theme:      ThemeRoma,
 		ShowLegend: true,
+		Animation:  true,
 	}
 
 	for _, apply := range opts {
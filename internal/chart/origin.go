@@ -0,0 +1,65 @@
+package chart
+
+import (
+	"encoding/json"
+)
+
+// originTooltipFormatter is the tooltip formatter of a chart, listing the origin of each data point
+// (raw benchmark name, input files and number of samples) below its value.
+//
+// The origins are passed as an array of strings for each series, in the order of the data points.
+// Hovering over something else than a data point (e.g. a mark line) shows no origin.
+const originTooltipFormatter = `function (params) {` +
+	`var encode = echarts.format.encodeHTML;` +
+	`var items = [].concat(params);` +
+	`var lines = [encode(items[0].name)];` +
+	`items.forEach(function (p) {` +
+	`var value = Array.isArray(p.value) ? p.value[1] : p.value;` +
+	`var line = p.marker + encode(p.seriesName) + ": <b>" + (typeof value === "number" ? value.toLocaleString() : value) + "</b>";` +
+	`var origin = p.componentType === "series" ? (origins[p.seriesIndex] || [])[p.dataIndex] : "";` +
+	`if (origin) { line += "<br/><small>" + encode(origin) + "</small>"; }` +
+	`lines.push(line);` +
+	`});` +
+	`return lines.join("<br/>");` +
+	`}`
+
+// originTooltips returns the scripts displaying the origin of data points in tooltips.
+//
+// go-echarts renders formatters as strings, without data: the formatter is set once the chart is initialized.
+// No script is returned when no data point has a known origin.
+func (c *Chart) originTooltips() []string {
+	origins, known := originTexts(c.Series)
+	if !known {
+		return nil
+	}
+
+	data, err := json.Marshal(origins)
+	if err != nil {
+		return nil
+	}
+
+	return []string{
+		"(function (origins) {" +
+			"%MY_ECHARTS%.setOption({tooltip: {formatter: " + originTooltipFormatter + "}});" +
+			"})(" + string(data) + ");",
+	}
+}
+
+// originTexts returns the origins of the data points of each series, as texts.
+//
+// It reports whether the origin of any data point is known.
+func originTexts(series []Series) (origins [][]string, known bool) {
+	origins = make([][]string, 0, len(series))
+
+	for _, s := range series {
+		texts := make([]string, 0, len(s.Origins))
+		for _, origin := range s.Origins {
+			text := origin.String()
+			known = known || text != ""
+			texts = append(texts, text)
+		}
+		origins = append(origins, texts)
+	}
+
+	return origins, known
+}
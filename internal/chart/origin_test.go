@@ -0,0 +1,66 @@
+package chart
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/model"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestOriginTooltips(t *testing.T) {
+	render := func(t *testing.T, chart *Chart) string {
+		t.Helper()
+
+		page := NewPage("Origins")
+		page.AddChart(chart)
+
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+
+		return buf.String()
+	}
+
+	t.Run("should list the origin of data points in tooltips", func(t *testing.T) {
+		chart := NewChart(WithTitle("Traced"), WithXAxisLabels([]string{"small", "large"}))
+		chart.AddSeries(model.MetricSeries{
+			Title: "v1",
+			Points: []model.MetricPoint{
+				{Label: "small", Value: 1, Origin: model.Origin{Files: []string{"old.txt"}, Benchmark: "BenchmarkSort/small-8", Samples: 1}},
+				{Label: "large", Value: 2, Origin: model.Origin{Files: []string{"old.txt", "new.txt"}, Benchmark: "BenchmarkSort/large-8", Samples: 3}},
+			},
+		})
+
+		html := render(t, chart)
+
+		assert.Contains(t, html, `.setOption({tooltip: {formatter: function (params) {`)
+		assert.Contains(t, html, `})([["BenchmarkSort/small-8 (old.txt)","BenchmarkSort/large-8 (old.txt, new.txt, 3 samples)"]]);`)
+	})
+
+	t.Run("should keep origins in the order of points on a numeric axis", func(t *testing.T) {
+		chart := NewChart(WithXAxisType(xAxisValue))
+		chart.AddNumericSeries("v1", []model.MetricPoint{
+			{Label: "large", Value: 2, Origin: model.Origin{Benchmark: "BenchmarkSort/1000"}},
+			{Label: "small", Value: 1, Origin: model.Origin{Benchmark: "BenchmarkSort/10"}},
+		}, func(point model.MetricPoint) (float64, bool) {
+			if point.Label == "small" {
+				return 10, true
+			}
+
+			return 1000, true
+		})
+
+		require.Len(t, chart.Series, 1)
+		assert.Equal(t, []model.Origin{{Benchmark: "BenchmarkSort/10"}, {Benchmark: "BenchmarkSort/1000"}}, chart.Series[0].Origins)
+	})
+
+	t.Run("should keep the default tooltip without origins", func(t *testing.T) {
+		chart := NewChart(WithXAxisLabels([]string{"small"}))
+		chart.AddSeries(model.MetricSeries{Title: "v1", Points: []model.MetricPoint{{Label: "small", Value: 1}}})
+
+		assert.Empty(t, chart.originTooltips())
+		assert.NotContains(t, render(t, chart), "tooltip: {formatter")
+	})
+}
@@ -0,0 +1,129 @@
+package chart
+
+import (
+	"math"
+	"slices"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	echartsopts "github.com/go-echarts/go-echarts/v2/opts"
+)
+
+const (
+	overviewTitle = "Overview"
+	overviewLabel = "Geometric mean"
+)
+
+// buildOverview creates the summary charts of a page: one chart per metric, with the geometric mean
+// of each version across all categories.
+//
+// Only benchmarks measured for every version are retained, so that versions are compared on the same ground.
+// A benchmark charted in several categories is counted once. Non-positive values, which have no geometric mean, are skipped.
+func (b *Builder) buildOverview() []*Chart {
+	var (
+		metrics    []config.Metric
+		versions   = make(map[config.MetricName][]string)
+		benchmarks = make(map[config.MetricName][]model.SeriesKey)
+		seen       = make(map[model.SeriesKey]bool)
+		values     = make(map[config.MetricName]map[string]map[model.SeriesKey]float64)
+	)
+
+	for _, category := range b.scenario.Categories {
+		for _, data := range category.Data {
+			for _, series := range data.Series {
+				for _, point := range series.Points {
+					if point.Value <= 0 {
+						continue
+					}
+
+					if _, ok := values[point.Metric]; !ok {
+						metrics = append(metrics, data.Metric)
+						values[point.Metric] = make(map[string]map[model.SeriesKey]float64)
+					}
+
+					byVersion := values[point.Metric]
+					if _, ok := byVersion[point.Version]; !ok {
+						versions[point.Metric] = append(versions[point.Metric], point.Version)
+						byVersion[point.Version] = make(map[model.SeriesKey]float64)
+					}
+
+					// benchmarks are compared across versions
+					key := point.SeriesKey
+					key.Version = ""
+					if !seen[key] {
+						seen[key] = true
+						benchmarks[point.Metric] = append(benchmarks[point.Metric], key)
+					}
+					byVersion[point.Version][key] = point.Value
+				}
+			}
+		}
+	}
+
+	charts := make([]*Chart, 0, len(metrics))
+	for _, metric := range metrics {
+		means := geometricMeans(versions[metric.ID], benchmarks[metric.ID], values[metric.ID])
+		if len(means) == 0 {
+			continue
+		}
+
+		chart := b.newOverviewChart(metric)
+		for i, version := range versions[metric.ID] {
+			chart.Series = append(chart.Series, Series{
+				Name: b.versionTitle(version),
+				Data: []echartsopts.BarData{{Name: overviewLabel, Value: means[i]}},
+			})
+		}
+		charts = append(charts, chart)
+	}
+
+	return charts
+}
+
+// geometricMeans computes the geometric mean of each version, over the benchmarks common to all versions.
+//
+// It returns nil when versions have no benchmark in common.
+func geometricMeans(versions []string, benchmarks []model.SeriesKey, values map[string]map[model.SeriesKey]float64) []float64 {
+	var common []model.SeriesKey
+	for _, key := range benchmarks {
+		if !slices.ContainsFunc(versions, func(version string) bool {
+			_, ok := values[version][key]
+
+			return !ok
+		}) {
+			common = append(common, key)
+		}
+	}
+
+	if len(common) == 0 {
+		return nil
+	}
+
+	means := make([]float64, 0, len(versions))
+	for _, version := range versions {
+		var sumLogs float64
+		for _, key := range common {
+			sumLogs += math.Log(values[version][key])
+		}
+		means = append(means, math.Exp(sumLogs/float64(len(common))))
+	}
+
+	return means
+}
+
+func (b *Builder) newOverviewChart(metric config.Metric) *Chart {
+	return NewChart(append(b.renderOptions(),
+		WithTitle(overviewTitle+" ("+metric.Title+")"),
+		WithXAxisLabels([]string{overviewLabel}),
+		WithYAxisLabel(metric.Title+" ("+metric.Axis+")"),
+	)...)
+}
+
+// versionTitle returns the title of a version, or its ID when the version is not defined in config.
+func (b *Builder) versionTitle(id string) string {
+	if version, ok := b.cfg.GetVersion(id); ok && version.Title != "" {
+		return version.Title
+	}
+
+	return id
+}
@@ -0,0 +1,91 @@
+package chart
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+func TestBuildOverview(t *testing.T) {
+	cfg := mustLoadConfig(t, `
+render:
+  overview: true
+metrics:
+  - id: nsPerOp
+    title: Timings
+    axis: ns/op
+versions:
+  - id: v1
+    title: Before
+  - id: v2
+    title: After
+categories:
+  - id: all
+    includes:
+      metrics: [nsPerOp]
+`)
+	metric, ok := cfg.GetMetric(config.MetricNsPerOp)
+	require.True(t, ok)
+
+	point := func(function, version string, value float64) model.MetricPoint {
+		return model.MetricPoint{
+			SeriesKey: model.SeriesKey{Function: function, Version: version, Metric: metric.ID},
+			Value:     value,
+		}
+	}
+	series := func(version string, points ...model.MetricPoint) model.CategoryData {
+		return model.CategoryData{Metric: metric, Series: []model.MetricSeries{{
+			SeriesKey: model.SeriesKey{Version: version, Metric: metric.ID},
+			Title:     version,
+			Points:    points,
+		}}}
+	}
+
+	scenario := &model.Scenario{Categories: []model.Category{
+		{ID: "encoding", Title: "Encoding", Data: []model.CategoryData{
+			series("v1", point("encode", "v1", 10), point("decode", "v1", 1000), point("only", "v1", 5)),
+			series("v2", point("encode", "v2", 20), point("decode", "v2", 500)),
+		}},
+		{ID: "sorting", Title: "Sorting", Data: []model.CategoryData{
+			series("v1", point("sort", "v1", 100), point("noop", "v1", 0)),
+			series("v2", point("sort", "v2", 50), point("noop", "v2", 0)),
+		}},
+		{ID: "highlights", Title: "Highlights", Data: []model.CategoryData{ // benchmarks already charted
+			series("v1", point("encode", "v1", 10)),
+			series("v2", point("encode", "v2", 20)),
+		}},
+	}}
+
+	page := New(cfg, scenario, WithLogger(discard)).BuildPage()
+	require.Len(t, page.Charts, 4)
+
+	overview := page.Charts[0]
+	assert.EqualT(t, "Overview (Timings)", overview.Title)
+	require.Len(t, overview.Series, 2)
+
+	t.Run("should compare versions on distinct common benchmarks only", func(t *testing.T) {
+		assert.EqualT(t, "Before", overview.Series[0].Name)
+		require.Len(t, overview.Series[0].Data, 1)
+		before, ok := overview.Series[0].Data[0].Value.(float64)
+		require.True(t, ok)
+		assert.InDeltaT(t, math.Cbrt(10*1000*100), before, 1e-9)
+
+		assert.EqualT(t, "After", overview.Series[1].Name)
+		after, ok := overview.Series[1].Data[0].Value.(float64)
+		require.True(t, ok)
+		assert.InDeltaT(t, math.Cbrt(20*500*50), after, 1e-9)
+	})
+
+	t.Run("should not add an overview unless configured", func(t *testing.T) {
+		cfg.Render.Overview = false
+		t.Cleanup(func() { cfg.Render.Overview = true })
+
+		page := New(cfg, scenario, WithLogger(discard)).BuildPage()
+		assert.Len(t, page.Charts, 3)
+	})
+}
@@ -1,6 +1,7 @@
 package chart
 
 import (
+	"bytes"
 	"io"
 
 	"github.com/go-echarts/go-echarts/v2/components"
@@ -10,8 +11,13 @@ import (
 //
 // A [Page] knows how to [Page.Render] as HTML.
 type Page struct {
-	Title  string
-	Charts []*Chart
+	Title    string
+	Charts   []*Chart
+	Sections []Section // sections grouping the charts, if any (see [Page.AddSection])
+	Metadata *Metadata // metadata of the run, rendered in a collapsible section at the top of the page, if any
+
+	concurrency     int  // maximum number of charts built concurrently when rendering (see [WithConcurrency])
+	tableOfContents bool // render a table of contents at the top of the page
 }
 
 // NewPage creates a new page with the given title.
@@ -32,9 +38,64 @@ func (p *Page) Render(w io.Writer) error {
 	page.SetLayout(components.PageFlexLayout)
 	page.SetPageTitle(p.Title)
 
-	for _, c := range p.Charts {
-		page.AddCharts(c.Build())
+	built := p.buildCharts()
+	for _, chart := range built {
+		page.AddCharts(chart)
+	}
+
+	if !p.tableOfContents && p.Metadata == nil && len(p.Sections) == 0 {
+		return page.Render(w)
+	}
+
+	var buf bytes.Buffer
+	if err := page.Render(&buf); err != nil {
+		return err
+	}
+
+	rendered, err := p.insertSections(buf.Bytes(), built)
+	if err != nil {
+		return err
+	}
+
+	return p.writeWithHeader(w, rendered, built)
+}
+
+// buildCharts builds the ECharts charts of the page concurrently, in the order of the page.
+func (p *Page) buildCharts() []builtChart {
+	return Concurrently(p.concurrency, p.Charts, (*Chart).build)
+}
+
+// writeWithHeader writes a rendered page, with the metadata of the run and a table of contents
+// inserted at the top of its body.
+func (p *Page) writeWithHeader(w io.Writer, page []byte, built []builtChart) error {
+	var header bytes.Buffer
+
+	if p.Metadata != nil {
+		if err := metadataTemplate.Execute(&header, p.Metadata); err != nil {
+			return err
+		}
+	}
+
+	if p.tableOfContents {
+		if err := tocTemplate.Execute(&header, p.tocEntries(built)); err != nil {
+			return err
+		}
+	}
+
+	// insert the header right after the opening body tag, if any
+	const body = "<body>"
+	head, tail, found := bytes.Cut(page, []byte(body))
+	if !found {
+		head, tail = nil, page
+	} else {
+		head = append(head, body...)
+	}
+
+	for _, part := range [][]byte{head, header.Bytes(), tail} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
 	}
 
-	return page.Render(w)
+	return nil
 }
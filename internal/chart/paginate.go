@@ -0,0 +1,97 @@
+package chart
+
+import (
+	"fmt"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// paginate splits a category with more than maxBars bars into several parts, each rendered as a separate chart.
+//
+// Positions on the X axis are distributed across parts in order, so that no part exceeds maxBars bars
+// (at least one position per part). Parts are titled after the category, like "Title (part 1/3)".
+//
+// Categories with a numeric X axis are never split: lines don't crowd like bars do.
+// A zero maxBars disables pagination.
+func paginate(category model.Category, maxBars int) []model.Category {
+	if maxBars <= 0 || category.XAxis.IsNumeric() {
+		return []model.Category{category}
+	}
+
+	var (
+		keys        []model.SeriesKey
+		seenKeys    = make(map[model.SeriesKey]struct{})
+		seriesCount = make(map[config.MetricName]int)
+	)
+
+	for _, data := range category.Data {
+		seriesCount[data.Metric.ID] += len(data.Series)
+
+		for _, series := range data.Series {
+			for _, point := range series.Points {
+				key := category.XKey(point)
+				if _, seen := seenKeys[key]; seen {
+					continue
+				}
+				seenKeys[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	var bars int
+	for _, count := range seriesCount {
+		if category.MetricLayout == config.MetricLayoutDualAxis {
+			bars += count // the bars of both metrics are side by side
+
+			continue
+		}
+		bars = max(bars, count)
+	}
+	barsPerKey := max(1, bars)
+
+	keysPerPart := max(1, maxBars/barsPerKey)
+	if len(keys) <= keysPerPart {
+		return []model.Category{category}
+	}
+
+	parts := (len(keys) + keysPerPart - 1) / keysPerPart
+	paginated := make([]model.Category, 0, parts)
+
+	for i := range parts {
+		retained := make(map[model.SeriesKey]struct{}, keysPerPart)
+		for _, key := range keys[i*keysPerPart : min((i+1)*keysPerPart, len(keys))] {
+			retained[key] = struct{}{}
+		}
+
+		part := category
+		part.ID = fmt.Sprintf("%s-part%d", category.ID, i+1)
+		part.Title = fmt.Sprintf("%s (part %d/%d)", category.Title, i+1, parts)
+		part.Data = make([]model.CategoryData, 0, len(category.Data))
+
+		for _, data := range category.Data {
+			partData := data
+			partData.Series = make([]model.MetricSeries, 0, len(data.Series))
+
+			for _, series := range data.Series {
+				partSeries := series
+				partSeries.Points = make([]model.MetricPoint, 0, keysPerPart)
+
+				for _, point := range series.Points {
+					if _, ok := retained[category.XKey(point)]; ok {
+						partSeries.Points = append(partSeries.Points, point)
+					}
+				}
+
+				partData.Series = append(partData.Series, partSeries)
+			}
+
+			part.Data = append(part.Data, partData)
+		}
+
+		paginated = append(paginated, part)
+	}
+
+	return paginated
+}
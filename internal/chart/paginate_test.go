@@ -0,0 +1,64 @@
+package chart
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestPaginate(t *testing.T) {
+	// 10 contexts, with 2 versions as series: 20 bars
+	category := model.Category{ID: "sort", Title: "{metric} sorting"}
+	metric := config.Metric{ID: config.MetricNsPerOp}
+	for _, version := range []string{"v1", "v2"} {
+		series := model.MetricSeries{Title: version}
+		for i := range 10 {
+			context := "c" + strconv.Itoa(i)
+			series.Points = append(series.Points, model.MetricPoint{
+				SeriesKey: model.SeriesKey{Function: "sort", Version: version, Context: context, Metric: metric.ID},
+				Label:     context,
+				Value:     float64(i),
+			})
+		}
+		category.Data = append(category.Data, model.CategoryData{Metric: metric, Series: []model.MetricSeries{series}})
+	}
+
+	t.Run("should not split a category without a maximum number of bars", func(t *testing.T) {
+		assert.Len(t, paginate(category, 0), 1)
+		assert.Len(t, paginate(category, 20), 1)
+	})
+
+	t.Run("should split a category with too many bars", func(t *testing.T) {
+		parts := paginate(category, 8)
+		require.Len(t, parts, 3)
+
+		assert.EqualT(t, "sort-part1", parts[0].ID)
+		assert.EqualT(t, "{metric} sorting (part 1/3)", parts[0].Title)
+		assert.Equal(t, []string{"c0", "c1", "c2", "c3"}, parts[0].Labels())
+		assert.Equal(t, []string{"c4", "c5", "c6", "c7"}, parts[1].Labels())
+		assert.Equal(t, []string{"c8", "c9"}, parts[2].Labels())
+
+		// both versions are kept in each part
+		require.Len(t, parts[2].Data, 2)
+		assert.Len(t, parts[2].Data[1].Series[0].Points, 2)
+
+		// the original category is left untouched
+		assert.Len(t, category.Data[0].Series[0].Points, 10)
+	})
+
+	t.Run("should keep at least one position per part", func(t *testing.T) {
+		assert.Len(t, paginate(category, 1), 10)
+	})
+
+	t.Run("should not split a category with a numeric X axis", func(t *testing.T) {
+		numeric := category
+		numeric.XAxis = config.XAxisValue
+
+		assert.Len(t, paginate(numeric, 8), 1)
+	})
+}
@@ -0,0 +1,32 @@
+package chart
+
+import (
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+// palettes holds the colors of the built-in colorblind-safe palettes.
+var palettes = map[config.Palette][]string{
+	// see https://jfly.uni-koeln.de/color/
+	config.PaletteOkabeIto: {"#0072B2", "#E69F00", "#009E73", "#CC79A7", "#56B4E9", "#D55E00", "#F0E442", "#000000"},
+	// see https://personal.sron.nl/~pault/
+	config.PaletteTolBright: {"#4477AA", "#EE6677", "#228833", "#CCBB44", "#66CCEE", "#AA3377", "#BBBBBB"},
+}
+
+// paletteColors returns the colors of a palette, or nil to use the colors of the theme.
+func paletteColors(palette config.Palette) []string {
+	return palettes[palette]
+}
+
+// changeColors returns the colors of improvements and degradations.
+//
+// With a colorblind-safe palette, blue replaces green, and vermillion (Okabe-Ito) or yellow (Tol bright) replaces red.
+func changeColors(palette config.Palette) (better, worse string) {
+	switch palette {
+	case config.PaletteOkabeIto:
+		return "#0072B2", "#D55E00"
+	case config.PaletteTolBright:
+		return "#4477AA", "#CCBB44"
+	default:
+		return colorBetter, colorWorse
+	}
+}
@@ -0,0 +1,53 @@
+package chart
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestAccessibleRendering(t *testing.T) {
+	scenario := largeScenario(1, 2, 3)
+
+	render := func(t *testing.T, cfg *config.Config) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		page := New(cfg, scenario, WithLogger(discard)).BuildPage()
+		require.NoError(t, page.Render(&buf))
+
+		return buf.String()
+	}
+
+	t.Run("should describe charts to screen readers, with the colors of the theme", func(t *testing.T) {
+		html := render(t, &config.Config{})
+
+		assert.Contains(t, html, `"aria":{"enabled":true,"label":{"enabled":true},"decal":{"show":false}}`)
+		assert.NotContains(t, html, `"itemStyle":{"color"`)
+	})
+
+	t.Run("should render a colorblind-safe palette with patterns", func(t *testing.T) {
+		html := render(t, &config.Config{Render: config.Rendering{Palette: config.PaletteOkabeIto, Patterns: true}})
+
+		assert.Contains(t, html, `"name":"v0","type":"bar"`)
+		assert.Contains(t, html, `"itemStyle":{"color":"#0072B2"}`)
+		assert.Contains(t, html, `"itemStyle":{"color":"#E69F00"}`)
+		assert.Contains(t, html, `"decal":{"show":true}`)
+	})
+}
+
+func TestChangeColors(t *testing.T) {
+	better, worse := changeColors("")
+	assert.EqualT(t, colorBetter, better)
+	assert.EqualT(t, colorWorse, worse)
+
+	for _, palette := range []config.Palette{config.PaletteOkabeIto, config.PaletteTolBright} {
+		better, worse := changeColors(palette)
+		assert.Contains(t, paletteColors(palette), better)
+		assert.Contains(t, paletteColors(palette), worse)
+	}
+}
@@ -0,0 +1,81 @@
+package chart
+
+import (
+	"bytes"
+	"html/template"
+)
+
+// Section is a titled group of consecutive charts on a [Page], e.g. the charts of a project
+// on a dashboard combining several projects.
+//
+// Start is the index in the charts of the page of the first chart of the section.
+type Section struct {
+	Title string
+	Start int
+}
+
+// sectionTemplate renders the heading of a section, spanning the full width of the page.
+var sectionTemplate = template.Must(template.New("section").Parse(
+	`<h2 id="{{ .ID }}" class="benchviz-section" style="flex-basis:100%;margin:1em 1em 0.5em;font-family:sans-serif">{{ .Title }}</h2>`,
+))
+
+// AddSection adds the charts of another page to the page, as a titled section.
+//
+// The page keeps its own metadata, or else the metadata of its first section. It renders a table of contents
+// if any of its sections does.
+func (p *Page) AddSection(title string, page *Page) {
+	p.Sections = append(p.Sections, Section{Title: title, Start: len(p.Charts)})
+	p.Charts = append(p.Charts, page.Charts...)
+
+	if p.Metadata == nil {
+		p.Metadata = page.Metadata
+	}
+	p.tableOfContents = p.tableOfContents || page.tableOfContents
+}
+
+// sectionOf returns the title of the section of the chart at some index, if any.
+func (p *Page) sectionOf(index int) string {
+	var title string
+	for _, section := range p.Sections {
+		if section.Start > index {
+			break
+		}
+		title = section.Title
+	}
+
+	return title
+}
+
+// insertSections inserts the heading of each section of a rendered page right before the element
+// of its first chart.
+func (p *Page) insertSections(page []byte, built []builtChart) ([]byte, error) {
+	const container = `<div class="container">`
+
+	for i := len(p.Sections) - 1; i >= 0; i-- {
+		section := p.Sections[i]
+		if section.Start >= len(built) {
+			continue
+		}
+
+		id := chartID(built[section.Start])
+		at := bytes.Index(page, []byte(`id="`+id+`"`))
+		if at < 0 {
+			continue
+		}
+		if start := bytes.LastIndex(page[:at], []byte(container)); start >= 0 {
+			at = start
+		}
+
+		var heading bytes.Buffer
+		if err := sectionTemplate.Execute(&heading, struct {
+			ID    string
+			Title string
+		}{ID: "section-" + id, Title: section.Title}); err != nil {
+			return nil, err
+		}
+
+		page = append(page[:at:at], append(heading.Bytes(), page[at:]...)...)
+	}
+
+	return page, nil
+}
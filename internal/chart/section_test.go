@@ -0,0 +1,51 @@
+package chart
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestSections(t *testing.T) {
+	first := New(&config.Config{}, largeScenario(1, 2, 3), WithLogger(discard)).BuildPage()
+	second := New(&config.Config{}, largeScenario(2, 1, 3), WithLogger(discard)).BuildPage()
+
+	page := NewPage("Dashboard")
+	page.AddSection("first", first)
+	page.AddSection("second", second)
+
+	require.Len(t, page.Charts, 6)
+	assert.Equal(t, []Section{{Title: "first", Start: 0}, {Title: "second", Start: 2}}, page.Sections)
+	assert.EqualT(t, "first", page.sectionOf(1))
+	assert.EqualT(t, "second", page.sectionOf(2))
+
+	t.Run("should render a heading before the first chart of each section", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, page.Render(&buf))
+		html := buf.String()
+
+		firstHeading := strings.Index(html, `class="benchviz-section" style="flex-basis:100%;margin:1em 1em 0.5em;font-family:sans-serif">first</h2>`)
+		secondHeading := strings.Index(html, `>second</h2>`)
+		require.True(t, firstHeading > 0)
+		require.True(t, secondHeading > firstHeading)
+		assert.EqualT(t, 2, strings.Count(html, `class="benchviz-section"`))
+
+		// the heading of a section comes right before the container of its first chart
+		assert.True(t, strings.HasPrefix(html[secondHeading+len(`>second</h2>`):], `<div class="container">`))
+		assert.EqualT(t, 2, strings.Count(html[firstHeading:secondHeading], `<div class="container">`))
+	})
+
+	t.Run("should expose sections to page templates", func(t *testing.T) {
+		tmpl := template.Must(template.New("sections").Parse(`{{ range .Charts }}{{ .Section }};{{ end }}`))
+
+		var buf bytes.Buffer
+		require.NoError(t, page.RenderTemplate(&buf, tmpl, nil))
+		assert.EqualT(t, "first;first;second;second;second;second;", buf.String())
+	})
+}
@@ -0,0 +1,112 @@
+package chart
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// TemplateData is the data exposed to a user-provided page template (see [Page.RenderTemplate]).
+//
+// Scripts lists the JavaScript assets (ECharts library and themes) to load in the page header.
+// Metadata describes the run that produced the page, if known.
+// TOC lists the links to each chart, when the page has a table of contents (see render.toc).
+type TemplateData struct {
+	Title    string
+	Scenario *model.Scenario
+	Metadata *Metadata
+	Charts   []TemplateChart
+	TOC      []TOCEntry
+	Scripts  []string
+}
+
+// TemplateChart exposes a rendered chart to a page template.
+//
+// A chart may be inserted as is, with its Element followed by its Script.
+// Alternatively, Option holds the ECharts option as JSON, for templates that initialize charts themselves.
+//
+// Section is the title of the section of the chart, on a page with sections (see [Page.AddSection]).
+type TemplateChart struct {
+	ID       string
+	Title    string
+	Subtitle string
+	Section  string
+	Element  template.HTML
+	Script   template.HTML
+	Option   template.JS
+}
+
+// TemplateFuncs are the functions available to page templates, in addition to the standard ones.
+var TemplateFuncs = template.FuncMap{
+	"json": func(value any) (template.JS, error) {
+		buf, err := json.Marshal(value)
+
+		return template.JS(buf), err //nolint:gosec // marshaled JSON is safe to embed in a script
+	},
+}
+
+// ParseTemplate loads a page template from a file, with [TemplateFuncs] available.
+func ParseTemplate(file string) (*template.Template, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading page template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(file)).Funcs(TemplateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing page template %q: %w", file, err)
+	}
+
+	return tmpl, nil
+}
+
+// RenderTemplate writes the page HTML to the given writer, using a user-provided template.
+//
+// The template is executed with [TemplateData], built from the charts of the page and the scenario they represent.
+func (p *Page) RenderTemplate(w io.Writer, tmpl *template.Template, scenario *model.Scenario) error {
+	data := TemplateData{
+		Title:    p.Title,
+		Scenario: scenario,
+		Metadata: p.Metadata,
+		Charts:   make([]TemplateChart, 0, len(p.Charts)),
+	}
+
+	built := p.buildCharts()
+	if p.tableOfContents {
+		data.TOC = p.tocEntries(built)
+	}
+
+	for i, chart := range built {
+		c := p.Charts[i]
+		snippet := chart.RenderSnippet()
+
+		data.Charts = append(data.Charts, TemplateChart{
+			ID:       chartID(chart),
+			Title:    c.Title,
+			Subtitle: c.Subtitle,
+			Section:  p.sectionOf(i),
+			Element:  template.HTML(snippet.Element),                 //nolint:gosec // rendered by go-echarts
+			Script:   template.HTML(snippet.Script),                  //nolint:gosec // rendered by go-echarts
+			Option:   template.JS(strings.TrimSpace(snippet.Option)), //nolint:gosec // rendered by go-echarts
+		})
+
+		for _, script := range chart.GetAssets().JSAssets.Values {
+			if !slices.Contains(data.Scripts, script) {
+				data.Scripts = append(data.Scripts, script)
+			}
+		}
+	}
+
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("executing page template: %w", err)
+	}
+
+	return nil
+}
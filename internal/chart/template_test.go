@@ -0,0 +1,80 @@
+package chart
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/organizer"
+	"github.com/fredbi/benchviz/internal/parser"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+const testPageTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<title>ACME - {{ .Title }}</title>
+{{- range .Scripts }}
+<script src="{{ . }}"></script>
+{{- end }}
+</head>
+<body>
+<h1>{{ .Scenario.Name }}</h1>
+{{- range .Charts }}
+<section id="section-{{ .ID }}">
+<h2>{{ .Title }}</h2>
+{{ .Element }}
+{{ .Script }}
+</section>
+{{- end }}
+<script>const categories = {{ json .Scenario.Categories }};</script>
+<script>const firstOption = {{ (index .Charts 0).Option }};</script>
+</body>
+</html>
+`
+
+func TestRenderTemplate(t *testing.T) {
+	cfg := mustLoadConfig(t, smokeConfig())
+
+	p := parser.New(cfg, parser.WithParseJSON(true))
+	require.NoError(t, p.ParseFiles(parserTestdataPath("sample_generics.json")))
+	scenario, err := organizer.New(cfg).Scenarize(p.Sets())
+	require.NoError(t, err)
+	page := New(cfg, scenario).BuildPage()
+	require.NotEmpty(t, page.Charts)
+
+	file := filepath.Join(t.TempDir(), "page.tmpl")
+	require.NoError(t, os.WriteFile(file, []byte(testPageTemplate), 0o600))
+	tmpl, err := ParseTemplate(file)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, page.RenderTemplate(&buf, tmpl, scenario))
+	html := buf.String()
+
+	assert.Contains(t, html, "<title>ACME - "+page.Title+"</title>")
+	assert.Contains(t, html, "echarts.min.js")
+	assert.Contains(t, html, `<section id="section-`)
+	assert.Contains(t, html, "<h2>"+page.Charts[0].Title+"</h2>")
+	assert.Contains(t, html, "echarts.init(")
+	assert.Contains(t, html, `const categories = [{"ID":`)
+	assert.Contains(t, html, `const firstOption = {`)
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	t.Run("with missing file", func(t *testing.T) {
+		_, err := ParseTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+		require.Error(t, err)
+	})
+
+	t.Run("with invalid template", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "page.tmpl")
+		require.NoError(t, os.WriteFile(file, []byte("{{ .Title "), 0o600))
+
+		_, err := ParseTemplate(file)
+		require.ErrorContains(t, err, "parsing page template")
+	})
+}
@@ -0,0 +1,39 @@
+package chart
+
+import "html/template"
+
+// tocTemplate renders the table of contents of a page: a sticky list of links to each chart,
+// and a floating link back to the top of the page.
+var tocTemplate = template.Must(template.New("toc").Parse(`
+<nav id="benchviz-toc" aria-label="Table of contents" style="position:sticky;top:0;z-index:10;max-height:30vh;overflow:auto;padding:0.5em 1em;background:#fff;border-bottom:1px solid #ddd;font-family:sans-serif;font-size:14px">
+  <ul style="display:flex;flex-wrap:wrap;gap:0.25em 1.5em;list-style:none;margin:0;padding:0">
+  {{- range . }}
+    <li><a href="#{{ .ID }}">{{ .Title }}</a></li>
+  {{- end }}
+  </ul>
+</nav>
+<a href="#benchviz-toc" title="Back to top" style="position:fixed;right:1em;bottom:1em;z-index:10;padding:0.25em 0.5em;background:#fff;border:1px solid #ddd;border-radius:4px;font-family:sans-serif;text-decoration:none">&uarr; Top</a>
+`))
+
+// TOCEntry is a link to a chart in the table of contents.
+type TOCEntry struct {
+	ID    string
+	Title string
+}
+
+// tocEntries lists the charts of the page, in order.
+func (p *Page) tocEntries(built []builtChart) []TOCEntry {
+	entries := make([]TOCEntry, 0, len(built))
+	for i, chart := range built {
+		title := p.Charts[i].Title
+		if title == "" {
+			title = chartID(chart)
+		}
+		if section := p.sectionOf(i); section != "" {
+			title = section + ": " + title
+		}
+		entries = append(entries, TOCEntry{ID: chartID(chart), Title: title})
+	}
+
+	return entries
+}
@@ -0,0 +1,62 @@
+package chart
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestTableOfContents(t *testing.T) {
+	render := func(t *testing.T, cfg *config.Config, categories int) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		page := New(cfg, largeScenario(categories, 2, 3), WithLogger(discard)).BuildPage()
+		require.NoError(t, page.Render(&buf))
+
+		return buf.String()
+	}
+
+	t.Run("should not add a table of contents to a page with a few charts", func(t *testing.T) {
+		html := render(t, &config.Config{}, 2) // two charts (metrics) per category
+
+		assert.NotContains(t, html, `id="benchviz-toc"`)
+	})
+
+	t.Run("should link to each chart from a table of contents at the top of the page", func(t *testing.T) {
+		html := render(t, &config.Config{}, 4) // two charts (metrics) per category
+
+		toc := strings.Index(html, `<nav id="benchviz-toc"`)
+		require.Positive(t, toc)
+		assert.Less(t, strings.Index(html, "<body>"), toc)
+		assert.Less(t, toc, strings.Index(html, `class="container"`))
+		assert.Contains(t, html, `href="#benchviz-toc"`)
+
+		links := strings.Count(html[toc:], `<li><a href="#`)
+		assert.EqualT(t, 8, links)
+	})
+
+	t.Run("should follow the configured mode", func(t *testing.T) {
+		assert.Contains(t, render(t, &config.Config{Render: config.Rendering{TOC: config.TOCOn}}, 1), `id="benchviz-toc"`)
+		assert.NotContains(t, render(t, &config.Config{Render: config.Rendering{TOC: config.TOCOff}}, 4), `id="benchviz-toc"`)
+	})
+	t.Run("should expose the table of contents to page templates", func(t *testing.T) {
+		tmpl := template.Must(template.New("page").Parse(`{{ range .TOC }}<a href="#{{ .ID }}">{{ .Title }}</a>{{ end }}`))
+
+		var buf bytes.Buffer
+		page := New(&config.Config{}, largeScenario(4, 2, 3), WithLogger(discard)).BuildPage()
+		require.NoError(t, page.RenderTemplate(&buf, tmpl, nil))
+		assert.EqualT(t, 8, strings.Count(buf.String(), `<a href="#`))
+
+		buf.Reset()
+		page = New(&config.Config{Render: config.Rendering{TOC: config.TOCOff}}, largeScenario(4, 2, 3), WithLogger(discard)).BuildPage()
+		require.NoError(t, page.RenderTemplate(&buf, tmpl, nil))
+		assert.Empty(t, buf.String())
+	})
+}
@@ -0,0 +1,83 @@
+package chart
+
+import (
+	"math"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	echartsopts "github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// missingValue is rendered by ECharts as a gap in a line.
+const missingValue = "-"
+
+// TrendSeries is a named series of values over successive runs. Missing values are NaN.
+type TrendSeries struct {
+	Name   string
+	Values []float64
+}
+
+// Trend represents a line chart of benchmark values over successive runs.
+//
+// Labels identify the runs on the X axis.
+type Trend struct {
+	Title      string
+	YAxisLabel string
+	Theme      string
+	Labels     []string
+	Series     []TrendSeries
+}
+
+// Build creates the ECharts line chart for the trend.
+func (t *Trend) Build() *charts.Line {
+	line := charts.NewLine()
+
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(echartsopts.Initialization{
+			Theme: t.Theme,
+		}),
+		charts.WithTitleOpts(echartsopts.Title{
+			Title: t.Title,
+		}),
+		charts.WithLegendOpts(echartsopts.Legend{
+			Show: echartsopts.Bool(true),
+			Top:  "bottom",
+		}),
+		charts.WithGridOpts(echartsopts.Grid{
+			Bottom: "100",
+			Top:    "100",
+		}),
+		charts.WithXAxisOpts(echartsopts.XAxis{
+			AxisLabel: &echartsopts.AxisLabel{
+				Rotate: xAxisLabelAngle,
+			},
+		}),
+		charts.WithYAxisOpts(echartsopts.YAxis{
+			Name:         t.YAxisLabel,
+			NameLocation: "middle",
+			NameGap:      axisNameGap * 2, //nolint:mnd // leave room for large values
+		}),
+		charts.WithTooltipOpts(echartsopts.Tooltip{
+			Show:    echartsopts.Bool(true),
+			Trigger: "axis",
+		}),
+	)
+
+	line.SetXAxis(t.Labels)
+
+	for _, s := range t.Series {
+		data := make([]echartsopts.LineData, 0, len(s.Values))
+		for _, value := range s.Values {
+			if math.IsNaN(value) {
+				data = append(data, echartsopts.LineData{Value: missingValue})
+
+				continue
+			}
+			data = append(data, echartsopts.LineData{Value: value})
+		}
+		line.AddSeries(s.Name, data, charts.WithLineChartOpts(echartsopts.LineChart{
+			ConnectNulls: echartsopts.Bool(true),
+		}))
+	}
+
+	return line
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
+	"github.com/fredbi/benchviz/internal/notify"
+)
+
+const (
+	// baselineCommand is the first CLI argument that triggers a command about baseline snapshots.
+	baselineCommand = "baseline"
+
+	// baselineSave stores a named snapshot of organized results, e.g. "benchviz baseline save main bench.txt".
+	baselineSave = "save"
+
+	// baselineCompare compares a new run against a named snapshot, e.g. "benchviz baseline compare main bench.txt".
+	baselineCompare = "compare"
+
+	// defaultBaselineDir is the default directory where baseline snapshots are stored.
+	defaultBaselineDir = ".benchviz/baselines"
+
+	// defaultThreshold is the default relative change (in percent) beyond which a worse result is a regression.
+	defaultThreshold = 5.0
+)
+
+// executeBaseline saves or compares a baseline snapshot of the organized input benchmarks.
+//
+// Arguments are the action (save or compare), the name of the baseline, then inputs.
+func (c *Command) executeBaseline(ctx context.Context, w io.Writer, cfg *config.Config, args []string) error {
+	const minArgs = 2
+	if len(args) < minArgs || (args[0] != baselineSave && args[0] != baselineCompare) {
+		return fmt.Errorf("invalid baseline command %v: should be %q or %q, followed by a baseline name", args, baselineSave, baselineCompare)
+	}
+	action, name, inputs := args[0], args[1], args[2:]
+
+	p, err := c.parse(ctx, cfg, inputs)
+	if err != nil {
+		return err
+	}
+
+	scenario, err := c.scenarize(cfg, p.Sets())
+	if err != nil {
+		return err
+	}
+
+	if err := c.tagScenario(scenario); err != nil {
+		return err
+	}
+
+	current := baseline.New(name, scenario)
+	file := c.baselineFile(name)
+
+	if action == baselineSave {
+		return c.saveBaseline(file, current)
+	}
+
+	comparison, err := c.compareBaseline(file, current)
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkEnvironments(cfg, failure.StageCompare, comparison.Environment); err != nil {
+		return err
+	}
+
+	// filtering only applies to reports: regressions are still notified and may fail the command
+	shown := comparison.Filter(cfg.Changes)
+	if err := shown.WriteColored(w, c.palette()); err != nil {
+		return err
+	}
+
+	if err := c.annotateRegressions(w, comparison); err != nil {
+		return err
+	}
+
+	if err := c.appendJobSummary(shown.WriteMarkdown); err != nil {
+		return err
+	}
+
+	if err := c.writeJUnit(comparison); err != nil {
+		return err
+	}
+
+	if err := c.notifyRegressions(ctx, comparison); err != nil {
+		return err
+	}
+
+	regressions := comparison.Regressions()
+	if len(regressions) == 0 {
+		return nil
+	}
+
+	c.L.Warn("regressions found against baseline", slog.String("baseline", name), slog.Int("regressions", len(regressions)))
+	if c.FailOnRegression {
+		failures := make([]error, 0, len(regressions))
+		for _, d := range regressions {
+			failures = append(failures, &failure.Error{
+				Stage:     failure.StageCompare,
+				Benchmark: d.Result.String(),
+				Reason:    fmt.Sprintf("regression of %s against baseline %q (%.4g -> %.4g)", baseline.FormatChange(d.Change), name, d.Baseline, d.Value),
+			})
+		}
+
+		return failure.Join(fmt.Sprintf("%d regression(s) found against baseline %q", len(regressions), name), failures...)
+	}
+
+	return nil
+}
+
+// baselineDir is the directory where baseline snapshots are stored.
+func (c *Command) baselineDir() string {
+	if c.BaselineDir == "" {
+		return defaultBaselineDir
+	}
+
+	return c.BaselineDir
+}
+
+// baselineFile is the file where a named baseline snapshot is stored.
+func (c *Command) baselineFile(name string) string {
+	return filepath.Join(c.baselineDir(), config.SanitizeFileName(name)+".json")
+}
+
+func (c *Command) saveBaseline(file string, snapshot baseline.Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil { //nolint:gosec,mnd // usual permissions for an output directory
+		return fmt.Errorf("creating baseline directory: %w", err)
+	}
+
+	wrt, closer, err := getWriter(file, "baseline")
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	if err := snapshot.Write(wrt); err != nil {
+		return fmt.Errorf("writing baseline %q: %w", snapshot.Name, err)
+	}
+
+	c.L.Info("baseline saved", slog.String("baseline", snapshot.Name), slog.String("file", file), slog.Int("results", len(snapshot.Results)))
+
+	return nil
+}
+
+func (c *Command) compareBaseline(file string, current baseline.Snapshot) (baseline.Comparison, error) {
+	rdr, closer, err := getReader(file, "baseline")
+	if err != nil {
+		return baseline.Comparison{}, err
+	}
+	defer closer()
+
+	base, err := baseline.Read(rdr)
+	if err != nil {
+		return baseline.Comparison{}, fmt.Errorf("reading baseline %q: %w", current.Name, err)
+	}
+
+	return baseline.Compare(base, current, c.Threshold/100), nil //nolint:mnd // percentage
+}
+
+// writeJUnit writes the comparison against a baseline as a JUnit XML report, if requested.
+func (c *Command) writeJUnit(comparison baseline.Comparison) error {
+	if c.JUnit == "" {
+		return nil
+	}
+
+	wrt, closer, err := getWriter(c.JUnit, "JUnit")
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	if err := comparison.WriteJUnit(wrt); err != nil {
+		return fmt.Errorf("writing JUnit report: %w", err)
+	}
+
+	c.L.Info("JUnit report written", slog.String("file", c.JUnit))
+
+	return nil
+}
+
+// notifyRegressions posts the regressions found against a baseline to a webhook, if requested.
+func (c *Command) notifyRegressions(ctx context.Context, comparison baseline.Comparison) error {
+	if c.Webhook == "" {
+		return nil
+	}
+
+	opts := []notify.Option{
+		notify.WithLogger(c.logger()),
+	}
+
+	if c.WebhookTemplate != "" {
+		text, err := os.ReadFile(c.WebhookTemplate)
+		if err != nil {
+			return fmt.Errorf("reading webhook template: %w", err)
+		}
+
+		tpl, err := notify.ParseTemplate(filepath.Base(c.WebhookTemplate), string(text))
+		if err != nil {
+			return fmt.Errorf("parsing webhook template %q: %w", c.WebhookTemplate, err)
+		}
+		opts = append(opts, notify.WithTemplate(tpl))
+	}
+
+	return notify.New(c.Webhook, opts...).Notify(ctx, comparison)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/failure"
+)
+
+func TestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	cfg := mustLoadTestConfig(t, testConfig())
+	before := writeBenchmarks(t, dir, "before.txt", 100)
+	after := writeBenchmarks(t, dir, "after.txt", 150)
+
+	cli := &Command{
+		BaselineDir: filepath.Join(dir, "baselines"),
+		Threshold:   defaultThreshold,
+		L:           newTestLogger(),
+	}
+	ctx := context.Background()
+
+	require.NoError(t, cli.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{baselineSave, "main", before}))
+	_, err := os.Stat(filepath.Join(dir, "baselines", "main.json"))
+	require.NoError(t, err)
+
+	t.Run("should compare without regression", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, cli.executeBaseline(ctx, &buf, cfg, []string{baselineCompare, "main", before}))
+		assert.Contains(t, buf.String(), "Regressions: 0\n")
+	})
+
+	t.Run("should report regressions", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, cli.executeBaseline(ctx, &buf, cfg, []string{baselineCompare, "main", after}))
+		assert.Contains(t, buf.String(), "greater/reflect/int (nsPerOp)")
+		assert.Contains(t, buf.String(), "+50.0%")
+		assert.Contains(t, buf.String(), "Regressions: 1\n")
+	})
+
+	t.Run("should fail on regressions", func(t *testing.T) {
+		failing := *cli
+		failing.FailOnRegression = true
+
+		err := failing.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{baselineCompare, "main", after})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `1 regression(s) found against baseline "main"`)
+
+		details := failure.Details(err)
+		require.Len(t, details, 1)
+		assert.EqualT(t, failure.StageCompare, details[0].Stage)
+		assert.EqualT(t, "greater/reflect/int (nsPerOp)", details[0].Benchmark)
+		assert.StringContainsT(t, details[0].Reason, "+50.0%")
+	})
+
+	t.Run("should write a JUnit report", func(t *testing.T) {
+		junit := *cli
+		junit.JUnit = filepath.Join(dir, "junit.xml")
+
+		require.NoError(t, junit.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{baselineCompare, "main", after}))
+
+		content, err := os.ReadFile(junit.JUnit)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `failures="1"`)
+	})
+
+	t.Run("should notify a webhook", func(t *testing.T) {
+		var received []byte
+		server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			received, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		template := filepath.Join(dir, "payload.tpl")
+		require.NoError(t, os.WriteFile(template, []byte(`{"count": {{ len .Regressions }}}`), 0o600))
+
+		notified := *cli
+		notified.Webhook = server.URL
+		notified.WebhookTemplate = template
+
+		require.NoError(t, notified.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{baselineCompare, "main", after}))
+		assert.JSONEq(t, `{"count": 1}`, string(received))
+	})
+
+	t.Run("should fail on unknown baseline", func(t *testing.T) {
+		require.Error(t, cli.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{baselineCompare, "other", after}))
+	})
+
+	t.Run("should fail on invalid command", func(t *testing.T) {
+		require.Error(t, cli.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{baselineSave}))
+		require.Error(t, cli.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{"delete", "main"}))
+	})
+}
+
+func TestExecuteBaseline(t *testing.T) {
+	dir := t.TempDir()
+	cli := &Command{
+		Config:      writeTestConfig(t, testConfig()),
+		BaselineDir: dir,
+		L:           newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute(baselineCommand, baselineSave, "release/v1", writeBenchmarks(t, dir, "bench.txt", 100)))
+
+	_, err := os.Stat(filepath.Join(dir, "release-v1.json"))
+	require.NoError(t, err)
+}
+
+// writeBenchmarks writes a benchmark output in text format, with a configurable timing for the reflect version.
+func writeBenchmarks(t *testing.T, dir, name string, reflectNsPerOp int) string {
+	t.Helper()
+
+	content := "goos: linux\n" +
+		"BenchmarkGreater/reflect/int-16   1000   " + strconv.Itoa(reflectNsPerOp) + " ns/op   64 B/op   2 allocs/op\n" +
+		"BenchmarkGreater/generic/int-16   1000   10 ns/op   0 B/op   0 allocs/op\n"
+
+	file := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+	return file
+}
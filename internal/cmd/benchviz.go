@@ -3,20 +3,32 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"html/template"
+	"io"
 	"log"
 	"log/slog"
+	"maps"
 	"os"
 	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/fredbi/benchviz/internal/chart"
+	"github.com/fredbi/benchviz/internal/color"
 	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/export"
+	"github.com/fredbi/benchviz/internal/failure"
 	"github.com/fredbi/benchviz/internal/image"
+	"github.com/fredbi/benchviz/internal/model"
 	"github.com/fredbi/benchviz/internal/organizer"
 	"github.com/fredbi/benchviz/internal/parser"
+	"github.com/fredbi/benchviz/internal/profiling"
 )
 
 // Command holds command line flags and executes the benchviz command.
@@ -31,15 +43,85 @@ import (
 // All other invoked functionalities deal with streams ([io.Reader],[io.Writer]).
 // Exception the benchmark parser may collect several files directly.
 type Command struct {
-	Config         string
-	OutputFile     string
-	IsJSON         bool
-	Environment    string
-	Report         bool
-	GenerateConfig bool
-	Png            bool
-	IsStrict       bool
-	L              *slog.Logger
+	Config           string
+	OutputFile       string
+	IsJSON           bool
+	Format           string
+	Plugin           string
+	Environment      string
+	EnvFiles         []string
+	Meta             []string
+	Report           bool
+	ReportFormat     string
+	ReportOutput     string
+	GenerateConfig   bool
+	FromReport       bool
+	CompareFiles     bool
+	Png              bool
+	Open             bool
+	Gzip             bool
+	Minify           bool
+	DebugBrowser     bool
+	OutputTemplate   string
+	Template         string
+	Manifest         string
+	DryRun           bool
+	Lint             bool
+	SkipPreflight    bool
+	KeepZeroMetrics  bool
+	BaselineDir      string
+	Threshold        float64
+	FailOnRegression bool
+	Only             string
+	MinChange        float64
+	GHA              bool
+	JUnit            string
+	Webhook          string
+	WebhookTemplate  string
+	Exports          []string
+	Exporters        []string
+	SQLite           string
+	Branch           string
+	KeepRuns         int
+	KeepDays         int
+	Benchmark        string
+	Since            string
+	HistoryFormat    string
+	Addr             string
+	ReloadInterval   time.Duration
+	CacheDir         string
+	CPUProfile       string
+	MemProfile       string
+	Trace            string
+	Strict           string
+	Inputs           []string
+	Projects         []string
+	Match            string
+	Exclude          string
+	LogLevel         string
+	LogFormat        string
+	ErrorFormat      string
+	Color            string
+	Quiet            bool
+	Verbose          bool
+	Theme            string
+	Chart            string
+	Orientation      string
+	Scale            string
+	Title            string
+	Legend           string
+	Categories       []string
+	Metrics          []string
+	L                *slog.Logger
+
+	root      *slog.Logger
+	progress  io.Writer
+	artifacts []artifact
+	page      *template.Template
+	inputs    []string        // input files, when not parsed (e.g. merged scenarios)
+	projects  []project       // projects of a dashboard, loaded from -project
+	metadata  *chart.Metadata // metadata of the run, embedded in rendered pages
+	timings   *timings        // time spent in each phase of the run, when executed
 }
 
 // NewCommand builds a CLI command with registered flags and an injected logger.
@@ -54,13 +136,38 @@ func NewCommand() *Command {
 	return cli
 }
 
-// Parse command line flags and arguments.
-func (*Command) Parse() error {
-	return flag.CommandLine.Parse(os.Args[1:])
+// Parse command line flags and arguments, then sets up logging.
+//
+// Unless --quiet is set, the progress of parsing is reported when stderr is a terminal.
+func (c *Command) Parse() error {
+	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+		return err
+	}
+
+	if !c.Quiet && isTerminal(os.Stderr) {
+		c.progress = os.Stderr
+	}
+
+	if err := c.checkErrorFormat(); err != nil {
+		return err
+	}
+
+	if err := c.checkColor(); err != nil {
+		return err
+	}
+
+	return c.setupLogger(os.Stderr)
 }
 
 // Fatalf logs an error message then exits. The output is spewed on both stderr and the structured logger output.
+//
+// With --error-format=json, the error is only reported on stderr, as JSON.
 func (c *Command) Fatalf(err error) {
+	if strings.EqualFold(c.ErrorFormat, errorFormatJSON) {
+		_ = c.writeError(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	c.L.Error(err.Error())
 	log.Fatalf("%v", err)
 }
@@ -68,16 +175,69 @@ func (c *Command) Fatalf(err error) {
 // Execute the CLI with flags and extra arguments.
 //
 // If no argument is passed, command line arguments (i.e. [os.Args]) are used.
-func (c *Command) Execute(args ...string) error {
+//
+// When the first argument is "run", the remaining arguments are passed to "go test"
+// and its JSON output is parsed directly, instead of reading input files.
+//
+// When the first arguments are "config show", the effective configuration is printed.
+//
+// When the first argument is "site", a static website is generated from the history of baseline snapshots.
+//
+// When the first arguments are "baseline save NAME" or "baseline compare NAME", a snapshot of the
+// organized input benchmarks is saved or compared against.
+//
+// When the first arguments are "history prune", the runs stored in the database set with -sqlite are pruned.
+// With "history show", the stored values of a benchmark are printed over time.
+//
+// When the first argument is "merge", scenarios exported as JSON are merged and rendered as a single scenario.
+//
+// When the first argument is "serve", the input benchmarks are served over HTTP as an HTML page and a JSON API.
+//
+// When the first argument is "quick", the input benchmarks are rendered with a generated config, without a config file.
+//
+// With -cpuprofile, -memprofile or -trace, the execution of benchviz itself is profiled.
+func (c *Command) Execute(args ...string) (err error) {
+	profiler, err := profiling.Start(profiling.Files{
+		CPU:    c.CPUProfile,
+		Memory: c.MemProfile,
+		Trace:  c.Trace,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, profiler.Stop())
+	}()
+
+	return c.execute(args...)
+}
+
+func (c *Command) execute(args ...string) error {
 	if args == nil { // passing explicit args allows for testing Execute without altering [os.Args]
 		args = c.args()
 	}
-	if len(args) == 0 { // no file is provided: assume stdin
-		args = append(args, "-")
+
+	ctx := context.Background()
+	c.timings = newTimings()
+
+	if len(args) > 0 && args[0] == configCommand {
+		return c.executeConfig(os.Stdout, args[1:])
+	}
+
+	if len(args) > 0 && args[0] == siteCommand {
+		return c.executeSite(args[1:])
 	}
 
 	if c.GenerateConfig {
-		return c.generateConfig(args)
+		return c.generateConfig(ctx, args)
+	}
+
+	if c.CompareFiles {
+		return c.compareFiles(ctx, args)
+	}
+
+	if len(args) > 0 && args[0] == quickCommand {
+		return c.executeQuick(ctx, args[1:])
 	}
 
 	cfg, cleanup, err := c.prepareConfig()
@@ -86,57 +246,165 @@ func (c *Command) Execute(args ...string) error {
 	}
 	defer cleanup()
 
+	if len(args) > 0 && args[0] == baselineCommand {
+		return c.executeBaseline(ctx, os.Stdout, cfg, args[1:])
+	}
+
+	if len(args) > 0 && args[0] == historyCommand {
+		return c.executeHistory(ctx, os.Stdout, cfg, args[1:])
+	}
+
+	if len(args) > 0 && args[0] == mergeCommand {
+		return c.executeMerge(ctx, cfg, args[1:])
+	}
+
+	if len(args) > 0 && args[0] == serveCommand {
+		return c.executeServe(ctx, cfg, args[1:])
+	}
+
 	if c.Report {
 		// just want to report about the content of the benchmark files
-		return c.report(cfg, args)
+		return c.report(ctx, os.Stdout, cfg, args)
+	}
+
+	if c.Lint {
+		// just want to check the config against sample benchmarks
+		p, err := c.parse(ctx, cfg, args)
+		if err != nil {
+			return failure.WithStage(failure.StageParse, err)
+		}
+
+		return c.lint(os.Stdout, cfg, p.Sets())
 	}
 
-	// 1. parse benchmark parses input benchmark files and build a chart page
-	htmlRenderer, err := buildPage(cfg, args)
+	// 1. parse input benchmarks and organize them, possibly from the cache
+	p, scenario, err := c.load(ctx, cfg, args)
 	if err != nil {
 		return err
 	}
 
+	return failure.WithStage(failure.StageRender, c.render(ctx, cfg, p, scenario))
+}
+
+// render produces all the requested outputs for a scenario.
+//
+// The parser that produced the scenario is used to report about inputs in an output directory. It may be nil.
+func (c *Command) render(ctx context.Context, cfg *config.Config, p *parser.BenchmarkParser, scenario *model.Scenario) error {
+	if err := c.tagScenario(scenario); err != nil {
+		return err
+	}
+
+	if c.DryRun {
+		// just want to know what would be rendered
+		return c.dryRun(ctx, os.Stdout, cfg, scenario)
+	}
+
+	if cfg.Outputs.Directory != "" {
+		if err := os.MkdirAll(cfg.Outputs.Directory, 0o755); err != nil { //nolint:gosec,mnd // usual permissions for an output directory
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+
+	c.resolveMetadata(ctx, cfg, p)
+	stop := c.measure(phaseChart)
+	htmlRenderer := c.newPage(cfg, scenario)
+
 	// 2. render the page as HTML, possibly to stdout, possibly to temp file
-	htmlWriter, htmlCloser, err := getWriter(cfg.Outputs.HTMLFile, "HTML")
+	err := writeHTML(cfg, cfg.Outputs.HTMLFile, func(w io.Writer) error {
+		return c.renderPage(w, htmlRenderer, scenario)
+	})
+	stop()
 	if err != nil {
+		return fmt.Errorf("rendering page: %w", err)
+	}
+
+	if !cfg.Outputs.IsTemp {
+		c.produced(artifactHTML, cfg.Outputs.HTMLFile)
+	}
+
+	if cfg.Outputs.PngFile != "" {
+		// 3. convert the HTML page to a PNG image, possibly to stdout
+		stop := c.measure(phaseScreenshot)
+		c.slept(screenshotSleep(cfg))
+		err := c.renderImage(ctx, cfg, cfg.Outputs.HTMLFile, cfg.Outputs.PngFile)
+		stop()
+		if err != nil {
+			return err
+		}
+		c.produced(artifactPNG, cfg.Outputs.PngFile)
+	}
+
+	// 4. produce extra outputs
+	if err := c.renderDirectory(ctx, cfg, p, scenario); err != nil {
 		return err
 	}
 
-	if err := htmlRenderer.Render(htmlWriter); err != nil {
-		htmlCloser()
-		return fmt.Errorf("rendering page: %w", err)
+	if err := c.exportScenario(ctx, scenario); err != nil {
+		return err
+	}
+
+	if err := c.storeResults(ctx, cfg, p, scenario); err != nil {
+		return err
 	}
 
-	htmlCloser()
+	c.logTimings()
 
-	if cfg.Outputs.PngFile == "" {
-		// html only: we're done
-		return nil
+	if err := c.writeManifest(cfg); err != nil {
+		return err
 	}
 
-	// 3. convert the HTML page to a PNG image, possibly to stdout
-	htmlReader, htmlCloser, err := getReader(cfg.Outputs.HTMLFile, "HTML")
-	if err != nil {
+	if err := c.appendJobSummary(func(w io.Writer) error {
+		return c.writeScenarioSummary(w, scenario)
+	}); err != nil {
 		return err
 	}
 
-	pngWriter, pngCloser, err := getWriter(cfg.Outputs.PngFile, "PNG")
+	return c.openResult(cfg)
+}
+
+// unanimatedSleep is the time left to the headless browser to draw charts without animation,
+// unless render.screenshot.sleep is configured.
+const unanimatedSleep = 200 * time.Millisecond
+
+// screenshotSleep is the time left to the headless browser to render a page before a screenshot.
+func screenshotSleep(cfg *config.Config) time.Duration {
+	sleep := cfg.Render.Screenshot.SleepDuration()
+	if sleep == 0 && !cfg.Render.IsAnimated(true) {
+		// no need to wait for animations to complete
+		sleep = unanimatedSleep
+	}
+
+	return sleep
+}
+
+// renderImage converts a HTML page to a PNG image.
+//
+// Screenshots are timed by the callers, since they may run concurrently.
+func (c *Command) renderImage(ctx context.Context, cfg *config.Config, htmlFile, pngFile string) error {
+	htmlReader, htmlCloser, err := readHTML(htmlFile)
 	if err != nil {
-		htmlCloser()
 		return err
 	}
+	defer htmlCloser()
 
+	pngWriter, pngCloser, err := getWriter(pngFile, "PNG")
+	if err != nil {
+		return err
+	}
 	defer pngCloser()
 
 	r := image.New(
 		// if not set, the default values are those from package image
 		image.WithHeight(cfg.Render.Screenshot.Height),
 		image.WithWidth(cfg.Render.Screenshot.Width),
-		image.WithSleep(cfg.Render.Screenshot.SleepDuration()),
+		image.WithSleep(screenshotSleep(cfg)),
+		image.WithRetries(cfg.Render.Screenshot.Retries),
+		image.WithBackoff(cfg.Render.Screenshot.BackoffDuration()),
+		image.WithDebug(c.DebugBrowser),
+		image.WithAutoFit(cfg.Render.Screenshot.AutoFit),
+		image.WithLogger(c.logger()),
 	)
 
-	ctx := context.Background()
 	if err = r.Render(ctx, pngWriter, htmlReader); err != nil {
 		return fmt.Errorf("rendering image: %w", err)
 	}
@@ -153,73 +421,243 @@ func (c *Command) registerFlags() {
 	defaults := Command{
 		Config:         "benchviz.yaml",
 		OutputFile:     "-",
+		OutputTemplate: defaultOutputTemplate,
 		Png:            false,
 		IsJSON:         false,
+		Format:         string(parser.FormatGo),
 		Environment:    "",
 		Report:         false,
+		ReportFormat:   string(parser.ReportFormatJSON),
+		HistoryFormat:  historyFormatTable,
+		ReportOutput:   "-",
 		GenerateConfig: false,
-		IsStrict:       false,
+		FromReport:     false,
+		BaselineDir:    defaultBaselineDir,
+		Addr:           defaultAddr,
+		ReloadInterval: defaultReloadInterval,
+		Threshold:      defaultThreshold,
+		Strict:         "",
+		LogLevel:       "info",
+		LogFormat:      logFormatText,
+		ErrorFormat:    errorFormatText,
+		Color:          string(color.ModeAuto),
+		Quiet:          false,
+		Verbose:        false,
 	}
 
 	flag.BoolVar(&c.IsJSON, "json", defaults.IsJSON, "read input from JSON")
+	flag.StringVar(&c.Format, "format", defaults.Format, fmt.Sprintf("input format, one of %v", parser.AllFormats()))
+	flag.StringVar(&c.Plugin, "plugin", defaults.Plugin, "command converting inputs in a custom format to canonical benchmark JSON, e.g. \"python3 convert.py\"")
 	flag.StringVar(&c.Config, "config", defaults.Config, "config file")
 	flag.StringVar(&c.Config, "c", defaults.Config, "config file (shorthand)")
 	flag.StringVar(&c.OutputFile, "output", defaults.OutputFile, "file output or - for standard output")
 	flag.StringVar(&c.OutputFile, "o", defaults.OutputFile, "file output or - for standard output (shorthand)")
+	flag.StringVar(&c.Manifest, "manifest", defaults.Manifest, "write a JSON manifest of all produced artifacts to this file (default: manifest.json in an output directory)")
+	flag.StringVar(&c.OutputTemplate, "output-template", defaults.OutputTemplate,
+		"file name template for outputs produced in an output directory, with placeholders {name}, {category}, {date} and {commit}",
+	)
+	flag.StringVar(&c.Template, "template", defaults.Template, "render HTML pages with this Go template, exposing the scenario and the charts")
 	flag.StringVar(&c.Environment, "environment", defaults.Environment, "environment string")
 	flag.StringVar(&c.Environment, "e", defaults.Environment, "environment string (shorthand)")
+	flag.Var((*stringsFlag)(&c.Meta), "meta", "metadata of the run as key=value, e.g. a feature flag, stored with results and shown on pages (may be repeated)")
+	flag.Var((*stringsFlag)(&c.EnvFiles), "env-file", "environment string for an input file, as file=environment (may be repeated)")
 	flag.BoolVar(&c.Report, "r", defaults.Report, "report about benchmark contents only to standard output, no rendering (shorthand)")
 	flag.BoolVar(&c.Report, "report", defaults.Report, "report benchmark contents only")
+	flag.StringVar(&c.ReportFormat, "report-format", defaults.ReportFormat, fmt.Sprintf("report output format, one of %v", parser.AllReportFormats()))
+	flag.StringVar(&c.ReportOutput, "report-output", defaults.ReportOutput, "report file output or - for standard output")
 	flag.BoolVar(&c.Png, "png", defaults.Png, "enable PNG screenshot output")
-	flag.BoolVar(&c.Png, "strict", defaults.IsStrict, "fails if some benchmark series are omitted by config (default is to warn and skip)")
+	flag.BoolVar(&c.Open, "open", defaults.Open, "open the rendered output in the default browser")
+	flag.BoolVar(&c.Gzip, "gzip", defaults.Gzip, "write HTML outputs gzip-compressed, as .html.gz files (implied by an output file ending with .gz)")
+	flag.BoolVar(&c.Minify, "minify", defaults.Minify, "minify HTML outputs")
+	flag.BoolVar(&c.DebugBrowser, "debug-browser", defaults.DebugBrowser, "log the messages exchanged with the headless browser rendering PNG images")
+	c.Strict = defaults.Strict
+	flag.Var((*strictFlag)(&c.Strict), "strict",
+		"fails if some benchmark series are omitted by config (default is to warn and skip). "+
+			"May be set to a level: functions, metrics or all (same as -strict)",
+	)
+	flag.BoolVar(&c.DryRun, "dry-run", defaults.DryRun, "parse inputs and print what would be rendered, without writing any file")
+	flag.BoolVar(&c.Lint, "lint", defaults.Lint, "check the config against the input benchmarks: report unused and shadowed rules and empty charts")
+	flag.BoolVar(&c.SkipPreflight, "skip-preflight", defaults.SkipPreflight, "skip the quick check of the config against the input benchmarks, run before organizing and rendering")
+	flag.StringVar(&c.BaselineDir, "baseline-dir", defaults.BaselineDir, "directory where baseline snapshots are stored")
+	flag.Float64Var(&c.Threshold, "threshold", defaults.Threshold, "relative change (in percent) beyond which a worse result compared to a baseline is a regression")
+	flag.BoolVar(&c.FailOnRegression, "fail-on-regression", defaults.FailOnRegression, "fail when regressions are found against a baseline")
+	flag.StringVar(&c.Only, "only", defaults.Only, "show only these changes in comparisons and difference charts: all, regressions or improvements")
+	flag.Float64Var(&c.MinChange, "min-change", defaults.MinChange, "show only changes beyond this relative change (in percent) in comparisons and difference charts")
+	flag.BoolVar(&c.GHA, "gha", defaults.GHA, "append a Markdown report to the GitHub Actions job summary, and annotate regressions against a baseline")
+	flag.StringVar(&c.JUnit, "junit", defaults.JUnit, "write the comparison against a baseline to this file as a JUnit XML report")
+	flag.StringVar(&c.Webhook, "webhook", defaults.Webhook, "webhook URL (e.g. Slack) to notify when regressions are found against a baseline")
+	flag.StringVar(&c.WebhookTemplate, "webhook-template", defaults.WebhookTemplate, "template file rendering the JSON payload posted to the webhook (default is Slack-compatible)")
+	flag.Var((*stringsFlag)(&c.Exports), "export",
+		fmt.Sprintf("export the organized benchmarks as format=file, with format one of %v or declared with -exporter (may be repeated)", export.Names()),
+	)
+	flag.Var((*stringsFlag)(&c.Exporters), "exporter", "declare an external exporter as name=command, receiving the organized benchmarks as JSON (may be repeated)")
+	flag.StringVar(&c.SQLite, "sqlite", defaults.SQLite, "append the parsed and organized benchmarks to this SQLite database, created if needed")
+	flag.StringVar(&c.Branch, "branch", defaults.Branch, "branch of the runs stored with -sqlite, or managed by the history command (default: the current git branch when storing)")
+	flag.IntVar(&c.KeepRuns, "keep-runs", defaults.KeepRuns, "override the number of most recent runs kept for each branch in the -sqlite database")
+	flag.IntVar(&c.KeepDays, "keep-days", defaults.KeepDays, "override the number of days runs are kept for in the -sqlite database")
+	flag.StringVar(&c.Benchmark, "benchmark", defaults.Benchmark, "function ID of the benchmark shown by the history command")
+	flag.StringVar(&c.Since, "since", defaults.Since, "show only runs since this date (e.g. 2026-01-31) or duration (e.g. 30d, 12h) with the history command")
+	flag.StringVar(&c.HistoryFormat, "history-format", defaults.HistoryFormat, "output format of the history command: table or sparkline")
+	flag.StringVar(&c.CacheDir, "cache-dir", defaults.CacheDir, "cache parsed and organized benchmarks in this directory, and restore them when inputs and config are unchanged")
+	flag.StringVar(&c.Addr, "addr", defaults.Addr, "address the serve command listens on")
+	flag.DurationVar(&c.ReloadInterval, "reload-interval", defaults.ReloadInterval,
+		"interval at which the serve command checks the config and inputs for changes, and reloads pages open in a browser (0 disables live reload)",
+	)
 	flag.BoolVar(&c.GenerateConfig, "generate-config", defaults.GenerateConfig, "generate a naive config file from benchmark data and exit")
+	flag.BoolVar(&c.CompareFiles, "compare-files", defaults.CompareFiles,
+		"compare input files without a config: each input file is a version, named by its label or file name",
+	)
+	flag.BoolVar(&c.FromReport, "from-report", defaults.FromReport, "with -generate-config, read inputs as reports previously produced with -report")
+	flag.BoolVar(&c.KeepZeroMetrics, "keep-zero-metrics", defaults.KeepZeroMetrics,
+		"retain metrics measured with a zero value (e.g. 0 allocs/op) in reports and generated configs, instead of considering them missing",
+	)
+	flag.StringVar(&c.Match, "match", defaults.Match, "regexp to retain only matching benchmarks at parse time")
+	flag.StringVar(&c.Exclude, "exclude", defaults.Exclude, "regexp to drop matching benchmarks at parse time")
+	flag.Var((*stringsFlag)(&c.Inputs), "input", "input file, optionally labeled as file:label=value (may be repeated)")
+	flag.Var((*stringsFlag)(&c.Inputs), "i", "input file, optionally labeled as file:label=value (shorthand)")
+	flag.Var((*stringsFlag)(&c.Projects), "project",
+		"project of a dashboard as config=input[,input...]: projects are rendered on a single page, with a section per project (may be repeated)",
+	)
+	flag.StringVar(&c.LogLevel, "log-level", defaults.LogLevel, "log level, one of [debug info warn error]")
+	flag.StringVar(&c.LogFormat, "log-format", defaults.LogFormat, fmt.Sprintf("log format, one of [%s %s]", logFormatText, logFormatJSON))
+	flag.StringVar(&c.Color, "color", defaults.Color,
+		fmt.Sprintf("color the textual report, lint and compare outputs, one of %v (auto honors NO_COLOR)", color.AllModes()),
+	)
+	flag.StringVar(&c.ErrorFormat, "error-format", defaults.ErrorFormat,
+		fmt.Sprintf("format of the error reported on failure, one of [%s %s]", errorFormatText, errorFormatJSON),
+	)
+	flag.BoolVar(&c.Quiet, "quiet", defaults.Quiet, "only log warnings and errors")
+	flag.BoolVar(&c.Verbose, "verbose", defaults.Verbose, "enable debug logs, including benchmark matching decisions")
+	flag.BoolVar(&c.Verbose, "v", defaults.Verbose, "enable debug logs (shorthand)")
+	flag.StringVar(&c.CPUProfile, "cpuprofile", defaults.CPUProfile, "write a CPU profile of benchviz to this file, for go tool pprof")
+	flag.StringVar(&c.MemProfile, "memprofile", defaults.MemProfile, "write a memory profile of benchviz to this file on exit, for go tool pprof")
+	flag.StringVar(&c.Trace, "trace", defaults.Trace, "write an execution trace of benchviz to this file, for go tool trace")
+	flag.StringVar(&c.Theme, "theme", defaults.Theme, "override the chart theme set in config")
+	flag.StringVar(&c.Chart, "chart", defaults.Chart, "override the chart type set in config: barchart or linechart")
+	flag.StringVar(&c.Orientation, "orientation", defaults.Orientation, "override the bar orientation set in config: vertical or horizontal")
+	flag.StringVar(&c.Scale, "scale", defaults.Scale, "override the Y-axis scale set in config: auto or log")
+	flag.StringVar(&c.Title, "title", defaults.Title, "override the chart title set in config")
+	flag.StringVar(&c.Legend, "legend", defaults.Legend, "override the legend position set in config: none, bottom, top, left or right")
+	flag.Var((*stringsFlag)(&c.Categories), "category", "render only this category ID (may be repeated)")
+	flag.Var((*stringsFlag)(&c.Metrics), "metric", "render only this metric ID (may be repeated)")
+}
+
+// stringsFlag is a [flag.Value] that collects the values of a repeated flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	if f == nil {
+		return ""
+	}
+
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+// strictFlag is a [flag.Value] for a strictness level, that may also be used as a boolean flag.
+//
+// "-strict" is equivalent to "-strict=all".
+type strictFlag string
+
+func (f *strictFlag) String() string {
+	if f == nil {
+		return ""
+	}
+
+	return string(*f)
+}
+
+func (f *strictFlag) Set(value string) error {
+	if _, err := config.ParseStrictLevel(value); err != nil {
+		return err
+	}
+	*f = strictFlag(value)
+
+	return nil
+}
+
+// IsBoolFlag allows the flag to be passed without a value.
+func (*strictFlag) IsBoolFlag() bool {
+	return true
 }
 
 func (c *Command) prepareConfig() (cfg *config.Config, cleanup func(), err error) {
-	cfg, err = config.Load(c.Config)
+	if len(c.Projects) > 0 {
+		cfg, err = c.loadProjects()
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		cfg, err = config.Load(c.Config)
+		if err != nil {
+			return nil, nil, &failure.Error{Stage: failure.StageConfig, File: c.Config, Reason: err.Error(), Err: fmt.Errorf("loading config: %w", err)}
+		}
+	}
+
+	cleanup, err = c.prepareOutputs(cfg)
 	if err != nil {
-		return nil, nil, fmt.Errorf("loading config: %w", err)
+		return nil, nil, err
 	}
 
+	return cfg, cleanup, nil
+}
+
+// prepareOutputs applies CLI flags to a config. The returned cleanup function removes temporary outputs.
+func (c *Command) prepareOutputs(cfg *config.Config) (cleanup func(), err error) {
 	if err = c.setConfig(cfg); err != nil {
-		return nil, nil, fmt.Errorf("preparing config: %w", err)
+		return nil, failure.WithStage(failure.StageConfig, fmt.Errorf("preparing config: %w", err))
 	}
 
 	if cfg.Outputs.IsTemp && !c.Report {
-		cleanup = func() {
+		return func() {
 			_ = os.Remove(cfg.Outputs.HTMLFile)
-		}
-
-		return cfg, cleanup, err
+		}, nil
 	}
 
-	return cfg, func() {}, err
+	return func() {}, nil
 }
 
 // apply CLI flags overrides to YAML config.
 func (c *Command) setConfig(cfg *config.Config) error {
-	cfg.IsJSON = c.IsJSON
-	if c.IsStrict {
-		cfg.IsStrict = true
+	if err := c.overrideConfig(cfg); err != nil {
+		return err
 	}
 
-	if c.Environment != "" {
-		cfg.Environment = c.Environment
-	}
+	cfg.Outputs.Compress = c.Gzip || strings.HasSuffix(c.OutputFile, gzipExt)
+	cfg.Outputs.Minify = c.Minify
 
-	if c.OutputFile != "" && c.OutputFile != "-" {
+	switch {
+	case isOutputDir(c.OutputFile):
+		// an output directory is defined: the main page is rendered as index.html
+		cfg.Outputs.Directory = c.OutputFile
+		page := filepath.Join(c.OutputFile, indexPage+".html")
+		cfg.Outputs.HTMLFile = compressedFile(cfg, page)
+		if c.Png {
+			cfg.Outputs.PngFile = inferImageFile(page)
+		}
+	case c.OutputFile != "" && c.OutputFile != "-":
 		// an outfile is defined: infer the PNG file from the HTML file provided
-		cfg.Outputs.HTMLFile = inferHTMLFile(c.OutputFile)
+		page := inferHTMLFile(strings.TrimSuffix(c.OutputFile, gzipExt))
+		cfg.Outputs.HTMLFile = compressedFile(cfg, page)
 		if c.Png {
-			cfg.Outputs.PngFile = inferImageFile(cfg.Outputs.HTMLFile)
+			cfg.Outputs.PngFile = inferImageFile(page)
 		}
 	}
 
-	if c.Report {
-		// no need to prepare output files since the report is sent to stdout
+	if c.Report || c.DryRun || c.Lint {
+		// no need to prepare output files since the report is sent to stdout, and nothing is written in dry-run mode
 		return nil
 	}
 
+	if c.Gzip && cfg.Outputs.HTMLFile == "" {
+		return errors.New("-gzip requires an output file: HTML sent to standard output or to a temporary file is not compressed")
+	}
+
 	switch {
 	case cfg.Outputs.HTMLFile == "" && cfg.Outputs.PngFile == "":
 		c.L.Info("output sent to standard output as HTML, no PNG image rendered")
@@ -241,43 +679,154 @@ func (c *Command) setConfig(cfg *config.Config) error {
 	return nil
 }
 
-// report produces a report that explores the input benchmarks.
-func (c *Command) report(cfg *config.Config, args []string) error {
-	p := parser.New(cfg, parser.WithParseJSON(cfg.IsJSON))
-	if err := p.ParseFiles(args...); err != nil {
-		return fmt.Errorf("parsing files: %w", err)
+// overrideConfig applies CLI flags overrides to the settings of the YAML config, but not to outputs.
+func (c *Command) overrideConfig(cfg *config.Config) error {
+	cfg.IsJSON = c.IsJSON
+	if c.Strict != "" {
+		level, err := config.ParseStrictLevel(c.Strict)
+		if err != nil {
+			return err
+		}
+		cfg.StrictLevel = level
 	}
 
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", " ")
+	if c.Environment != "" {
+		cfg.Environment = c.Environment
+	}
 
-	return enc.Encode(p.Report())
+	if c.KeepRuns > 0 {
+		cfg.History.KeepRuns = c.KeepRuns
+	}
+
+	if c.KeepDays > 0 {
+		cfg.History.KeepDays = c.KeepDays
+	}
+
+	if c.KeepZeroMetrics {
+		cfg.KeepZeroMetrics = true
+	}
+
+	if c.Only != "" {
+		only := config.ChangeKind(c.Only)
+		if !only.IsValid() {
+			return fmt.Errorf("invalid -only=%s (should be one of %v)",
+				c.Only, []config.ChangeKind{config.ChangeAll, config.ChangeRegressions, config.ChangeImprovements},
+			)
+		}
+		cfg.Changes.Only = only
+	}
+
+	if c.MinChange > 0 {
+		cfg.Changes.MinChange = c.MinChange
+	}
+
+	return c.setRender(&cfg.Render)
 }
 
-// generateConfig parses benchmark files using defaults, generates a config, and writes it.
-func (c *Command) generateConfig(args []string) error {
-	cfg, err := config.LoadDefaults()
+// setRender applies CLI flags overrides to the render settings of the YAML config.
+//
+// Overrides are applied after the config is validated: they are checked here.
+func (c *Command) setRender(render *config.Rendering) error {
+	if c.Theme != "" {
+		render.Theme = c.Theme
+	}
+
+	if c.Chart != "" {
+		chartType := config.ChartType(c.Chart)
+		if !chartType.IsValid() {
+			return fmt.Errorf("invalid -chart=%s (should be one of %v)", c.Chart, []config.ChartType{config.ChartTypeBar, config.ChartTypeLine})
+		}
+		render.Chart = chartType
+	}
+
+	if c.Orientation != "" {
+		orientation := config.Orientation(c.Orientation)
+		if !orientation.IsValid() {
+			return fmt.Errorf("invalid -orientation=%s (should be one of %v)",
+				c.Orientation, []config.Orientation{config.OrientationVertical, config.OrientationHorizontal},
+			)
+		}
+		render.Orientation = orientation
+	}
+
+	if c.Scale != "" {
+		scale := config.Scale(c.Scale)
+		if !scale.IsValid() {
+			return fmt.Errorf("invalid -scale=%s (should be one of %v)", c.Scale, []config.Scale{config.ScaleAuto, config.ScaleLog})
+		}
+		render.Scale = scale
+	}
+
+	if c.Title != "" {
+		render.Title = c.Title
+	}
+
+	if c.Legend != "" {
+		legend := config.LegendPosition(c.Legend)
+		if !legend.IsValid() {
+			return fmt.Errorf("invalid -legend=%s (should be one of %v)",
+				c.Legend, []config.LegendPosition{
+					config.LegendPositionNone, config.LegendPositionBottom, config.LegendPositionTop,
+					config.LegendPositionLeft, config.LegendPositionRight,
+				},
+			)
+		}
+		render.Legend = legend
+	}
+
+	return nil
+}
+
+// report produces a report that explores the input benchmarks, written to w unless a report output file is set.
+func (c *Command) report(ctx context.Context, w io.Writer, cfg *config.Config, args []string) error {
+	format := parser.ReportFormat(c.ReportFormat)
+	if format == "" {
+		format = parser.ReportFormatJSON
+	}
+
+	if !format.IsValid() {
+		return fmt.Errorf("invalid report format %q: should be one of %v", format, parser.AllReportFormats())
+	}
+
+	p, err := c.parse(ctx, cfg, args)
 	if err != nil {
-		return fmt.Errorf("loading defaults: %w", err)
+		return err
 	}
-	cfg.IsJSON = c.IsJSON
 
-	p := parser.New(cfg, parser.WithParseJSON(cfg.IsJSON))
-	if err := p.ParseFiles(args...); err != nil {
-		return fmt.Errorf("parsing files: %w", err)
+	// audit how benchmarks are matched by the config rules
+	opts, err := c.organizerOptions(cfg)
+	if err != nil {
+		return err
+	}
+	report := organizer.MatchingReport{
+		ParsingReport: p.Report(),
+		Matching:      organizer.New(cfg, opts...).Matching(p.Sets()),
 	}
 
-	report := p.Report()
+	if c.ReportOutput == "" || c.ReportOutput == "-" {
+		return report.WriteColored(w, format, c.palette())
+	}
 
-	metricNames := make([]config.MetricName, 0, len(report.Metrics))
-	for _, m := range report.Metrics {
-		metricNames = append(metricNames, m.Metric)
+	reportWriter, reportCloser, err := getWriter(c.ReportOutput, "report")
+	if err != nil {
+		return err
 	}
+	defer reportCloser()
 
-	generated := config.Generate(config.GenerateInput{
-		Functions: report.Functions,
-		Metrics:   metricNames,
-	})
+	return report.Write(reportWriter, format)
+}
+
+// generateConfig parses benchmark files using defaults, generates a config, and writes it.
+//
+// With FromReport, the inputs are reports produced by a previous run with [Command.Report],
+// so the original benchmark results are not needed.
+func (c *Command) generateConfig(ctx context.Context, args []string) error {
+	input, err := c.generateInput(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	generated := config.Generate(input)
 
 	outPath := c.Config
 	f, err := os.Create(outPath)
@@ -295,6 +844,104 @@ func (c *Command) generateConfig(args []string) error {
 	return nil
 }
 
+// generateInput collects benchmark functions and metrics, either from benchmark results or from reports.
+func (c *Command) generateInput(ctx context.Context, args []string) (config.GenerateInput, error) {
+	var reports []parser.ParsingReport
+
+	if c.FromReport {
+		var err error
+		reports, err = readReports(append(slices.Clone(c.Inputs), args...))
+		if err != nil {
+			return config.GenerateInput{}, err
+		}
+	} else {
+		cfg, err := config.LoadDefaults()
+		if err != nil {
+			return config.GenerateInput{}, fmt.Errorf("loading defaults: %w", err)
+		}
+		cfg.IsJSON = c.IsJSON
+		cfg.KeepZeroMetrics = c.KeepZeroMetrics
+
+		p, err := c.parse(ctx, cfg, args)
+		if err != nil {
+			return config.GenerateInput{}, err
+		}
+		reports = append(reports, p.Report())
+	}
+
+	return generateInputFromReports(reports), nil
+}
+
+// generateInputFromReports collects the benchmark functions and metrics found in parsing reports.
+func generateInputFromReports(reports []parser.ParsingReport) config.GenerateInput {
+	var input config.GenerateInput
+	seenFunctions := make(map[string]struct{})
+	seenMetrics := make(map[config.MetricName]struct{})
+
+	for _, report := range reports {
+		for _, function := range report.Functions {
+			if _, seen := seenFunctions[function]; seen {
+				continue
+			}
+			seenFunctions[function] = struct{}{}
+			input.Functions = append(input.Functions, function)
+		}
+
+		for _, m := range report.Metrics {
+			if _, seen := seenMetrics[m.Metric]; seen {
+				continue
+			}
+			seenMetrics[m.Metric] = struct{}{}
+			input.Metrics = append(input.Metrics, m.Metric)
+		}
+	}
+	slices.Sort(input.Functions)
+
+	return input
+}
+
+// readReports reads the reports produced by [Command.Report], from files or standard input.
+func readReports(files []string) ([]parser.ParsingReport, error) {
+	if len(files) == 0 { // no file is provided: assume stdin
+		files = append(files, "-")
+	}
+
+	reports := make([]parser.ParsingReport, 0, len(files))
+	for _, file := range files {
+		report, err := readReport(file)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func readReport(file string) (parser.ParsingReport, error) {
+	if file == "-" {
+		report, err := parser.ReadReport(os.Stdin)
+		if err != nil {
+			return report, fmt.Errorf("reading report from standard input: %w", err)
+		}
+
+		return report, nil
+	}
+
+	rdr, closer, err := getReader(file, "report")
+	if err != nil {
+		return parser.ParsingReport{}, err
+	}
+	defer closer()
+
+	report, err := parser.ReadReport(rdr)
+	if err != nil {
+		return report, fmt.Errorf("reading report %q: %w", file, err)
+	}
+
+	return report, nil
+}
+
 func getReader(file, kind string) (rdr *os.File, cleanup func(), err error) {
 	rdr, err = os.Open(file)
 	if err != nil {
@@ -321,25 +968,264 @@ func getWriter(file, kind string) (wrt *os.File, cleanup func(), err error) {
 	return wrt, cleanup, nil
 }
 
-func buildPage(cfg *config.Config, args []string) (*chart.Page, error) {
-	// 1. parse input benchmarks passed as CLI args
-	p := parser.New(cfg, parser.WithParseJSON(cfg.IsJSON))
-	if err := p.ParseFiles(args...); err != nil {
-		return nil, fmt.Errorf("parsing files: %w", err)
+// parse input benchmarks, either from input files passed as CLI args or from a "go test" run.
+func (c *Command) parse(ctx context.Context, cfg *config.Config, args []string) (*parser.BenchmarkParser, error) {
+	opts, err := c.parserOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) > 0 && args[0] == runCommand {
+		return c.runBenchmarks(ctx, cfg, args[1:], opts...)
+	}
+
+	args = append(slices.Clone(c.Inputs), args...)
+	if len(args) == 0 { // no file is provided: assume stdin
+		args = append(args, "-")
+	}
+
+	return parseInputs(cfg, args, opts...)
+}
+
+// parserOptions builds the parser options from CLI flags.
+func (c *Command) parserOptions() ([]parser.Option, error) {
+	opts := []parser.Option{
+		parser.WithLogger(c.logger()),
+	}
+
+	if c.progress != nil {
+		opts = append(opts, parser.WithProgress(newProgressReporter(c.progress).report))
+	}
+
+	if len(c.EnvFiles) > 0 {
+		environments, err := splitEnvironments(c.EnvFiles)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, parser.WithEnvironments(environments))
+	}
+
+	if c.Match != "" {
+		match, err := regexp.Compile(c.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match regexp %q: %w", c.Match, err)
+		}
+		opts = append(opts, parser.WithMatch(match))
+	}
+
+	if c.Exclude != "" {
+		exclude, err := regexp.Compile(c.Exclude)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude regexp %q: %w", c.Exclude, err)
+		}
+		opts = append(opts, parser.WithExclude(exclude))
 	}
 
-	// 2. re-organize the data series according to the configuration
-	o := organizer.New(cfg)
-	scenario, err := o.Scenarize(p.Sets())
+	if c.Format != "" {
+		format := parser.Format(c.Format)
+		if !format.IsValid() {
+			return nil, fmt.Errorf("invalid input format %q: should be one of %v", c.Format, parser.AllFormats())
+		}
+		opts = append(opts, parser.WithFormat(format))
+	}
+
+	if command := strings.Fields(c.Plugin); len(command) > 0 {
+		opts = append(opts, parser.WithPlugin(command...))
+	}
+
+	return opts, nil
+}
+
+func (c *Command) buildPage(cfg *config.Config, sets []parser.Set) (*chart.Page, error) {
+	// 1. re-organize the data series according to the configuration
+	scenario, err := c.scenarize(cfg, sets)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. build a page with this visualization scenario
+	return c.newPage(cfg, scenario), nil
+}
+
+// scenarize re-organizes the parsed data series according to the configuration.
+func (c *Command) scenarize(cfg *config.Config, sets []parser.Set) (*model.Scenario, error) {
+	opts, err := c.organizerOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	o := organizer.New(cfg, opts...)
+	scenario, err := o.Scenarize(sets)
 	if err != nil {
 		return nil, fmt.Errorf("building scenario: %w", err)
 	}
 
-	// 3. build a page with this visualization scenario
-	builder := chart.New(cfg, scenario)
-	page := builder.BuildPage()
+	return scenario, nil
+}
+
+// newPage builds a chart page for a visualization scenario.
+//
+// Pages embed the metadata of the run, once resolved.
+// With -project, the page is a dashboard with a section per project.
+func (c *Command) newPage(cfg *config.Config, scenario *model.Scenario, extra ...chart.BuilderOption) *chart.Page {
+	opts := []chart.BuilderOption{
+		chart.WithLogger(c.logger()),
+		chart.WithScreenshot(cfg.Outputs.PngFile != ""),
+	}
+	if c.metadata != nil {
+		opts = append(opts, chart.WithMetadata(*c.metadata))
+	}
+	opts = append(opts, extra...)
+
+	if len(c.projects) > 0 {
+		return c.dashboardPage(scenario, opts)
+	}
+
+	return chart.New(cfg, scenario, opts...).BuildPage()
+}
+
+// renderPage renders a chart page as HTML, with the page template set by -template, if any.
+func (c *Command) renderPage(w io.Writer, page *chart.Page, scenario *model.Scenario) error {
+	if c.Template == "" {
+		return page.Render(w)
+	}
+
+	if c.page == nil {
+		tmpl, err := chart.ParseTemplate(c.Template)
+		if err != nil {
+			return err
+		}
+		c.page = tmpl
+	}
+
+	return page.RenderTemplate(w, c.page, scenario)
+}
+
+// organizerOptions builds the organizer options from CLI flags.
+//
+// Categories and metrics selected from the CLI must be defined in the config.
+func (c *Command) organizerOptions(cfg *config.Config) ([]organizer.Option, error) {
+	opts := []organizer.Option{
+		organizer.WithLogger(c.logger()),
+	}
+
+	if len(c.Categories) > 0 {
+		for _, id := range c.Categories {
+			if !slices.ContainsFunc(cfg.Categories, func(category config.Category) bool { return category.ID == id }) {
+				return nil, fmt.Errorf("unknown category %q: not defined in config", id)
+			}
+		}
+
+		opts = append(opts, organizer.WithCategoryFilter(func(category config.Category) bool {
+			return slices.Contains(c.Categories, category.ID)
+		}))
+	}
+
+	if len(c.Metrics) > 0 {
+		for _, id := range c.Metrics {
+			if _, ok := cfg.GetMetric(config.MetricName(id)); !ok {
+				return nil, fmt.Errorf("unknown metric %q: not defined in config", id)
+			}
+		}
+
+		opts = append(opts, organizer.WithMetricFilter(func(metric config.MetricName) bool {
+			return slices.Contains(c.Metrics, string(metric))
+		}))
+	}
+
+	return opts, nil
+}
+
+// parseInputs parses the input benchmark files passed as CLI args.
+//
+// Each input may be labeled with a ":label=value" suffix, e.g. "old.txt:label=v1.2".
+func parseInputs(cfg *config.Config, args []string, opts ...parser.Option) (*parser.BenchmarkParser, error) {
+	files, labels := splitLabels(args)
+
+	p := parser.New(cfg, append([]parser.Option{
+		parser.WithParseJSON(cfg.IsJSON),
+		parser.WithZeroMetrics(cfg.KeepZeroMetrics),
+		parser.WithLabels(labels),
+	}, opts...)...)
+	if err := p.ParseFiles(files...); err != nil {
+		return nil, fmt.Errorf("parsing files: %w", err)
+	}
+
+	return p, nil
+}
+
+// splitEnvironments builds a map of environment strings by input file name, from "file=environment" values.
+func splitEnvironments(values []string) (map[string]string, error) {
+	environments := make(map[string]string, len(values))
+
+	for _, value := range values {
+		file, env, ok := strings.Cut(value, "=")
+		if !ok || file == "" {
+			return nil, fmt.Errorf("invalid environment mapping %q: should be file=environment", value)
+		}
+
+		environments[file] = env
+	}
+
+	return environments, nil
+}
+
+// splitMeta builds a map of metadata from "key=value" values.
+func splitMeta(values []string) (map[string]string, error) {
+	meta := make(map[string]string, len(values))
+
+	for _, value := range values {
+		key, v, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid metadata %q: should be key=value", value)
+		}
+
+		meta[key] = v
+	}
+
+	return meta, nil
+}
+
+// tagScenario sets the metadata of the run on the scenario, overriding the metadata of merged scenarios.
+func (c *Command) tagScenario(scenario *model.Scenario) error {
+	if len(c.Meta) == 0 {
+		return nil
+	}
+
+	meta, err := splitMeta(c.Meta)
+	if err != nil {
+		return err
+	}
+
+	if scenario.Meta == nil {
+		scenario.Meta = make(map[string]string, len(meta))
+	}
+	maps.Copy(scenario.Meta, meta)
+
+	return nil
+}
+
+// splitLabels separates input file names from their optional ":label=value" suffix.
+func splitLabels(args []string) (files []string, labels map[string]string) {
+	const labelSep = ":label="
+	files = make([]string, 0, len(args))
+	labels = make(map[string]string, len(args))
+
+	for _, arg := range args {
+		idx := strings.LastIndex(arg, labelSep)
+		if idx <= 0 {
+			files = append(files, arg)
+
+			continue
+		}
+
+		file := arg[:idx]
+		files = append(files, file)
+		labels[file] = arg[idx+len(labelSep):]
+	}
 
-	return page, nil
+	return files, labels
 }
 
 func inferHTMLFile(base string) string {
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -59,6 +63,70 @@ func TestInferImageFile(t *testing.T) {
 	}
 }
 
+func TestSplitLabels(t *testing.T) {
+	files, labels := splitLabels([]string{
+		"old.txt:label=v1.2",
+		"new.txt:label=main",
+		"plain.txt",
+		"-",
+	})
+
+	assert.Equal(t, []string{"old.txt", "new.txt", "plain.txt", "-"}, files)
+	assert.Equal(t, map[string]string{
+		"old.txt": "v1.2",
+		"new.txt": "main",
+	}, labels)
+}
+
+func TestSplitEnvironments(t *testing.T) {
+	environments, err := splitEnvironments([]string{
+		"run_a.txt=AMD 5800X",
+		"run_b.txt=linux arm64 cpu: Apple M2=x",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"run_a.txt": "AMD 5800X",
+		"run_b.txt": "linux arm64 cpu: Apple M2=x",
+	}, environments)
+
+	_, err = splitEnvironments([]string{"run_a.txt"})
+	require.Error(t, err)
+
+	_, err = splitEnvironments([]string{"=AMD 5800X"})
+	require.Error(t, err)
+}
+
+func TestSplitMeta(t *testing.T) {
+	meta, err := splitMeta([]string{"go=1.26", "flags=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"go": "1.26", "flags": "a=b", "empty": ""}, meta)
+
+	_, err = splitMeta([]string{"go"})
+	require.Error(t, err)
+
+	_, err = splitMeta([]string{"=1.26"})
+	require.Error(t, err)
+}
+
+func TestExecuteEnvironmentPerFile(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfigText())
+	outFile := filepath.Join(t.TempDir(), "report.json")
+
+	cli := &Command{
+		Config:       cfgFile,
+		Report:       true,
+		ReportOutput: outFile,
+		EnvFiles:     []string{parserTestdataPath("run.txt") + "=AMD 5800X"},
+		L:            newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute(parserTestdataPath("run.txt")+":label=stdlib", parserTestdataPath("run1.txt")))
+
+	content, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"environment": "AMD 5800X"`)
+}
+
 func TestSetConfigJSON(t *testing.T) {
 	cfg := &config.Config{}
 	cli := &Command{
@@ -73,6 +141,113 @@ func TestSetConfigJSON(t *testing.T) {
 	assert.Equal(t, "test-env", cfg.Environment)
 }
 
+func TestStrictFlag(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want config.StrictLevel
+	}{
+		{args: nil, want: config.StrictNone},
+		{args: []string{"-strict"}, want: config.StrictAll},
+		{args: []string{"-strict=functions"}, want: config.StrictFunctions},
+		{args: []string{"-strict=metrics"}, want: config.StrictMetrics},
+		{args: []string{"-strict=false"}, want: config.StrictNone},
+	} {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var cli Command
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.BoolVar(&cli.Png, "png", false, "")
+			fs.Var((*strictFlag)(&cli.Strict), "strict", "")
+			require.NoError(t, fs.Parse(tt.args))
+
+			cli.L = newTestLogger()
+			cfg := &config.Config{}
+			require.NoError(t, cli.setConfig(cfg))
+
+			assert.Equal(t, tt.want, cfg.StrictLevel)
+			assert.False(t, cli.Png)
+		})
+	}
+
+	t.Run("invalid level", func(t *testing.T) {
+		var cli Command
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var((*strictFlag)(&cli.Strict), "strict", "")
+		require.Error(t, fs.Parse([]string{"-strict=contexts"}))
+	})
+}
+
+func TestSetConfigChangesOverrides(t *testing.T) {
+	t.Run("should override changes", func(t *testing.T) {
+		cli := &Command{Only: "regressions", MinChange: 5, L: newTestLogger()}
+		cfg := &config.Config{}
+		require.NoError(t, cli.setConfig(cfg))
+		assert.Equal(t, config.Changes{Only: config.ChangeRegressions, MinChange: 5}, cfg.Changes)
+	})
+
+	t.Run("should reject an invalid kind of changes", func(t *testing.T) {
+		cli := &Command{Only: "worse", L: newTestLogger()}
+		require.ErrorContains(t, cli.setConfig(&config.Config{}), "invalid -only=worse")
+	})
+}
+
+func TestSetConfigRenderOverrides(t *testing.T) {
+	cfg := &config.Config{
+		Render: config.Rendering{
+			Title:       "from config",
+			Theme:       "roma",
+			Chart:       "barchart",
+			Orientation: config.OrientationVertical,
+			Scale:       config.ScaleAuto,
+			Legend:      config.LegendPositionBottom,
+		},
+	}
+
+	t.Run("should retain config without overrides", func(t *testing.T) {
+		cli := &Command{L: newTestLogger()}
+		overridden := *cfg
+		require.NoError(t, cli.setConfig(&overridden))
+		assert.Equal(t, cfg.Render, overridden.Render)
+	})
+
+	t.Run("should override config", func(t *testing.T) {
+		cli := &Command{
+			Theme:       "dark",
+			Chart:       "linechart",
+			Orientation: "horizontal",
+			Scale:       "log",
+			Title:       "from CLI",
+			Legend:      "none",
+			L:           newTestLogger(),
+		}
+		overridden := *cfg
+		require.NoError(t, cli.setConfig(&overridden))
+
+		assert.Equal(t, "dark", overridden.Render.Theme)
+		assert.Equal(t, config.ChartTypeLine, overridden.Render.Chart)
+		assert.Equal(t, config.OrientationHorizontal, overridden.Render.Orientation)
+		assert.Equal(t, config.ScaleLog, overridden.Render.Scale)
+		assert.Equal(t, "from CLI", overridden.Render.Title)
+		assert.Equal(t, config.LegendPositionNone, overridden.Render.Legend)
+	})
+
+	t.Run("should reject unsupported overrides", func(t *testing.T) {
+		for _, tt := range []struct {
+			cli      *Command
+			expected string
+		}{
+			{cli: &Command{Chart: "piechart"}, expected: "invalid -chart=piechart"},
+			{cli: &Command{Orientation: "diagonal"}, expected: "invalid -orientation=diagonal"},
+			{cli: &Command{Scale: "linear"}, expected: "invalid -scale=linear"},
+			{cli: &Command{Legend: "center"}, expected: "invalid -legend=center"},
+		} {
+			tt.cli.L = newTestLogger()
+			overridden := *cfg
+			require.ErrorContains(t, tt.cli.setConfig(&overridden), tt.expected)
+		}
+	})
+}
+
 func TestSetConfigOutputToStdout(t *testing.T) {
 	cfg := &config.Config{}
 	cli := &Command{
@@ -182,15 +357,64 @@ func TestPrepareConfigDefaultArgs(t *testing.T) {
 func TestBuildPage(t *testing.T) {
 	cfg := mustLoadTestConfig(t, testConfig())
 
-	page, err := buildPage(cfg, []string{parserTestdataPath("sample_generics.json")})
+	p, err := parseInputs(cfg, []string{parserTestdataPath("sample_generics.json")})
+	require.NoError(t, err)
+
+	cli := &Command{L: newTestLogger()}
+	page, err := cli.buildPage(cfg, p.Sets())
 	require.NoError(t, err)
 	require.NotNil(t, page)
 }
 
-func TestBuildPageMissingFile(t *testing.T) {
+func TestBuildPageSelection(t *testing.T) {
 	cfg := mustLoadTestConfig(t, testConfig())
 
-	_, err := buildPage(cfg, []string{"/nonexistent/file.txt"})
+	p, err := parseInputs(cfg, []string{parserTestdataPath("sample_generics.json")})
+	require.NoError(t, err)
+
+	t.Run("should render all metrics by default", func(t *testing.T) {
+		cli := &Command{L: newTestLogger()}
+		page, err := cli.buildPage(cfg, p.Sets())
+		require.NoError(t, err)
+		assert.Len(t, page.Charts, 2)
+	})
+
+	t.Run("should render selected category and metric", func(t *testing.T) {
+		cli := &Command{
+			Categories: []string{"comparisons"},
+			Metrics:    []string{"allocsPerOp"},
+			L:          newTestLogger(),
+		}
+		page, err := cli.buildPage(cfg, p.Sets())
+		require.NoError(t, err)
+		assert.Len(t, page.Charts, 1)
+	})
+
+	t.Run("should refuse unknown category", func(t *testing.T) {
+		cli := &Command{
+			Categories: []string{"unknown"},
+			L:          newTestLogger(),
+		}
+		_, err := cli.buildPage(cfg, p.Sets())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown category")
+	})
+
+	t.Run("should refuse unknown metric", func(t *testing.T) {
+		cli := &Command{
+			Metrics: []string{"MBytesPerS"},
+			L:       newTestLogger(),
+		}
+		_, err := cli.buildPage(cfg, p.Sets())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown metric")
+	})
+}
+
+func TestParseInputsMissingFile(t *testing.T) {
+	cfg := mustLoadTestConfig(t, testConfig())
+
+	_, err := parseInputs(cfg, []string{"/nonexistent/file.txt"})
 	require.Error(t, err)
 }
 
@@ -213,6 +437,45 @@ func TestExecuteHTMLOutput(t *testing.T) {
 	assert.NotZero(t, info.Size())
 }
 
+func TestExecuteTemplate(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfig())
+	dir := t.TempDir()
+	tmplFile := filepath.Join(dir, "page.tmpl")
+	require.NoError(t, os.WriteFile(tmplFile, []byte(
+		`<html><body class="acme"><h1>{{ .Scenario.Name }}</h1>{{ range .Charts }}{{ .Element }}{{ .Script }}{{ end }}</body></html>`,
+	), 0o600))
+	outDir := filepath.Join(dir, "out") + string(os.PathSeparator)
+
+	cli := &Command{
+		Config:     cfgFile,
+		IsJSON:     true,
+		OutputFile: outDir,
+		Template:   tmplFile,
+		L:          newTestLogger(),
+	}
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+	for _, page := range []string{"index.html", "Test-comparisons.html"} {
+		content, err := os.ReadFile(filepath.Join(outDir, page))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `<body class="acme"><h1>Test</h1>`)
+		assert.Contains(t, string(content), "echarts.init(")
+	}
+
+	t.Run("should fail with an invalid template", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(tmplFile, []byte("{{ .Unknown }"), 0o600))
+
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: filepath.Join(dir, "output.html"),
+			Template:   tmplFile,
+			L:          newTestLogger(),
+		}
+		require.Error(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+	})
+}
+
 func TestExecuteMultipleInputs(t *testing.T) {
 	cfgFile := writeTestConfig(t, testConfigText())
 	outFile := filepath.Join(t.TempDir(), "output.html")
@@ -233,6 +496,139 @@ func TestExecuteMultipleInputs(t *testing.T) {
 	assert.NotZero(t, info.Size())
 }
 
+func TestExecuteLabeledInputs(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfigText())
+	outFile := filepath.Join(t.TempDir(), "output.html")
+
+	cli := &Command{
+		Config:     cfgFile,
+		OutputFile: outFile,
+		Inputs:     []string{parserTestdataPath("run.txt") + ":label=stdlib"},
+		L:          newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute(parserTestdataPath("run1.txt")+":label=easyjson"))
+
+	info, err := os.Stat(outFile)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Size())
+}
+
+func TestExecuteInputFormat(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfigText())
+
+	t.Run("with JMH input", func(t *testing.T) {
+		cli := &Command{
+			Config:       cfgFile,
+			Report:       true,
+			ReportOutput: filepath.Join(t.TempDir(), "report.json"),
+			Format:       "jmh",
+			L:            newTestLogger(),
+		}
+
+		require.NoError(t, cli.Execute(parserTestdataPath("jmh.json")))
+		content, err := os.ReadFile(cli.ReportOutput)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "BenchmarkSortBenchmark/quickSort/size=100")
+	})
+
+	t.Run("with invalid format", func(t *testing.T) {
+		cli := &Command{
+			Config:     cfgFile,
+			Report:     true,
+			Format:     "cobol",
+			OutputFile: "-",
+			L:          newTestLogger(),
+		}
+
+		err := cli.Execute(parserTestdataPath("jmh.json"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid input format")
+	})
+}
+
+func TestExecuteFilteredInputs(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfigText())
+
+	t.Run("with valid filters", func(t *testing.T) {
+		cli := &Command{
+			Match:   "ReadJSON",
+			Exclude: "large",
+			L:       newTestLogger(),
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, cli.report(context.Background(), &buf, mustLoadTestConfig(t, testConfigText()), []string{parserTestdataPath("run.txt")}))
+		assert.Contains(t, buf.String(), "ReadJSON")
+		assert.NotContains(t, buf.String(), "WriteJSON")
+		assert.NotContains(t, buf.String(), "ReadJSON_-_large")
+	})
+
+	t.Run("with invalid regexp", func(t *testing.T) {
+		cli := &Command{
+			Config:     cfgFile,
+			Report:     true,
+			Exclude:    "(",
+			OutputFile: "-",
+			L:          newTestLogger(),
+		}
+
+		err := cli.Execute(parserTestdataPath("run.txt"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exclude")
+	})
+}
+
+func TestExecuteReportFormat(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfigText())
+
+	t.Run("with markdown format", func(t *testing.T) {
+		cli := &Command{
+			Config:       cfgFile,
+			Report:       true,
+			ReportFormat: "markdown",
+			OutputFile:   "-",
+			L:            newTestLogger(),
+		}
+
+		require.NoError(t, cli.Execute(parserTestdataPath("run.txt")))
+	})
+
+	t.Run("with report output file", func(t *testing.T) {
+		outFile := filepath.Join(t.TempDir(), "report.md")
+		cli := &Command{
+			Config:       cfgFile,
+			Report:       true,
+			ReportFormat: "markdown",
+			ReportOutput: outFile,
+			OutputFile:   "-",
+			L:            newTestLogger(),
+		}
+
+		require.NoError(t, cli.Execute(parserTestdataPath("run.txt")))
+
+		content, err := os.ReadFile(outFile)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), "# Benchmark report"))
+		assert.Contains(t, string(content), "## Matching")
+		assert.Contains(t, string(content), "| readjson | stdlib | small |")
+	})
+
+	t.Run("with invalid format", func(t *testing.T) {
+		cli := &Command{
+			Config:       cfgFile,
+			Report:       true,
+			ReportFormat: "xml",
+			OutputFile:   "-",
+			L:            newTestLogger(),
+		}
+
+		err := cli.Execute(parserTestdataPath("run.txt"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid report format")
+	})
+}
+
 func TestExecuteMissingInput(t *testing.T) {
 	cfgFile := writeTestConfig(t, testConfig())
 
@@ -294,6 +690,47 @@ func TestGenerateConfigText(t *testing.T) {
 	assert.NotEmpty(t, cfg.Metrics)
 }
 
+func TestGenerateConfigZeroMetrics(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "bench.txt")
+	require.NoError(t, os.WriteFile(input, []byte(
+		"BenchmarkFoo/small-8   1000   10 ns/op   0 B/op   0 allocs/op\n"+
+			"BenchmarkFoo/large-8   1000   20 ns/op   0 B/op   0 allocs/op\n",
+	), 0o600))
+
+	generate := func(t *testing.T, keepZero bool) *config.Config {
+		t.Helper()
+
+		outFile := filepath.Join(t.TempDir(), "generated.yaml")
+		cli := &Command{
+			Config:          outFile,
+			GenerateConfig:  true,
+			KeepZeroMetrics: keepZero,
+			L:               newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(input))
+
+		cfg, err := config.Load(outFile)
+		require.NoError(t, err)
+
+		return cfg
+	}
+
+	t.Run("should consider zero values missing by default", func(t *testing.T) {
+		cfg := generate(t, false)
+		_, ok := cfg.GetMetric(config.MetricAllocsPerOp)
+		assert.False(t, ok)
+	})
+
+	t.Run("should retain zero values", func(t *testing.T) {
+		cfg := generate(t, true)
+		_, ok := cfg.GetMetric(config.MetricAllocsPerOp)
+		assert.True(t, ok)
+		_, ok = cfg.GetMetric(config.MetricBytesPerOp)
+		assert.True(t, ok)
+	})
+}
+
 func TestGenerateConfigMissingInput(t *testing.T) {
 	outFile := filepath.Join(t.TempDir(), "generated.yaml")
 
@@ -306,6 +743,60 @@ func TestGenerateConfigMissingInput(t *testing.T) {
 	require.Error(t, cli.Execute("/nonexistent/file.txt"))
 }
 
+func TestGenerateConfigFromReport(t *testing.T) {
+	dir := t.TempDir()
+
+	// config generated from the original benchmark results
+	fromBenchmarks := filepath.Join(dir, "from-benchmarks.yaml")
+	cli := &Command{
+		Config:         fromBenchmarks,
+		IsJSON:         true,
+		GenerateConfig: true,
+		L:              newTestLogger(),
+	}
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+	expected, err := os.ReadFile(fromBenchmarks)
+	require.NoError(t, err)
+
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			reportFile := filepath.Join(dir, "report."+format)
+			cli := &Command{
+				Config:       writeTestConfig(t, testConfig()),
+				IsJSON:       true,
+				Report:       true,
+				ReportFormat: format,
+				ReportOutput: reportFile,
+				L:            newTestLogger(),
+			}
+			require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+			fromReport := filepath.Join(dir, "from-report-"+format+".yaml")
+			cli = &Command{
+				Config:         fromReport,
+				GenerateConfig: true,
+				FromReport:     true,
+				L:              newTestLogger(),
+			}
+			require.NoError(t, cli.Execute(reportFile))
+
+			generated, err := os.ReadFile(fromReport)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(generated))
+		})
+	}
+
+	t.Run("should fail with benchmark results as input", func(t *testing.T) {
+		cli := &Command{
+			Config:         filepath.Join(dir, "invalid.yaml"),
+			GenerateConfig: true,
+			FromReport:     true,
+			L:              newTestLogger(),
+		}
+		require.Error(t, cli.Execute(parserTestdataPath("run.txt")))
+	})
+}
+
 // helpers
 
 func newTestLogger() *slog.Logger {
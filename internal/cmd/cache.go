@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/fredbi/benchviz/internal/cache"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// load parses input benchmarks and organizes them into a scenario.
+//
+// With -cache-dir, both are restored from the cache whenever the inputs, the config and the settings
+// are unchanged since a previous run.
+//
+// With -project, the inputs of all projects are loaded and combined into a single scenario.
+func (c *Command) load(ctx context.Context, cfg *config.Config, args []string) (*parser.BenchmarkParser, *model.Scenario, error) {
+	if len(c.projects) > 0 {
+		if len(args) > 0 {
+			return nil, nil, errors.New("the inputs of a dashboard must be set with -project")
+		}
+
+		return c.loadDashboard(ctx)
+	}
+
+	return c.loadInputs(ctx, c.Config, cfg, args)
+}
+
+// loadInputs parses input benchmarks and organizes them with a config loaded from a file.
+func (c *Command) loadInputs(ctx context.Context, configFile string, cfg *config.Config, args []string) (*parser.BenchmarkParser, *model.Scenario, error) {
+	key, cacheable := c.cacheKey(configFile, args)
+	store := cache.New(c.CacheDir)
+
+	if cacheable {
+		entry, ok, err := store.Get(key)
+		switch {
+		case err != nil:
+			c.L.Warn("ignoring cache entry", slog.String("error", err.Error()))
+		case ok:
+			opts, err := c.parserOptions()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			p := parser.New(cfg, append(opts, parser.WithZeroMetrics(cfg.KeepZeroMetrics))...)
+			p.AddSets(entry.Sets...)
+			c.L.Info("benchmarks restored from cache", slog.String("key", key))
+
+			return p, entry.Scenario, nil
+		}
+	}
+
+	stop := c.measure(phaseParse)
+	p, err := c.parse(ctx, cfg, args)
+	stop()
+	if err != nil {
+		return nil, nil, failure.WithStage(failure.StageParse, err)
+	}
+
+	if !c.SkipPreflight {
+		if err := c.preflight(cfg, p.Sets()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	stop = c.measure(phaseOrganize)
+	scenario, err := c.scenarize(cfg, p.Sets())
+	stop()
+	if err != nil {
+		return nil, nil, failure.WithStage(failure.StageOrganize, err)
+	}
+
+	if cacheable {
+		if err := store.Put(key, cache.Entry{Sets: p.Sets(), Scenario: scenario}); err != nil {
+			c.L.Warn("could not cache benchmarks", slog.String("error", err.Error()))
+		} else {
+			c.L.Debug("benchmarks cached", slog.String("key", key))
+		}
+	}
+
+	return p, scenario, nil
+}
+
+// cacheKey hashes everything that determines the parsed and organized benchmarks:
+// the content of input files and config file, and the settings of parsing and organizing.
+//
+// Results are not cacheable when the cache is disabled, when benchmarks are run or read from standard input,
+// or when some input can't be read.
+func (c *Command) cacheKey(configFile string, args []string) (string, bool) {
+	if c.CacheDir == "" || (len(args) > 0 && args[0] == runCommand) {
+		return "", false
+	}
+
+	inputs := append(slices.Clone(c.Inputs), args...)
+	files, labels := splitLabels(inputs)
+	if len(files) == 0 || slices.Contains(files, "-") {
+		return "", false
+	}
+
+	h := cache.NewHasher()
+	if err := h.AddJSON(struct {
+		Inputs      []string
+		Labels      map[string]string
+		IsJSON      bool
+		Format      string
+		Plugin      string
+		Environment string
+		EnvFiles    []string
+		Strict      string
+		Match       string
+		Exclude     string
+		Categories  []string
+		Metrics     []string
+		Render      []string
+	}{
+		Inputs:      files,
+		Labels:      labels,
+		IsJSON:      c.IsJSON,
+		Format:      c.Format,
+		Plugin:      c.Plugin,
+		Environment: c.Environment,
+		EnvFiles:    c.EnvFiles,
+		Strict:      c.Strict,
+		Match:       c.Match,
+		Exclude:     c.Exclude,
+		Categories:  c.Categories,
+		Metrics:     c.Metrics,
+		Render:      []string{c.Theme, c.Chart, c.Orientation, c.Scale, c.Title, c.Legend},
+	}); err != nil {
+		return "", false
+	}
+
+	for _, file := range append([]string{configFile}, files...) {
+		if file == "" {
+			continue
+		}
+
+		if err := h.AddFile(file); err != nil {
+			c.L.Debug("input not cacheable", slog.String("file", file), slog.String("error", err.Error()))
+
+			return "", false
+		}
+	}
+
+	return h.Sum(), true
+}
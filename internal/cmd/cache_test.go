@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/cache"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecuteCache(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := writeTestConfig(t, testConfig())
+	input := writeBenchmarks(t, dir, "bench.txt", 100)
+	cacheDir := filepath.Join(dir, "cache")
+	output := filepath.Join(dir, "output.html")
+
+	execute := func(t *testing.T) string {
+		t.Helper()
+
+		cli := &Command{
+			Config:     cfgFile,
+			OutputFile: output,
+			CacheDir:   cacheDir,
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(input))
+
+		content, err := os.ReadFile(output)
+		require.NoError(t, err)
+
+		return string(content)
+	}
+
+	execute(t)
+	entries, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	t.Run("should restore unchanged inputs from the cache", func(t *testing.T) {
+		// tamper with the cached scenario to detect that it is used
+		content, err := os.ReadFile(entries[0])
+		require.NoError(t, err)
+		var entry cache.Entry
+		require.NoError(t, json.Unmarshal(content, &entry))
+		entry.Scenario.Categories[0].Title = "Restored from cache"
+		content, err = json.Marshal(entry)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(entries[0], content, 0o600))
+
+		assert.Contains(t, execute(t), "Restored from cache")
+	})
+
+	t.Run("should parse changed inputs again", func(t *testing.T) {
+		writeBenchmarks(t, dir, "bench.txt", 200)
+
+		assert.NotContains(t, execute(t), "Restored from cache")
+		entries, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("should not cache standard input", func(t *testing.T) {
+		cli := &Command{CacheDir: cacheDir, L: newTestLogger()}
+		_, cacheable := cli.cacheKey(cli.Config, []string{"-"})
+		assert.False(t, cacheable)
+
+		_, cacheable = cli.cacheKey(cli.Config, []string{runCommand, "./..."})
+		assert.False(t, cacheable)
+
+		cli.CacheDir = ""
+		_, cacheable = cli.cacheKey(cli.Config, []string{input})
+		assert.False(t, cacheable)
+	})
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fredbi/benchviz/internal/color"
+)
+
+// palette returns the colors of textual outputs written to stdout, according to the --color flag.
+func (c *Command) palette() color.Palette {
+	return color.New(color.Enabled(color.Mode(c.Color), isTerminal(os.Stdout)))
+}
+
+func (c *Command) checkColor() error {
+	if mode := color.Mode(c.Color); !mode.IsValid() {
+		return fmt.Errorf("invalid color mode %q: should be one of %v", c.Color, color.AllModes())
+	}
+
+	return nil
+}
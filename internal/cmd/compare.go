@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
+)
+
+// stdinLabel is the version of benchmarks read from standard input, with -compare-files.
+const stdinLabel = "stdin"
+
+// compareFiles compares input files without a config, e.g. "benchviz -compare-files old.txt new.txt".
+//
+// Each input file is a version, named after its label (as in "file:label=value") or else its base name.
+// The config is generated from the input benchmarks, like with -generate-config: benchmarks are compared
+// across versions in a single category.
+func (c *Command) compareFiles(ctx context.Context, args []string) error {
+	files, labels, err := fileVersions(append(slices.Clone(c.Inputs), args...))
+	if err != nil {
+		return failure.WithStage(failure.StageConfig, err)
+	}
+
+	labeled := make([]string, 0, len(files))
+	versions := make([]string, 0, len(files))
+	for _, file := range files {
+		labeled = append(labeled, file+":label="+labels[file])
+		versions = append(versions, labels[file])
+	}
+
+	c.L.Info("comparing input files", slog.Any("versions", versions))
+
+	return c.renderGenerated(ctx, labeled, func(input config.GenerateInput) *config.Config {
+		input.Versions = versions
+		cfg := config.Generate(input)
+		cfg.Name = "Comparison of " + strings.Join(versions, ", ")
+
+		return cfg
+	})
+}
+
+// fileVersions resolves the version of each input file: its label, or else its base name without extension.
+func fileVersions(args []string) (files []string, labels map[string]string, err error) {
+	files, labels = splitLabels(args)
+	if len(files) < 2 { //nolint:mnd // a comparison needs two files
+		return nil, nil, errors.New("invalid -compare-files: at least two input files should be compared")
+	}
+
+	seen := make(map[string]string, len(files))
+	for _, file := range files {
+		label, ok := labels[file]
+		switch {
+		case ok:
+		case file == "-":
+			label = stdinLabel
+		default:
+			label = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		}
+
+		if other, dup := seen[label]; dup {
+			return nil, nil, fmt.Errorf("invalid -compare-files: input files %q and %q are both named %q: label them as file:label=value", other, file, label)
+		}
+		seen[label] = file
+		labels[file] = label
+	}
+
+	return files, labels, nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestFileVersions(t *testing.T) {
+	t.Run("should name versions after labels or file names", func(t *testing.T) {
+		files, labels, err := fileVersions([]string{"dir/old.txt", "new.txt:label=v2", "-"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"dir/old.txt", "new.txt", "-"}, files)
+		assert.Equal(t, map[string]string{"dir/old.txt": "old", "new.txt": "v2", "-": stdinLabel}, labels)
+	})
+
+	t.Run("should require two files", func(t *testing.T) {
+		_, _, err := fileVersions([]string{"old.txt"})
+		require.Error(t, err)
+	})
+
+	t.Run("should refuse files with the same name", func(t *testing.T) {
+		_, _, err := fileVersions([]string{"a/bench.txt", "b/bench.txt"})
+		require.Error(t, err)
+		assert.StringContainsT(t, err.Error(), "file:label=value")
+	})
+}
+
+func TestExecuteCompareFiles(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "output.html")
+
+	cli := &Command{
+		Config:       filepath.Join(t.TempDir(), "missing.yaml"), // no config is needed
+		CompareFiles: true,
+		OutputFile:   outFile,
+		L:            newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute(
+		parserTestdataPath("run.txt")+":label=before",
+		parserTestdataPath("run1.txt")+":label=after",
+	))
+
+	content, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+
+	html := string(content)
+	assert.StringContainsT(t, html, "Comparison of before, after")
+	assert.StringContainsT(t, html, `"name":"before"`)
+	assert.StringContainsT(t, html, `"name":"after"`)
+}
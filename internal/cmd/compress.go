@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+// gzipExt is the extension of gzip-compressed HTML files, e.g. "index.html.gz".
+const gzipExt = ".gz"
+
+// compressedFile returns the name of a HTML file written by the command, with the extension of gzip
+// files when outputs are compressed.
+func compressedFile(cfg *config.Config, file string) string {
+	if cfg.Outputs.Compress {
+		return file + gzipExt
+	}
+
+	return file
+}
+
+// writeHTML writes a HTML file rendered by render, minified and gzip-compressed as configured.
+func writeHTML(cfg *config.Config, file string, render func(io.Writer) error) error {
+	wrt, closer, err := getWriter(file, "HTML")
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	var (
+		w  io.Writer = wrt
+		zw *gzip.Writer
+	)
+
+	if strings.HasSuffix(file, gzipExt) {
+		zw, _ = gzip.NewWriterLevel(wrt, gzip.BestCompression) // the level is valid: no error
+		zw.Name = strings.TrimSuffix(filepath.Base(file), gzipExt)
+		w = zw
+	}
+
+	if cfg.Outputs.Minify {
+		var buf bytes.Buffer
+		if err := render(&buf); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(minifyHTML(buf.Bytes())); err != nil {
+			return err
+		}
+	} else if err := render(w); err != nil {
+		return err
+	}
+
+	if zw == nil {
+		return nil
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compressing HTML file %q: %w", file, err)
+	}
+
+	return nil
+}
+
+// readHTML opens a HTML file written by [writeHTML], uncompressing it when needed.
+func readHTML(file string) (io.Reader, func(), error) {
+	rdr, closer, err := getReader(file, "HTML")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !strings.HasSuffix(file, gzipExt) {
+		return rdr, closer, nil
+	}
+
+	zr, err := gzip.NewReader(rdr)
+	if err != nil {
+		closer()
+
+		return nil, nil, fmt.Errorf("uncompressing HTML file %q: %w", file, err)
+	}
+
+	return zr, func() {
+		_ = zr.Close()
+		closer()
+	}, nil
+}
+
+// verbatimElements are the HTML elements with a content that is not minified, since whitespace matters in it.
+var verbatimElements = []string{"pre", "textarea", "script", "style"}
+
+// minifyHTML strips the indentation and the blank lines of a HTML page.
+//
+// Line breaks are retained. The content of elements where whitespace matters (e.g. <pre> or inline scripts)
+// is kept as is.
+func minifyHTML(page []byte) []byte {
+	minified := make([]byte, 0, len(page))
+	var closing []byte // closing tag of the element with a content kept as is, if any
+
+	for line := range bytes.Lines(page) {
+		if closing != nil {
+			minified = append(minified, line...)
+			if bytes.Contains(bytes.ToLower(line), closing) {
+				closing = nil
+			}
+
+			continue
+		}
+
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			continue
+		}
+
+		if closing = openedElement(trimmed); closing != nil {
+			// the content of the element starts on this line: only the indentation is stripped
+			minified = append(minified, bytes.TrimLeft(line, " \t")...)
+
+			continue
+		}
+
+		minified = append(minified, trimmed...)
+		minified = append(minified, '\n')
+	}
+
+	return minified
+}
+
+// openedElement returns the closing tag of the element with a verbatim content opened but not closed on a line, if any.
+func openedElement(line []byte) []byte {
+	lower := bytes.ToLower(line)
+
+	for _, element := range verbatimElements {
+		opening := []byte("<" + element)
+		closing := []byte("</" + element)
+
+		at := bytes.LastIndex(lower, opening)
+		if at < 0 || bytes.Contains(lower[at:], closing) {
+			continue
+		}
+
+		// e.g. <pre> or <pre class="...">, but not <prefix>
+		if next := at + len(opening); next < len(lower) && lower[next] != '>' && lower[next] != ' ' && lower[next] != '\t' {
+			continue
+		}
+
+		return closing
+	}
+
+	return nil
+}
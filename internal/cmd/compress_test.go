@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestSetConfigCompressedOutput(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		output string
+		gzip   bool
+	}{
+		{name: "should infer compression from the output file", output: "results.html.gz"},
+		{name: "should compress with -gzip", output: "results.html", gzip: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cli := &Command{
+				OutputFile: tt.output,
+				Gzip:       tt.gzip,
+				Png:        true,
+				L:          newTestLogger(),
+			}
+
+			require.NoError(t, cli.setConfig(cfg))
+
+			assert.TrueT(t, cfg.Outputs.Compress)
+			assert.EqualT(t, "results.html.gz", cfg.Outputs.HTMLFile)
+			assert.EqualT(t, "results.png", cfg.Outputs.PngFile)
+		})
+	}
+}
+
+func TestSetConfigCompressedStdout(t *testing.T) {
+	cli := &Command{Gzip: true, L: newTestLogger()}
+	require.ErrorContains(t, cli.setConfig(&config.Config{}), "-gzip requires an output file")
+}
+
+func TestExecuteCompressedOutput(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfigText())
+	outFile := filepath.Join(t.TempDir(), "output.html.gz")
+
+	cli := &Command{
+		Config:     cfgFile,
+		OutputFile: outFile,
+		Minify:     true,
+		L:          newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute(parserTestdataPath("run.txt")))
+
+	file, err := os.Open(outFile)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	zr, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	assert.EqualT(t, "output.html", zr.Name)
+
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	html := string(content)
+	assert.StringContainsT(t, html, "<html>")
+	assert.StringContainsT(t, html, "\n<body>\n<details") // markup is not indented
+}
+
+func TestMinifyHTML(t *testing.T) {
+	t.Run("should strip indentation and blank lines", func(t *testing.T) {
+		page := "<html>\n  <body>\n\n    <div>\n      <p>text</p>\n    </div>\n  </body>\r\n</html>"
+
+		assert.EqualT(t, "<html>\n<body>\n<div>\n<p>text</p>\n</div>\n</body>\n</html>\n", string(minifyHTML([]byte(page))))
+	})
+
+	t.Run("should keep the content of scripts and preformatted text", func(t *testing.T) {
+		page := "<body>\n  <script type=\"text/javascript\">\n    // a comment\n\n    let a = 1\n  </script>\n" +
+			"  <pre>  indented\n\n    text</pre>\n  <prefix>\n</body>"
+
+		assert.EqualT(t,
+			"<body>\n<script type=\"text/javascript\">\n    // a comment\n\n    let a = 1\n  </script>\n"+
+				"<pre>  indented\n\n    text</pre>\n<prefix>\n</body>\n",
+			string(minifyHTML([]byte(page))),
+		)
+	})
+}
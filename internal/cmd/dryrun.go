@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// dryRun prints what would be rendered for a scenario (categories, charts, series and output paths),
+// without writing any file.
+func (c *Command) dryRun(ctx context.Context, w io.Writer, cfg *config.Config, scenario *model.Scenario) error {
+	ew := &errWriter{w: w}
+
+	ew.printf("Dry run: no file is written\n\n")
+	ew.printf("Scenario: %s\n", scenario.Name)
+	ew.printf("Categories: %d\n", len(scenario.Categories))
+
+	var charts int
+	for _, category := range scenario.Categories {
+		metrics := category.Metrics()
+		charts += len(metrics)
+		ew.printf("  - %s (%d chart(s), %d label(s))\n", category.ID, len(metrics), len(category.Labels()))
+
+		for _, metric := range metrics {
+			var series, points int
+			for _, data := range category.Data {
+				if data.Metric.ID != metric.ID {
+					continue
+				}
+
+				for _, s := range data.Series {
+					series++
+					points += len(s.Points)
+				}
+			}
+
+			ew.printf("      %s: %q, %d series, %d point(s)\n", metric.ID, category.TitleWithPlaceHolders(metric), series, points)
+		}
+	}
+	ew.printf("Charts: %d\n\n", charts)
+
+	ew.printf("Outputs:\n")
+	c.dryRunOutputs(ctx, ew, cfg, scenario)
+
+	return ew.err
+}
+
+// dryRunOutputs prints the files which would be written.
+//
+// PNG images are only rendered along with an output file: -png is ignored when the page is sent to the standard output.
+func (c *Command) dryRunOutputs(ctx context.Context, ew *errWriter, cfg *config.Config, scenario *model.Scenario) {
+	htmlFile := cfg.Outputs.HTMLFile
+	if htmlFile == "" || htmlFile == "-" {
+		htmlFile = "(standard output)"
+	}
+	ew.printf("  HTML: %s\n", htmlFile)
+
+	switch {
+	case cfg.Outputs.PngFile != "":
+		ew.printf("  PNG: %s\n", cfg.Outputs.PngFile)
+	case c.Png:
+		ew.printf("  PNG: none (-png requires an output file)\n")
+	}
+
+	if dir := cfg.Outputs.Directory; dir != "" {
+		placeholders := c.templatePlaceholders(ctx, cfg)
+		for _, category := range scenario.Categories {
+			placeholders["{category}"] = category.ID
+			base := filepath.Join(dir, expandTemplate(c.outputTemplate(), placeholders))
+			ew.printf("  HTML: %s.html\n", base)
+			if cfg.Outputs.PngFile != "" {
+				ew.printf("  PNG: %s.png\n", base)
+			}
+		}
+		ew.printf("  report: %s\n", filepath.Join(dir, reportFile))
+	}
+
+	for _, value := range c.Exports {
+		format, file, _ := strings.Cut(value, "=")
+		ew.printf("  export (%s): %s\n", format, file)
+	}
+
+	if c.SQLite != "" {
+		ew.printf("  sqlite: %s\n", c.SQLite)
+	}
+
+	if manifest := c.manifestPath(cfg); manifest != "" {
+		ew.printf("  manifest: %s\n", manifest)
+	}
+}
+
+// errWriter is an [io.Writer] that retains the first write error.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) printf(format string, args ...any) {
+	if e.err != nil {
+		return
+	}
+
+	_, e.err = fmt.Fprintf(e.w, format, args...)
+}
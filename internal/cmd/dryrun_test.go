@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+func TestDryRun(t *testing.T) {
+	cfg := mustLoadTestConfig(t, testConfig())
+	outDir := filepath.Join(t.TempDir(), "results")
+	cli := &Command{
+		OutputFile: outDir + "/",
+		Png:        true,
+		DryRun:     true,
+		L:          newTestLogger(),
+	}
+	require.NoError(t, cli.setConfig(cfg))
+
+	p, err := parseInputs(cfg, []string{parserTestdataPath("sample_generics.json")})
+	require.NoError(t, err)
+	scenario, err := cli.scenarize(cfg, p.Sets())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, cli.dryRun(context.Background(), &buf, cfg, scenario))
+
+	output := buf.String()
+	assert.Contains(t, output, "Categories: 1\n")
+	assert.Contains(t, output, "  - comparisons (2 chart(s)")
+	assert.Contains(t, output, "      nsPerOp: ")
+	assert.Contains(t, output, "Charts: 2\n")
+	assert.Contains(t, output, "  HTML: "+filepath.Join(outDir, "index.html")+"\n")
+	assert.Contains(t, output, "  PNG: "+filepath.Join(outDir, "Test-comparisons.png")+"\n")
+	assert.Contains(t, output, "  manifest: "+filepath.Join(outDir, manifestFile)+"\n")
+
+	// nothing is written
+	_, err = os.Stat(outDir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDryRunWithoutOutputFile(t *testing.T) {
+	cfg := mustLoadTestConfig(t, testConfig())
+	cli := &Command{
+		OutputFile: "-",
+		Png:        true,
+		DryRun:     true,
+		L:          newTestLogger(),
+	}
+	require.NoError(t, cli.setConfig(cfg))
+
+	var buf bytes.Buffer
+	ew := &errWriter{w: &buf}
+	cli.dryRunOutputs(context.Background(), ew, cfg, &model.Scenario{})
+	require.NoError(t, ew.err)
+
+	assert.EqualT(t, "  HTML: (standard output)\n  PNG: none (-png requires an output file)\n", buf.String())
+}
+
+func TestExecuteDryRun(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfig())
+	outFile := filepath.Join(t.TempDir(), "output.html")
+
+	cli := &Command{
+		Config:     cfgFile,
+		IsJSON:     true,
+		OutputFile: outFile,
+		DryRun:     true,
+		L:          newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+	_, err := os.Stat(outFile)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
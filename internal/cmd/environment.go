@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
+)
+
+// checkEnvironments warns when results from incompatible environments are mixed, given their differences
+// (e.g. "cpu: X != Y"). It fails when strict requirements are enforced.
+func (c *Command) checkEnvironments(cfg *config.Config, stage failure.Stage, differences []string) error {
+	if len(differences) == 0 {
+		return nil
+	}
+
+	c.L.Warn("results from incompatible environments are mixed", slog.Any("differences", differences))
+	if !cfg.IsStrictFor(config.StrictAll) {
+		return nil
+	}
+
+	err := &failure.Error{
+		Stage:  stage,
+		Reason: "incompatible environments",
+		Err:    fmt.Errorf("strict requirement not met: results from incompatible environments are mixed (%s). Stopping here", strings.Join(differences, ", ")),
+	}
+	c.L.Error("strict requirement not met", slog.String("error", err.Error()))
+
+	return err
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/failure"
+)
+
+// Supported formats of the error reported on failure.
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+// writeError reports the error that made the command fail, in the format set by --error-format.
+//
+// In JSON format, the error is written as a single line with the stage, file, benchmark and reason
+// of every failure, so CI wrappers don't need to parse log lines.
+func (c *Command) writeError(w io.Writer, err error) error {
+	if err := c.checkErrorFormat(); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(c.ErrorFormat, errorFormatJSON) {
+		return failure.WriteJSON(w, err)
+	}
+
+	_, werr := fmt.Fprintln(w, err)
+
+	return werr
+}
+
+func (c *Command) checkErrorFormat() error {
+	switch strings.ToLower(c.ErrorFormat) {
+	case "", errorFormatText, errorFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid error format %q: should be one of [%s %s]", c.ErrorFormat, errorFormatText, errorFormatJSON)
+	}
+}
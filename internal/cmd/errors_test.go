@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/failure"
+)
+
+func TestWriteError(t *testing.T) {
+	t.Run("should report a strict mode violation as JSON", func(t *testing.T) {
+		// no function matches the Positive benchmarks
+		cfg := strings.Replace(testConfig(), "  - id: positive\n    Match: 'Positive'\n", "", 1)
+
+		cli := &Command{
+			Config:      writeTestConfig(t, cfg),
+			IsJSON:      true,
+			Strict:      "functions",
+			OutputFile:  filepath.Join(t.TempDir(), "output.html"),
+			ErrorFormat: errorFormatJSON,
+			L:           newTestLogger(),
+		}
+		err := cli.Execute(parserTestdataPath("sample_generics.json"))
+		require.Error(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, cli.writeError(&buf, err))
+
+		var report failure.Report
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+		assert.StringContainsT(t, report.Error, "strict requirement not met")
+		require.NotEmpty(t, report.Failures)
+		for _, f := range report.Failures {
+			assert.EqualT(t, failure.StageOrganize, f.Stage)
+			assert.EqualT(t, parserTestdataPath("sample_generics.json"), f.File)
+			assert.NotEmpty(t, f.Benchmark)
+			assert.StringContainsT(t, f.Reason, "no function matched")
+		}
+	})
+
+	t.Run("should report a missing input file as JSON", func(t *testing.T) {
+		cli := &Command{
+			Config:      writeTestConfig(t, testConfig()),
+			OutputFile:  filepath.Join(t.TempDir(), "output.html"),
+			ErrorFormat: errorFormatJSON,
+			L:           newTestLogger(),
+		}
+		err := cli.Execute("missing.txt")
+		require.Error(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, cli.writeError(&buf, err))
+
+		var report failure.Report
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+		require.Len(t, report.Failures, 1)
+		assert.EqualT(t, failure.StageParse, report.Failures[0].Stage)
+		assert.EqualT(t, "missing.txt", report.Failures[0].File)
+	})
+
+	t.Run("should report an error as text", func(t *testing.T) {
+		cli := &Command{}
+		var buf bytes.Buffer
+		require.NoError(t, cli.writeError(&buf, errors.New("boom")))
+		assert.EqualT(t, "boom\n", buf.String())
+	})
+
+	t.Run("should reject an invalid error format", func(t *testing.T) {
+		cli := &Command{ErrorFormat: "xml"}
+		require.ErrorContains(t, cli.writeError(&bytes.Buffer{}, errors.New("boom")), `invalid error format "xml"`)
+	})
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/export"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// exportScenario writes the organized scenario with the exporters requested with -export, as "format=file".
+//
+// Formats are either exporters registered in [export], or external programs declared with -exporter, as "name=command".
+func (c *Command) exportScenario(ctx context.Context, scenario *model.Scenario) error {
+	if len(c.Exports) == 0 {
+		return nil
+	}
+
+	commands, err := splitExporters(c.Exporters)
+	if err != nil {
+		return err
+	}
+
+	for _, value := range c.Exports {
+		format, file, ok := strings.Cut(value, "=")
+		if !ok || format == "" || file == "" {
+			return fmt.Errorf("invalid export %q: expected format=file", value)
+		}
+
+		exporter, err := lookupExporter(format, commands)
+		if err != nil {
+			return err
+		}
+
+		if err := exportTo(ctx, exporter, file, scenario); err != nil {
+			return fmt.Errorf("exporting %s: %w", format, err)
+		}
+
+		c.L.Info("scenario exported", slog.String("format", format), slog.String("file", file))
+		c.produced(artifactExport, file)
+	}
+
+	return nil
+}
+
+func exportTo(ctx context.Context, exporter export.Exporter, file string, scenario *model.Scenario) error {
+	w, closer, err := getWriter(file, "export")
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	return exporter.Export(ctx, w, scenario)
+}
+
+// lookupExporter resolves an export format: external programs declared on the command line take precedence.
+func lookupExporter(format string, commands map[string][]string) (export.Exporter, error) {
+	if command, ok := commands[format]; ok {
+		return export.NewExec(command...), nil
+	}
+
+	return export.Lookup(format)
+}
+
+// splitExporters builds a map of exporter commands by name, from "name=command" values.
+func splitExporters(values []string) (map[string][]string, error) {
+	commands := make(map[string][]string, len(values))
+
+	for _, value := range values {
+		name, command, ok := strings.Cut(value, "=")
+		args := strings.Fields(command)
+		if !ok || name == "" || len(args) == 0 {
+			return nil, fmt.Errorf("invalid exporter %q: expected name=command", value)
+		}
+		commands[name] = args
+	}
+
+	return commands, nil
+}
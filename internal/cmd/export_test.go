@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/model"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecuteExport(t *testing.T) {
+	t.Run("should export the scenario as JSON", func(t *testing.T) {
+		cfgFile := writeTestConfig(t, testConfig())
+		dir := t.TempDir()
+		exported := filepath.Join(dir, "scenario.json")
+
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: filepath.Join(dir, "output.html"),
+			Manifest:   filepath.Join(dir, "manifest.json"),
+			Exports:    []string{"json=" + exported},
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+		content, err := os.ReadFile(exported)
+		require.NoError(t, err)
+		var scenario model.Scenario
+		require.NoError(t, json.Unmarshal(content, &scenario))
+		assert.NotEmpty(t, scenario.Categories)
+
+		manifest := readManifest(t, cli.Manifest)
+		require.Len(t, manifest.Artifacts, 2)
+		assert.Equal(t, artifactExport, manifest.Artifacts[1].Kind)
+		assert.Equal(t, "scenario.json", manifest.Artifacts[1].Path)
+	})
+
+	t.Run("should export with an external program", func(t *testing.T) {
+		if _, err := exec.LookPath("grep"); err != nil {
+			t.Skip("no grep available to run as exporter")
+		}
+
+		cfgFile := writeTestConfig(t, testConfig())
+		dir := t.TempDir()
+		exported := filepath.Join(dir, "dashboard.txt")
+
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: filepath.Join(dir, "output.html"),
+			Exports:    []string{"dashboard=" + exported},
+			Exporters:  []string{"dashboard=grep -c Categories"},
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+		content, err := os.ReadFile(exported)
+		require.NoError(t, err)
+		assert.Equal(t, "1\n", string(content))
+	})
+
+	t.Run("should fail on invalid exports", func(t *testing.T) {
+		for _, exports := range [][]string{{"unknown=out.txt"}, {"json"}, {"=out.txt"}} {
+			cfgFile := writeTestConfig(t, testConfig())
+
+			cli := &Command{
+				Config:     cfgFile,
+				IsJSON:     true,
+				OutputFile: filepath.Join(t.TempDir(), "output.html"),
+				Exports:    exports,
+				L:          newTestLogger(),
+			}
+			require.Error(t, cli.Execute(parserTestdataPath("sample_generics.json")), exports)
+		}
+	})
+
+	t.Run("should fail on invalid exporters", func(t *testing.T) {
+		_, err := splitExporters([]string{"dashboard="})
+		require.Error(t, err)
+
+		commands, err := splitExporters([]string{"dashboard=push --team perf"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"push", "--team", "perf"}, commands["dashboard"])
+	})
+}
+
+func TestExecuteSQLite(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfig())
+	dir := t.TempDir()
+	database := filepath.Join(dir, "bench.db")
+
+	cli := &Command{
+		Config:     cfgFile,
+		IsJSON:     true,
+		OutputFile: filepath.Join(dir, "output.html"),
+		Manifest:   filepath.Join(dir, "manifest.json"),
+		SQLite:     database,
+		L:          newTestLogger(),
+	}
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+	db, err := sql.Open("sqlite", database)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var benchmarks, metrics int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM benchmarks").Scan(&benchmarks))
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM metrics").Scan(&metrics))
+	assert.Positive(t, benchmarks)
+	assert.Positive(t, metrics)
+
+	manifest := readManifest(t, cli.Manifest)
+	require.Len(t, manifest.Artifacts, 2)
+	assert.Equal(t, artifactSQLite, manifest.Artifacts[1].Kind)
+	assert.Equal(t, "bench.db", manifest.Artifacts[1].Path)
+}
+
+func TestExecuteProfiles(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfig())
+	dir := t.TempDir()
+
+	cli := &Command{
+		Config:     cfgFile,
+		IsJSON:     true,
+		OutputFile: filepath.Join(dir, "output.html"),
+		CPUProfile: filepath.Join(dir, "cpu.pprof"),
+		MemProfile: filepath.Join(dir, "mem.pprof"),
+		Trace:      filepath.Join(dir, "trace.out"),
+		L:          newTestLogger(),
+	}
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+	for _, file := range []string{cli.CPUProfile, cli.MemProfile, cli.Trace} {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		assert.Positive(t, info.Size(), file)
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// envStepSummary is the environment variable set by GitHub Actions with the file of the job summary.
+const envStepSummary = "GITHUB_STEP_SUMMARY"
+
+// appendJobSummary appends a Markdown report to the GitHub Actions job summary.
+//
+// Outside of GitHub Actions (i.e. when GITHUB_STEP_SUMMARY is not set), the report is skipped with a warning.
+func (c *Command) appendJobSummary(write func(io.Writer) error) error {
+	if !c.GHA {
+		return nil
+	}
+
+	file := os.Getenv(envStepSummary)
+	if file == "" {
+		c.L.Warn("not running in GitHub Actions: no job summary written", slog.String("variable", envStepSummary))
+
+		return nil
+	}
+
+	summary, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec,mnd // usual permissions for a file
+	if err != nil {
+		return fmt.Errorf("opening job summary %q: %w", file, err)
+	}
+	defer summary.Close()
+
+	if err := write(summary); err != nil {
+		return fmt.Errorf("writing job summary: %w", err)
+	}
+
+	c.L.Info("job summary written", slog.String("file", file))
+
+	return nil
+}
+
+// writeScenarioSummary writes a Markdown report of a scenario: one table per chart, and links to produced artifacts.
+func (c *Command) writeScenarioSummary(w io.Writer, scenario *model.Scenario) error {
+	var b strings.Builder
+
+	name := scenario.Name
+	if name == "" {
+		name = "Benchmarks"
+	}
+	b.WriteString("## " + name + "\n")
+	for _, category := range scenario.Categories {
+		for _, metric := range category.Metrics() {
+			var columns []model.MetricSeries
+			for _, data := range category.Data {
+				if data.Metric.ID == metric.ID {
+					columns = append(columns, data.Series...)
+				}
+			}
+
+			title := category.TitleWithPlaceHolders(metric)
+			if title == "" {
+				title = category.ID + " (" + metric.Title + ")"
+			}
+			b.WriteString("\n### " + title + "\n\n")
+			writeMarkdownTable(&b, category, metric.Axis, columns)
+			writeComplexity(&b, category, metric.ID)
+		}
+	}
+
+	if len(c.artifacts) > 0 {
+		// images are not embedded: GitHub doesn't render files from the runner
+		b.WriteString("\n### Artifacts\n\n")
+		for _, produced := range c.artifacts {
+			fmt.Fprintf(&b, "- [%s](%s) (%s)\n", filepath.Base(produced.Path), produced.Path, produced.Kind)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// writeMarkdownTable renders the series of a chart as a table: one row per position on the X axis, one column per series.
+func writeMarkdownTable(b *strings.Builder, category model.Category, unit string, columns []model.MetricSeries) {
+	var rows []model.SeriesKey
+	labels := make(map[model.SeriesKey]string)
+	values := make([]map[model.SeriesKey]float64, len(columns))
+
+	for i, series := range columns {
+		values[i] = make(map[model.SeriesKey]float64, len(series.Points))
+		for _, point := range series.Points {
+			key := category.XKey(point)
+			if _, seen := labels[key]; !seen {
+				labels[key] = point.Label
+				rows = append(rows, key)
+			}
+			values[i][key] = point.Value
+		}
+	}
+
+	header := "Benchmark"
+	if unit != "" {
+		header += " (" + unit + ")"
+	}
+	b.WriteString("| " + baseline.EscapeMarkdown(header))
+	for _, series := range columns {
+		b.WriteString(" | " + baseline.EscapeMarkdown(series.Title))
+	}
+	b.WriteString(" |\n|" + strings.Repeat(" --- |", len(columns)+1) + "\n")
+
+	for _, key := range rows {
+		b.WriteString("| " + baseline.EscapeMarkdown(labels[key]))
+		for i := range columns {
+			cell := "-"
+			if value, ok := values[i][key]; ok {
+				cell = strconv.FormatFloat(value, 'g', 4, 64) //nolint:mnd // 4 significant digits
+			}
+			b.WriteString(" | " + cell)
+		}
+		b.WriteString(" |\n")
+	}
+}
+
+// writeComplexity lists the complexity estimated for each function and series of a chart, if any.
+func writeComplexity(b *strings.Builder, category model.Category, metric config.MetricName) {
+	first := true
+
+	for _, fit := range category.Fits {
+		if fit.Metric != metric {
+			continue
+		}
+
+		if first {
+			b.WriteString("\nEstimated complexity:\n\n")
+			first = false
+		}
+
+		fmt.Fprintf(b, "- %s %s: %s\n", baseline.EscapeMarkdown(fit.Function), baseline.EscapeMarkdown(fit.Series), fit.Complexity)
+	}
+}
+
+// annotateRegressions emits a GitHub Actions warning annotation for each regression found against a baseline.
+func (c *Command) annotateRegressions(w io.Writer, comparison baseline.Comparison) error {
+	if !c.GHA {
+		return nil
+	}
+
+	for _, d := range comparison.Regressions() {
+		message := fmt.Sprintf("%s: %s against baseline %s (%.4g -> %.4g)",
+			d.Result, baseline.FormatChange(d.Change), comparison.Baseline, d.Baseline, d.Value,
+		)
+		if _, err := fmt.Fprintf(w, "::warning title=Benchmark regression::%s\n", escapeWorkflowCommand(message)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// escapeWorkflowCommand escapes the message of a GitHub Actions workflow command (e.g. "::warning::"),
+// so that it stays on a single line.
+func escapeWorkflowCommand(message string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(message)
+}
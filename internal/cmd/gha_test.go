@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/complexity"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+func TestExecuteJobSummary(t *testing.T) {
+	dir := t.TempDir()
+	summary := filepath.Join(dir, "summary.md")
+	t.Setenv(envStepSummary, summary)
+
+	cli := &Command{
+		Config:     writeTestConfig(t, testConfig()),
+		IsJSON:     true,
+		OutputFile: filepath.Join(dir, "output.html"),
+		GHA:        true,
+		L:          newTestLogger(),
+	}
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+	content, err := os.ReadFile(summary)
+	require.NoError(t, err)
+
+	output := string(content)
+	assert.Contains(t, output, "## Test\n")
+	assert.Contains(t, output, "\n### Comparisons\n\n| Benchmark (ns/op) | Reflect | Generics |\n| --- | --- | --- |\n")
+	assert.Contains(t, output, "\n### Artifacts\n\n- [output.html]("+filepath.Join(dir, "output.html")+") (html)\n")
+
+	t.Run("should append to an existing summary", func(t *testing.T) {
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+		appended, err := os.ReadFile(summary)
+		require.NoError(t, err)
+		assert.Equal(t, 2, bytes.Count(appended, []byte("## Test\n")))
+	})
+}
+
+func TestJobSummaryOutsideGitHubActions(t *testing.T) {
+	t.Setenv(envStepSummary, "")
+	cli := &Command{GHA: true, L: newTestLogger()}
+
+	require.NoError(t, cli.appendJobSummary(func(w io.Writer) error {
+		t.Fatal("should not write a job summary")
+
+		return nil
+	}))
+}
+
+func TestAnnotateRegressions(t *testing.T) {
+	dir := t.TempDir()
+	summary := filepath.Join(dir, "summary.md")
+	t.Setenv(envStepSummary, summary)
+
+	cfg := mustLoadTestConfig(t, testConfig())
+	cli := &Command{
+		BaselineDir: dir,
+		Threshold:   defaultThreshold,
+		GHA:         true,
+		L:           newTestLogger(),
+	}
+	ctx := context.Background()
+
+	require.NoError(t, cli.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{baselineSave, "main", writeBenchmarks(t, dir, "before.txt", 100)}))
+
+	var buf bytes.Buffer
+	require.NoError(t, cli.executeBaseline(ctx, &buf, cfg, []string{baselineCompare, "main", writeBenchmarks(t, dir, "after.txt", 150)}))
+	assert.Contains(t, buf.String(), "::warning title=Benchmark regression::greater/reflect/int (nsPerOp): +50.0%25 against baseline main (100 -> 150)\n")
+
+	content, err := os.ReadFile(summary)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "## Comparison with baseline main\n")
+
+	t.Run("should annotate regressions filtered out of reports", func(t *testing.T) {
+		cfg.Changes.Only = config.ChangeImprovements
+		defer func() { cfg.Changes.Only = "" }()
+
+		var filtered bytes.Buffer
+		require.NoError(t, cli.executeBaseline(ctx, &filtered, cfg, []string{baselineCompare, "main", writeBenchmarks(t, dir, "after.txt", 150)}))
+		assert.Contains(t, filtered.String(), "::warning title=Benchmark regression::greater/reflect/int (nsPerOp)")
+	})
+}
+
+func TestEscapeWorkflowCommand(t *testing.T) {
+	assert.EqualT(t, "+5%25 on two%0D%0Alines", escapeWorkflowCommand("+5% on two\r\nlines"))
+}
+
+func TestWriteComplexity(t *testing.T) {
+	category := model.Category{Fits: []model.Fit{
+		{Result: complexity.Result{Complexity: complexity.ONLogN}, Function: "sort", Series: "Generics", Metric: config.MetricNsPerOp},
+		{Result: complexity.Result{Complexity: complexity.O1}, Function: "sort", Series: "Generics", Metric: config.MetricAllocsPerOp},
+	}}
+
+	var b strings.Builder
+	writeComplexity(&b, category, config.MetricNsPerOp)
+	assert.EqualT(t, "\nEstimated complexity:\n\n- sort Generics: O(n log n)\n", b.String())
+
+	t.Run("should write nothing without fits", func(t *testing.T) {
+		var empty strings.Builder
+		writeComplexity(&empty, category, config.MetricBytesPerOp)
+		assert.Empty(t, empty.String())
+	})
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/color"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/store"
+)
+
+const (
+	// historyCommand is the first CLI argument that manages the runs stored in the SQLite database set with -sqlite,
+	// e.g. "benchviz -sqlite bench.db history prune".
+	historyCommand = "history"
+
+	// historyPrune deletes the runs beyond the retention policy.
+	historyPrune = "prune"
+
+	// historyShow prints the stored values of a benchmark over time.
+	historyShow = "show"
+)
+
+// Formats of the history printed by "history show".
+const (
+	historyFormatTable     = "table"
+	historyFormatSparkline = "sparkline"
+)
+
+// sparks are the bars of a sparkline, from the lowest to the highest value.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// executeHistory manages the runs stored in the SQLite database set with -sqlite.
+//
+// "history prune" applies the retention policy of the config (or -keep-runs, -keep-days), to all branches
+// or to the branch set with -branch.
+//
+// "history show" prints the stored values of the function set with -benchmark, over time,
+// possibly restricted to the runs tagged with -meta.
+func (c *Command) executeHistory(ctx context.Context, w io.Writer, cfg *config.Config, args []string) error {
+	if len(args) == 0 || (args[0] != historyPrune && args[0] != historyShow) || (args[0] == historyPrune && len(args) > 1) {
+		return fmt.Errorf("invalid history command %v: should be %q or %q", args, historyPrune, historyShow)
+	}
+
+	if c.SQLite == "" {
+		return errors.New("the history command requires a database, set with -sqlite")
+	}
+
+	if args[0] == historyShow {
+		// query flags are also accepted after the command, e.g. "history show -benchmark greater"
+		if err := c.historyFlags().Parse(args[1:]); err != nil {
+			return err
+		}
+	}
+
+	if args[0] == historyPrune && cfg.History.IsZero() {
+		return errors.New("no retention policy to prune the history: set history.keepRuns or history.keepDays in config, or -keep-runs or -keep-days")
+	}
+
+	db, err := store.Open(ctx, c.SQLite)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if args[0] == historyPrune {
+		return c.pruneResults(ctx, db, cfg.History, c.Branch)
+	}
+
+	return c.showHistory(ctx, w, db)
+}
+
+// historyFlags returns the flags of "history show", bound to the same settings as the global flags.
+func (c *Command) historyFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet(historyCommand+" "+historyShow, flag.ContinueOnError)
+	fs.StringVar(&c.Benchmark, "benchmark", c.Benchmark, "function ID of the benchmark to show")
+	fs.Var((*stringsFlag)(&c.Metrics), "metric", "show only this metric ID (may be repeated)")
+	fs.StringVar(&c.Since, "since", c.Since, "show only runs since this date (e.g. 2026-01-31) or duration (e.g. 30d, 12h)")
+	fs.StringVar(&c.Branch, "branch", c.Branch, "show only runs of this branch")
+	fs.Var((*stringsFlag)(&c.Meta), "meta", "show only runs tagged with this metadata, as key=value (may be repeated)")
+	fs.StringVar(&c.HistoryFormat, "history-format", c.HistoryFormat, "history output format: table or sparkline")
+
+	return fs
+}
+
+// showHistory prints the stored values of a benchmark over time, for each metric: either as a table
+// with a row per run and a column per series, or as a sparkline per series.
+func (c *Command) showHistory(ctx context.Context, w io.Writer, db *store.Store) error {
+	if c.Benchmark == "" {
+		return errors.New("no benchmark to show: set the function ID with -benchmark")
+	}
+
+	format := c.HistoryFormat
+	if format == "" {
+		format = historyFormatTable
+	}
+	if format != historyFormatTable && format != historyFormatSparkline {
+		return fmt.Errorf("unsupported history format %q: should be %q or %q", format, historyFormatTable, historyFormatSparkline)
+	}
+
+	meta, err := splitMeta(c.Meta)
+	if err != nil {
+		return err
+	}
+
+	query := store.Query{Function: c.Benchmark, Branch: c.Branch, Meta: meta}
+	for _, metric := range c.Metrics {
+		query.Metrics = append(query.Metrics, config.MetricName(metric))
+	}
+
+	if c.Since != "" {
+		since, err := parseSince(c.Since, time.Now())
+		if err != nil {
+			return err
+		}
+		query.Since = since
+	}
+
+	points, err := db.History(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	if len(points) == 0 {
+		return fmt.Errorf("no history of benchmark %q in %q", c.Benchmark, c.SQLite)
+	}
+
+	palette := c.palette()
+	var lines []color.Line
+
+	for i, metric := range historyMetrics(points) {
+		if i > 0 {
+			lines = append(lines, color.Line{})
+		}
+
+		var selected []store.Point
+		for _, point := range points {
+			if point.Metric == metric {
+				selected = append(selected, point)
+			}
+		}
+
+		title := c.Benchmark + ": " + string(metric)
+		if unit := selected[0].Unit; unit != "" {
+			title += " (" + unit + ")"
+		}
+		lines = append(lines, color.Line{Text: title, Style: palette.Bold})
+
+		if format == historyFormatSparkline {
+			lines = append(lines, sparklineLines(selected)...)
+
+			continue
+		}
+		lines = append(lines, historyTableLines(selected)...)
+	}
+
+	return color.WriteTable(w, lines)
+}
+
+// historyMetrics returns the metrics of data points, in order of appearance.
+func historyMetrics(points []store.Point) []config.MetricName {
+	var metrics []config.MetricName
+	for _, point := range points {
+		if !slices.Contains(metrics, point.Metric) {
+			metrics = append(metrics, point.Metric)
+		}
+	}
+
+	return metrics
+}
+
+// historySeries returns the keys of the series of data points, in order of appearance.
+func historySeries(points []store.Point) []string {
+	var keys []string
+	for _, point := range points {
+		if key := point.Key(); !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// historyTableLines renders data points as a table: one row per run, one column per series.
+func historyTableLines(points []store.Point) []color.Line {
+	keys := historySeries(points)
+	lines := []color.Line{{Text: "Run\tBranch\t" + strings.Join(keys, "\t")}}
+
+	for i := 0; i < len(points); {
+		run := points[i]
+		cells := make([]string, len(keys))
+		for j := range cells {
+			cells[j] = "-"
+		}
+
+		for ; i < len(points) && points[i].Run == run.Run; i++ {
+			cells[slices.Index(keys, points[i].Key())] = strconv.FormatFloat(points[i].Value, 'g', 4, 64) //nolint:mnd // 4 significant digits
+		}
+
+		lines = append(lines, color.Line{
+			Text: run.Created.Local().Format(time.DateTime) + "\t" + cmp.Or(run.Branch, "-") + "\t" + strings.Join(cells, "\t"),
+		})
+	}
+
+	return lines
+}
+
+// sparklineLines renders data points as a sparkline per series, with the range and the latest value.
+func sparklineLines(points []store.Point) []color.Line {
+	lines := []color.Line{{Text: "Series\tTrend\tMin\tMax\tLast\tRuns"}}
+
+	for _, key := range historySeries(points) {
+		var values []float64
+		for _, point := range points {
+			if point.Key() == key {
+				values = append(values, point.Value)
+			}
+		}
+
+		lines = append(lines, color.Line{
+			Text: fmt.Sprintf("%s\t%s\t%.4g\t%.4g\t%.4g\t%d",
+				cmp.Or(key, "-"), sparkline(values), slices.Min(values), slices.Max(values), values[len(values)-1], len(values),
+			),
+		})
+	}
+
+	return lines
+}
+
+// sparkline renders values as a line of bars, scaled between the lowest and the highest value.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := slices.Min(values), slices.Max(values)
+	var b strings.Builder
+	for _, value := range values {
+		index := len(sparks) / 2 //nolint:mnd // constant values are rendered in the middle
+		if high > low {
+			index = int(math.Round((value - low) / (high - low) * float64(len(sparks)-1)))
+		}
+		b.WriteRune(sparks[index])
+	}
+
+	return b.String()
+}
+
+// parseSince resolves the start of a period, given as a date (e.g. "2026-01-31"), a timestamp (RFC 3339),
+// or a duration before now (e.g. "12h", or "30d" for days).
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid -since %q: should be a date (e.g. 2026-01-31), a timestamp or a duration (e.g. 30d, 12h)", value)
+}
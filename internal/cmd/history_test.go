@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecuteHistoryPrune(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfig())
+	dir := t.TempDir()
+	database := filepath.Join(dir, "bench.db")
+
+	store := func(branch string) {
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: filepath.Join(dir, "output.html"),
+			SQLite:     database,
+			Branch:     branch,
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+	}
+
+	runs := func(t *testing.T) map[string]int {
+		t.Helper()
+
+		db, err := sql.Open("sqlite", database)
+		require.NoError(t, err)
+		defer db.Close()
+
+		rows, err := db.Query("SELECT branch, count(*) FROM runs GROUP BY branch")
+		require.NoError(t, err)
+		defer rows.Close()
+
+		counts := make(map[string]int)
+		for rows.Next() {
+			var (
+				branch string
+				count  int
+			)
+			require.NoError(t, rows.Scan(&branch, &count))
+			counts[branch] = count
+		}
+		require.NoError(t, rows.Err())
+
+		return counts
+	}
+
+	for range 3 {
+		store("main")
+		store("feature")
+	}
+	require.Equal(t, map[string]int{"main": 3, "feature": 3}, runs(t))
+
+	t.Run("should fail without a retention policy", func(t *testing.T) {
+		cli := &Command{Config: cfgFile, SQLite: database, L: newTestLogger()}
+		require.ErrorContains(t, cli.Execute(historyCommand, historyPrune), "no retention policy")
+	})
+
+	t.Run("should fail without a database", func(t *testing.T) {
+		cli := &Command{Config: cfgFile, KeepRuns: 1, L: newTestLogger()}
+		require.ErrorContains(t, cli.Execute(historyCommand, historyPrune), "-sqlite")
+	})
+
+	t.Run("should prune a single branch", func(t *testing.T) {
+		cli := &Command{Config: cfgFile, SQLite: database, KeepRuns: 2, Branch: "feature", L: newTestLogger()}
+		require.NoError(t, cli.Execute(historyCommand, historyPrune))
+		assert.Equal(t, map[string]int{"main": 3, "feature": 2}, runs(t))
+	})
+
+	t.Run("should prune all branches", func(t *testing.T) {
+		cli := &Command{Config: cfgFile, SQLite: database, KeepRuns: 1, L: newTestLogger()}
+		require.NoError(t, cli.Execute(historyCommand, historyPrune))
+		assert.Equal(t, map[string]int{"main": 1, "feature": 1}, runs(t))
+	})
+
+	t.Run("should prune when storing with a retention policy", func(t *testing.T) {
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: filepath.Join(dir, "output.html"),
+			SQLite:     database,
+			Branch:     "main",
+			KeepRuns:   1,
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+		assert.Equal(t, map[string]int{"main": 1, "feature": 1}, runs(t))
+	})
+
+	t.Run("should fail with an unknown action", func(t *testing.T) {
+		cli := &Command{Config: cfgFile, SQLite: database, L: newTestLogger()}
+		require.Error(t, cli.Execute(historyCommand, "drop"))
+	})
+}
+
+func TestExecuteHistoryShow(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfig())
+	dir := t.TempDir()
+	database := filepath.Join(dir, "bench.db")
+
+	for i, branch := range []string{"main", "main", "feature"} {
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: filepath.Join(dir, "output.html"),
+			SQLite:     database,
+			Branch:     branch,
+			Meta:       []string{"run=" + strconv.Itoa(i)},
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+	}
+
+	cfg := mustLoadTestConfig(t, testConfig())
+	show := func(t *testing.T, cli *Command, args ...string) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		require.NoError(t, cli.executeHistory(context.Background(), &buf, cfg, append([]string{historyShow}, args...)))
+
+		return buf.String()
+	}
+
+	t.Run("should print a table", func(t *testing.T) {
+		cli := &Command{SQLite: database, Benchmark: "greater", Metrics: []string{"nsPerOp"}, L: newTestLogger()}
+		output := show(t, cli)
+
+		assert.Contains(t, output, "greater: nsPerOp (ns/op)\n")
+		assert.NotContains(t, output, "allocsPerOp")
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		require.Len(t, lines, 5)
+		assert.Contains(t, lines[1], "Run")
+		assert.Contains(t, lines[1], "generics/int")
+		assert.Contains(t, lines[4], "feature")
+	})
+
+	t.Run("should accept flags after the command", func(t *testing.T) {
+		cli := &Command{SQLite: database, L: newTestLogger()}
+		output := show(t, cli, "-benchmark", "greater", "-metric", "nsPerOp", "-branch", "main", "-history-format", "sparkline")
+
+		assert.Contains(t, output, "Series")
+		assert.Contains(t, output, "Trend")
+		assert.NotContains(t, output, "feature")
+		assert.Contains(t, output, "▅▅")
+	})
+
+	t.Run("should filter by metadata", func(t *testing.T) {
+		cli := &Command{SQLite: database, Benchmark: "greater", Metrics: []string{"nsPerOp"}, L: newTestLogger()}
+		output := show(t, cli, "-meta", "run=2")
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		require.Len(t, lines, 3)
+		assert.Contains(t, lines[2], "feature")
+	})
+
+	t.Run("should filter by date", func(t *testing.T) {
+		cli := &Command{SQLite: database, Benchmark: "greater", Since: "2099-01-01", L: newTestLogger()}
+		err := cli.executeHistory(context.Background(), &bytes.Buffer{}, cfg, []string{historyShow})
+		require.ErrorContains(t, err, "no history")
+	})
+
+	t.Run("should fail without a benchmark", func(t *testing.T) {
+		cli := &Command{SQLite: database, L: newTestLogger()}
+		require.ErrorContains(t, cli.executeHistory(context.Background(), &bytes.Buffer{}, cfg, []string{historyShow}), "-benchmark")
+	})
+
+	t.Run("should fail with an unsupported format", func(t *testing.T) {
+		cli := &Command{SQLite: database, Benchmark: "greater", HistoryFormat: "chart", L: newTestLogger()}
+		require.Error(t, cli.executeHistory(context.Background(), &bytes.Buffer{}, cfg, []string{historyShow}))
+	})
+}
+
+func TestSparkline(t *testing.T) {
+	assert.EqualT(t, "▁▅█", sparkline([]float64{1, 2, 3}))
+	assert.EqualT(t, "▅▅", sparkline([]float64{3, 3}))
+	assert.Empty(t, sparkline(nil))
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		input string
+		want  time.Time
+	}{
+		{input: "30d", want: now.AddDate(0, 0, -30)},
+		{input: "12h", want: now.Add(-12 * time.Hour)},
+		{input: "2026-01-31T10:00:00Z", want: time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			since, err := parseSince(tt.input, now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(since))
+		})
+	}
+
+	t.Run("should parse a date", func(t *testing.T) {
+		since, err := parseSince("2026-01-31", now)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 1, 31, 0, 0, 0, 0, time.Local), since)
+	})
+
+	t.Run("should reject an invalid value", func(t *testing.T) {
+		_, err := parseSince("yesterday", now)
+		require.Error(t, err)
+	})
+}
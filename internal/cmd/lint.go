@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/organizer"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// lint checks the config against sample benchmarks and prints the issues found.
+//
+// Candidate function matches are suggested for benchmarks that match no function.
+// It fails whenever some issue is found, so a config may be checked in CI.
+func (c *Command) lint(w io.Writer, cfg *config.Config, sets []parser.Set) error {
+	opts, err := c.organizerOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	o := organizer.New(cfg, opts...)
+	issues, err := o.Lint(sets)
+	if err != nil {
+		return fmt.Errorf("linting config: %w", err)
+	}
+
+	palette := c.palette()
+	ew := &errWriter{w: w}
+	if suggestions := organizer.SuggestFunctions(o.Unmatched()); len(suggestions) > 0 {
+		ew.printf("%s\n", palette.Yellow("Benchmarks matching no function, with suggested function matches:"))
+		for _, suggestion := range suggestions {
+			ew.printf("  - %s\n", suggestion)
+		}
+	}
+
+	if len(issues) == 0 {
+		ew.printf("%s\n", palette.Green("No issue found in config"))
+
+		return ew.err
+	}
+
+	ew.printf("%s\n", palette.Red(fmt.Sprintf("Config issues: %d", len(issues))))
+	for _, issue := range issues {
+		ew.printf("  - %s\n", palette.Yellow(issue.String()))
+	}
+	if ew.err != nil {
+		return ew.err
+	}
+
+	return fmt.Errorf("config lint: %d issue(s) found", len(issues))
+}
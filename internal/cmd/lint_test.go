@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestLint(t *testing.T) {
+	inputCfg := mustLoadTestConfig(t, testConfig())
+	inputCfg.IsJSON = true
+	p, err := parseInputs(inputCfg, []string{parserTestdataPath("sample_generics.json")})
+	require.NoError(t, err)
+
+	t.Run("should report no issue", func(t *testing.T) {
+		cfg := mustLoadTestConfig(t, testConfig())
+		cli := &Command{L: newTestLogger()}
+
+		var buf bytes.Buffer
+		require.NoError(t, cli.lint(&buf, cfg, p.Sets()))
+		assert.Equal(t, "No issue found in config\n", buf.String())
+	})
+
+	t.Run("should report issues and fail", func(t *testing.T) {
+		cfg := mustLoadTestConfig(t, strings.Replace(testConfig(), "contexts:", `  - id: greaterOrEqual
+    Match: 'Greater'
+contexts:`, 1)+`
+  - id: greater-or-equal
+    includes:
+      functions: [greaterOrEqual]
+      metrics: [nsPerOp]
+`)
+
+		cli := &Command{L: newTestLogger()}
+
+		var buf bytes.Buffer
+		err := cli.lint(&buf, cfg, p.Sets())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 issue(s) found")
+		assert.Contains(t, buf.String(), "Config issues: 2\n")
+		assert.Contains(t, buf.String(), `  - function "greaterOrEqual": shadowed (shadowed by: greater)`)
+		assert.Contains(t, buf.String(), `  - category "greater-or-equal": empty charts`)
+		assert.NotContains(t, buf.String(), "suggested function matches")
+
+		t.Run("with colors", func(t *testing.T) {
+			colored := &Command{L: newTestLogger(), Color: "always"}
+
+			buf.Reset()
+			require.Error(t, colored.lint(&buf, cfg, p.Sets()))
+			assert.Contains(t, buf.String(), "\x1b[31mConfig issues: 2\x1b[0m\n")
+			assert.Contains(t, buf.String(), "  - \x1b[33mfunction \"greaterOrEqual\"")
+		})
+	})
+}
+
+func TestLintSuggestions(t *testing.T) {
+	inputCfg := mustLoadTestConfig(t, testConfig())
+	inputCfg.IsJSON = true
+	p, err := parseInputs(inputCfg, []string{parserTestdataPath("sample_generics.json")})
+	require.NoError(t, err)
+
+	cfg := mustLoadTestConfig(t, testConfig())
+	cfg.Functions = cfg.Functions[:1]
+	cli := &Command{L: newTestLogger()}
+
+	var buf bytes.Buffer
+	_ = cli.lint(&buf, cfg, p.Sets())
+	assert.Contains(t, buf.String(), "Benchmarks matching no function, with suggested function matches:\n")
+	assert.Contains(t, buf.String(), `  - '^BenchmarkLess`)
+}
+
+func TestExecuteLint(t *testing.T) {
+	cli := &Command{
+		Config: writeTestConfig(t, testConfig()),
+		IsJSON: true,
+		Lint:   true,
+		L:      newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Supported log formats.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// newLogger builds a structured logger writing to w, with the given level ("debug", "info", "warn" or "error")
+// and format ("text" or "json").
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: should be one of [debug info warn error]", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case "", logFormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case logFormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: should be one of [%s %s]", format, logFormatText, logFormatJSON)
+	}
+}
+
+// setupLogger configures the loggers of the command from the log level and format CLI flags.
+//
+// The quiet and verbose flags override the log level.
+//
+// The root logger is injected in all the packages invoked by the command.
+func (c *Command) setupLogger(w io.Writer) error {
+	level := c.LogLevel
+	switch {
+	case c.Quiet && c.Verbose:
+		return errors.New("flags --quiet and --verbose are mutually exclusive")
+	case c.Quiet:
+		level = slog.LevelWarn.String()
+	case c.Verbose:
+		level = slog.LevelDebug.String()
+	}
+
+	root, err := newLogger(w, level, c.LogFormat)
+	if err != nil {
+		return err
+	}
+
+	c.root = root
+	c.L = root.With(slog.String("module", "main"))
+
+	return nil
+}
+
+// logger returns the root logger to be injected in other packages.
+func (c *Command) logger() *slog.Logger {
+	if c.root == nil {
+		return slog.Default()
+	}
+
+	return c.root
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	t.Run("text logger filters levels", func(t *testing.T) {
+		var buf bytes.Buffer
+		l, err := newLogger(&buf, "warn", "")
+		require.NoError(t, err)
+
+		l.Info("hidden")
+		l.Warn("shown")
+		assert.NotContains(t, buf.String(), "hidden")
+		assert.Contains(t, buf.String(), "level=WARN msg=shown")
+	})
+
+	t.Run("json logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		l, err := newLogger(&buf, "DEBUG", "json")
+		require.NoError(t, err)
+
+		l.Debug("shown")
+		assert.Contains(t, buf.String(), `"msg":"shown"`)
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		_, err := newLogger(&bytes.Buffer{}, "verbose", "text")
+		require.Error(t, err)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := newLogger(&bytes.Buffer{}, "info", "xml")
+		require.Error(t, err)
+	})
+}
+
+func TestSetupLoggerQuietVerbose(t *testing.T) {
+	t.Run("quiet suppresses informational logs", func(t *testing.T) {
+		var buf bytes.Buffer
+		cli := &Command{LogLevel: "debug", Quiet: true}
+		require.NoError(t, cli.setupLogger(&buf))
+
+		cli.L.Info("hidden")
+		cli.L.Warn("shown")
+		assert.NotContains(t, buf.String(), "hidden")
+		assert.Contains(t, buf.String(), "shown")
+	})
+
+	t.Run("verbose enables debug logs", func(t *testing.T) {
+		var buf bytes.Buffer
+		cli := &Command{LogLevel: "error", Verbose: true}
+		require.NoError(t, cli.setupLogger(&buf))
+
+		cli.L.Debug("shown")
+		assert.Contains(t, buf.String(), "shown")
+	})
+
+	t.Run("quiet and verbose are exclusive", func(t *testing.T) {
+		cli := &Command{Quiet: true, Verbose: true}
+		require.Error(t, cli.setupLogger(&bytes.Buffer{}))
+	})
+}
+
+func TestExecuteInjectsLogger(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfig())
+	outFile := filepath.Join(t.TempDir(), "output.html")
+
+	cli := &Command{
+		Config:     cfgFile,
+		IsJSON:     true,
+		OutputFile: outFile,
+		LogLevel:   "debug",
+		LogFormat:  "json",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cli.setupLogger(&buf))
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+	_, err := os.Stat(outFile)
+	require.NoError(t, err)
+
+	logs := buf.String()
+	assert.Contains(t, logs, `"module":"parser"`)
+	assert.Contains(t, logs, `"module":"organizer"`)
+	assert.Contains(t, logs, `"module":"chart"`)
+	assert.Contains(t, logs, `"msg":"benchmark matched"`)
+}
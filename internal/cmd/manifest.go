@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+// manifestFile is the name of the manifest produced in an output directory.
+const manifestFile = "manifest.json"
+
+// Kinds of produced artifacts.
+const (
+	artifactHTML   = "html"
+	artifactPNG    = "png"
+	artifactReport = "report"
+	artifactExport = "export"
+	artifactSQLite = "sqlite"
+)
+
+// artifact is an output file produced by the command.
+type artifact struct {
+	Kind string
+	Path string
+}
+
+// Manifest lists all the artifacts produced by a run, so CI pipelines may upload and reference them programmatically.
+//
+// Timings report the time spent in each phase of the run, to diagnose slow CI steps.
+type Manifest struct {
+	Artifacts []ManifestEntry  `json:"artifacts"`
+	Timings   []ManifestTiming `json:"timings,omitempty"`
+}
+
+// ManifestEntry describes a produced artifact.
+//
+// The path is relative to the location of the manifest, whenever possible.
+type ManifestEntry struct {
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestTiming is the time spent in a phase of the run: parse, organize, chart, screenshot,
+// and the total time of the run.
+type ManifestTiming struct {
+	Phase   string  `json:"phase"`
+	Seconds float64 `json:"seconds"`
+}
+
+// produced records an artifact written to a file. Outputs sent to standard output are ignored.
+func (c *Command) produced(kind, file string) {
+	if file == "" || file == "-" {
+		return
+	}
+
+	c.artifacts = append(c.artifacts, artifact{Kind: kind, Path: file})
+}
+
+// manifestPath returns the file to write the manifest to, if any.
+func (c *Command) manifestPath(cfg *config.Config) string {
+	if c.Manifest != "" {
+		return c.Manifest
+	}
+
+	if cfg.Outputs.Directory != "" {
+		return filepath.Join(cfg.Outputs.Directory, manifestFile)
+	}
+
+	return ""
+}
+
+// writeManifest writes a JSON manifest of all produced artifacts, with their sizes and hashes.
+func (c *Command) writeManifest(cfg *config.Config) error {
+	file := c.manifestPath(cfg)
+	if file == "" {
+		return nil
+	}
+
+	manifest := Manifest{
+		Artifacts: make([]ManifestEntry, 0, len(c.artifacts)),
+	}
+
+	for _, produced := range c.artifacts {
+		entry, err := newManifestEntry(produced, filepath.Dir(file))
+		if err != nil {
+			return err
+		}
+		manifest.Artifacts = append(manifest.Artifacts, entry)
+	}
+
+	for _, timing := range c.phaseTimings() {
+		manifest.Timings = append(manifest.Timings, ManifestTiming{Phase: timing.phase, Seconds: timing.elapsed.Seconds()})
+	}
+
+	manifestWriter, manifestCloser, err := getWriter(file, "manifest")
+	if err != nil {
+		return err
+	}
+	defer manifestCloser()
+
+	enc := json.NewEncoder(manifestWriter)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+
+	c.L.Info("manifest written", slog.String("file", file), slog.Int("artifacts", len(manifest.Artifacts)))
+
+	return nil
+}
+
+func newManifestEntry(produced artifact, base string) (ManifestEntry, error) {
+	rdr, cleanup, err := getReader(produced.Path, produced.Kind)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	defer cleanup()
+
+	h := sha256.New()
+	size, err := io.Copy(h, rdr)
+	if err != nil {
+		return ManifestEntry{}, fmt.Errorf("hashing %s file %q: %w", produced.Kind, produced.Path, err)
+	}
+
+	pth := produced.Path
+	if rel, err := filepath.Rel(base, produced.Path); err == nil {
+		pth = filepath.ToSlash(rel)
+	}
+
+	return ManifestEntry{
+		Kind:   produced.Kind,
+		Path:   pth,
+		Size:   size,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
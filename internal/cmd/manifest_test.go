@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecuteManifest(t *testing.T) {
+	t.Run("should list artifacts produced in an output directory", func(t *testing.T) {
+		cfgFile := writeTestConfig(t, testConfig())
+		outDir := t.TempDir()
+
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: outDir,
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+		manifest := readManifest(t, filepath.Join(outDir, manifestFile))
+		require.Len(t, manifest.Artifacts, 3)
+
+		assert.Equal(t, artifactHTML, manifest.Artifacts[0].Kind)
+		assert.Equal(t, "index.html", manifest.Artifacts[0].Path)
+		assert.Equal(t, "Test-comparisons.html", manifest.Artifacts[1].Path)
+		assert.Equal(t, artifactReport, manifest.Artifacts[2].Kind)
+		assert.Equal(t, "report.json", manifest.Artifacts[2].Path)
+
+		for _, entry := range manifest.Artifacts {
+			content, err := os.ReadFile(filepath.Join(outDir, entry.Path))
+			require.NoError(t, err)
+			sum := sha256.Sum256(content)
+
+			assert.Equal(t, int64(len(content)), entry.Size)
+			assert.Equal(t, hex.EncodeToString(sum[:]), entry.SHA256)
+		}
+	})
+
+	t.Run("should write an explicit manifest for a single output file", func(t *testing.T) {
+		cfgFile := writeTestConfig(t, testConfig())
+		dir := t.TempDir()
+		manifestPath := filepath.Join(dir, "artifacts.json")
+
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: filepath.Join(dir, "output.html"),
+			Manifest:   manifestPath,
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+		manifest := readManifest(t, manifestPath)
+		require.Len(t, manifest.Artifacts, 1)
+		assert.Equal(t, "output.html", manifest.Artifacts[0].Path)
+		assert.NotZero(t, manifest.Artifacts[0].Size)
+	})
+
+	t.Run("should not write a manifest by default", func(t *testing.T) {
+		cfgFile := writeTestConfig(t, testConfig())
+		dir := t.TempDir()
+
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: filepath.Join(dir, "output.html"),
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+		_, err := os.Stat(filepath.Join(dir, manifestFile))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func readManifest(t *testing.T, file string) Manifest {
+	t.Helper()
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(content, &manifest))
+
+	return manifest
+}
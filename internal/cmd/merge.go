@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// mergeCommand is the first CLI argument that merges scenarios previously exported as JSON,
+// e.g. "benchviz merge linux.json:label=linux darwin.json:label=darwin".
+const mergeCommand = "merge"
+
+// executeMerge merges scenarios exported with "-export json=FILE", then renders the merged scenario.
+//
+// Each input is labeled, like "file:label=value". By default, the label is the environment of the scenario,
+// or else the base name of the file.
+func (c *Command) executeMerge(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("invalid merge command: should be followed by scenario files exported as JSON")
+	}
+
+	files, labels := splitLabels(args)
+	scenarios := make([]*model.Scenario, 0, len(files))
+	mergeLabels := make([]string, 0, len(files))
+
+	for _, file := range files {
+		scenario, err := readScenario(file)
+		if err != nil {
+			return err
+		}
+
+		scenarios = append(scenarios, scenario)
+		mergeLabels = append(mergeLabels, cmp.Or(labels[file], scenarioEnvironment(scenario), strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))))
+	}
+
+	environments := make([]string, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		environments = append(environments, scenario.Environments()...)
+	}
+	if err := c.checkEnvironments(cfg, failure.StageOrganize, model.EnvironmentDiff(environments)); err != nil {
+		return err
+	}
+
+	merged := model.Merge(cmp.Or(cfg.Name, scenarios[0].Name), mergeLabels, scenarios)
+	c.L.Info("scenarios merged", slog.Int("scenarios", len(scenarios)), slog.Int("categories", len(merged.Categories)))
+	c.inputs = files
+
+	return c.render(ctx, cfg, nil, merged)
+}
+
+func readScenario(file string) (*model.Scenario, error) {
+	rdr, closer, err := getReader(file, "scenario")
+	if err != nil {
+		return nil, err
+	}
+	defer closer()
+
+	var scenario model.Scenario
+	if err := json.NewDecoder(rdr).Decode(&scenario); err != nil {
+		return nil, fmt.Errorf("decoding scenario %q: %w", file, err)
+	}
+
+	if len(scenario.Categories) == 0 {
+		return nil, fmt.Errorf("decoding scenario %q: no category found", file)
+	}
+
+	return &scenario, nil
+}
+
+// scenarioEnvironment returns the environment of the first category that knows about it.
+func scenarioEnvironment(scenario *model.Scenario) string {
+	for _, category := range scenario.Categories {
+		if category.Environment != "" {
+			return category.Environment
+		}
+	}
+
+	return ""
+}
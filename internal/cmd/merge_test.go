@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/export"
+	"github.com/fredbi/benchviz/internal/model"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecuteMerge(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := writeTestConfig(t, testConfig())
+	cfg := mustLoadTestConfig(t, testConfig())
+
+	// scenarios exported by two CI jobs
+	var exported []string
+	for _, job := range []string{"linux", "darwin"} {
+		cli := &Command{L: newTestLogger()}
+		p, err := cli.parse(context.Background(), cfg, []string{writeBenchmarks(t, dir, job+".txt", 100)})
+		require.NoError(t, err)
+		scenario, err := cli.scenarize(cfg, p.Sets())
+		require.NoError(t, err)
+		if job == "darwin" {
+			// without environment, the label defaults to the file name
+			for i := range scenario.Categories {
+				scenario.Categories[i].Environment = ""
+			}
+		}
+		scenario.Meta = map[string]string{"job": job, "os": job}
+		for i := range scenario.Categories {
+			scenario.Categories[i].MetricLayout = config.MetricLayoutSelector
+			scenario.Categories[i].AxisLabels = config.Labels{Width: 80}
+			scenario.Categories[i].Fits = []model.Fit{{Function: "greater", Series: "Reflect", Metric: config.MetricNsPerOp}}
+		}
+
+		file := filepath.Join(dir, job+".json")
+		out, err := os.Create(file)
+		require.NoError(t, err)
+		require.NoError(t, export.WriteJSON(context.Background(), out, scenario))
+		require.NoError(t, out.Close())
+		exported = append(exported, file)
+	}
+
+	t.Run("should merge labeled scenarios", func(t *testing.T) {
+		merged := filepath.Join(dir, "merged.json")
+		cli := &Command{
+			Config:     cfgFile,
+			OutputFile: filepath.Join(dir, "merged.html"),
+			Exports:    []string{"json=" + merged},
+			Meta:       []string{"job=merge"},
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute(mergeCommand, exported[0]+":label=linux", exported[1]))
+
+		content, err := os.ReadFile(merged)
+		require.NoError(t, err)
+		var scenario model.Scenario
+		require.NoError(t, json.Unmarshal(content, &scenario))
+
+		assert.Equal(t, map[string]string{"job": "merge", "os": "linux"}, scenario.Meta)
+
+		require.Len(t, scenario.Categories, 1)
+		var titles []string
+		for _, data := range scenario.Categories[0].Data {
+			for _, series := range data.Series {
+				titles = append(titles, series.Title)
+			}
+		}
+		assert.Contains(t, titles, "Reflect (linux)")
+		assert.Contains(t, titles, "Reflect (darwin)")
+
+		category := scenario.Categories[0]
+		assert.EqualT(t, config.MetricLayoutSelector, category.MetricLayout)
+		assert.EqualT(t, 80, category.AxisLabels.Width)
+		require.Len(t, category.Fits, 2)
+		assert.EqualT(t, "Reflect (linux)", category.Fits[0].Series)
+		assert.EqualT(t, "Reflect (darwin)", category.Fits[1].Series)
+
+		info, err := os.Stat(cli.OutputFile)
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size())
+	})
+
+	t.Run("should fail on incompatible environments when strict", func(t *testing.T) {
+		var incompatible []string
+		for _, env := range []string{"linux amd64 cpu: CPU A", "linux arm64 cpu: CPU B"} {
+			scenario, err := readScenario(exported[0])
+			require.NoError(t, err)
+			for i := range scenario.Categories {
+				scenario.Categories[i].Environment = env
+			}
+
+			content, err := json.Marshal(scenario)
+			require.NoError(t, err)
+			file := filepath.Join(dir, config.SanitizeFileName(env)+".json")
+			require.NoError(t, os.WriteFile(file, content, 0o600))
+			incompatible = append(incompatible, file)
+		}
+
+		cli := &Command{Config: cfgFile, OutputFile: filepath.Join(dir, "strict.html"), Strict: "all", L: newTestLogger()}
+		err := cli.Execute(append([]string{mergeCommand}, incompatible...)...)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "goarch: amd64 != arm64")
+
+		cli.Strict = ""
+		require.NoError(t, cli.Execute(append([]string{mergeCommand}, incompatible...)...))
+	})
+
+	t.Run("should fail without inputs", func(t *testing.T) {
+		cli := &Command{Config: cfgFile, OutputFile: filepath.Join(dir, "none.html"), L: newTestLogger()}
+		require.Error(t, cli.Execute(mergeCommand))
+	})
+
+	t.Run("should fail with invalid inputs", func(t *testing.T) {
+		invalid := filepath.Join(dir, "invalid.json")
+		require.NoError(t, os.WriteFile(invalid, []byte(`{"Categories": []}`), 0o600))
+
+		cli := &Command{Config: cfgFile, OutputFile: filepath.Join(dir, "invalid.html"), L: newTestLogger()}
+		require.Error(t, cli.Execute(mergeCommand, invalid))
+	})
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/chart"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// resolveMetadata resolves the metadata of the run, embedded in the rendered pages.
+//
+// It is resolved once, so that all the pages of a run carry the same metadata.
+// Input files are those of the parser, if any, or else those of a merge.
+func (c *Command) resolveMetadata(ctx context.Context, cfg *config.Config, p *parser.BenchmarkParser) {
+	if c.metadata != nil {
+		return
+	}
+
+	inputs := c.inputs
+	if p != nil {
+		inputs = inputFilesOf(p.Sets())
+	}
+
+	metadata := c.newMetadata(ctx, cfg, inputs)
+	c.metadata = &metadata
+}
+
+// newMetadata builds the metadata of a page generated now from some input files.
+func (c *Command) newMetadata(ctx context.Context, cfg *config.Config, inputs []string) chart.Metadata {
+	metadata := chart.Metadata{
+		Config:    cmp.Or(cfg.Name, filepath.Base(c.Config)),
+		Inputs:    inputs,
+		Generated: time.Now(),
+		Version:   benchvizVersion(),
+	}
+
+	commit, err := gitCommit(ctx)
+	if err != nil {
+		c.L.Debug("no git commit in page metadata", slog.String("error", err.Error()))
+	}
+	metadata.Commit = commit
+
+	return metadata
+}
+
+// inputFilesOf returns the distinct input files of parsed sets, in order.
+func inputFilesOf(sets []parser.Set) []string {
+	var inputs []string
+	for _, set := range sets {
+		if set.File != "" && !slices.Contains(inputs, set.File) {
+			inputs = append(inputs, set.File)
+		}
+	}
+
+	return inputs
+}
+
+// benchvizVersion returns the version of the benchviz module, as recorded in the binary.
+func benchvizVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	return info.Main.Version
+}
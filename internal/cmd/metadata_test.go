@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecuteMetadata(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfigText())
+	outFile := filepath.Join(t.TempDir(), "output.html")
+
+	original := gitCommand
+	gitCommand = "/nonexistent/git"
+	t.Cleanup(func() { gitCommand = original })
+
+	cli := &Command{
+		Config:     cfgFile,
+		OutputFile: outFile,
+		Meta:       []string{"feature=<on>"},
+		L:          newTestLogger(),
+	}
+
+	input := parserTestdataPath("run.txt")
+	require.NoError(t, cli.Execute(input))
+
+	content, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+
+	html := string(content)
+	assert.StringContainsT(t, html, `<details id="benchviz-metadata"`)
+	assert.StringContainsT(t, html, "<code>"+input+"</code>")
+	assert.StringContainsT(t, html, "<dt>Generated</dt>")
+	assert.NotContains(t, html, "<dt>Commit</dt>")
+	assert.StringContainsT(t, html, "<dt>feature</dt><dd style=\"margin:0\">&lt;on&gt;</dd>")
+
+	t.Run("should fail with invalid metadata", func(t *testing.T) {
+		cli := &Command{Config: cfgFile, OutputFile: outFile, Meta: []string{"feature"}, L: newTestLogger()}
+		require.ErrorContains(t, cli.Execute(input), "key=value")
+	})
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+// browserCommand builds the command that opens a file with the default application of the platform
+// (e.g. a web browser for HTML files).
+var browserCommand = func(file string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", file)
+	case "windows":
+		return exec.Command("cmd", "/c", "start", "", file)
+	default:
+		return exec.Command("xdg-open", file)
+	}
+}
+
+// openResult opens the rendered output in the default browser.
+//
+// The HTML page is opened, unless it is a temporary file: the PNG image is opened instead.
+// A compressed HTML page is opened as an uncompressed temporary copy.
+// Nothing is opened when the output is sent to standard output.
+func (c *Command) openResult(cfg *config.Config) error {
+	if !c.Open {
+		return nil
+	}
+
+	file := cfg.Outputs.HTMLFile
+	if cfg.Outputs.IsTemp {
+		file = cfg.Outputs.PngFile
+	}
+
+	if file == "" || file == "-" {
+		c.L.Warn("output sent to standard output: nothing to open")
+
+		return nil
+	}
+
+	if strings.HasSuffix(file, gzipExt) {
+		// browsers don't render compressed files: an uncompressed copy is opened instead
+		uncompressed, err := uncompressedCopy(file)
+		if err != nil {
+			return err
+		}
+		file = uncompressed
+	}
+
+	cmd := browserCommand(file)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("opening %q: %w", file, err)
+	}
+
+	c.L.Info("output opened", slog.String("file", file))
+
+	// the opener runs detached: we don't wait for it to complete
+	return cmd.Process.Release()
+}
+
+// uncompressedCopy writes an uncompressed copy of a gzip-compressed HTML file to a temporary file.
+//
+// The copy is not removed, since the browser opens it after the command is done.
+func uncompressedCopy(file string) (string, error) {
+	rdr, closer, err := readHTML(file)
+	if err != nil {
+		return "", err
+	}
+	defer closer()
+
+	tmp, err := os.CreateTemp("", "benchviz.*.html")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(tmp, rdr); err != nil {
+		_ = tmp.Close()
+
+		return "", fmt.Errorf("uncompressing %q: %w", file, err)
+	}
+
+	return tmp.Name(), tmp.Close()
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestOpenResult(t *testing.T) {
+	var opened []string
+	original := browserCommand
+	browserCommand = func(file string) *exec.Cmd {
+		opened = append(opened, file)
+
+		return exec.Command(os.Args[0], "-test.run=^$") // a harmless command: runs no test
+	}
+	t.Cleanup(func() { browserCommand = original })
+
+	t.Run("should open rendered HTML", func(t *testing.T) {
+		opened = nil
+		cfgFile := writeTestConfig(t, testConfig())
+		outFile := filepath.Join(t.TempDir(), "output.html")
+
+		cli := &Command{
+			Config:     cfgFile,
+			IsJSON:     true,
+			OutputFile: outFile,
+			Open:       true,
+			L:          newTestLogger(),
+		}
+
+		require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+		assert.Equal(t, []string{outFile}, opened)
+	})
+
+	t.Run("should open an uncompressed copy of compressed HTML", func(t *testing.T) {
+		opened = nil
+		outFile := filepath.Join(t.TempDir(), "output.html.gz")
+		cfg := &config.Config{Outputs: config.Output{HTMLFile: outFile, Compress: true}}
+		require.NoError(t, writeHTML(cfg, outFile, func(w io.Writer) error {
+			_, err := io.WriteString(w, "<html></html>")
+
+			return err
+		}))
+
+		cli := &Command{Open: true, L: newTestLogger()}
+		require.NoError(t, cli.openResult(cfg))
+		require.Len(t, opened, 1)
+		t.Cleanup(func() { _ = os.Remove(opened[0]) })
+
+		assert.True(t, strings.HasSuffix(opened[0], ".html"))
+		content, err := os.ReadFile(opened[0])
+		require.NoError(t, err)
+		assert.EqualT(t, "<html></html>", string(content))
+	})
+
+	t.Run("should open PNG when HTML is temporary", func(t *testing.T) {
+		opened = nil
+		cli := &Command{Open: true, L: newTestLogger()}
+
+		require.NoError(t, cli.openResult(&config.Config{
+			Outputs: config.Output{HTMLFile: "tmp.html", PngFile: "out.png", IsTemp: true},
+		}))
+		assert.Equal(t, []string{"out.png"}, opened)
+	})
+
+	t.Run("should not open standard output", func(t *testing.T) {
+		opened = nil
+		cli := &Command{Open: true, L: newTestLogger()}
+
+		require.NoError(t, cli.openResult(&config.Config{
+			Outputs: config.Output{HTMLFile: "-"},
+		}))
+		assert.Empty(t, opened)
+	})
+
+	t.Run("should not open unless requested", func(t *testing.T) {
+		opened = nil
+		cli := &Command{L: newTestLogger()}
+
+		require.NoError(t, cli.openResult(&config.Config{
+			Outputs: config.Output{HTMLFile: "out.html"},
+		}))
+		assert.Empty(t, opened)
+	})
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/chart"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+const (
+	// defaultOutputTemplate is the default file name template for outputs produced in an output directory.
+	defaultOutputTemplate = "{name}-{category}"
+
+	// indexPage is the base name of the page with all charts, in an output directory.
+	indexPage = "index"
+
+	// reportFile is the name of the report produced in an output directory.
+	reportFile = "report.json"
+
+	// defaultScreenshotConcurrency is the default maximum number of pages screenshot at the same time,
+	// each by a headless browser.
+	defaultScreenshotConcurrency = 4
+)
+
+// gitCommand is the git executable used to resolve the {commit} placeholder in output file names,
+// the commit in the metadata of pages, and the branch of stored runs.
+var gitCommand = "git"
+
+// isOutputDir reports whether the output is a directory: either an existing one, or a path
+// with a trailing separator.
+func isOutputDir(output string) bool {
+	if output == "" || output == "-" {
+		return false
+	}
+
+	if strings.HasSuffix(output, "/") || strings.HasSuffix(output, string(os.PathSeparator)) {
+		return true
+	}
+
+	info, err := os.Stat(output)
+
+	return err == nil && info.IsDir()
+}
+
+// renderDirectory produces the outputs of the output directory mode, in addition to the index page:
+// one HTML page (and PNG image) per category, and a report about the input benchmarks, when parsed.
+//
+// PNG images are rendered concurrently, once all pages are written (see [Command.renderImages]).
+//
+// File names are built from the output template.
+func (c *Command) renderDirectory(ctx context.Context, cfg *config.Config, p *parser.BenchmarkParser, scenario *model.Scenario) error {
+	dir := cfg.Outputs.Directory
+	if dir == "" {
+		return nil
+	}
+
+	placeholders := c.templatePlaceholders(ctx, cfg)
+	var screenshots []screenshot
+
+	for _, category := range scenario.Categories {
+		placeholders["{category}"] = category.ID
+		base := filepath.Join(dir, expandTemplate(c.outputTemplate(), placeholders))
+		htmlFile := compressedFile(cfg, base+".html")
+
+		categoryScenario := &model.Scenario{Name: scenario.Name, Categories: []model.Category{category}}
+		stop := c.measure(phaseChart)
+		err := writeHTML(cfg, htmlFile, func(w io.Writer) error {
+			return c.renderPage(w, c.newPage(cfg, categoryScenario), categoryScenario)
+		})
+		stop()
+		if err != nil {
+			return fmt.Errorf("rendering page for category %q: %w", category.ID, err)
+		}
+		c.L.Info("category page written", slog.String("category", category.ID), slog.String("file", htmlFile))
+		c.produced(artifactHTML, htmlFile)
+
+		if cfg.Outputs.PngFile != "" {
+			screenshots = append(screenshots, screenshot{htmlFile: htmlFile, pngFile: base + ".png"})
+		}
+	}
+
+	if err := c.renderImages(ctx, cfg, screenshots); err != nil {
+		return err
+	}
+
+	for _, job := range screenshots {
+		c.produced(artifactPNG, job.pngFile)
+	}
+
+	if p == nil {
+		// no report about inputs, e.g. for merged scenarios
+		return nil
+	}
+
+	reportPath := filepath.Join(dir, reportFile)
+	reportWriter, reportCloser, err := getWriter(reportPath, "report")
+	if err != nil {
+		return err
+	}
+
+	err = p.Report().Write(reportWriter, parser.ReportFormatJSON)
+	reportCloser()
+	if err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	c.produced(artifactReport, reportPath)
+
+	return nil
+}
+
+func (c *Command) outputTemplate() string {
+	if c.OutputTemplate == "" {
+		return defaultOutputTemplate
+	}
+
+	return c.OutputTemplate
+}
+
+// templatePlaceholders resolves the values of the placeholders of the output template, but {category}.
+//
+// The commit is only resolved when required by the template.
+func (c *Command) templatePlaceholders(ctx context.Context, cfg *config.Config) map[string]string {
+	name := cfg.Name
+	if name == "" {
+		name = "benchviz"
+	}
+
+	placeholders := map[string]string{
+		"{name}": name,
+		"{date}": time.Now().Format(time.DateOnly),
+	}
+
+	if strings.Contains(c.outputTemplate(), "{commit}") {
+		placeholders["{commit}"] = c.currentCommit(ctx)
+	}
+
+	return placeholders
+}
+
+// currentCommit returns the short hash of the current git commit, or "unknown".
+func (c *Command) currentCommit(ctx context.Context) string {
+	commit, err := gitCommit(ctx)
+	if err != nil {
+		c.L.Warn("could not resolve the current git commit", slog.String("error", err.Error()))
+
+		return "unknown"
+	}
+
+	return commit
+}
+
+// gitCommit returns the short hash of the current git commit.
+func gitCommit(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, gitCommand, "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitBranch returns the name of the current git branch.
+//
+// It fails on a detached HEAD, e.g. when a CI job checks out a commit.
+func gitBranch(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, gitCommand, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		return "", errors.New("detached HEAD")
+	}
+
+	return branch, nil
+}
+
+// expandTemplate replaces placeholders in a file name template.
+//
+// Values are sanitized so they may be safely used in a file name.
+func expandTemplate(template string, placeholders map[string]string) string {
+	replacements := make([]string, 0, 2*len(placeholders)) //nolint:mnd // pairs of old, new strings
+	for placeholder, value := range placeholders {
+		replacements = append(replacements, placeholder, config.SanitizeFileName(value))
+	}
+
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// screenshot is a HTML page to render as a PNG image.
+type screenshot struct {
+	htmlFile string
+	pngFile  string
+}
+
+// renderImages renders HTML pages as PNG images, with at most render.screenshot.concurrency
+// headless browsers running at the same time.
+//
+// All screenshots are attempted: errors are joined.
+func (c *Command) renderImages(ctx context.Context, cfg *config.Config, screenshots []screenshot) error {
+	if len(screenshots) == 0 {
+		return nil
+	}
+
+	limit := cfg.Render.Screenshot.Concurrency
+	if limit < 1 {
+		limit = min(runtime.GOMAXPROCS(0), defaultScreenshotConcurrency)
+	}
+
+	// the wall time of concurrent screenshots is measured: browsers wait for pages to render
+	// in rounds of at most limit pages
+	defer c.measure(phaseScreenshot)()
+	rounds := (len(screenshots) + limit - 1) / limit
+	c.slept(time.Duration(rounds) * screenshotSleep(cfg))
+
+	errs := chart.Concurrently(limit, screenshots, func(job screenshot) error {
+		if err := c.renderImage(ctx, cfg, job.htmlFile, job.pngFile); err != nil {
+			return fmt.Errorf("page %q: %w", job.htmlFile, err)
+		}
+
+		return nil
+	})
+
+	return errors.Join(errs...)
+}
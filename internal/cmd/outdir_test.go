@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestIsOutputDir(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.False(t, isOutputDir(""))
+	assert.False(t, isOutputDir("-"))
+	assert.False(t, isOutputDir(filepath.Join(dir, "output.html")))
+	assert.True(t, isOutputDir(dir))
+	assert.True(t, isOutputDir(filepath.Join(dir, "new")+"/"))
+}
+
+func TestExpandTemplate(t *testing.T) {
+	name := expandTemplate("{name}-{category}-{date}-{commit}", map[string]string{
+		"{name}":     "my benchmarks",
+		"{category}": "a/b",
+		"{date}":     "2026-10-16",
+		"{commit}":   "abc1234",
+	})
+
+	assert.Equal(t, "my-benchmarks-a-b-2026-10-16-abc1234", name)
+}
+
+func TestExecuteOutputDir(t *testing.T) {
+	original := gitCommand
+	gitCommand = "/nonexistent/git"
+	t.Cleanup(func() { gitCommand = original })
+
+	cfgFile := writeTestConfig(t, testConfig())
+	outDir := filepath.Join(t.TempDir(), "results") + "/"
+
+	cli := &Command{
+		Config:         cfgFile,
+		IsJSON:         true,
+		OutputFile:     outDir,
+		OutputTemplate: "{name}-{category}-{date}-{commit}",
+		L:              newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+	today := time.Now().Format(time.DateOnly)
+	for _, file := range []string{
+		"index.html",
+		"Test-comparisons-" + today + "-unknown.html",
+		"report.json",
+	} {
+		info, err := os.Stat(filepath.Join(outDir, file))
+		require.NoError(t, err, file)
+		assert.NotZero(t, info.Size(), file)
+	}
+}
+
+func TestRenderImages(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{Render: config.Rendering{Screenshot: config.Screenshot{Concurrency: 1}}}
+	cli := &Command{L: newTestLogger()}
+
+	require.NoError(t, cli.renderImages(context.Background(), cfg, nil))
+
+	// pages are missing: all screenshots fail before starting a browser, and all errors are reported
+	err := cli.renderImages(context.Background(), cfg, []screenshot{
+		{htmlFile: filepath.Join(dir, "a.html"), pngFile: filepath.Join(dir, "a.png")},
+		{htmlFile: filepath.Join(dir, "b.html"), pngFile: filepath.Join(dir, "b.png")},
+	})
+	require.Error(t, err)
+	assert.StringContainsT(t, err.Error(), "a.html")
+	assert.StringContainsT(t, err.Error(), "b.html")
+}
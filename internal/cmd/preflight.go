@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
+	"github.com/fredbi/benchviz/internal/organizer"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// preflight matches the names of all parsed benchmarks against the config, and logs a coverage summary
+// (e.g. "87/92 benchmarks matched; 5 skipped: ...") before spending time on organizing and rendering.
+//
+// It fails fast when no benchmark is matched at all, since there would be nothing to chart.
+func (c *Command) preflight(cfg *config.Config, sets []parser.Set) error {
+	opts, err := c.organizerOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	coverage := organizer.New(cfg, opts...).Coverage(sets)
+	switch {
+	case coverage.Total == 0:
+		return nil
+	case coverage.Matched == 0:
+		err := &failure.Error{
+			Stage:  failure.StageConfig,
+			Reason: "no benchmark matched",
+			Err:    errors.New("no benchmark matched the functions in config: " + coverage.String() + ". Stopping here"),
+		}
+		c.L.Error("config check failed", slog.String("error", err.Error()))
+
+		return err
+	case len(coverage.Skipped) > 0:
+		c.L.Warn("config check", slog.String("coverage", coverage.String()))
+	default:
+		c.L.Info("config check", slog.String("coverage", coverage.String()))
+	}
+
+	return nil
+}
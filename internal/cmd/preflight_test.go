@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecutePreflight(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := writeTestConfig(t, testConfig())
+	input := filepath.Join(dir, "bench.txt")
+	require.NoError(t, os.WriteFile(input, []byte("goos: linux\nBenchmarkUnknown/reflect/int-16   1000   100 ns/op\n"), 0o600))
+	output := filepath.Join(dir, "output.html")
+
+	t.Run("should fail fast when no benchmark matches the config", func(t *testing.T) {
+		cli := &Command{
+			Config:     cfgFile,
+			OutputFile: output,
+			L:          newTestLogger(),
+		}
+
+		err := cli.Execute(input)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "0/1 benchmarks matched; 1 skipped: BenchmarkUnknown/reflect/int-16")
+		_, err = os.Stat(output)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("should render as before when the check is skipped", func(t *testing.T) {
+		cli := &Command{
+			Config:        cfgFile,
+			OutputFile:    output,
+			SkipPreflight: true,
+			L:             newTestLogger(),
+		}
+
+		require.NoError(t, cli.Execute(input))
+		_, err := os.Stat(output)
+		require.NoError(t, err)
+	})
+}
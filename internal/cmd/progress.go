@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/parser"
+	"golang.org/x/term"
+)
+
+// progressInterval is the minimum delay between two updates of the progress indicator.
+const progressInterval = 200 * time.Millisecond
+
+// isTerminal reports whether a file is a terminal (e.g. stderr is not redirected).
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd())) //nolint:gosec // file descriptors fit in an int
+}
+
+// progressReporter prints a progress indicator for the parsing of input files on a single, refreshed line.
+type progressReporter struct {
+	w       io.Writer
+	now     func() time.Time
+	started time.Time
+	printed time.Time
+}
+
+func newProgressReporter(w io.Writer) *progressReporter {
+	now := time.Now
+
+	return &progressReporter{
+		w:       w,
+		now:     now,
+		started: now(),
+	}
+}
+
+// report the progress of parsing, at most every [progressInterval] until all inputs are parsed.
+func (r *progressReporter) report(progress parser.Progress) {
+	now := r.now()
+	done := progress.Done()
+	if !done && now.Sub(r.printed) < progressInterval {
+		return
+	}
+	r.printed = now
+
+	line := fmt.Sprintf("parsing inputs: %d/%d files, %d benchmarks, %3.0f%%",
+		progress.Files, progress.TotalFiles, progress.Benchmarks, 100*progress.Fraction(), //nolint:mnd // percent
+	)
+
+	switch {
+	case done:
+		line += fmt.Sprintf(" in %s", now.Sub(r.started).Round(time.Millisecond))
+	case progress.Fraction() > 0:
+		elapsed := now.Sub(r.started)
+		eta := time.Duration(float64(elapsed)/progress.Fraction()) - elapsed
+		line += fmt.Sprintf(", ETA %s", eta.Round(time.Second))
+	}
+
+	// carriage return and erase to the end of line
+	fmt.Fprint(r.w, "\r"+line+"\x1b[K")
+	if done {
+		fmt.Fprintln(r.w)
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestProgressReporter(t *testing.T) {
+	var buf bytes.Buffer
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	r := newProgressReporter(&buf)
+	r.started = start
+	r.now = func() time.Time { return now }
+
+	now = start.Add(10 * time.Second)
+	r.report(parser.Progress{Files: 1, TotalFiles: 4, Benchmarks: 100, Bytes: 25, TotalBytes: 100})
+	assert.StringContainsT(t, buf.String(), "parsing inputs: 1/4 files, 100 benchmarks,  25%, ETA 30s")
+
+	t.Run("should throttle updates", func(t *testing.T) {
+		buf.Reset()
+		now = now.Add(progressInterval / 2)
+		r.report(parser.Progress{Files: 2, TotalFiles: 4, Bytes: 50, TotalBytes: 100})
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("should always report completion", func(t *testing.T) {
+		buf.Reset()
+		r.report(parser.Progress{Files: 4, TotalFiles: 4, Benchmarks: 400, Bytes: 100, TotalBytes: 100})
+		assert.StringContainsT(t, buf.String(), "4/4 files, 400 benchmarks, 100% in 10.1s")
+		assert.True(t, strings.HasSuffix(buf.String(), "\n"))
+	})
+}
+
+func TestIsTerminal(t *testing.T) {
+	t.Run("should not take a character device for a terminal", func(t *testing.T) {
+		null, err := os.Open(os.DevNull)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = null.Close() })
+
+		assert.False(t, isTerminal(null))
+	})
+
+	t.Run("should not take a regular file for a terminal", func(t *testing.T) {
+		file, err := os.CreateTemp(t.TempDir(), "output")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = file.Close() })
+
+		assert.False(t, isTerminal(file))
+	})
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/chart"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// defaultDashboardTitle is the title of a dashboard combining several projects, unless set with -title.
+const defaultDashboardTitle = "Benchmark dashboard"
+
+// project is a config with its own inputs, rendered as a section of a dashboard combining several projects
+// (see -project).
+//
+// The name of a project is the name of its config, or else the name of the directory of its config file.
+// Categories of the dashboard are identified by the ID of their project, followed by their own ID, e.g. "mylib.encode".
+type project struct {
+	name   string
+	id     string
+	config string
+	inputs []string
+	cfg    *config.Config
+}
+
+// parseProjects parses the values of -project, as config=input[,input...].
+func parseProjects(values []string) ([]project, error) {
+	projects := make([]project, 0, len(values))
+
+	for _, value := range values {
+		file, inputs, ok := strings.Cut(value, "=")
+		if !ok || file == "" || inputs == "" {
+			return nil, fmt.Errorf("invalid project %q: expected config=input[,input...]", value)
+		}
+
+		projects = append(projects, project{config: file, inputs: strings.Split(inputs, ",")})
+	}
+
+	return projects, nil
+}
+
+// loadProjects loads the config of every project declared with -project, with the overrides of CLI flags.
+//
+// It returns the config of the first project, which drives the outputs of the dashboard.
+func (c *Command) loadProjects() (*config.Config, error) {
+	if len(c.Inputs) > 0 {
+		return nil, failure.WithStage(failure.StageConfig, errors.New("the inputs of a dashboard must be set with -project"))
+	}
+
+	projects, err := parseProjects(c.Projects)
+	if err != nil {
+		return nil, failure.WithStage(failure.StageConfig, err)
+	}
+
+	ids := make(map[string]string, len(projects))
+	for i := range projects {
+		p := &projects[i]
+
+		cfg, err := config.Load(p.config)
+		if err != nil {
+			return nil, &failure.Error{Stage: failure.StageConfig, File: p.config, Reason: err.Error(), Err: fmt.Errorf("loading config: %w", err)}
+		}
+		if err := c.overrideConfig(cfg); err != nil {
+			return nil, failure.WithStage(failure.StageConfig, err)
+		}
+
+		dir, err := filepath.Abs(filepath.Dir(p.config))
+		if err != nil {
+			return nil, err
+		}
+
+		p.cfg = cfg
+		p.name = cmp.Or(cfg.Name, filepath.Base(dir))
+		p.id = config.SanitizeFileName(strings.ToLower(p.name))
+		if other, ok := ids[p.id]; ok {
+			return nil, failure.WithStage(failure.StageConfig, fmt.Errorf("projects %q and %q have the same name: set a distinct name in their config", other, p.config))
+		}
+		ids[p.id] = p.config
+	}
+
+	c.projects = projects
+
+	return projects[0].cfg, nil
+}
+
+// loadDashboard parses and organizes the inputs of every project, and combines them into a single scenario.
+func (c *Command) loadDashboard(ctx context.Context) (*parser.BenchmarkParser, *model.Scenario, error) {
+	var (
+		sets     []parser.Set
+		combined = &model.Scenario{Name: cmp.Or(c.Title, defaultDashboardTitle)}
+	)
+
+	for _, project := range c.projects {
+		p, scenario, err := c.loadInputs(ctx, project.config, project.cfg, project.inputs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("project %q: %w", project.name, err)
+		}
+
+		sets = append(sets, p.Sets()...)
+		for _, category := range scenario.Categories {
+			category.ID = project.id + "." + category.ID
+			category.Project = project.name
+			combined.Categories = append(combined.Categories, category)
+		}
+		c.L.Info("project loaded", slog.String("project", project.name), slog.Int("categories", len(scenario.Categories)))
+	}
+
+	opts, err := c.parserOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := parser.New(c.projects[0].cfg, opts...)
+	p.AddSets(sets...)
+
+	return p, combined, nil
+}
+
+// dashboardPage builds a page with a section per project, each rendered with the config of its project.
+//
+// Charts are cached per project, when cached.
+func (c *Command) dashboardPage(scenario *model.Scenario, opts []chart.BuilderOption) *chart.Page {
+	page := chart.NewPage(scenario.Name)
+
+	for _, project := range c.projects {
+		section := &model.Scenario{Name: project.name, Meta: scenario.Meta}
+		for _, category := range scenario.Categories {
+			if category.Project == project.name {
+				section.Categories = append(section.Categories, category)
+			}
+		}
+		if len(section.Categories) == 0 {
+			continue
+		}
+
+		projectOpts := append(slices.Clone(opts), chart.WithCacheScope(project.id))
+		page.AddSection(project.name, chart.New(project.cfg, section, projectOpts...).BuildPage())
+	}
+
+	return page
+}
+
+// configFiles returns the config files of the run: the config file of every project of a dashboard,
+// or else the config file set with -config.
+func (c *Command) configFiles() []string {
+	if len(c.projects) == 0 {
+		if c.Config == "" {
+			return nil
+		}
+
+		return []string{c.Config}
+	}
+
+	files := make([]string, 0, len(c.projects))
+	for _, project := range c.projects {
+		files = append(files, project.config)
+	}
+
+	return files
+}
+
+// inputFiles returns the input files of the run: the inputs of every project of a dashboard,
+// or else the inputs set as arguments or with -input.
+func (c *Command) inputFiles(args []string) []string {
+	inputs := append(slices.Clone(c.Inputs), args...)
+	for _, project := range c.projects {
+		inputs = append(inputs, project.inputs...)
+	}
+	files, _ := splitLabels(inputs)
+
+	return files
+}
+
+// reloadConfig loads the config files of the run again, with the overrides of CLI flags.
+func (c *Command) reloadConfig() (*config.Config, error) {
+	if len(c.projects) > 0 {
+		return c.loadProjects()
+	}
+
+	cfg, err := config.Load(c.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, c.overrideConfig(cfg)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/server"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecuteDashboard(t *testing.T) {
+	dir := t.TempDir()
+	first := writeTestConfig(t, strings.Replace(testConfig(), "name: Test", "name: First", 1))
+	second := writeTestConfig(t, testConfig())
+	firstInput := writeBenchmarks(t, dir, "first.txt", 100)
+	secondInput := writeBenchmarks(t, dir, "second.txt", 200)
+	projects := []string{first + "=" + firstInput, second + "=" + secondInput}
+
+	t.Run("should render a section per project", func(t *testing.T) {
+		output := filepath.Join(dir, "dashboard.html")
+		exported := filepath.Join(dir, "dashboard.json")
+		cli := &Command{
+			Projects:   projects,
+			OutputFile: output,
+			Exports:    []string{"json=" + exported},
+			L:          newTestLogger(),
+		}
+		require.NoError(t, cli.Execute())
+
+		content, err := os.ReadFile(output)
+		require.NoError(t, err)
+		html := string(content)
+		assert.Contains(t, html, "<title>Benchmark dashboard</title>")
+		assert.Contains(t, html, `font-family:sans-serif">First</h2>`)
+		assert.Contains(t, html, `font-family:sans-serif">Test</h2>`)
+		assert.Contains(t, html, `id="benchviz-metadata"`)
+		assert.Contains(t, html, "<code>"+secondInput+"</code>")
+
+		content, err = os.ReadFile(exported)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"ID": "first.comparisons"`)
+		assert.Contains(t, string(content), `"Project": "Test"`)
+	})
+
+	t.Run("should serve a dashboard", func(t *testing.T) {
+		cli := &Command{Projects: projects, L: newTestLogger()}
+		cfg, err := cli.loadProjects()
+		require.NoError(t, err)
+		handler, err := cli.serveHandler(context.Background(), cfg, nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/categories", nil))
+		require.EqualT(t, http.StatusOK, rec.Code)
+
+		var categories []server.CategorySummary
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &categories))
+		require.Len(t, categories, 2)
+		assert.EqualT(t, "first.comparisons", categories[0].ID)
+		assert.EqualT(t, "test.comparisons", categories[1].ID)
+	})
+
+	t.Run("should fail on invalid projects", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			cli      *Command
+			expected string
+		}{
+			{
+				name:     "missing inputs",
+				cli:      &Command{Projects: []string{first}},
+				expected: "expected config=input",
+			},
+			{
+				name:     "duplicate names",
+				cli:      &Command{Projects: []string{second + "=" + firstInput, second + "=" + secondInput}},
+				expected: "have the same name",
+			},
+			{
+				name:     "inputs outside projects",
+				cli:      &Command{Projects: projects, Inputs: []string{firstInput}},
+				expected: "must be set with -project",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				tc.cli.L = newTestLogger()
+				require.ErrorContains(t, tc.cli.Execute(), tc.expected)
+			})
+		}
+
+		cli := &Command{Projects: projects, OutputFile: filepath.Join(dir, "other.html"), L: newTestLogger()}
+		require.ErrorContains(t, cli.Execute(firstInput), "must be set with -project")
+	})
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// quickCommand is the first CLI argument that renders input benchmarks without a config,
+// e.g. "benchviz quick -o bench.html bench.txt".
+const quickCommand = "quick"
+
+// executeQuick renders input benchmarks in one step, with a config generated from the input benchmarks
+// (like -generate-config) and one category per top-level benchmark. No config file is read or written.
+func (c *Command) executeQuick(ctx context.Context, args []string) error {
+	return c.renderGenerated(ctx, append(slices.Clone(c.Inputs), args...), func(input config.GenerateInput) *config.Config {
+		input.CategoryPerBenchmark = true
+
+		return config.Generate(input)
+	})
+}
+
+// renderGenerated parses input files, then renders them with a config generated from the input benchmarks.
+//
+// The generate function builds the config from the functions and metrics found in the inputs.
+func (c *Command) renderGenerated(ctx context.Context, inputs []string, generate func(config.GenerateInput) *config.Config) error {
+	defaults, err := config.LoadDefaults()
+	if err != nil {
+		return fmt.Errorf("loading defaults: %w", err)
+	}
+	defaults.IsJSON = c.IsJSON
+	defaults.KeepZeroMetrics = c.KeepZeroMetrics
+
+	opts, err := c.parserOptions()
+	if err != nil {
+		return err
+	}
+
+	if len(inputs) == 0 { // no file is provided: assume stdin
+		inputs = append(inputs, "-")
+	}
+
+	stop := c.measure(phaseParse)
+	p, err := parseInputs(defaults, inputs, opts...)
+	stop()
+	if err != nil {
+		return failure.WithStage(failure.StageParse, err)
+	}
+
+	cfg := generate(generateInputFromReports([]parser.ParsingReport{p.Report()}))
+	if err := cfg.Validate(); err != nil {
+		return failure.WithStage(failure.StageConfig, fmt.Errorf("generating config: %w", err))
+	}
+
+	cleanup, err := c.prepareOutputs(cfg)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	stop = c.measure(phaseOrganize)
+	scenario, err := c.scenarize(cfg, p.Sets())
+	stop()
+	if err != nil {
+		return failure.WithStage(failure.StageOrganize, err)
+	}
+
+	return failure.WithStage(failure.StageRender, c.render(ctx, cfg, p, scenario))
+}
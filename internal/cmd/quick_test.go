@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecuteQuick(t *testing.T) {
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "output.html")
+
+	cli := &Command{
+		Config:     filepath.Join(dir, "missing.yaml"), // no config is read or written
+		IsJSON:     true,
+		OutputFile: outFile,
+		L:          newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute(quickCommand, parserTestdataPath("sample_generics.json")))
+
+	content, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.Greater(t, strings.Count(string(content), `class="item"`), 1, "expected a chart per top-level benchmark")
+
+	_, err = os.Stat(cli.Config)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
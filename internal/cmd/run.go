@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+const (
+	// runCommand is the first CLI argument that triggers a "go test" run instead of reading input files.
+	runCommand = "run"
+
+	// runInput is the name given to the benchmark set produced by a "go test" run.
+	runInput = "go test"
+)
+
+// goCommand is the go toolchain executable used to run benchmarks.
+var goCommand = "go"
+
+// runBenchmarks invokes "go test -json" with the provided arguments (e.g. "./... -bench . -benchmem")
+// and streams its output through the benchmark parser.
+//
+// Unit tests are skipped by default ("-run ^$"). This may be overridden by passing an explicit "-run" argument.
+//
+// The standard error of "go test" is forwarded to our standard error.
+//
+// The version of the Go toolchain is recorded with the benchmarks.
+func (c *Command) runBenchmarks(ctx context.Context, cfg *config.Config, goTestArgs []string, opts ...parser.Option) (*parser.BenchmarkParser, error) {
+	cfg.IsJSON = true // go test is always run with -json
+
+	opts = slices.Concat(opts, []parser.Option{parser.WithParseJSON(true), parser.WithZeroMetrics(cfg.KeepZeroMetrics)})
+	if version, err := goVersion(ctx); err != nil {
+		c.L.Warn("could not resolve the version of the Go toolchain", slog.String("error", err.Error()))
+	} else {
+		opts = append(opts, parser.WithGoVersion(version))
+	}
+	p := parser.New(cfg, opts...)
+
+	cmdArgs := append([]string{"test", "-json", "-run", "^$"}, goTestArgs...)
+	cmd := exec.CommandContext(ctx, goCommand, cmdArgs...)
+	cmd.Stderr = os.Stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("preparing go test: %w", err)
+	}
+
+	c.L.Info("running benchmarks", slog.Any("args", cmdArgs))
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting go test: %w", err)
+	}
+
+	parseErr := p.ParseReader(runInput, stdout)
+	if parseErr != nil {
+		// the output is no longer read: stop go test, which would otherwise block on a full pipe
+		_ = cmd.Process.Kill()
+	}
+	waitErr := cmd.Wait()
+
+	if parseErr != nil {
+		return nil, fmt.Errorf("parsing go test output: %w", parseErr)
+	}
+
+	if waitErr != nil {
+		return nil, fmt.Errorf("running go test: %w", waitErr)
+	}
+
+	return p, nil
+}
+
+// goVersion returns the version of the Go toolchain used to run benchmarks, e.g. "go1.23.4".
+func goVersion(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, goCommand, "env", "GOVERSION").Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
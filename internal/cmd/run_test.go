@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestExecuteRun(t *testing.T) {
+	pkgDir := writeTestBenchmarkPackage(t)
+	cfgFile := writeTestConfig(t, testConfigRun())
+	outFile := filepath.Join(t.TempDir(), "output.html")
+
+	t.Chdir(pkgDir)
+
+	cli := &Command{
+		Config:     cfgFile,
+		OutputFile: outFile,
+		L:          newTestLogger(),
+	}
+
+	require.NoError(t, cli.Execute("run", ".", "-bench", ".", "-benchtime", "1x", "-benchmem"))
+
+	info, err := os.Stat(outFile)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Size())
+}
+
+func TestExecuteRunFailure(t *testing.T) {
+	pkgDir := writeTestBenchmarkPackage(t)
+	cfgFile := writeTestConfig(t, testConfigRun())
+
+	t.Chdir(pkgDir)
+
+	cli := &Command{
+		Config:     cfgFile,
+		OutputFile: filepath.Join(t.TempDir(), "output.html"),
+		L:          newTestLogger(),
+	}
+
+	require.Error(t, cli.Execute("run", "./nonexistent", "-bench", "."))
+}
+
+func TestRunBenchmarksParseFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell script")
+	}
+
+	// a fake go command, writing a line too long to be parsed, and then output without end
+	dir := t.TempDir()
+	script := filepath.Join(dir, "go")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+[ "$1" = env ] && echo go1.26 && exit 0
+head -c 100000 /dev/zero | tr '\0' x
+echo
+exec yes '{"Action":"output"}'
+`), 0o700)) //nolint:gosec // executable script
+
+	previous := goCommand
+	goCommand = script
+	t.Cleanup(func() { goCommand = previous })
+
+	cli := &Command{L: newTestLogger()}
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	_, err := cli.runBenchmarks(ctx, &config.Config{}, []string{"."})
+	require.ErrorContains(t, err, "parsing go test output")
+	require.NoError(t, ctx.Err())
+}
+
+func writeTestBenchmarkPackage(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/bench\n\ngo 1.25\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bench_test.go"), []byte(`package bench
+
+import (
+	"strings"
+	"testing"
+)
+
+func BenchmarkJoin(b *testing.B) {
+	for _, size := range []string{"small", "large"} {
+		b.Run(size, func(b *testing.B) {
+			for range b.N {
+				_ = strings.Join([]string{size, size}, ",")
+			}
+		})
+	}
+}
+`), 0o600))
+
+	return dir
+}
+
+func testConfigRun() string {
+	return `
+name: Run Test
+metrics:
+  - id: nsPerOp
+    title: Timings
+    axis: 'ns/op'
+functions:
+  - id: join
+    Match: 'Join'
+contexts:
+  - id: small
+    Match: '/small'
+  - id: large
+    Match: '/large'
+categories:
+  - id: join
+    includes:
+      metrics: [nsPerOp]
+`
+}
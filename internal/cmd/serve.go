@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+	"github.com/fredbi/benchviz/internal/chart"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/grafana"
+	"github.com/fredbi/benchviz/internal/server"
+)
+
+const (
+	// serveCommand is the first CLI argument that serves the organized benchmarks over HTTP,
+	// e.g. "benchviz serve bench.json".
+	serveCommand = "serve"
+
+	// defaultAddr is the default address the HTTP server listens on.
+	defaultAddr = "localhost:8080"
+
+	// defaultReloadInterval is the default interval at which the config and inputs are checked for changes.
+	defaultReloadInterval = time.Second
+
+	// grafanaPrefix is the path under which the history is served to Grafana datasources.
+	grafanaPrefix = "/grafana/"
+
+	serveReadHeaderTimeout = 10 * time.Second
+	serveShutdownTimeout   = 5 * time.Second
+)
+
+// executeServe serves the HTML page and a JSON API about the input benchmarks, until interrupted.
+//
+// Inputs are checked for changes on every request, so results are always up to date.
+// Inputs are parsed again only when some input file changed, and only the charts of the categories
+// affected by the change are built again. The config file is loaded again when it changes.
+// With -cache-dir, unchanged inputs are restored from the cache instead.
+func (c *Command) executeServe(ctx context.Context, cfg *config.Config, args []string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	handler, err := c.serveHandler(ctx, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              c.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+		// requests streaming events to live-reloaded pages end on shutdown
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+
+		_ = srv.Shutdown(shutdownCtx) //nolint:contextcheck // the parent context is already canceled
+	}()
+
+	c.L.Info("serving benchmarks", slog.String("address", "http://"+c.Addr))
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving benchmarks: %w", err)
+	}
+
+	return nil
+}
+
+// serveHandler builds the HTTP handler of the serve command.
+//
+// Besides the page and the JSON API about inputs, the history of baseline snapshots is served to Grafana under /grafana/.
+//
+// With -reload-interval, the config file and the inputs are checked for changes until the context is done,
+// and pages open in a browser reload on changes.
+func (c *Command) serveHandler(ctx context.Context, cfg *config.Config, args []string) (http.Handler, error) {
+	if len(args) == 0 && len(c.Inputs) == 0 && len(c.projects) == 0 {
+		return nil, errors.New("the serve command requires input files")
+	}
+	if slices.Contains(args, "-") {
+		return nil, errors.New("the serve command cannot read inputs from standard input")
+	}
+
+	if c.Template != "" {
+		// parse the page template once: requests are served concurrently
+		tmpl, err := chart.ParseTemplate(c.Template)
+		if err != nil {
+			return nil, err
+		}
+		c.page = tmpl
+	}
+
+	data := c.watchData(cfg, args)
+	opts := []server.Option{
+		server.WithPage(data.page),
+		server.WithLogger(c.logger()),
+	}
+	if c.ReloadInterval > 0 {
+		data.changes = server.NewChanges()
+		opts = append(opts, server.WithLiveReload(data.changes))
+		go data.watch(ctx, c.ReloadInterval)
+	}
+
+	history := func(context.Context) ([]baseline.Snapshot, error) {
+		return baseline.ReadDir(c.baselineDir())
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", server.New(data.load, opts...))
+	mux.HandleFunc("POST /api/refresh", data.serveRefresh)
+	mux.Handle(grafanaPrefix, http.StripPrefix(strings.TrimSuffix(grafanaPrefix, "/"), grafana.New(history, c.logger())))
+
+	return mux, nil
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/server"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestServeHandler(t *testing.T) {
+	dir := t.TempDir()
+	cfg := mustLoadTestConfig(t, testConfig())
+	input := writeBenchmarks(t, dir, "bench.txt", 100)
+
+	cli := &Command{BaselineDir: filepath.Join(dir, "baselines"), L: newTestLogger()}
+	require.NoError(t, cli.executeBaseline(context.Background(), &bytes.Buffer{}, cfg, []string{baselineSave, "main", input}))
+	handler, err := cli.serveHandler(context.Background(), cfg, []string{input})
+	require.NoError(t, err)
+
+	t.Run("should serve the organized scenario", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/scenario", nil))
+		require.EqualT(t, http.StatusOK, rec.Code)
+
+		var scenario model.Scenario
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &scenario))
+		assert.NotEmpty(t, scenario.Categories)
+	})
+
+	t.Run("should serve live results", func(t *testing.T) {
+		writeBenchmarks(t, dir, "bench.txt", 200)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/raw", nil))
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"nsPerOp":200`)
+	})
+
+	t.Run("should not parse unchanged inputs again", func(t *testing.T) {
+		load := cli.watchData(cfg, []string{input}).load
+		data, err := load(context.Background())
+		require.NoError(t, err)
+
+		again, err := load(context.Background())
+		require.NoError(t, err)
+		assert.True(t, data.Scenario == again.Scenario)
+
+		writeBenchmarks(t, dir, "bench.txt", 300)
+		changed, err := load(context.Background())
+		require.NoError(t, err)
+		assert.False(t, data.Scenario == changed.Scenario)
+		assert.Equal(t, []string{"comparisons"}, affectedCategories(changed.Scenario, []string{input}))
+	})
+
+	t.Run("should serve the HTML page", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "echarts")
+		assert.Contains(t, rec.Body.String(), "<code>"+input+"</code>") // metadata of the page
+	})
+
+	t.Run("should serve the history to Grafana", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/grafana/metrics", strings.NewReader("{}")))
+		require.EqualT(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "greater/reflect/int (nsPerOp)")
+	})
+
+	t.Run("should require input files", func(t *testing.T) {
+		_, err := cli.serveHandler(context.Background(), cfg, nil)
+		require.ErrorContains(t, err, "requires input files")
+
+		_, err = cli.serveHandler(context.Background(), cfg, []string{"-"})
+		require.ErrorContains(t, err, "standard input")
+	})
+}
+
+func TestServeLiveReload(t *testing.T) {
+	dir := t.TempDir()
+	input := writeBenchmarks(t, dir, "bench.txt", 100)
+	configFile := filepath.Join(dir, "benchviz.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(testConfig()), 0o600))
+	cfg, err := config.Load(configFile)
+	require.NoError(t, err)
+
+	cli := &Command{Config: configFile, L: newTestLogger()}
+	data := cli.watchData(cfg, []string{input})
+	data.changes = server.NewChanges()
+
+	loaded, err := data.load(context.Background())
+	require.NoError(t, err)
+
+	t.Run("should not notify without changes", func(t *testing.T) {
+		data.refresh()
+		assert.EqualT(t, uint64(0), data.changes.Version())
+	})
+
+	t.Run("should notify changed inputs", func(t *testing.T) {
+		writeBenchmarks(t, dir, "bench.txt", 200)
+		data.refresh()
+		assert.EqualT(t, uint64(1), data.changes.Version())
+
+		reloaded, err := data.load(context.Background())
+		require.NoError(t, err)
+		assert.False(t, loaded.Scenario == reloaded.Scenario)
+	})
+
+	t.Run("should reload a changed config", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(configFile, []byte(strings.Replace(testConfig(), "name: Test", "name: Changed", 1)), 0o600))
+		data.refresh()
+		assert.EqualT(t, uint64(2), data.changes.Version())
+
+		reloaded, err := data.load(context.Background())
+		require.NoError(t, err)
+		assert.EqualT(t, "Changed", reloaded.Scenario.Name)
+	})
+
+	t.Run("should keep the previous config when invalid", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(configFile, []byte("categories: [\n"), 0o600))
+		data.refresh()
+		assert.EqualT(t, uint64(2), data.changes.Version())
+
+		reloaded, err := data.load(context.Background())
+		require.NoError(t, err)
+		assert.EqualT(t, "Changed", reloaded.Scenario.Name)
+	})
+}
+
+func TestServeRunRefresh(t *testing.T) {
+	cli := &Command{L: newTestLogger()}
+	data := cli.watchData(&config.Config{}, []string{runCommand, "."})
+	data.changes = server.NewChanges()
+	data.last = &server.Data{}
+
+	t.Run("should keep the results of benchmarks run", func(t *testing.T) {
+		data.refresh()
+		assert.NotNil(t, data.last)
+		assert.EqualT(t, uint64(0), data.changes.Version())
+	})
+
+	t.Run("should run benchmarks again on an explicit refresh", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		data.serveRefresh(rec, httptest.NewRequest(http.MethodPost, "/api/refresh", nil))
+		require.EqualT(t, http.StatusNoContent, rec.Code)
+		assert.Nil(t, data.last)
+		assert.EqualT(t, uint64(1), data.changes.Version())
+	})
+}
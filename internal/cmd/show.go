@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+const (
+	// configCommand is the first CLI argument that triggers a command about the configuration.
+	configCommand = "config"
+
+	// showCommand prints the effective configuration, e.g. "benchviz config show".
+	showCommand = "show"
+)
+
+// executeConfig executes a command about the configuration.
+func (c *Command) executeConfig(w io.Writer, args []string) error {
+	if len(args) != 1 || args[0] != showCommand {
+		return fmt.Errorf("invalid config command %v: should be %q", args, showCommand)
+	}
+
+	return c.showConfig(w)
+}
+
+// showConfig prints the effective configuration as YAML, after defaults and CLI overrides are applied.
+//
+// Runtime-only settings (e.g. outputs) are not printed.
+func (c *Command) showConfig(w io.Writer) error {
+	cfg, err := config.Load(c.Config)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if err := c.overrideConfig(cfg); err != nil {
+		return fmt.Errorf("preparing config: %w", err)
+	}
+
+	if err := cfg.EncodeYAML(w); err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestShowConfig(t *testing.T) {
+	cli := &Command{
+		Config:      writeTestConfig(t, testConfig()),
+		Theme:       "dark",
+		Environment: "my laptop",
+		L:           newTestLogger(),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cli.executeConfig(&buf, []string{showCommand}))
+
+	output := buf.String()
+	assert.Contains(t, output, "Theme: dark\n")
+	assert.Contains(t, output, "Environment: my laptop\n")
+	assert.NotContains(t, output, "Outputs")
+
+	t.Run("should load the effective config back", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "effective.yaml")
+		require.NoError(t, os.WriteFile(file, buf.Bytes(), 0o600))
+
+		cfg, err := config.Load(file)
+		require.NoError(t, err)
+		assert.Equal(t, "dark", cfg.Render.Theme)
+		assert.Len(t, cfg.Functions, 4)
+	})
+}
+
+func TestShowConfigErrors(t *testing.T) {
+	t.Run("should fail on unknown config command", func(t *testing.T) {
+		cli := &Command{Config: writeTestConfig(t, testConfig()), L: newTestLogger()}
+		require.Error(t, cli.Execute(configCommand, "edit"))
+	})
+
+	t.Run("should fail on missing config", func(t *testing.T) {
+		cli := &Command{Config: "/nonexistent/config.yaml", L: newTestLogger()}
+		require.Error(t, cli.Execute(configCommand, showCommand))
+	})
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+	"github.com/fredbi/benchviz/internal/site"
+)
+
+const (
+	// siteCommand is the first CLI argument that generates a static website about the history of
+	// baseline snapshots, e.g. "benchviz site public".
+	siteCommand = "site"
+
+	// defaultSiteDir is the default directory where the website is generated.
+	defaultSiteDir = "site"
+)
+
+// executeSite generates a static website from all the snapshots saved in the baseline directory.
+//
+// The only optional argument is the output directory.
+func (c *Command) executeSite(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("invalid site command %v: should be followed by an output directory only", args)
+	}
+
+	dir := defaultSiteDir
+	if len(args) == 1 {
+		dir = args[0]
+	}
+
+	snapshots, err := baseline.ReadDir(c.baselineDir())
+	if err != nil {
+		return fmt.Errorf("reading baselines: %w", err)
+	}
+
+	generator := site.New(
+		site.WithLogger(c.logger()),
+		site.WithTitle(c.Title),
+		site.WithTheme(c.Theme),
+		site.WithThreshold(c.Threshold/100), //nolint:mnd // percentage
+	)
+
+	return generator.Generate(dir, snapshots)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestExecuteSite(t *testing.T) {
+	dir := t.TempDir()
+	cfg := mustLoadTestConfig(t, testConfig())
+	baselineDir := filepath.Join(dir, "baselines")
+
+	cli := &Command{
+		BaselineDir: baselineDir,
+		Threshold:   defaultThreshold,
+		L:           newTestLogger(),
+	}
+	ctx := context.Background()
+
+	require.NoError(t, cli.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{baselineSave, "v1", writeBenchmarks(t, dir, "v1.txt", 100)}))
+	require.NoError(t, cli.executeBaseline(ctx, &bytes.Buffer{}, cfg, []string{baselineSave, "v2", writeBenchmarks(t, dir, "v2.txt", 150)}))
+
+	siteDir := filepath.Join(dir, "public")
+	require.NoError(t, cli.Execute(siteCommand, siteDir))
+
+	index, err := os.ReadFile(filepath.Join(siteDir, "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), `<a href="Test/index.html">Test</a>`)
+
+	project, err := os.ReadFile(filepath.Join(siteDir, "Test", "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(project), "v2 against v1")
+	assert.Contains(t, string(project), `<tr class="regression">`)
+
+	_, err = os.Stat(filepath.Join(siteDir, "Test", "greater.html"))
+	require.NoError(t, err)
+
+	t.Run("should fail without baselines", func(t *testing.T) {
+		empty := &Command{BaselineDir: filepath.Join(dir, "none"), L: newTestLogger()}
+		require.Error(t, empty.Execute(siteCommand, siteDir))
+	})
+
+	t.Run("should fail with extra arguments", func(t *testing.T) {
+		require.Error(t, cli.Execute(siteCommand, siteDir, "extra"))
+	})
+}
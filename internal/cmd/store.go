@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+	"github.com/fredbi/benchviz/internal/store"
+)
+
+// storeResults appends the parsed and organized benchmarks to the SQLite database requested with -sqlite.
+//
+// Parsed benchmarks are not available when rendering merged scenarios: only the organized ones are stored then.
+// When the config sets a retention policy, older runs are pruned afterwards.
+func (c *Command) storeResults(ctx context.Context, cfg *config.Config, p *parser.BenchmarkParser, scenario *model.Scenario) error {
+	if c.SQLite == "" {
+		return nil
+	}
+
+	db, err := store.Open(ctx, c.SQLite)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	run := store.Run{
+		Created:  time.Now(),
+		Branch:   c.currentBranch(ctx),
+		Meta:     scenario.Meta,
+		Scenario: scenario,
+	}
+	if p != nil {
+		run.Sets = p.Sets()
+	}
+
+	id, err := db.Write(ctx, run)
+	if err != nil {
+		return fmt.Errorf("storing results into %q: %w", c.SQLite, err)
+	}
+
+	c.L.Info("results stored", slog.String("database", c.SQLite), slog.Int64("run", id), slog.String("branch", run.Branch))
+	c.produced(artifactSQLite, c.SQLite)
+
+	if cfg.History.IsZero() {
+		return nil
+	}
+
+	return c.pruneResults(ctx, db, cfg.History, "")
+}
+
+// pruneResults deletes the runs beyond the retention policy, possibly of a single branch.
+func (c *Command) pruneResults(ctx context.Context, db *store.Store, history config.History, branch string) error {
+	retention := store.Retention{Runs: history.KeepRuns, Days: history.KeepDays}
+
+	pruned, err := db.Prune(ctx, retention, branch, time.Now())
+	if err != nil {
+		return fmt.Errorf("pruning results from %q: %w", c.SQLite, err)
+	}
+
+	c.L.Info("results pruned",
+		slog.String("database", c.SQLite),
+		slog.Int("runs", pruned),
+		slog.Int("keep_runs", history.KeepRuns),
+		slog.Int("keep_days", history.KeepDays),
+	)
+
+	return nil
+}
+
+// currentBranch returns the branch of stored runs: the one set with -branch, or else the current git branch, if any.
+func (c *Command) currentBranch(ctx context.Context) string {
+	if c.Branch != "" {
+		return c.Branch
+	}
+
+	branch, err := gitBranch(ctx)
+	if err != nil {
+		c.L.Debug("no git branch recorded with the run", slog.String("error", err.Error()))
+	}
+
+	return branch
+}
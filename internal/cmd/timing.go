@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Phases of a run, timed by [Command.measure].
+const (
+	phaseParse      = "parse"
+	phaseOrganize   = "organize"
+	phaseChart      = "chart"
+	phaseScreenshot = "screenshot"
+	phaseTotal      = "total"
+)
+
+// timings accumulates the time spent in each phase of a run.
+//
+// It is safe for concurrent use, since pages may be screenshot concurrently.
+type timings struct {
+	mx      sync.Mutex
+	started time.Time
+	phases  []string
+	elapsed map[string]time.Duration
+	sleep   time.Duration // time left to the browser to render pages before a screenshot
+}
+
+func newTimings() *timings {
+	return &timings{
+		started: time.Now(),
+		elapsed: make(map[string]time.Duration),
+	}
+}
+
+// phaseTiming is the time spent in a phase of a run.
+type phaseTiming struct {
+	phase   string
+	elapsed time.Duration
+}
+
+// measure starts timing a phase of the run. The returned function stops it.
+//
+// A phase may be measured several times, e.g. once per page: durations add up.
+// Nothing is measured unless the command is executed.
+func (c *Command) measure(phase string) func() {
+	if c.timings == nil {
+		return func() {}
+	}
+	start := time.Now()
+
+	return func() {
+		elapsed := time.Since(start)
+
+		c.timings.mx.Lock()
+		if _, ok := c.timings.elapsed[phase]; !ok {
+			c.timings.phases = append(c.timings.phases, phase)
+		}
+		c.timings.elapsed[phase] += elapsed
+		c.timings.mx.Unlock()
+
+		c.L.Debug("phase completed", slog.String("phase", phase), slog.Duration("duration", elapsed))
+	}
+}
+
+// slept records the time a browser was left to render a page before a screenshot.
+func (c *Command) slept(sleep time.Duration) {
+	if c.timings == nil {
+		return
+	}
+
+	c.timings.mx.Lock()
+	c.timings.sleep += sleep
+	c.timings.mx.Unlock()
+}
+
+// phaseTimings returns the time spent in each phase of the run, in order, then the total time of the run.
+func (c *Command) phaseTimings() []phaseTiming {
+	if c.timings == nil {
+		return nil
+	}
+
+	c.timings.mx.Lock()
+	defer c.timings.mx.Unlock()
+
+	result := make([]phaseTiming, 0, len(c.timings.phases)+1)
+	for _, phase := range c.timings.phases {
+		result = append(result, phaseTiming{phase: phase, elapsed: c.timings.elapsed[phase]})
+	}
+
+	return append(result, phaseTiming{phase: phaseTotal, elapsed: time.Since(c.timings.started)})
+}
+
+// logTimings logs the time spent in each phase of the run.
+//
+// It warns when most of the run is spent waiting for the browser to render pages before screenshots,
+// since render.screenshot.sleep may then be lowered.
+func (c *Command) logTimings() {
+	phases := c.phaseTimings()
+	if len(phases) == 0 {
+		return
+	}
+
+	attrs := make([]any, 0, len(phases))
+	var total time.Duration
+	for _, timing := range phases {
+		attrs = append(attrs, slog.Duration(timing.phase, timing.elapsed))
+		if timing.phase == phaseTotal {
+			total = timing.elapsed
+		}
+	}
+	c.L.Info("run timings", attrs...)
+
+	c.timings.mx.Lock()
+	sleep := c.timings.sleep
+	c.timings.mx.Unlock()
+
+	if total > 0 && sleep > total/2 {
+		c.L.Warn("most of the run is spent waiting for pages to render before screenshots: consider lowering render.screenshot.sleep",
+			slog.Duration("sleep", sleep),
+			slog.Duration("total", total),
+		)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestExecuteTimings(t *testing.T) {
+	cfgFile := writeTestConfig(t, testConfig())
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "artifacts.json")
+
+	cli := &Command{
+		Config:     cfgFile,
+		IsJSON:     true,
+		OutputFile: filepath.Join(dir, "output.html"),
+		Manifest:   manifestPath,
+		L:          newTestLogger(),
+	}
+	require.NoError(t, cli.Execute(parserTestdataPath("sample_generics.json")))
+
+	manifest := readManifest(t, manifestPath)
+	phases := make([]string, 0, len(manifest.Timings))
+	for _, timing := range manifest.Timings {
+		phases = append(phases, timing.Phase)
+		assert.GreaterOrEqual(t, timing.Seconds, 0.0)
+	}
+
+	assert.Equal(t, []string{phaseParse, phaseOrganize, phaseChart, phaseTotal}, phases)
+}
+
+func TestLogTimings(t *testing.T) {
+	var buf bytes.Buffer
+	cli := &Command{
+		L:       slog.New(slog.NewTextHandler(&buf, nil)),
+		timings: newTimings(),
+	}
+
+	t.Run("should log the time spent in each phase", func(t *testing.T) {
+		cli.measure(phaseParse)()
+		cli.logTimings()
+
+		assert.StringContainsT(t, buf.String(), "run timings")
+		assert.StringContainsT(t, buf.String(), "parse=")
+		assert.NotContains(t, buf.String(), "render.screenshot.sleep")
+	})
+
+	t.Run("should warn when the run is spent waiting for screenshots", func(t *testing.T) {
+		buf.Reset()
+		cli.slept(time.Hour)
+		cli.logTimings()
+
+		assert.StringContainsT(t, buf.String(), "level=WARN")
+		assert.StringContainsT(t, buf.String(), "render.screenshot.sleep")
+	})
+
+	t.Run("should not measure a command that is not executed", func(t *testing.T) {
+		idle := &Command{L: newTestLogger()}
+		idle.measure(phaseParse)()
+		idle.slept(time.Second)
+
+		assert.Empty(t, idle.phaseTimings())
+	})
+}
+
+func TestScreenshotTimings(t *testing.T) {
+	cli := &Command{L: newTestLogger(), timings: newTimings()}
+	cfg := &config.Config{}
+	cfg.Render.Screenshot.Concurrency = 2
+	cfg.Render.Screenshot.Sleep = "1h"
+
+	// screenshots fail fast on missing pages: only the time slept by concurrent browsers is accounted for
+	require.Error(t, cli.renderImages(context.Background(), cfg, []screenshot{
+		{htmlFile: "missing1.html", pngFile: filepath.Join(t.TempDir(), "1.png")},
+		{htmlFile: "missing2.html", pngFile: filepath.Join(t.TempDir(), "2.png")},
+		{htmlFile: "missing3.html", pngFile: filepath.Join(t.TempDir(), "3.png")},
+	}))
+
+	assert.EqualT(t, 2*time.Hour, cli.timings.sleep)
+	phases := cli.phaseTimings()
+	require.Len(t, phases, 2)
+	assert.EqualT(t, phaseScreenshot, phases[0].phase)
+	assert.LessOrEqual(t, phases[0].elapsed, phases[1].elapsed)
+}
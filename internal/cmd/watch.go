@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/cache"
+	"github.com/fredbi/benchviz/internal/chart"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/server"
+)
+
+// watchedData holds the data served by the serve command.
+//
+// Inputs are parsed again only when the config file or some input file changed, and only the charts
+// of the categories affected by a change are built again.
+type watchedData struct {
+	c       *Command
+	args    []string
+	inputs  *inputWatcher   // nil when benchmarks are run: benchmarks are then run again only on explicit refreshes
+	config  *inputWatcher   // nil without a config file
+	changes *server.Changes // notified on changes, with live reload
+
+	mu      sync.Mutex
+	cfg     *config.Config
+	charts  *chart.Cache
+	last    *server.Data // nil when the data must be loaded again
+	changed []string     // input files changed since the data was last loaded
+}
+
+func (c *Command) watchData(cfg *config.Config, args []string) *watchedData {
+	d := &watchedData{
+		c:      c,
+		args:   args,
+		cfg:    cfg,
+		charts: chart.NewCache(),
+	}
+
+	if len(args) == 0 || args[0] != runCommand {
+		d.inputs = newInputWatcher(c.inputFiles(args))
+		if _, err := d.inputs.changes(); err != nil {
+			c.L.Warn("could not check inputs for changes", slog.String("error", err.Error()))
+		}
+	}
+
+	if files := c.configFiles(); len(files) > 0 {
+		d.config = newInputWatcher(files)
+		if _, err := d.config.changes(); err != nil {
+			c.L.Warn("could not check config for changes", slog.String("error", err.Error()))
+		}
+	}
+
+	return d
+}
+
+// load implements [server.Loader].
+func (d *watchedData) load(ctx context.Context) (server.Data, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.refresh()
+	if d.last != nil {
+		return *d.last, nil
+	}
+
+	p, scenario, err := d.c.load(ctx, d.cfg, d.args)
+	if err != nil {
+		return server.Data{}, err
+	}
+
+	if len(d.changed) > 0 {
+		d.c.L.Info("inputs changed",
+			slog.Any("files", d.changed),
+			slog.Any("categories", affectedCategories(scenario, d.changed)),
+		)
+		d.changed = nil
+	}
+
+	d.last = &server.Data{Sets: p.Sets(), Scenario: scenario}
+
+	return *d.last, nil
+}
+
+// page renders the HTML page of a scenario, reusing the charts of unchanged categories.
+//
+// Pages are rendered with the lock held, since the configs of a dashboard may be loaded again.
+// The metadata of each page is resolved when it is rendered, from the inputs last loaded.
+func (d *watchedData) page(w io.Writer, scenario *model.Scenario) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var inputs []string
+	if d.last != nil {
+		inputs = inputFilesOf(d.last.Sets)
+	}
+	metadata := d.c.newMetadata(context.Background(), d.cfg, inputs)
+
+	return d.c.renderPage(w, d.c.newPage(d.cfg, scenario, chart.WithCache(d.charts), chart.WithMetadata(metadata)), scenario)
+}
+
+// watch checks the config file and the inputs for changes at every interval, until the context is done.
+func (d *watchedData) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		d.mu.Lock()
+		d.refresh()
+		d.mu.Unlock()
+	}
+}
+
+// refresh checks the config file and the inputs for changes, so that the data is loaded again
+// when they changed. Changes are notified to live-reloaded pages.
+//
+// An invalid config is ignored: the previous config is kept until the config file is fixed.
+//
+// It must be called with the lock held.
+func (d *watchedData) refresh() {
+	var changed bool
+
+	if d.config != nil {
+		files, err := d.config.changes()
+		switch {
+		case err != nil:
+			d.c.L.Warn("could not check config for changes", slog.String("error", err.Error()))
+		case len(files) > 0:
+			cfg, err := d.c.reloadConfig()
+			if err != nil {
+				d.c.L.Warn("ignoring invalid config", slog.Any("files", files), slog.String("error", err.Error()))
+
+				break
+			}
+
+			d.c.L.Info("config changed", slog.Any("files", files))
+			d.cfg = cfg
+			d.charts = chart.NewCache() // charts are cached for a given config
+			changed = true
+		}
+	}
+
+	if d.inputs != nil {
+		files, err := d.inputs.changes()
+		switch {
+		case err != nil:
+			d.c.L.Warn("could not check inputs for changes", slog.String("error", err.Error()))
+			d.last = nil
+		case len(files) > 0:
+			d.changed = slices.Compact(slices.Sorted(slices.Values(append(d.changed, files...))))
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	d.last = nil
+	if d.changes != nil {
+		d.changes.Notify()
+	}
+}
+
+// invalidate discards the data last loaded, so that it is loaded again (and benchmarks run again) on the next request.
+// Live-reloaded pages are notified.
+func (d *watchedData) invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.last = nil
+	if d.changes != nil {
+		d.changes.Notify()
+	}
+}
+
+// serveRefresh handles explicit refresh requests.
+func (d *watchedData) serveRefresh(w http.ResponseWriter, _ *http.Request) {
+	d.invalidate()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// inputWatcher detects the input files which changed since they were last checked.
+//
+// Files are compared by content: the contents of directories are watched file by file.
+// Files are hashed again only when their size or modification time changed, or when they were modified
+// shortly before the previous check, since modification times are not precise enough to tell such changes.
+type inputWatcher struct {
+	inputs  []string
+	files   map[string]watchedFile
+	checked time.Time
+}
+
+// watchedFile is the state of a watched file, as of the last check.
+type watchedFile struct {
+	size    int64
+	modTime time.Time
+	digest  string
+}
+
+// racyInterval is the interval before a check, during which a change of a file may not be told by its modification time.
+const racyInterval = time.Second
+
+func newInputWatcher(inputs []string) *inputWatcher {
+	return &inputWatcher{inputs: inputs}
+}
+
+// changes returns the files which were added, removed or modified since the previous call, in lexical order.
+//
+// The first call reports all files as changed.
+func (w *inputWatcher) changes() ([]string, error) {
+	checked := time.Now()
+	files := make(map[string]watchedFile)
+	for _, input := range w.inputs {
+		if err := w.digestFiles(input, files); err != nil {
+			return nil, err
+		}
+	}
+
+	var changed []string
+	for file, current := range files {
+		if previous, ok := w.files[file]; !ok || previous.digest != current.digest {
+			changed = append(changed, file)
+		}
+	}
+	for file := range w.files {
+		if _, ok := files[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	w.files = files
+	w.checked = checked
+	slices.Sort(changed)
+
+	return changed, nil
+}
+
+// digestFiles hashes the content of a file, or of all the files in a directory.
+//
+// The digests of the files unchanged since the previous check are reused.
+func (w *inputWatcher) digestFiles(input string, files map[string]watchedFile) error {
+	return filepath.WalkDir(input, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		current := watchedFile{size: info.Size(), modTime: info.ModTime()}
+		if previous, ok := w.files[file]; ok && previous.size == current.size && previous.modTime.Equal(current.modTime) &&
+			current.modTime.Before(w.checked.Add(-racyInterval)) {
+			files[file] = previous
+
+			return nil
+		}
+
+		h := cache.NewHasher()
+		if err := h.AddFile(file); err != nil {
+			return err
+		}
+		current.digest = h.Sum()
+		files[file] = current
+
+		return nil
+	})
+}
+
+// affectedCategories returns the IDs of the categories with points originating from some files.
+func affectedCategories(scenario *model.Scenario, files []string) []string {
+	changed := make(map[string]struct{}, len(files))
+	for _, file := range files {
+		changed[file] = struct{}{}
+	}
+
+	affected := make(map[string]struct{})
+	for _, category := range scenario.Categories {
+		for _, data := range category.Data {
+			for _, series := range data.Series {
+				for _, point := range series.Points {
+					if slices.ContainsFunc(point.Origin.Files, func(file string) bool {
+						_, ok := changed[file]
+
+						return ok
+					}) {
+						affected[category.ID] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	return slices.Sorted(maps.Keys(affected))
+}
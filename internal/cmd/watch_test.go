@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestInputWatcher(t *testing.T) {
+	dir := t.TempDir()
+	first := writeBenchmarks(t, dir, "first.txt", 100)
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(sub, 0o700))
+	second := writeBenchmarks(t, sub, "second.txt", 100)
+
+	watcher := newInputWatcher([]string{first, sub})
+
+	t.Run("should report all files at first", func(t *testing.T) {
+		changed, err := watcher.changes()
+		require.NoError(t, err)
+		assert.Equal(t, []string{first, second}, changed)
+	})
+
+	t.Run("should report no change", func(t *testing.T) {
+		changed, err := watcher.changes()
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+	})
+
+	t.Run("should report modified, added and removed files", func(t *testing.T) {
+		writeBenchmarks(t, dir, "first.txt", 200)
+		third := writeBenchmarks(t, sub, "third.txt", 100)
+		require.NoError(t, os.Remove(second))
+
+		changed, err := watcher.changes()
+		require.NoError(t, err)
+		assert.Equal(t, []string{first, second, third}, changed)
+	})
+
+	t.Run("should not hash files with an unchanged size and modification time", func(t *testing.T) {
+		old := time.Now().Add(-time.Hour)
+		require.NoError(t, os.Chtimes(first, old, old))
+		_, err := watcher.changes()
+		require.NoError(t, err)
+
+		// same size and modification time: the content is not checked
+		writeBenchmarks(t, dir, "first.txt", 300)
+		require.NoError(t, os.Chtimes(first, old, old))
+		changed, err := watcher.changes()
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+
+		touched := old.Add(time.Minute)
+		require.NoError(t, os.Chtimes(first, touched, touched))
+		changed, err = watcher.changes()
+		require.NoError(t, err)
+		assert.Equal(t, []string{first}, changed)
+	})
+
+	t.Run("should fail on a missing input", func(t *testing.T) {
+		_, err := newInputWatcher([]string{filepath.Join(dir, "missing.txt")}).changes()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,151 @@
+package color
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Mode tells when to color outputs.
+type Mode string
+
+// Supported color modes.
+const (
+	ModeAuto   Mode = "auto"
+	ModeAlways Mode = "always"
+	ModeNever  Mode = "never"
+)
+
+// AllModes returns all supported color modes.
+func AllModes() []Mode {
+	return []Mode{ModeAuto, ModeAlways, ModeNever}
+}
+
+// IsValid reports whether the mode is supported. The empty mode stands for [ModeAuto].
+func (m Mode) IsValid() bool {
+	switch m {
+	case "", ModeAuto, ModeAlways, ModeNever:
+		return true
+	default:
+		return false
+	}
+}
+
+// envNoColor is the environment variable that disables colors when set to a non-empty value.
+const envNoColor = "NO_COLOR"
+
+// Enabled reports whether outputs are colored in this mode, when writing to a terminal or not.
+//
+// In [ModeAuto], colors are disabled by a non-empty NO_COLOR environment variable, and on dumb terminals.
+func Enabled(mode Mode, terminal bool) bool {
+	switch mode {
+	case ModeAlways:
+		return true
+	case ModeNever:
+		return false
+	default:
+		return terminal && os.Getenv(envNoColor) == "" && os.Getenv("TERM") != "dumb"
+	}
+}
+
+// ANSI escape sequences.
+const (
+	reset  = "\x1b[0m"
+	bold   = "\x1b[1m"
+	red    = "\x1b[31m"
+	green  = "\x1b[32m"
+	yellow = "\x1b[33m"
+)
+
+// Style colors a text.
+type Style func(string) string
+
+// Palette provides the styles of colored outputs.
+//
+// When disabled (e.g. the zero value), styles return texts unchanged.
+type Palette struct {
+	enabled bool
+}
+
+// New palette, enabled or not.
+func New(enabled bool) Palette {
+	return Palette{enabled: enabled}
+}
+
+// Enabled reports whether the palette colors texts.
+func (p Palette) Enabled() bool {
+	return p.enabled
+}
+
+// Bold text, e.g. for titles.
+func (p Palette) Bold(s string) string {
+	return p.style(bold, s)
+}
+
+// Red text, e.g. for regressions and failures.
+func (p Palette) Red(s string) string {
+	return p.style(red, s)
+}
+
+// Green text, e.g. for improvements.
+func (p Palette) Green(s string) string {
+	return p.style(green, s)
+}
+
+// Yellow text, e.g. for warnings.
+func (p Palette) Yellow(s string) string {
+	return p.style(yellow, s)
+}
+
+func (p Palette) style(sequence, s string) string {
+	if !p.enabled || s == "" {
+		return s
+	}
+
+	return sequence + s + reset
+}
+
+// Line of a table, with cells separated by tabs and colored with an optional Style.
+type Line struct {
+	Text  string
+	Style Style
+}
+
+// lineBreaks replaces the characters breaking lines in the cells of a table.
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\f", " ")
+
+// WriteTable aligns the cells of lines in columns, padded with 2 spaces, then colors each line with its style.
+//
+// Lines are colored after alignment, since escape sequences would otherwise be counted in the width of cells.
+// Line breaks within lines are replaced by spaces, so that each line remains on a single row.
+func WriteTable(w io.Writer, lines []Line) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0) //nolint:mnd // 2 spaces of padding between columns
+	for _, line := range lines {
+		if _, err := io.WriteString(tw, lineBreaks.Replace(line.Text)+"\n"); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// the tabwriter yields exactly one output line per input line
+	aligned := strings.SplitAfter(buf.String(), "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		text := aligned[i]
+		if line.Style != nil {
+			content := strings.TrimSuffix(text, "\n")
+			text = line.Style(content) + text[len(content):]
+		}
+		b.WriteString(text)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
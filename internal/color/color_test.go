@@ -0,0 +1,81 @@
+package color
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestEnabled(t *testing.T) {
+	t.Setenv(envNoColor, "")
+	t.Setenv("TERM", "xterm")
+
+	assert.True(t, Enabled(ModeAuto, true))
+	assert.True(t, Enabled("", true))
+	assert.False(t, Enabled(ModeAuto, false))
+	assert.True(t, Enabled(ModeAlways, false))
+	assert.False(t, Enabled(ModeNever, true))
+
+	t.Run("should honor NO_COLOR", func(t *testing.T) {
+		t.Setenv(envNoColor, "1")
+
+		assert.False(t, Enabled(ModeAuto, true))
+		assert.True(t, Enabled(ModeAlways, true))
+	})
+
+	t.Run("should not color dumb terminals", func(t *testing.T) {
+		t.Setenv("TERM", "dumb")
+
+		assert.False(t, Enabled(ModeAuto, true))
+	})
+}
+
+func TestMode(t *testing.T) {
+	for _, mode := range AllModes() {
+		assert.True(t, mode.IsValid())
+	}
+	assert.True(t, Mode("").IsValid())
+	assert.False(t, Mode("sometimes").IsValid())
+}
+
+func TestPalette(t *testing.T) {
+	var disabled Palette
+	assert.False(t, disabled.Enabled())
+	assert.EqualT(t, "text", disabled.Red("text"))
+
+	p := New(true)
+	assert.True(t, p.Enabled())
+	assert.EqualT(t, "\x1b[31mtext\x1b[0m", p.Red("text"))
+	assert.EqualT(t, "\x1b[32mtext\x1b[0m", p.Green("text"))
+	assert.EqualT(t, "\x1b[33mtext\x1b[0m", p.Yellow("text"))
+	assert.EqualT(t, "\x1b[1mtext\x1b[0m", p.Bold("text"))
+	assert.EqualT(t, "", p.Red(""))
+}
+
+func TestWriteTable(t *testing.T) {
+	p := New(true)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTable(&buf, []Line{
+		{Text: "Name\tValue\t"},
+		{Text: "a\t1\t", Style: p.Red},
+		{Text: "longer name\t22\t"},
+	}))
+
+	assert.EqualT(t, "Name         Value  \n"+
+		"\x1b[31ma            1      \x1b[0m\n"+
+		"longer name  22     \n", buf.String())
+
+	t.Run("should keep cells with line breaks on a single row", func(t *testing.T) {
+		buf.Reset()
+		require.NoError(t, WriteTable(&buf, []Line{
+			{Text: "multi\nline\t1\t", Style: p.Red},
+			{Text: "b\t2\t", Style: p.Green},
+		}))
+
+		assert.EqualT(t, "\x1b[31mmulti line  1  \x1b[0m\n"+
+			"\x1b[32mb           2  \x1b[0m\n", buf.String())
+	})
+}
@@ -0,0 +1,7 @@
+// Package color colors the textual outputs of benchviz on terminals.
+//
+// Colors are enabled according to a [Mode]: in [ModeAuto], only when writing to a terminal and
+// unless the NO_COLOR environment variable is set (see https://no-color.org).
+//
+// The zero [Palette] doesn't color anything, so outputs are unchanged unless a palette is explicitly enabled.
+package color
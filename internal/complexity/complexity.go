@@ -0,0 +1,139 @@
+package complexity
+
+import (
+	"math"
+	"slices"
+)
+
+// Complexity is an asymptotic complexity, in big-O notation.
+type Complexity string
+
+// Complexities fitted by [Fit].
+const (
+	O1     Complexity = "O(1)"
+	OLogN  Complexity = "O(log n)"
+	ON     Complexity = "O(n)"
+	ONLogN Complexity = "O(n log n)"
+	ON2    Complexity = "O(n²)"
+	ON3    Complexity = "O(n³)"
+)
+
+// AllComplexities returns the complexities fitted by [Fit], from the lowest to the highest.
+func AllComplexities() []Complexity {
+	return []Complexity{O1, OLogN, ON, ONLogN, ON2, ON3}
+}
+
+// Curve returns the function of n growing at this complexity.
+func (c Complexity) Curve() func(n float64) float64 {
+	switch c {
+	case OLogN:
+		return math.Log2
+	case ON:
+		return func(n float64) float64 { return n }
+	case ONLogN:
+		return func(n float64) float64 { return n * math.Log2(n) }
+	case ON2:
+		return func(n float64) float64 { return n * n }
+	case ON3:
+		return func(n float64) float64 { return n * n * n }
+	default:
+		return func(float64) float64 { return 1 }
+	}
+}
+
+// Point is a measurement at input size N.
+type Point struct {
+	N     float64
+	Value float64
+}
+
+// Result is the complexity curve best fitting a set of points: Value ≈ Coefficient × curve(N).
+//
+// RMS is the root mean square error of the fit, normalized by the mean of the values:
+// the lower, the better the fit.
+type Result struct {
+	Complexity  Complexity
+	Coefficient float64
+	RMS         float64
+}
+
+// minPoints is the minimum number of distinct input sizes required to fit a curve.
+const minPoints = 2
+
+// Fit finds the complexity curve that best fits the points, by least squares.
+//
+// It returns false when points don't span at least two distinct input sizes, or if all values are zero.
+// When several curves fit equally well, the lowest complexity wins.
+func Fit(points []Point) (Result, bool) {
+	if !canFit(points) {
+		return Result{}, false
+	}
+
+	var (
+		best  Result
+		found bool
+	)
+
+	for _, complexity := range AllComplexities() {
+		result := fitCurve(points, complexity)
+		if math.IsNaN(result.RMS) || math.IsInf(result.RMS, 0) {
+			continue
+		}
+
+		if !found || result.RMS < best.RMS {
+			best = result
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+func canFit(points []Point) bool {
+	var (
+		sizes   []float64
+		nonZero bool
+	)
+
+	for _, point := range points {
+		if point.Value != 0 {
+			nonZero = true
+		}
+
+		if !slices.Contains(sizes, point.N) {
+			sizes = append(sizes, point.N)
+		}
+	}
+
+	return nonZero && len(sizes) >= minPoints
+}
+
+// fitCurve computes the least squares coefficient of a curve and the normalized error of the fit.
+func fitCurve(points []Point, complexity Complexity) Result {
+	curve := complexity.Curve()
+
+	var sumXY, sumXX, sumY float64
+	for _, point := range points {
+		x := curve(point.N)
+		sumXY += x * point.Value
+		sumXX += x * x
+		sumY += point.Value
+	}
+
+	coefficient := sumXY / sumXX
+
+	var squares float64
+	for _, point := range points {
+		residual := point.Value - coefficient*curve(point.N)
+		squares += residual * residual
+	}
+
+	count := float64(len(points))
+	mean := sumY / count
+
+	return Result{
+		Complexity:  complexity,
+		Coefficient: coefficient,
+		RMS:         math.Sqrt(squares/count) / math.Abs(mean),
+	}
+}
@@ -0,0 +1,66 @@
+package complexity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestFit(t *testing.T) {
+	sizes := []float64{10, 100, 1000, 10000}
+
+	for _, complexity := range AllComplexities() {
+		t.Run("should fit "+string(complexity), func(t *testing.T) {
+			curve := complexity.Curve()
+			points := make([]Point, 0, len(sizes))
+			for i, n := range sizes {
+				// a little noise, a constant factor
+				noise := 1 + 0.02*float64(i%2)
+				points = append(points, Point{N: n, Value: 3 * curve(n) * noise})
+			}
+
+			result, ok := Fit(points)
+			require.True(t, ok)
+			assert.EqualT(t, complexity, result.Complexity)
+			assert.InDeltaT(t, 3, result.Coefficient, 0.1)
+			assert.Less(t, result.RMS, 0.05)
+		})
+	}
+
+	t.Run("should fit a linear growth with a constant overhead", func(t *testing.T) {
+		points := make([]Point, 0, len(sizes))
+		for _, n := range sizes {
+			points = append(points, Point{N: n, Value: 50 + 2*n})
+		}
+
+		result, ok := Fit(points)
+		require.True(t, ok)
+		assert.EqualT(t, ON, result.Complexity)
+	})
+
+	t.Run("should not fit a single input size", func(t *testing.T) {
+		_, ok := Fit([]Point{{N: 10, Value: 1}, {N: 10, Value: 2}})
+		assert.False(t, ok)
+	})
+
+	t.Run("should not fit zero values", func(t *testing.T) {
+		_, ok := Fit([]Point{{N: 10}, {N: 100}})
+		assert.False(t, ok)
+	})
+
+	t.Run("should not fit no point", func(t *testing.T) {
+		_, ok := Fit(nil)
+		assert.False(t, ok)
+	})
+}
+
+func TestCurve(t *testing.T) {
+	assert.InDeltaT(t, 1, O1.Curve()(1024), 0)
+	assert.InDeltaT(t, 10, OLogN.Curve()(1024), 1e-9)
+	assert.InDeltaT(t, 1024, ON.Curve()(1024), 0)
+	assert.InDeltaT(t, 10240, ONLogN.Curve()(1024), 1e-9)
+	assert.InDeltaT(t, math.Pow(1024, 2), ON2.Curve()(1024), 0)
+	assert.InDeltaT(t, math.Pow(1024, 3), ON3.Curve()(1024), 0)
+}
@@ -0,0 +1,5 @@
+// Package complexity estimates the asymptotic complexity of benchmarks (big-O), from measurements taken at several input sizes.
+//
+// Common complexity curves are fitted by least squares, like the complexity feature of Google Benchmark:
+// the curve with the smallest normalized root mean square error is the best fit.
+package complexity
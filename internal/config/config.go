@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,8 +26,8 @@ var efs embed.FS
 // Config holds the configuration for benchviz.
 type Config struct {
 	Name        string
-	IsJSON      bool `mapstructure:"-"`
-	IsStrict    bool `mapstructure:"-"`
+	IsJSON      bool        `mapstructure:"-"`
+	StrictLevel StrictLevel `mapstructure:"-"`
 	Environment string
 	Render      Rendering
 	Outputs     Output `mapstructure:"-"`
@@ -35,10 +38,22 @@ type Config struct {
 	Categories  []Category
 	Files       []File // Files allows for enrichments based on the input file name
 
+	GroupEnvironments EnvironmentGrouping // GroupEnvironments tells how to render benchmarks collected from different environments
+	EnvironmentRules  []ReplaceRule       // EnvironmentRules normalize the environments found in inputs, e.g. to shorten CPU names
+	NameRules         []ReplaceRule       // NameRules strip parameters from benchmark names before matching functions, e.g. "/size=1024"
+	Filters           Filters             // Filters select the benchmarks to organize, by name, environment and input file
+	FunctionGroups    []FunctionGroup     // FunctionGroups regroup functions by area on charts, e.g. "comparisons" vs "collections"
+	Aggregation       Aggregation         // Aggregation tells how duplicate benchmarks are aggregated into a single point
+	Changes           Changes             // Changes filters the changes shown by comparisons and difference charts
+	History           History             // History sets the retention of runs stored in a database
+	CompareGoVersions bool                // CompareGoVersions uses the Go toolchain of each input as the version of its benchmarks
+	KeepZeroMetrics   bool                // KeepZeroMetrics retains metrics measured with a zero value (e.g. "0 allocs/op"), instead of considering them missing
+
 	functionIndex map[string]Function
 	contextIndex  map[string]Context
 	versionIndex  map[string]Version
-	metricIndex map[MetricName]Metric
+	metricIndex   map[MetricName]Metric
+	allVersions   map[string]struct{} // IDs of the categories including all versions, by default
 }
 
 // GetFunction retrieves a function definition by its ID.
@@ -70,7 +85,11 @@ func (c Config) GetMetric(id MetricName) (Metric, bool) {
 }
 
 // FindFunction returns the ID of the first function whose regexp matches the given benchmark name.
+//
+// The name is first normalized by the name rules (see [Config.NormalizeName]).
 func (c Config) FindFunction(name string) (id string, ok bool) {
+	name = c.NormalizeName(name)
+
 	for _, def := range c.Functions {
 		if id, ok := def.MatchString(name); ok {
 			return id, true
@@ -80,6 +99,17 @@ func (c Config) FindFunction(name string) (id string, ok bool) {
 	return "", false
 }
 
+// FindFunctionGroup returns the first function group whose regexp matches the given benchmark name.
+func (c Config) FindFunctionGroup(name string) (FunctionGroup, bool) {
+	for _, def := range c.FunctionGroups {
+		if _, ok := def.MatchString(name); ok {
+			return def, true
+		}
+	}
+
+	return FunctionGroup{}, false
+}
+
 // FindVersion returns the ID of the first version whose regexp matches the given benchmark name.
 func (c Config) FindVersion(name string) (id string, ok bool) {
 	for _, def := range c.Versions {
@@ -119,6 +149,21 @@ func (c Config) FindContext(name string) (id string, ok bool) {
 	return "", false
 }
 
+// FindContextFromParams returns the ID of the first context bound to one of the parameters of a benchmark.
+func (c Config) FindContextFromParams(params map[string]string) (id string, ok bool) {
+	if len(params) == 0 {
+		return "", false
+	}
+
+	for _, def := range c.Contexts {
+		if def.MatchParams(params) {
+			return def.ID, true
+		}
+	}
+
+	return "", false
+}
+
 // FindContextFromFile returns the ID of the first context matched by a file-based rule.
 func (c Config) FindContextFromFile(file string) (id string, ok bool) {
 	for _, def := range c.Files {
@@ -138,7 +183,7 @@ func (c Config) FindContextFromFile(file string) (id string, ok bool) {
 
 // EncodeYAML serializes a [Config] to YAML into the provided writer.
 //
-// Runtime-only fields (IsJSON, IsStrict, Outputs) are excluded from the output.
+// Runtime-only fields (IsJSON, StrictLevel, Outputs) are excluded from the output.
 func (c *Config) EncodeYAML(w io.Writer) error {
 	var raw map[string]any
 
@@ -163,7 +208,7 @@ type Rendering struct {
 	Title       string
 	Theme       string
 	Layout      Layout
-	Chart       string
+	Chart       ChartType
 	Legend      LegendPosition
 	Scale       Scale
 	DualScale   bool
@@ -172,7 +217,131 @@ type Rendering struct {
 	// (the per-bar category names). Zero uses the ECharts default. Reduce it when
 	// long workload names overflow, typically on horizontal bar charts.
 	LabelFontSize int
-	Screenshot    Screenshot
+	// BarHeight is the minimum height (in px) of each bar on horizontal bar charts: charts with many bars
+	// grow taller than the layout height so that bars stay readable. Zero uses a default of 20px.
+	BarHeight int
+	// MaxBars splits the charts of a category with more bars than this into several charts (part 1/2/3...).
+	// Zero never splits charts.
+	MaxBars int
+	// Overview adds a summary chart at the top of the page for each metric, with the geometric mean
+	// of each version across all categories.
+	Overview bool
+	// Animation tells whether charts are animated when drawn. Animations are disabled by default
+	// for PNG screenshots, so that no half-drawn chart is captured.
+	Animation Animation
+	// Palette overrides the colors of the theme with a colorblind-safe palette,
+	// and Patterns distinguishes series with decal patterns, in addition to colors.
+	Palette  Palette
+	Patterns bool
+	// TOC adds a table of contents at the top of the page, with links to each chart.
+	TOC        TableOfContents
+	Screenshot Screenshot
+}
+
+// TableOfContents controls the table of contents of a page.
+type TableOfContents string
+
+// Supported table of contents modes.
+const (
+	TOCAuto TableOfContents = "auto" // only for pages with many charts (default)
+	TOCOn   TableOfContents = "on"   // always
+	TOCOff  TableOfContents = "off"  // never
+)
+
+// tocMinCharts is the number of charts from which a page has a table of contents, in [TOCAuto] mode.
+const tocMinCharts = 6
+
+// IsValid reports whether the table of contents mode is supported.
+func (t TableOfContents) IsValid() bool {
+	switch t {
+	case "", TOCAuto, TOCOn, TOCOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasTableOfContents reports whether a page with this number of charts has a table of contents.
+func (r Rendering) HasTableOfContents(charts int) bool {
+	switch r.TOC {
+	case TOCOn:
+		return true
+	case TOCOff:
+		return false
+	default:
+		return charts >= tocMinCharts
+	}
+}
+
+// Palette is a built-in set of colors for chart series.
+type Palette string
+
+// Supported palettes.
+const (
+	PaletteTheme     Palette = "theme"      // the colors of the theme (default)
+	PaletteOkabeIto  Palette = "okabe-ito"  // colorblind-safe palette by Okabe and Ito
+	PaletteTolBright Palette = "tol-bright" // colorblind-safe palette by Paul Tol
+)
+
+// IsValid reports whether the palette is supported.
+func (p Palette) IsValid() bool {
+	switch p {
+	case "", PaletteTheme, PaletteOkabeIto, PaletteTolBright:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAnimated reports whether charts are animated, when rendered for a screenshot or not.
+func (r Rendering) IsAnimated(screenshot bool) bool {
+	switch r.Animation {
+	case AnimationOn:
+		return true
+	case AnimationOff:
+		return false
+	default:
+		return !screenshot
+	}
+}
+
+// Animation controls the animation of charts when they are drawn.
+type Animation string
+
+// Supported animation modes.
+const (
+	AnimationAuto Animation = "auto" // animated, except for PNG screenshots (default)
+	AnimationOn   Animation = "on"   // always animated
+	AnimationOff  Animation = "off"  // never animated
+)
+
+// IsValid reports whether the animation mode is supported.
+func (a Animation) IsValid() bool {
+	switch a {
+	case "", AnimationAuto, AnimationOn, AnimationOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// ChartType controls how the series of charts are drawn.
+type ChartType string
+
+// Supported chart types.
+const (
+	ChartTypeBar  ChartType = "barchart"
+	ChartTypeLine ChartType = "linechart"
+)
+
+// IsValid reports whether the chart type is supported. An empty value is valid (defaults to barchart).
+func (t ChartType) IsValid() bool {
+	switch t {
+	case "", ChartTypeBar, ChartTypeLine:
+		return true
+	default:
+		return false
+	}
 }
 
 // Orientation controls the chart bar direction.
@@ -184,11 +353,31 @@ const (
 	OrientationHorizontal Orientation = "horizontal"
 )
 
+// IsValid reports whether the orientation is supported. An empty value is valid (defaults to vertical).
+func (o Orientation) IsValid() bool {
+	switch o {
+	case "", OrientationVertical, OrientationHorizontal:
+		return true
+	default:
+		return false
+	}
+}
+
 // Screenshot configures the headless Chrome screenshot used for PNG rendering.
 type Screenshot struct {
 	Height int64
 	Width  int64
 	Sleep  string
+	// Concurrency is the maximum number of pages screenshot at the same time, when a page is rendered
+	// per category. Zero uses a default.
+	Concurrency int
+	// Retries is the number of times a failed screenshot is retried, after waiting for Backoff,
+	// doubled at each retry.
+	Retries int
+	Backoff string
+	// AutoFit sizes the screenshot to the content of the page (i.e. all its charts),
+	// instead of the whole page in a Width x Height viewport.
+	AutoFit bool
 }
 
 // SleepDuration parses the Sleep field as a [time.Duration].
@@ -201,6 +390,16 @@ func (s Screenshot) SleepDuration() time.Duration {
 	return d
 }
 
+// BackoffDuration parses the Backoff field as a [time.Duration].
+func (s Screenshot) BackoffDuration() time.Duration {
+	d, err := time.ParseDuration(s.Backoff)
+	if d == 0 || err != nil {
+		return 0
+	}
+
+	return d
+}
+
 // File defines a file-matching rule that enriches benchmarks with version or context based on filename.
 type File struct {
 	ID        string
@@ -239,6 +438,16 @@ const (
 	ScaleLog  Scale = "log"
 )
 
+// IsValid reports whether the scale mode is supported. An empty value is valid (defaults to auto).
+func (s Scale) IsValid() bool {
+	switch s {
+	case "", ScaleAuto, ScaleLog:
+		return true
+	default:
+		return false
+	}
+}
+
 // LegendPosition controls where the chart legend is displayed.
 type LegendPosition string
 
@@ -251,18 +460,53 @@ const (
 	LegendPositionRight  LegendPosition = "right"
 )
 
+// IsValid reports whether the legend position is supported. An empty value is valid (defaults to bottom).
+func (l LegendPosition) IsValid() bool {
+	switch l {
+	case "", LegendPositionNone, LegendPositionBottom, LegendPositionTop, LegendPositionLeft, LegendPositionRight:
+		return true
+	default:
+		return false
+	}
+}
+
 // Output holds the resolved output file paths for HTML and PNG rendering.
 type Output struct {
-	HTMLFile string
-	PngFile  string
-	IsTemp   bool
+	HTMLFile  string
+	PngFile   string
+	IsTemp    bool
+	Directory string // when set, multiple outputs are produced in this directory
+	Compress  bool   // HTML files are gzip-compressed, as .html.gz files
+	Minify    bool   // HTML files are minified
+}
+
+// SanitizeFileName replaces the characters of a value that are not safe in a file name or a URL path with "-",
+// e.g. to name the page of a benchmark function.
+func SanitizeFileName(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ', '#', '%':
+			return '-'
+		default:
+			return r
+		}
+	}, value)
 }
 
 // Metric defines a benchmark metric with its display title and axis label.
+//
+// A metric with a Unit is a custom metric, reported by benchmarks with testing.B.ReportMetric
+// (e.g. "peak-MB"): its ID may be any identifier other than a standard metric name.
 type Metric struct {
 	ID    MetricName
 	Title string
 	Axis  string
+	Unit  string
+}
+
+// IsCustom reports whether the metric is a custom metric, identified by its unit.
+func (m Metric) IsCustom() bool {
+	return m.Unit != ""
 }
 
 // Object is the base type for regexp-matched configuration entries (functions, contexts, versions).
@@ -310,13 +554,70 @@ func (o Object) MatchString(name string) (id string, ok bool) {
 }
 
 // Function identifies a benchmark function by regexp matching on its name.
+//
+// Label overrides the template of the x-axis labels of the function, e.g. "{function} ({context})"
+// (see [LabelPlaceholders]). It may also refer to the submatches of the regexp of the function:
+// "{1}" for the first group, "{size}" for a group named "size".
 type Function struct {
 	Object `mapstructure:",deep,squash"`
+	Label  string
+}
+
+// ExpandLabel replaces the placeholders of the submatches of the function regexp in its label template,
+// with their values in a benchmark name. Other placeholders are left as is.
+func (f Function) ExpandLabel(name string) string {
+	if f.match == nil {
+		return f.Label
+	}
+
+	submatches := f.match.FindStringSubmatch(name)
+	if len(submatches) < 2 { //nolint:mnd // no group captured
+		return f.Label
+	}
+
+	names := f.match.SubexpNames()
+	replacements := make([]string, 0, 4*len(submatches)) //nolint:mnd // up to 2 placeholders per submatch
+	for i := 1; i < len(submatches); i++ {
+		replacements = append(replacements, "{"+strconv.Itoa(i)+"}", submatches[i])
+		if names[i] != "" {
+			replacements = append(replacements, "{"+names[i]+"}", submatches[i])
+		}
+	}
+
+	return strings.NewReplacer(replacements...).Replace(f.Label)
+}
+
+// FunctionGroup regroups benchmark functions by regexp matching on their name, e.g. by API area.
+//
+// Within a category, the functions of a group are charted next to each other, under the title of the group.
+type FunctionGroup struct {
+	Object `mapstructure:",deep,squash"`
 }
 
 // Context identifies a benchmark context (e.g. input size, data type) by regexp matching.
+//
+// A context may declare a numeric Value (e.g. the size of the input), used to position
+// the context on a numeric X axis (see [XAxis]).
+//
+// Alternatively, a context may be bound to a sub-benchmark parameter with Param, of the form "key=value"
+// (e.g. "size=1024" for "BenchmarkEncode/size=1024-16"), without a regexp.
+// A numeric parameter value is the default Value of the context.
 type Context struct {
 	Object `mapstructure:",deep,squash"`
+	Value  *float64
+	Param  string
+}
+
+// MatchParams reports whether the parameters parsed from the name of a benchmark, by key, include the parameter of the context.
+func (c Context) MatchParams(params map[string]string) bool {
+	key, value, ok := strings.Cut(c.Param, "=")
+	if !ok {
+		return false
+	}
+
+	actual, ok := params[key]
+
+	return ok && actual == value
 }
 
 // Version identifies a benchmark implementation variant (e.g. "reflect", "generics") by regexp matching.
@@ -324,189 +625,840 @@ type Version struct {
 	Object `mapstructure:",deep,squash"`
 }
 
-// Category groups functions, contexts, versions and metrics into a single chart.
-type Category struct {
-	ID       string
-	Title    string
-	Includes Includes
-}
+// EnvironmentGrouping tells how benchmarks collected from different environments (e.g. different machines) are rendered.
+//
+// By default, all benchmarks are flattened into the same series, regardless of their environment.
+type EnvironmentGrouping string
 
-// Includes lists the IDs of functions, versions, contexts and metrics included in a [Category].
-type Includes struct {
-	Functions []string
-	Versions  []string
-	Contexts  []string
-	Metrics   []MetricName
-}
+// Supported environment groupings.
+const (
+	GroupEnvironmentsNone   EnvironmentGrouping = "none"   // all environments flattened in the same series (default)
+	GroupEnvironmentsSeries EnvironmentGrouping = "series" // one series per environment, on the same chart
+	GroupEnvironmentsCharts EnvironmentGrouping = "charts" // one chart per environment
+)
 
-// Load a configuration file from the local file system.
-func Load(file string) (*Config, error) {
-	cfg, err := loadDefaults()
-	if err != nil {
-		return nil, fmt.Errorf("loading default config: %w", err)
+// IsValid reports whether the environment grouping is supported.
+func (g EnvironmentGrouping) IsValid() bool {
+	switch g {
+	case "", GroupEnvironmentsNone, GroupEnvironmentsSeries, GroupEnvironmentsCharts:
+		return true
+	default:
+		return false
 	}
-
-	fsys := os.DirFS(filepath.Dir(file))
-	pth := filepath.Join(".", filepath.Base(file))
-
-	return load(fsys, pth, cfg)
-}
-
-// LoadDefaults loads the default configuration from the embedded default_config.yaml.
-func LoadDefaults() (*Config, error) {
-	return loadDefaults()
 }
 
-// loadDefaults loads the default configuration from embedded FS.
-func loadDefaults() (*Config, error) {
-	return load(efs, "default_config.yaml", &Config{})
+// ReplaceRule replaces the parts of a string matching a regexp, e.g. to normalize the environment
+// "Intel(R) Core(TM) i7-9750H CPU @ 2.60GHz" as "i7-9750H", or to strip the parameter "/size=1024" from benchmark names.
+//
+// Replace may refer to submatches, e.g. "${1}".
+type ReplaceRule struct {
+	Match   string
+	Replace string
+	match   *regexp.Regexp
 }
 
-func load(fsys fs.FS, file string, cfg *Config) (*Config, error) {
-	content, err := fs.ReadFile(fsys, file)
-	if err != nil {
-		return nil, err
+// Apply replaces the parts of s matching the rule. It reports whether the rule matched.
+//
+// Rules decoded without validation (e.g. the abbreviations of an exported scenario) are compiled on the fly.
+func (r ReplaceRule) Apply(s string) (string, bool) {
+	match := r.match
+	if match == nil && r.Match != "" {
+		match, _ = regexp.Compile(r.Match) // invalid rules are rejected by [Config.Validate]
 	}
 
-	var raw any
-	err = yaml.Unmarshal(content, &raw)
-	if err != nil {
-		return nil, err
+	if match == nil || !match.MatchString(s) {
+		return s, false
 	}
 
-	err = mapstructure.Decode(raw, cfg)
-	if err != nil {
-		return nil, err
-	}
+	return match.ReplaceAllString(s, r.Replace), true
+}
 
-	// build indices and validate unique IDs
-	cfg.functionIndex = make(map[string]Function, len(cfg.Functions))
-	cfg.contextIndex = make(map[string]Context, len(cfg.Contexts))
-	cfg.versionIndex = make(map[string]Version, len(cfg.Versions))
-	cfg.metricIndex = make(map[MetricName]Metric, len(cfg.Metrics))
+// applyRules applies replace rules to s, in order. It reports whether some rule matched.
+func applyRules(rules []ReplaceRule, s string) (string, bool) {
+	var matched bool
 
-	if err = cfg.validateFunctions(); err != nil {
-		return nil, err
+	for _, rule := range rules {
+		var ok bool
+		s, ok = rule.Apply(s)
+		matched = matched || ok
 	}
 
-	if err = cfg.validateContexts(); err != nil {
-		return nil, err
-	}
+	return s, matched
+}
 
-	if err = cfg.validateVersions(); err != nil {
-		return nil, err
+// NormalizeEnvironment applies the environment rules to an environment string, in order.
+//
+// Spaces left over by replacements are collapsed.
+func (c Config) NormalizeEnvironment(env string) string {
+	env, normalized := applyRules(c.EnvironmentRules, env)
+	if !normalized {
+		return env
 	}
 
-	if err = cfg.validateMetrics(); err != nil {
-		return nil, err
-	}
+	return strings.Join(strings.Fields(env), " ")
+}
 
-	if err = cfg.validateCategories(); err != nil {
-		return nil, err
-	}
+// NormalizeName applies the name rules to a benchmark name, in order: name rules strip or rewrite parameter segments
+// of benchmark names before they are matched against functions, e.g. "/n(\d+)" replaced by "/${1}" keeps only
+// the value of a parameter.
+//
+// Versions and contexts are matched against the original name, so they may still be resolved from parameters.
+func (c Config) NormalizeName(name string) string {
+	name, _ = applyRules(c.NameRules, name)
 
-	if err = cfg.validateRegexps(); err != nil {
-		return nil, err
-	}
+	return name
+}
 
-	return cfg, nil
+// Filters select the input benchmarks to organize, so that a config may be reused for subsets of a large corpus
+// of benchmarks.
+//
+// Benchmarks filter raw benchmark names (e.g. "BenchmarkSort/small-8"), Environments filter the environments
+// found in inputs (before environment rules apply), and Files filter the names of input files.
+// A benchmark is organized only when all the filters retain it.
+type Filters struct {
+	Benchmarks   Filter
+	Environments Filter
+	Files        Filter
 }
 
-func (c *Config) validateFunctions() error {
-	for i, v := range c.Functions {
-		if v.ID == "" {
-			return fmt.Errorf("invalid functions: empty ID found: functions[%d]", i)
-		}
-		if _, ok := c.functionIndex[v.ID]; ok {
-			return fmt.Errorf("invalid functions: duplicate ID key found: %s", v.ID)
-		}
-		if v.Title == "" {
-			v.Title = titleize(v.ID)
-		}
-		c.functionIndex[v.ID] = v
-	}
+// IsZero reports whether no filter is set.
+func (f Filters) IsZero() bool {
+	return f.Benchmarks.IsZero() && f.Environments.IsZero() && f.Files.IsZero()
+}
 
-	return nil
+// Filter retains the values matching the Include regexp, when set, and not matching the Exclude regexp, when set.
+type Filter struct {
+	Include string
+	Exclude string
+	include *regexp.Regexp
+	exclude *regexp.Regexp
 }
 
-func (c *Config) validateContexts() error {
-	for i, v := range c.Contexts {
-		if v.ID == "" {
-			return fmt.Errorf("invalid contexts: empty ID found: contexts[%d]", i)
-		}
-		if _, ok := c.contextIndex[v.ID]; ok {
-			return fmt.Errorf("invalid contexts: duplicate ID key found: %s", v.ID)
-		}
-		if v.Title == "" {
-			v.Title = titleize(v.ID)
-		}
-		c.contextIndex[v.ID] = v
+// IsZero reports whether the filter retains all values.
+func (f Filter) IsZero() bool {
+	return f.Include == "" && f.Exclude == ""
+}
+
+// Retains reports whether the filter retains a value.
+func (f Filter) Retains(value string) bool {
+	if f.include != nil && !f.include.MatchString(value) {
+		return false
 	}
 
-	return nil
+	return f.exclude == nil || !f.exclude.MatchString(value)
 }
 
-func (c *Config) validateVersions() error {
-	for i, v := range c.Versions {
-		if v.ID == "" {
-			return fmt.Errorf("invalid versions: empty ID found: versions[%d]", i)
-		}
-		if _, ok := c.versionIndex[v.ID]; ok {
-			return fmt.Errorf("invalid versions: duplicate ID key found: %s", v.ID)
+// compile the regexps of the filter.
+func (f *Filter) compile(name string) error {
+	var err error
+
+	if f.Include != "" {
+		if f.include, err = regexp.Compile(f.Include); err != nil {
+			return fmt.Errorf("invalid regexp[filters.%s.include]: %w", name, err)
 		}
-		if v.Title == "" {
-			v.Title = titleize(v.ID)
+	}
+
+	if f.Exclude != "" {
+		if f.exclude, err = regexp.Compile(f.Exclude); err != nil {
+			return fmt.Errorf("invalid regexp[filters.%s.exclude]: %w", name, err)
 		}
-		c.versionIndex[v.ID] = v
 	}
 
 	return nil
 }
 
-func (c *Config) validateMetrics() error {
-	for i, v := range c.Metrics {
-		if v.ID == "" {
-			return fmt.Errorf("invalid metrics: empty ID found: metrics[%d]", i)
-		}
-		if !v.ID.IsValid() {
-			return fmt.Errorf("invalid metrics: invalid metric ID: metrics[%d]=%v (should be one of %v)", i, v.ID, AllMetricNames())
-		}
-		if v.Title == "" {
-			v.Title = titleize(v.ID)
-		}
-		if _, ok := c.metricIndex[v.ID]; ok {
-			return fmt.Errorf("invalid metrics: duplicate ID key found: %s", v.ID)
-		}
+// StrictLevel tells which requirements are enforced in strict mode.
+type StrictLevel string
 
-		c.metricIndex[v.ID] = v
+// Supported strictness levels.
+const (
+	StrictNone      StrictLevel = ""          // warn and skip (default)
+	StrictFunctions StrictLevel = "functions" // fail on benchmarks that match no function
+	StrictMetrics   StrictLevel = "metrics"   // fail on benchmarks that carry no configured metric
+	StrictAll       StrictLevel = "all"       // fail on any benchmark data that may not be rendered
+)
+
+// ParseStrictLevel parses a strictness level.
+//
+// For backward compatibility, a boolean value is accepted: "true" stands for [StrictAll] and "false" for [StrictNone].
+func ParseStrictLevel(value string) (StrictLevel, error) {
+	switch level := StrictLevel(strings.ToLower(value)); level {
+	case StrictNone, "false", "none":
+		return StrictNone, nil
+	case StrictAll, "true":
+		return StrictAll, nil
+	case StrictFunctions, StrictMetrics:
+		return level, nil
+	default:
+		return StrictNone, fmt.Errorf("invalid strict level %q: should be one of %v", value, []StrictLevel{StrictFunctions, StrictMetrics, StrictAll})
 	}
+}
 
-	return nil
+// Covers reports whether the strictness level enforces the requirements of another level.
+func (l StrictLevel) Covers(level StrictLevel) bool {
+	return l == StrictAll || (l != StrictNone && l == level)
 }
 
-func (c *Config) validateCategories() (err error) {
-	for i, v := range c.Categories {
-		v, err = c.validateCategory(v, i)
-		if err != nil {
-			return err
-		}
+// IsStrictFor reports whether the requirements of a strictness level are enforced.
+func (c Config) IsStrictFor(level StrictLevel) bool {
+	return c.StrictLevel.Covers(level)
+}
 
-		c.Categories[i] = v
+// Aggregation tells how duplicate benchmarks (e.g. several runs with -count) are aggregated.
+//
+// By default, duplicate benchmarks are not aggregated, and each run is plotted as a separate point.
+type Aggregation string
+
+// Supported aggregations.
+const (
+	AggregationNone     Aggregation = "none"     // no aggregation (default)
+	AggregationMean     Aggregation = "mean"     // plot the mean of duplicate benchmarks
+	AggregationWeighted Aggregation = "weighted" // plot the mean of duplicate benchmarks, weighted by their number of iterations
+)
+
+// IsValid reports whether the aggregation is supported.
+func (a Aggregation) IsValid() bool {
+	switch a {
+	case "", AggregationNone, AggregationMean, AggregationWeighted:
+		return true
+	default:
+		return false
 	}
+}
 
-	return nil
+// Changes filters the changes between versions shown in comparisons against a baseline
+// and on difference charts, e.g. to keep pull request comments short.
+//
+// MinChange is a percentage: changes lower than this, in absolute value, are not shown.
+type Changes struct {
+	Only      ChangeKind
+	MinChange float64
 }
 
-func (c *Config) validateCategory(v Category, i int) (vv Category, err error) {
-	if v.ID == "" {
-		return vv, fmt.Errorf("invalid categories: empty ID found: categories[%d]", i)
-	}
+// History sets the retention policy of the runs stored in an SQLite database, so it doesn't grow unbounded.
+//
+// Limits apply to each branch separately. A run beyond any of the limits is pruned. Zero values keep all runs.
+type History struct {
+	KeepRuns int // KeepRuns is the number of most recent runs kept for each branch
+	KeepDays int // KeepDays is the number of days runs are kept for
+}
 
-	if v.Title == "" {
-		v.Title = titleize(v.ID)
-	}
+// IsZero reports whether no retention policy is set.
+func (h History) IsZero() bool {
+	return h.KeepRuns == 0 && h.KeepDays == 0
+}
 
-	includes := v.Includes
+// ChangeKind selects the direction of changes to show.
+type ChangeKind string
+
+// Supported kinds of changes.
+const (
+	ChangeAll          ChangeKind = "all"          // all changes (default)
+	ChangeRegressions  ChangeKind = "regressions"  // only changes for the worse
+	ChangeImprovements ChangeKind = "improvements" // only changes for the better
+)
+
+// IsValid reports whether the kind of changes is supported.
+func (k ChangeKind) IsValid() bool {
+	switch k {
+	case "", ChangeAll, ChangeRegressions, ChangeImprovements:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFiltered reports whether some changes are filtered out.
+func (c Changes) IsFiltered() bool {
+	return (c.Only != "" && c.Only != ChangeAll) || c.MinChange > 0
+}
+
+// Retains reports whether a change is shown, given its relative value (e.g. 0.1 for +10%)
+// and whether it is a regression or an improvement.
+func (c Changes) Retains(change float64, regression, improvement bool) bool {
+	switch c.Only {
+	case ChangeRegressions:
+		if !regression {
+			return false
+		}
+	case ChangeImprovements:
+		if !improvement {
+			return false
+		}
+	}
+
+	return math.Abs(change)*100 >= c.MinChange //nolint:mnd // percentage
+}
+
+// Category groups functions, contexts, versions and metrics into a single chart.
+//
+// By default, versions are rendered as series and contexts on the X axis.
+// Pivot may swap this layout, and render contexts as series and versions on the X axis.
+//
+// Limit may restrict the chart to the N slowest (or fastest) benchmarks.
+//
+// Contexts are laid out in the order of Includes.Contexts, unless ContextOrder sorts them in a natural order.
+// XAxis may place contexts on a numeric axis, at the value they declare.
+// On a numeric axis, Complexity estimates the big-O complexity of each function.
+//
+// Difference may chart the relative change between two versions, instead of their values.
+//
+// Annotations document the charts of the category with freeform texts.
+//
+// MetricLayout tells how a category with several metrics is charted: by default, one chart per metric.
+type Category struct {
+	ID           string
+	Title        string
+	Label        string // template of the x-axis labels, e.g. "{function}\n{context}" (see [LabelPlaceholders])
+	AxisLabels   Labels // width and abbreviations of the x-axis labels, as displayed on the axis
+	SeriesTitle  string // template of the series titles, e.g. "{version} ({environment})" (see [SeriesTitlePlaceholders])
+	Pivot        Pivot
+	Limit        Limit
+	ContextOrder ContextOrder
+	XAxis        XAxis
+	Complexity   bool
+	Difference   Difference
+	Annotations  []Annotation
+	MetricLayout MetricLayout
+	Includes     Includes
+}
+
+// MetricLayout tells how the metrics of a [Category] are charted.
+type MetricLayout string
+
+// Supported metric layouts.
+const (
+	MetricLayoutSplit    MetricLayout = "split"           // one chart per metric (default)
+	MetricLayoutDualAxis MetricLayout = "dual-axis"       // two metrics on a single chart, each with its own Y axis
+	MetricLayoutSelector MetricLayout = "metric-selector" // a single chart, with buttons to select the metric shown
+)
+
+// IsValid reports whether the metric layout is supported.
+func (l MetricLayout) IsValid() bool {
+	switch l {
+	case "", MetricLayoutSplit, MetricLayoutDualAxis, MetricLayoutSelector:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsCombined reports whether the metrics are charted on a single chart.
+func (l MetricLayout) IsCombined() bool {
+	return l == MetricLayoutDualAxis || l == MetricLayoutSelector
+}
+
+// Placeholders of the x-axis label template of a [Category], replaced by the titles of the benchmark components.
+const (
+	LabelFunction = "{function}"
+	LabelVersion  = "{version}"
+	LabelContext  = "{context}"
+)
+
+// LabelPlaceholders returns all the placeholders supported by the x-axis label template of a [Category].
+func LabelPlaceholders() []string {
+	return []string{LabelFunction, LabelVersion, LabelContext}
+}
+
+// Placeholders of the series title template of a [Category], besides [LabelVersion] and [LabelContext].
+const (
+	SeriesMetric      = "{metric}"
+	SeriesEnvironment = "{environment}"
+)
+
+// SeriesTitlePlaceholders returns all the placeholders supported by the series title template of a [Category].
+func SeriesTitlePlaceholders() []string {
+	return []string{LabelVersion, LabelContext, SeriesMetric, SeriesEnvironment}
+}
+
+// Labels controls the width of the x-axis labels of a [Category], as displayed on the axis:
+// tooltips and annotations refer to full labels.
+//
+// Abbreviations are applied first, in order. Labels still longer than Width characters are then
+// truncated with an ellipsis, or wrapped on several lines, depending on Overflow.
+// A zero Width leaves labels at their full length.
+type Labels struct {
+	Width         int
+	Overflow      LabelOverflow
+	Abbreviations []ReplaceRule // Abbreviations shorten the parts of labels matching a regexp, e.g. "Benchmark" to "B"
+}
+
+// Abbreviate applies the abbreviation rules to a label.
+func (l Labels) Abbreviate(label string) string {
+	label, _ = applyRules(l.Abbreviations, label)
+
+	return label
+}
+
+// LabelOverflow tells how x-axis labels longer than the configured width are shortened.
+type LabelOverflow string
+
+// Supported label overflows.
+const (
+	LabelOverflowTruncate LabelOverflow = "truncate" // cut the label with an ellipsis (default)
+	LabelOverflowWrap     LabelOverflow = "wrap"     // break the label on several lines, between words
+)
+
+// IsValid reports whether the label overflow is supported. An empty value is valid (defaults to truncate).
+func (o LabelOverflow) IsValid() bool {
+	switch o {
+	case "", LabelOverflowTruncate, LabelOverflowWrap:
+		return true
+	default:
+		return false
+	}
+}
+
+// Annotation documents the charts of a [Category] with a freeform text, e.g. "go1.22, after sync.Pool change".
+//
+// X optionally positions the annotation on the X axis: a label on a category X axis, or a number on
+// a numeric X axis. Annotations without a position are displayed at the top of the chart.
+type Annotation struct {
+	Text string
+	X    string
+}
+
+// Difference compares two versions in a [Category]: each benchmark is charted as the relative change
+// of the Target version against the Base version, in percent.
+//
+// A zero Difference charts the values of all versions.
+type Difference struct {
+	Base   string
+	Target string
+}
+
+// IsEnabled reports whether the category charts the difference between two versions.
+func (d Difference) IsEnabled() bool {
+	return d.Base != "" || d.Target != ""
+}
+
+// Limit restricts a [Category] to its top N (or bottom N) benchmarks, ranked by the value of a metric.
+//
+// A zero N means no limit. By defaults to the first metric included in the category.
+type Limit struct {
+	N     int
+	By    MetricName
+	Order LimitOrder
+}
+
+// LimitOrder tells how benchmarks are ranked when applying a [Limit].
+type LimitOrder string
+
+// Supported ranking orders.
+const (
+	LimitOrderDesc LimitOrder = "desc" // keep the N largest values, e.g. the slowest benchmarks (default)
+	LimitOrderAsc  LimitOrder = "asc"  // keep the N smallest values, e.g. the fastest benchmarks
+)
+
+// Pivot selects which dimension is rendered as series on a chart.
+type Pivot string
+
+// Supported pivot modes.
+const (
+	PivotVersions Pivot = "versions" // versions as series, contexts on the X axis (default)
+	PivotContexts Pivot = "contexts" // contexts as series, versions on the X axis
+)
+
+// IsValid reports whether the pivot mode is supported.
+func (p Pivot) IsValid() bool {
+	switch p {
+	case "", PivotVersions, PivotContexts:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContextOrder tells how the contexts of a [Category] are ordered.
+type ContextOrder string
+
+// Supported context orders.
+const (
+	ContextOrderConfig  ContextOrder = "config"  // the order of the included contexts (default)
+	ContextOrderNatural ContextOrder = "natural" // context IDs compared by their embedded numbers, e.g. "10" < "100" < "1000"
+)
+
+// IsValid reports whether the context order is supported.
+func (o ContextOrder) IsValid() bool {
+	switch o {
+	case "", ContextOrderConfig, ContextOrderNatural:
+		return true
+	default:
+		return false
+	}
+}
+
+// XAxis tells how the contexts of a [Category] are laid out on the X axis.
+type XAxis string
+
+// Supported X axis types.
+const (
+	XAxisCategory XAxis = "category" // one label per context, evenly spaced (default)
+	XAxisValue    XAxis = "value"    // contexts positioned at their numeric value, on a linear scale
+	XAxisLog      XAxis = "log"      // contexts positioned at their numeric value, on a logarithmic scale
+)
+
+// IsValid reports whether the X axis type is supported.
+func (x XAxis) IsValid() bool {
+	switch x {
+	case "", XAxisCategory, XAxisValue, XAxisLog:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsNumeric reports whether contexts are positioned at their numeric value.
+func (x XAxis) IsNumeric() bool {
+	return x == XAxisValue || x == XAxisLog
+}
+
+// Includes lists the IDs of functions, versions, contexts and metrics included in a [Category].
+type Includes struct {
+	Functions []string
+	Versions  []string
+	Contexts  []string
+	Metrics   []MetricName
+}
+
+// Load a configuration file from the local file system.
+func Load(file string) (*Config, error) {
+	cfg, err := loadDefaults()
+	if err != nil {
+		return nil, fmt.Errorf("loading default config: %w", err)
+	}
+
+	fsys := os.DirFS(filepath.Dir(file))
+	pth := filepath.Join(".", filepath.Base(file))
+
+	return load(fsys, pth, cfg)
+}
+
+// LoadDefaults loads the default configuration from the embedded default_config.yaml.
+func LoadDefaults() (*Config, error) {
+	return loadDefaults()
+}
+
+// loadDefaults loads the default configuration from embedded FS.
+func loadDefaults() (*Config, error) {
+	return load(efs, "default_config.yaml", &Config{})
+}
+
+func load(fsys fs.FS, file string, cfg *Config) (*Config, error) {
+	content, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw any
+	err = yaml.Unmarshal(content, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mapstructure.Decode(raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks a configuration, builds its indices and compiles its regexps.
+//
+// Configurations loaded from a file are validated by [Load]. Configurations built in memory,
+// e.g. by [Generate], must be validated before use.
+func (c *Config) Validate() (err error) {
+	// build indices and validate unique IDs
+	c.functionIndex = make(map[string]Function, len(c.Functions))
+	c.contextIndex = make(map[string]Context, len(c.Contexts))
+	c.versionIndex = make(map[string]Version, len(c.Versions))
+	c.metricIndex = make(map[MetricName]Metric, len(c.Metrics))
+	c.allVersions = make(map[string]struct{}, len(c.Categories))
+
+	if err = c.validateFunctions(); err != nil {
+		return err
+	}
+
+	if err = c.validateFunctionGroups(); err != nil {
+		return err
+	}
+
+	if err = c.validateContexts(); err != nil {
+		return err
+	}
+
+	if err = c.validateVersions(); err != nil {
+		return err
+	}
+
+	if err = c.validateMetrics(); err != nil {
+		return err
+	}
+
+	if err = c.validateCategories(); err != nil {
+		return err
+	}
+
+	if !c.GroupEnvironments.IsValid() {
+		return fmt.Errorf("invalid config: unsupported groupEnvironments=%s (should be one of %v)",
+			c.GroupEnvironments, []EnvironmentGrouping{GroupEnvironmentsNone, GroupEnvironmentsSeries, GroupEnvironmentsCharts},
+		)
+	}
+
+	if c.Render.BarHeight < 0 {
+		return fmt.Errorf("invalid config: render.barHeight must be positive, got %d", c.Render.BarHeight)
+	}
+
+	if c.Render.MaxBars < 0 {
+		return fmt.Errorf("invalid config: render.maxBars must be positive, got %d", c.Render.MaxBars)
+	}
+
+	if !c.Render.Chart.IsValid() {
+		return fmt.Errorf("invalid config: unsupported render.chart=%s (should be one of %v)",
+			c.Render.Chart, []ChartType{ChartTypeBar, ChartTypeLine},
+		)
+	}
+
+	if !c.Render.Orientation.IsValid() {
+		return fmt.Errorf("invalid config: unsupported render.orientation=%s (should be one of %v)",
+			c.Render.Orientation, []Orientation{OrientationVertical, OrientationHorizontal},
+		)
+	}
+
+	if !c.Render.Scale.IsValid() {
+		return fmt.Errorf("invalid config: unsupported render.scale=%s (should be one of %v)",
+			c.Render.Scale, []Scale{ScaleAuto, ScaleLog},
+		)
+	}
+
+	if !c.Render.Legend.IsValid() {
+		return fmt.Errorf("invalid config: unsupported render.legend=%s (should be one of %v)",
+			c.Render.Legend, []LegendPosition{LegendPositionNone, LegendPositionBottom, LegendPositionTop, LegendPositionLeft, LegendPositionRight},
+		)
+	}
+
+	if !c.Render.Animation.IsValid() {
+		return fmt.Errorf("invalid config: unsupported render.animation=%s (should be one of %v)",
+			c.Render.Animation, []Animation{AnimationAuto, AnimationOn, AnimationOff},
+		)
+	}
+
+	if !c.Render.Palette.IsValid() {
+		return fmt.Errorf("invalid config: unsupported render.palette=%s (should be one of %v)",
+			c.Render.Palette, []Palette{PaletteTheme, PaletteOkabeIto, PaletteTolBright},
+		)
+	}
+
+	if !c.Render.TOC.IsValid() {
+		return fmt.Errorf("invalid config: unsupported render.toc=%s (should be one of %v)",
+			c.Render.TOC, []TableOfContents{TOCAuto, TOCOn, TOCOff},
+		)
+	}
+
+	if !c.Changes.Only.IsValid() {
+		return fmt.Errorf("invalid config: unsupported changes.only=%s (should be one of %v)",
+			c.Changes.Only, []ChangeKind{ChangeAll, ChangeRegressions, ChangeImprovements},
+		)
+	}
+
+	if c.Changes.MinChange < 0 {
+		return fmt.Errorf("invalid config: changes.minChange must be positive, got %g", c.Changes.MinChange)
+	}
+
+	if c.History.KeepRuns < 0 || c.History.KeepDays < 0 {
+		return fmt.Errorf("invalid config: history.keepRuns and history.keepDays must be positive, got %d and %d",
+			c.History.KeepRuns, c.History.KeepDays,
+		)
+	}
+
+	if !c.Aggregation.IsValid() {
+		return fmt.Errorf("invalid config: unsupported aggregation=%s (should be one of %v)",
+			c.Aggregation, []Aggregation{AggregationNone, AggregationMean, AggregationWeighted},
+		)
+	}
+
+	return c.validateRegexps()
+}
+
+func (c *Config) validateFunctions() error {
+	for i, v := range c.Functions {
+		if v.ID == "" {
+			return fmt.Errorf("invalid functions: empty ID found: functions[%d]", i)
+		}
+		if _, ok := c.functionIndex[v.ID]; ok {
+			return fmt.Errorf("invalid functions: duplicate ID key found: %s", v.ID)
+		}
+		if v.Title == "" {
+			v.Title = titleize(v.ID)
+		}
+		c.functionIndex[v.ID] = v
+	}
+
+	return nil
+}
+
+func (c *Config) validateFunctionGroups() error {
+	seen := make(map[string]struct{}, len(c.FunctionGroups))
+
+	for i, v := range c.FunctionGroups {
+		if v.ID == "" {
+			return fmt.Errorf("invalid functionGroups: empty ID found: functionGroups[%d]", i)
+		}
+		if _, ok := seen[v.ID]; ok {
+			return fmt.Errorf("invalid functionGroups: duplicate ID key found: %s", v.ID)
+		}
+		seen[v.ID] = struct{}{}
+
+		if v.Title == "" {
+			v.Title = titleize(v.ID)
+		}
+
+		match, notMatch, err := compileRex(v.Object)
+		if err != nil {
+			return fmt.Errorf("invalid regexp[function group %d - %s]: %w", i, v.ID, err)
+		}
+		v.match = match
+		v.notMatch = notMatch
+		c.FunctionGroups[i] = v
+	}
+
+	return nil
+}
+
+func (c *Config) validateContexts() error {
+	for i, v := range c.Contexts {
+		if v.ID == "" {
+			return fmt.Errorf("invalid contexts: empty ID found: contexts[%d]", i)
+		}
+		if _, ok := c.contextIndex[v.ID]; ok {
+			return fmt.Errorf("invalid contexts: duplicate ID key found: %s", v.ID)
+		}
+		if v.Title == "" {
+			v.Title = titleize(v.ID)
+		}
+		if v.Param != "" {
+			key, value, ok := strings.Cut(v.Param, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("invalid contexts: param should be of the form key=value: contexts[%d].param=%s", i, v.Param)
+			}
+
+			if number, err := strconv.ParseFloat(value, 64); err == nil && v.Value == nil {
+				v.Value = &number
+				c.Contexts[i].Value = v.Value
+			}
+		}
+		c.contextIndex[v.ID] = v
+	}
+
+	return nil
+}
+
+func (c *Config) validateVersions() error {
+	for i, v := range c.Versions {
+		if v.ID == "" {
+			return fmt.Errorf("invalid versions: empty ID found: versions[%d]", i)
+		}
+		if _, ok := c.versionIndex[v.ID]; ok {
+			return fmt.Errorf("invalid versions: duplicate ID key found: %s", v.ID)
+		}
+		if v.Title == "" {
+			v.Title = titleize(v.ID)
+		}
+		c.versionIndex[v.ID] = v
+	}
+
+	return nil
+}
+
+// AddVersion declares a version once the config is validated, e.g. for the label of an input file
+// which matches no version of the config.
+//
+// The version is included in the categories which include all versions by default.
+// It returns false if a version with the same ID is already declared.
+func (c *Config) AddVersion(version Version) bool {
+	if _, ok := c.versionIndex[version.ID]; ok {
+		return false
+	}
+
+	if version.Title == "" {
+		version.Title = titleize(version.ID)
+	}
+	c.Versions = append(c.Versions, version)
+	c.versionIndex[version.ID] = version
+
+	for i, category := range c.Categories {
+		if _, ok := c.allVersions[category.ID]; ok {
+			c.Categories[i].Includes.Versions = append(category.Includes.Versions, version.ID)
+		}
+	}
+
+	return true
+}
+
+func (c *Config) validateMetrics() error {
+	for i, v := range c.Metrics {
+		if v.ID == "" {
+			return fmt.Errorf("invalid metrics: empty ID found: metrics[%d]", i)
+		}
+		switch {
+		case v.IsCustom() && v.ID.IsValid():
+			return fmt.Errorf("invalid metrics: custom metric with a standard metric ID: metrics[%d]=%v", i, v.ID)
+		case v.IsCustom() && IsStandardUnit(v.Unit):
+			return fmt.Errorf("invalid metrics: custom metric with a standard unit: metrics[%d]=%v (unit %q)", i, v.ID, v.Unit)
+		case !v.IsCustom() && !v.ID.IsValid():
+			return fmt.Errorf("invalid metrics: invalid metric ID: metrics[%d]=%v (should be one of %v, or a custom metric with a unit)", i, v.ID, AllMetricNames())
+		}
+		if v.Title == "" {
+			v.Title = titleize(v.ID)
+		}
+		if _, ok := c.metricIndex[v.ID]; ok {
+			return fmt.Errorf("invalid metrics: duplicate ID key found: %s", v.ID)
+		}
+
+		c.metricIndex[v.ID] = v
+	}
+
+	return nil
+}
+
+func (c *Config) validateCategories() (err error) {
+	for i, v := range c.Categories {
+		v, err = c.validateCategory(v, i)
+		if err != nil {
+			return err
+		}
+
+		c.Categories[i] = v
+	}
+
+	return nil
+}
+
+func (c *Config) validateCategory(v Category, i int) (vv Category, err error) {
+	if v.ID == "" {
+		return vv, fmt.Errorf("invalid categories: empty ID found: categories[%d]", i)
+	}
+
+	if v.Title == "" {
+		v.Title = titleize(v.ID)
+	}
+
+	if !v.Pivot.IsValid() {
+		return vv, fmt.Errorf("invalid category: unsupported pivot categories.%s.pivot=%s (should be one of %v)", v.ID, v.Pivot, []Pivot{PivotVersions, PivotContexts})
+	}
+
+	if !v.ContextOrder.IsValid() {
+		return vv, fmt.Errorf("invalid category: unsupported context order categories.%s.contextOrder=%s (should be one of %v)",
+			v.ID, v.ContextOrder, []ContextOrder{ContextOrderConfig, ContextOrderNatural},
+		)
+	}
+
+	includes := v.Includes
 	for j, ref := range includes.Functions {
 		_, ok := c.functionIndex[ref]
 		if !ok {
@@ -544,6 +1496,7 @@ func (c *Config) validateCategory(v Category, i int) (vv Category, err error) {
 		for _, injected := range c.Versions {
 			v.Includes.Versions = append(v.Includes.Versions, injected.ID)
 		}
+		c.allVersions[v.ID] = struct{}{}
 	}
 
 	for j, ref := range includes.Metrics {
@@ -557,11 +1510,299 @@ func (c *Config) validateCategory(v Category, i int) (vv Category, err error) {
 		return vv, fmt.Errorf("invalid category: at least 1 metric must be included in a category. category.%s.metrics", v.ID)
 	}
 
+	if v.Limit, err = validateLimit(v.Limit, v.ID, includes.Metrics); err != nil {
+		return vv, err
+	}
+
+	if err = c.validateXAxis(v); err != nil {
+		return vv, err
+	}
+
+	if err = validateDifference(v); err != nil {
+		return vv, err
+	}
+
+	if err = c.validateMetricLayout(v); err != nil {
+		return vv, err
+	}
+
+	if err = validateAnnotations(v); err != nil {
+		return vv, err
+	}
+
+	if err = validateLabel(v); err != nil {
+		return vv, err
+	}
+
+	if v.AxisLabels, err = validateLabels(v.AxisLabels, v.ID); err != nil {
+		return vv, err
+	}
+
 	return v, nil
 }
 
+// validateMetricLayout checks that the metrics of a category may be charted together, as its metric layout requires.
+//
+// The default layout is resolved when the category is charted (see [Config.MetricLayoutOf]), since render settings
+// may be overridden after the config is validated.
+func (c *Config) validateMetricLayout(v Category) error {
+	if !v.MetricLayout.IsValid() {
+		return fmt.Errorf("invalid category: unsupported metric layout categories.%s.metricLayout=%s (should be one of %v)",
+			v.ID, v.MetricLayout, []MetricLayout{MetricLayoutSplit, MetricLayoutDualAxis, MetricLayoutSelector},
+		)
+	}
+
+	switch v.MetricLayout {
+	case MetricLayoutDualAxis:
+		if !c.dualAxisAllowed(v) {
+			return fmt.Errorf("invalid category: a dual axis requires 2 metrics, on vertical bar charts without difference categories.%s.metricLayout=%s", v.ID, v.MetricLayout)
+		}
+	case MetricLayoutSelector:
+		if v.XAxis.IsNumeric() || v.Difference.IsEnabled() {
+			return fmt.Errorf("invalid category: a metric selector requires bar charts without difference categories.%s.metricLayout=%s", v.ID, v.MetricLayout)
+		}
+	}
+
+	return nil
+}
+
+// MetricLayoutOf resolves the metric layout of a category with the current render settings.
+//
+// With render.dualScale, categories with two metrics default to a dual axis, when possible.
+// A dual axis falls back to split charts on horizontal charts, e.g. when the orientation is overridden from the CLI.
+func (c Config) MetricLayoutOf(v Category) MetricLayout {
+	switch v.MetricLayout {
+	case "":
+		if c.Render.DualScale && c.dualAxisAllowed(v) {
+			return MetricLayoutDualAxis
+		}
+
+		return MetricLayoutSplit
+	case MetricLayoutDualAxis:
+		if !c.dualAxisAllowed(v) {
+			return MetricLayoutSplit
+		}
+	}
+
+	return v.MetricLayout
+}
+
+// dualAxisAllowed reports whether the metrics of a category may be charted on a dual axis.
+func (c Config) dualAxisAllowed(v Category) bool {
+	return len(v.Includes.Metrics) == 2 && !v.XAxis.IsNumeric() && !v.Difference.IsEnabled() && //nolint:mnd // two Y axes
+		c.Render.Orientation != OrientationHorizontal
+}
+
+// validateXAxis checks that all the contexts of a category with a numeric X axis declare a value,
+// and that complexity is only estimated on a numeric X axis.
+func (c *Config) validateXAxis(v Category) error {
+	if !v.XAxis.IsValid() {
+		return fmt.Errorf("invalid category: unsupported x axis categories.%s.xAxis=%s (should be one of %v)",
+			v.ID, v.XAxis, []XAxis{XAxisCategory, XAxisValue, XAxisLog},
+		)
+	}
+
+	if !v.XAxis.IsNumeric() {
+		if v.Complexity {
+			return fmt.Errorf("invalid category: complexity requires a numeric x axis categories.%s.xAxis=%s", v.ID, v.XAxis)
+		}
+
+		return nil
+	}
+
+	if v.Pivot == PivotContexts {
+		return fmt.Errorf("invalid category: a numeric x axis requires versions as series categories.%s.xAxis=%s", v.ID, v.XAxis)
+	}
+
+	for j, ref := range v.Includes.Contexts {
+		value := c.contextIndex[ref].Value
+		if value == nil {
+			return fmt.Errorf("invalid category: context without a value on a numeric x axis categories.%s.includes.contexts[%d]=%s", v.ID, j, ref)
+		}
+
+		if v.XAxis == XAxisLog && *value <= 0 {
+			return fmt.Errorf("invalid category: context value must be positive on a log x axis categories.%s.includes.contexts[%d]=%s", v.ID, j, ref)
+		}
+	}
+
+	return nil
+}
+
+// validateDifference checks that a category compares two distinct versions it includes, with versions as series.
+func validateDifference(v Category) error {
+	diff := v.Difference
+	if !diff.IsEnabled() {
+		return nil
+	}
+
+	if diff.Base == "" || diff.Target == "" {
+		return fmt.Errorf("invalid category: a difference requires a base and a target version categories.%s.difference", v.ID)
+	}
+
+	if diff.Base == diff.Target {
+		return fmt.Errorf("invalid category: a difference requires two distinct versions categories.%s.difference.target=%s", v.ID, diff.Target)
+	}
+
+	if !slices.Contains(v.Includes.Versions, diff.Base) {
+		return fmt.Errorf("invalid category: difference version not included in the category categories.%s.difference.base=%s", v.ID, diff.Base)
+	}
+
+	if !slices.Contains(v.Includes.Versions, diff.Target) {
+		return fmt.Errorf("invalid category: difference version not included in the category categories.%s.difference.target=%s", v.ID, diff.Target)
+	}
+
+	if v.Pivot == PivotContexts || v.XAxis.IsNumeric() {
+		return fmt.Errorf("invalid category: a difference requires versions as series on a category x axis categories.%s.difference", v.ID)
+	}
+
+	return nil
+}
+
+// validateAnnotations checks that annotations have a text, and a numeric position on a numeric X axis.
+func validateAnnotations(v Category) error {
+	for j, annotation := range v.Annotations {
+		if annotation.Text == "" {
+			return fmt.Errorf("invalid category: empty annotation text categories.%s.annotations[%d]", v.ID, j)
+		}
+
+		if annotation.X == "" || !v.XAxis.IsNumeric() {
+			continue
+		}
+
+		if _, err := strconv.ParseFloat(annotation.X, 64); err != nil {
+			return fmt.Errorf("invalid category: annotation position must be a number on a numeric x axis categories.%s.annotations[%d].x=%s", v.ID, j, annotation.X)
+		}
+	}
+
+	return nil
+}
+
+var rexPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+func validateLabel(v Category) error {
+	for _, placeholder := range rexPlaceholder.FindAllString(v.Label, -1) {
+		if !slices.Contains(LabelPlaceholders(), placeholder) {
+			return fmt.Errorf("invalid category: unsupported placeholder in label categories.%s.label=%s (should be one of %v)",
+				v.ID, placeholder, LabelPlaceholders(),
+			)
+		}
+	}
+
+	// series are either versions or contexts: the other dimension would always expand to an empty title
+	series, empty := PivotVersions, LabelContext
+	if v.Pivot == PivotContexts {
+		series, empty = PivotContexts, LabelVersion
+	}
+	placeholders := slices.DeleteFunc(SeriesTitlePlaceholders(), func(placeholder string) bool {
+		return placeholder == empty
+	})
+
+	for _, placeholder := range rexPlaceholder.FindAllString(v.SeriesTitle, -1) {
+		if !slices.Contains(placeholders, placeholder) {
+			return fmt.Errorf("invalid category: unsupported placeholder in series title categories.%s.seriesTitle=%s (should be one of %v when series are %s)",
+				v.ID, placeholder, placeholders, series,
+			)
+		}
+	}
+
+	return nil
+}
+
+func validateFunctionLabel(v Function) error {
+	for _, placeholder := range rexPlaceholder.FindAllString(v.Label, -1) {
+		if slices.Contains(LabelPlaceholders(), placeholder) {
+			continue
+		}
+
+		group := strings.Trim(placeholder, "{}")
+		if v.match != nil {
+			if n, err := strconv.Atoi(group); err == nil && n > 0 && n <= v.match.NumSubexp() {
+				continue
+			}
+			if group != "" && slices.Contains(v.match.SubexpNames(), group) {
+				continue
+			}
+		}
+
+		return fmt.Errorf("invalid function: unsupported placeholder in label functions.%s.label=%s (should be one of %v, or a submatch of its regexp)",
+			v.ID, placeholder, LabelPlaceholders(),
+		)
+	}
+
+	return nil
+}
+
+func validateLabels(labels Labels, id string) (Labels, error) {
+	if labels.Width < 0 {
+		return labels, fmt.Errorf("invalid category: label width must be positive categories.%s.axisLabels.width=%d", id, labels.Width)
+	}
+
+	if !labels.Overflow.IsValid() {
+		return labels, fmt.Errorf("invalid category: unsupported label overflow categories.%s.axisLabels.overflow=%s (should be one of %v)",
+			id, labels.Overflow, []LabelOverflow{LabelOverflowTruncate, LabelOverflowWrap},
+		)
+	}
+	if labels.Overflow == "" {
+		labels.Overflow = LabelOverflowTruncate
+	}
+
+	labels.Abbreviations = slices.Clone(labels.Abbreviations)
+	if err := compileRules(labels.Abbreviations, "categories."+id+".axisLabels.abbreviations"); err != nil {
+		return labels, err
+	}
+
+	return labels, nil
+}
+
+func validateLimit(limit Limit, id string, metrics []MetricName) (Limit, error) {
+	if limit.N < 0 {
+		return limit, fmt.Errorf("invalid category: limit must be positive categories.%s.limit.n=%d", id, limit.N)
+	}
+
+	if limit.N == 0 {
+		return limit, nil
+	}
+
+	if limit.By == "" {
+		limit.By = metrics[0]
+	}
+
+	if !slices.Contains(metrics, limit.By) {
+		return limit, fmt.Errorf("invalid category: limit metric must be included in the category categories.%s.limit.by=%s", id, limit.By)
+	}
+
+	switch limit.Order {
+	case "":
+		limit.Order = LimitOrderDesc
+	case LimitOrderDesc, LimitOrderAsc:
+	default:
+		return limit, fmt.Errorf("invalid category: unsupported limit order categories.%s.limit.order=%s (should be one of %v)",
+			id, limit.Order, []LimitOrder{LimitOrderDesc, LimitOrderAsc},
+		)
+	}
+
+	return limit, nil
+}
+
 func (c *Config) validateRegexps() error {
 	// parse all regexps
+	if err := compileRules(c.EnvironmentRules, "environmentRules"); err != nil {
+		return err
+	}
+	if err := compileRules(c.NameRules, "nameRules"); err != nil {
+		return err
+	}
+
+	if err := c.Filters.Benchmarks.compile("benchmarks"); err != nil {
+		return err
+	}
+	if err := c.Filters.Environments.compile("environments"); err != nil {
+		return err
+	}
+	if err := c.Filters.Files.compile("files"); err != nil {
+		return err
+	}
+
 	for i, container := range c.Functions {
 		match, notMatch, err := compileRex(container.Object)
 		if err != nil {
@@ -570,6 +1811,17 @@ func (c *Config) validateRegexps() error {
 		container.match = match
 		container.notMatch = notMatch
 		c.Functions[i] = container
+
+		if err := validateFunctionLabel(container); err != nil {
+			return err
+		}
+
+		// the label of a function is expanded with the submatches of its regexp
+		if indexed, ok := c.functionIndex[container.ID]; ok {
+			indexed.match = match
+			indexed.notMatch = notMatch
+			c.functionIndex[container.ID] = indexed
+		}
 	}
 
 	for i, container := range c.Contexts {
@@ -643,6 +1895,23 @@ func (c *Config) validateRegexps() error {
 	return nil
 }
 
+// compileRules compiles the regexps of replace rules, found in the config under path.
+func compileRules(rules []ReplaceRule, path string) error {
+	for i, rule := range rules {
+		if rule.Match == "" {
+			return fmt.Errorf("invalid config: empty match %s[%d]", path, i)
+		}
+
+		match, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return fmt.Errorf("invalid config: invalid regexp %s[%d].match=%s: %w", path, i, rule.Match, err)
+		}
+		rules[i].match = match
+	}
+
+	return nil
+}
+
 func compileRex(o Object) (match, notMatch *regexp.Regexp, err error) {
 	if o.Match != "" {
 		match, err = regexp.Compile(o.Match)
@@ -682,15 +1951,28 @@ func titleize[T str](in T) string {
 // from parsed benchmark results.
 //
 // This avoids importing the parser package (which imports [config]).
+//
+// Versions are optional: when set, each version is the label of an input file, and benchmark names
+// are not searched for versions.
+//
+// With CategoryPerBenchmark, one category is generated per top-level benchmark, instead of a single one.
 type GenerateInput struct {
-	Functions []string
-	Metrics   []MetricName
+	Functions            []string
+	Metrics              []MetricName
+	Versions             []string
+	CategoryPerBenchmark bool
 }
 
 // Generate builds a [Config] from parsed benchmark data.
 //
-// It creates one function entry per unique benchmark name, includes all detected metrics,
-// and bundles everything into a single "all" category.
+// Benchmark names are split into path segments (e.g. "BenchmarkGreater/reflect/int-16").
+// Segments shared across all names are recognized as versions (the first shared segment)
+// and contexts (the last shared segment), while the remaining segments identify the function.
+//
+// Versions may be given instead, as the labels of input files (see [GenerateInput]).
+//
+// It includes all detected metrics and bundles everything into a single "all" category,
+// or into one category per top-level benchmark.
 func Generate(input GenerateInput) *Config {
 	defaults, err := loadDefaults()
 	if err != nil {
@@ -703,13 +1985,18 @@ func Generate(input GenerateInput) *Config {
 		Render: defaults.Render,
 	}
 
-	// build default metric info map from defaults
+	// build default metric info map from defaults: custom metrics are found by their unit
 	defaultMetrics := make(map[MetricName]Metric, len(defaults.Metrics))
 	for _, m := range defaults.Metrics {
+		if m.IsCustom() {
+			defaultMetrics[MetricName(m.Unit)] = m
+
+			continue
+		}
 		defaultMetrics[m.ID] = m
 	}
 
-	// metrics
+	// metrics: unknown metrics are custom metrics, named after their unit
 	for _, name := range input.Metrics {
 		if dm, ok := defaultMetrics[name]; ok {
 			cfg.Metrics = append(cfg.Metrics, dm)
@@ -717,53 +2004,292 @@ func Generate(input GenerateInput) *Config {
 			cfg.Metrics = append(cfg.Metrics, Metric{
 				ID:    name,
 				Title: titleize(name),
+				Axis:  name.String(),
+				Unit:  name.String(),
 			})
 		}
 	}
 
-	// functions
-	seen := make(map[string]struct{})
+	paths := make([][]string, 0, len(input.Functions))
 	for _, name := range input.Functions {
-		id := benchNameToID(name)
-		if _, dup := seen[id]; dup {
+		paths = append(paths, splitBenchName(name))
+	}
+	versionPos, contextPos := sharedSegments(paths)
+	if len(input.Versions) > 0 {
+		// versions are the labels of input files: the last shared segment holds contexts
+		versionPos = -1
+	}
+
+	// functions, by top-level benchmark
+	var (
+		benchmarks        []string
+		functionsPerBench = make(map[string][]string)
+	)
+	functionIDs := newIDSet()
+	for _, path := range paths {
+		name, match := functionFromSegments(path, versionPos, contextPos)
+		id, isNew := functionIDs.get(name)
+		if !isNew {
 			continue
 		}
-		seen[id] = struct{}{}
+
+		if _, ok := functionsPerBench[path[0]]; !ok {
+			benchmarks = append(benchmarks, path[0])
+		}
+		functionsPerBench[path[0]] = append(functionsPerBench[path[0]], id)
 
 		cfg.Functions = append(cfg.Functions, Function{
 			Object: Object{
 				ID:    id,
 				Title: titleize(id),
-				Match: regexp.QuoteMeta(name),
+				Match: match,
 			},
 		})
 	}
 
+	// versions and contexts
+	for _, object := range objectsFromSegments(paths, versionPos) {
+		cfg.Versions = append(cfg.Versions, Version{Object: object})
+	}
+
+	for _, label := range input.Versions {
+		// no match rule: benchmarks are assigned the version of the label of their input file
+		cfg.Versions = append(cfg.Versions, Version{Object: Object{ID: label, Title: label}})
+	}
+
+	for _, object := range objectsFromSegments(paths, contextPos) {
+		cfg.Contexts = append(cfg.Contexts, Context{Object: object})
+	}
+
 	// single category bundling everything
 	funcIDs := make([]string, 0, len(cfg.Functions))
 	for _, f := range cfg.Functions {
 		funcIDs = append(funcIDs, f.ID)
 	}
 
-	metricIDs := make([]MetricName, 0, len(cfg.Metrics))
+	versionIDs := make([]string, 0, len(cfg.Versions))
+	for _, v := range cfg.Versions {
+		versionIDs = append(versionIDs, v.ID)
+	}
+
+	contextIDs := make([]string, 0, len(cfg.Contexts))
+	for _, c := range cfg.Contexts {
+		contextIDs = append(contextIDs, c.ID)
+	}
+
+	// custom memory metrics are charted in a separate category
+	var metricIDs, memoryIDs []MetricName
 	for _, m := range cfg.Metrics {
+		if m.IsCustom() && IsMemoryUnit(m.Unit) {
+			memoryIDs = append(memoryIDs, m.ID)
+
+			continue
+		}
 		metricIDs = append(metricIDs, m.ID)
 	}
 
-	cfg.Categories = []Category{
-		{
-			ID:    "all",
-			Title: "All Benchmarks ({metric})",
+	if !input.CategoryPerBenchmark {
+		cfg.Categories = []Category{
+			{
+				ID:           "all",
+				Title:        "All Benchmarks ({metric})",
+				ContextOrder: ContextOrderNatural,
+				Includes: Includes{
+					Functions: funcIDs,
+					Versions:  versionIDs,
+					Contexts:  contextIDs,
+					Metrics:   metricIDs,
+				},
+			},
+		}
+	} else {
+		categoryIDs := newIDSet()
+		for _, bench := range benchmarks {
+			id, _ := categoryIDs.get("Benchmark" + bench)
+			cfg.Categories = append(cfg.Categories, Category{
+				ID:           id,
+				Title:        titleize(id) + " ({metric})",
+				ContextOrder: ContextOrderNatural,
+				Includes: Includes{
+					Functions: functionsPerBench[bench],
+					Versions:  versionIDs,
+					Contexts:  contextIDs,
+					Metrics:   metricIDs,
+				},
+			})
+		}
+	}
+
+	if len(memoryIDs) > 0 {
+		cfg.Categories = append(cfg.Categories, Category{
+			ID:           "memory-footprint",
+			Title:        "Memory Footprint ({metric})",
+			ContextOrder: ContextOrderNatural,
 			Includes: Includes{
 				Functions: funcIDs,
-				Metrics:   metricIDs,
+				Versions:  versionIDs,
+				Contexts:  contextIDs,
+				Metrics:   memoryIDs,
 			},
-		},
+		})
 	}
 
 	return cfg
 }
 
+// segmentEnd matches the end of a benchmark path segment: either a sub-benchmark separator,
+// the GOMAXPROCS suffix or the end of the name.
+const segmentEnd = `(/|-\d+$|$)`
+
+// splitBenchName splits a benchmark name into its path segments,
+// without the "Benchmark" prefix and the GOMAXPROCS suffix.
+func splitBenchName(name string) []string {
+	return strings.Split(TrimProcs(strings.TrimPrefix(name, "Benchmark")), "/")
+}
+
+// sharedSegments determines the positions of the path segments holding versions and contexts.
+//
+// A position (after the function name) is a dimension whenever it takes several values,
+// or whenever the same value is found under several functions.
+//
+// The first dimension holds versions and the last one holds contexts.
+// When a single dimension is found, it holds contexts. Missing positions are reported as -1.
+func sharedSegments(paths [][]string) (versionPos, contextPos int) {
+	var depth int
+	for _, path := range paths {
+		depth = max(depth, len(path))
+	}
+
+	var dimensions []int
+	for pos := 1; pos < depth; pos++ {
+		functionsPerValue := make(map[string]map[string]struct{})
+		for _, path := range paths {
+			if len(path) <= pos {
+				continue
+			}
+
+			functions, ok := functionsPerValue[path[pos]]
+			if !ok {
+				functions = make(map[string]struct{})
+				functionsPerValue[path[pos]] = functions
+			}
+			functions[path[0]] = struct{}{}
+		}
+
+		isDimension := len(functionsPerValue) > 1
+		for _, functions := range functionsPerValue {
+			if len(functions) > 1 {
+				isDimension = true
+			}
+		}
+
+		if isDimension {
+			dimensions = append(dimensions, pos)
+		}
+	}
+
+	switch len(dimensions) {
+	case 0:
+		return -1, -1
+	case 1:
+		return -1, dimensions[0]
+	default:
+		return dimensions[0], dimensions[len(dimensions)-1]
+	}
+}
+
+// functionFromSegments builds the name and the match regexp of the function identified
+// by all segments of a benchmark path, except versions and contexts.
+func functionFromSegments(path []string, versionPos, contextPos int) (name, match string) {
+	last := 0
+	for pos := range path {
+		if pos != versionPos && pos != contextPos {
+			last = pos
+		}
+	}
+
+	segments := make([]string, 0, last+1)
+	pattern := make([]string, 0, last+1)
+	for pos, segment := range path[:last+1] {
+		if pos == versionPos || pos == contextPos {
+			pattern = append(pattern, `[^/]+`)
+
+			continue
+		}
+
+		segments = append(segments, segment)
+		pattern = append(pattern, regexp.QuoteMeta(segment))
+	}
+
+	return "Benchmark" + strings.Join(segments, "/"), "^Benchmark" + strings.Join(pattern, "/") + segmentEnd
+}
+
+// objectsFromSegments builds one [Object] for each distinct path segment found at the given position.
+func objectsFromSegments(paths [][]string, pos int) []Object {
+	if pos < 0 {
+		return nil
+	}
+
+	var objects []Object
+	ids := newIDSet()
+	for _, path := range paths {
+		if len(path) <= pos {
+			continue
+		}
+
+		segment := path[pos]
+		id, isNew := ids.get(segment)
+		if !isNew {
+			continue
+		}
+
+		objects = append(objects, Object{
+			ID:    id,
+			Title: titleize(id),
+			Match: "/" + regexp.QuoteMeta(segment) + segmentEnd,
+		})
+	}
+
+	return objects
+}
+
+// idSet assigns distinct IDs to the names found in benchmarks.
+//
+// Different names may convert to the same ID (e.g. "a/b" and "a_b" both convert to "a-b"):
+// colliding IDs are disambiguated by a numbered suffix (e.g. "a-b-2").
+type idSet struct {
+	ids   map[string]string // IDs by name
+	taken map[string]struct{}
+}
+
+func newIDSet() *idSet {
+	return &idSet{
+		ids:   make(map[string]string),
+		taken: make(map[string]struct{}),
+	}
+}
+
+// get returns the ID of a name, and whether the name was not seen before.
+func (s *idSet) get(name string) (id string, isNew bool) {
+	if id, ok := s.ids[name]; ok {
+		return id, false
+	}
+
+	base := benchNameToID(name)
+	id = base
+	for n := 2; ; n++ {
+		if _, collides := s.taken[id]; !collides {
+			break
+		}
+		id = base + "-" + strconv.Itoa(n)
+	}
+
+	s.ids[name] = id
+	s.taken[id] = struct{}{}
+
+	return id, true
+}
+
 // benchNameToID converts a benchmark function name to a kebab-case ID.
 //
 // It strips the "Benchmark" prefix and the GOMAXPROCS suffix (e.g. "-16").
@@ -774,19 +2300,7 @@ func benchNameToID(name string) string {
 	id = strings.TrimPrefix(id, "_")
 
 	// strip GOMAXPROCS suffix like "-16"
-	if idx := strings.LastIndex(id, "-"); idx > 0 {
-		suffix := id[idx+1:]
-		allDigits := true
-		for _, r := range suffix {
-			if r < '0' || r > '9' {
-				allDigits = false
-				break
-			}
-		}
-		if allDigits && len(suffix) > 0 {
-			id = id[:idx]
-		}
-	}
+	id = TrimProcs(id)
 
 	// convert slashes and underscores to hyphens, lowercase
 	id = strings.Map(func(r rune) rune {
@@ -800,3 +2314,19 @@ func benchNameToID(name string) string {
 
 	return strings.ToLower(id)
 }
+
+// TrimProcs removes the GOMAXPROCS suffix (e.g. "-16") from a benchmark name.
+func TrimProcs(name string) string {
+	idx := strings.LastIndexByte(name, '-')
+	if idx <= 0 || idx == len(name)-1 {
+		return name
+	}
+
+	for _, r := range name[idx+1:] {
+		if r < '0' || r > '9' {
+			return name
+		}
+	}
+
+	return name[:idx]
+}
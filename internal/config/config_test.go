@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -28,7 +29,7 @@ func TestLoadDefaultContent(t *testing.T) {
 	assert.Len(t, cfg.Metrics, 4)
 
 	// verify metric index is populated
-	for _, name := range AllMetricNames() {
+	for _, name := range []MetricName{MetricNsPerOp, MetricAllocsPerOp, MetricBytesPerOp, MetricMBPerS} {
 		_, ok := cfg.GetMetric(name)
 		assert.True(t, ok, "expected metric %q in index", name)
 	}
@@ -52,7 +53,7 @@ func TestLoadDefaultContent(t *testing.T) {
 
 	// verify rendering defaults
 	assert.Equal(t, "roma", cfg.Render.Theme)
-	assert.Equal(t, "barchart", cfg.Render.Chart)
+	assert.Equal(t, ChartTypeBar, cfg.Render.Chart)
 	assert.Equal(t, ScaleAuto, cfg.Render.Scale)
 	assert.Equal(t, 2, cfg.Render.Layout.Horizontal)
 }
@@ -119,7 +120,7 @@ func TestMetricName(t *testing.T) {
 
 	t.Run("AllMetricNames", func(t *testing.T) {
 		names := AllMetricNames()
-		require.Len(t, names, 4)
+		require.Len(t, names, 5)
 		for _, n := range names {
 			assert.True(t, n.IsValid(), "AllMetricNames() returned invalid name %q", n)
 		}
@@ -244,6 +245,35 @@ func TestFindFunction(t *testing.T) {
 	}
 }
 
+func TestFindFunctionGroup(t *testing.T) {
+	cfg, err := loadFromString(t, `
+metrics:
+  - id: nsPerOp
+functionGroups:
+  - id: comparisons
+    Match: "Greater|Less"
+  - id: collections
+    title: Slices and maps
+    Match: "Contains|Len"
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`)
+	require.NoError(t, err)
+
+	group, ok := cfg.FindFunctionGroup("BenchmarkGreater/int")
+	require.TrueT(t, ok)
+	assert.EqualT(t, "Comparisons", group.Title)
+
+	group, ok = cfg.FindFunctionGroup("BenchmarkContains/int")
+	require.TrueT(t, ok)
+	assert.EqualT(t, "Slices and maps", group.Title)
+
+	_, ok = cfg.FindFunctionGroup("BenchmarkNegative/int")
+	assert.FalseT(t, ok)
+}
+
 func TestFindVersion(t *testing.T) {
 	cfg := mustLoadTestConfig(t, configWithVersionMatchers())
 
@@ -266,6 +296,38 @@ func TestFindVersion(t *testing.T) {
 	}
 }
 
+func TestAddVersion(t *testing.T) {
+	cfg := mustLoadTestConfig(t, `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: greater
+versions:
+  - id: reflect
+categories:
+  - id: all
+    includes:
+      functions: [greater]
+      metrics: [nsPerOp]
+  - id: reflected
+    includes:
+      functions: [greater]
+      versions: [reflect]
+      metrics: [nsPerOp]
+`)
+
+	require.True(t, cfg.AddVersion(Version{Object: Object{ID: "main", Title: "main"}}))
+	require.False(t, cfg.AddVersion(Version{Object: Object{ID: "reflect"}}))
+
+	version, ok := cfg.GetVersion("main")
+	require.True(t, ok)
+	assert.Equal(t, "main", version.Title)
+
+	// only the categories including all versions by default include the new version
+	assert.Equal(t, []string{"reflect", "main"}, cfg.Categories[0].Includes.Versions)
+	assert.Equal(t, []string{"reflect"}, cfg.Categories[1].Includes.Versions)
+}
+
 func TestFindContext(t *testing.T) {
 	cfg := mustLoadTestConfig(t, configWithContextMatchers())
 
@@ -431,64 +493,1048 @@ categories:
 			name: "metric with empty ID",
 			yaml: `
 metrics:
-  - id: ""
+  - id: ""
+categories:
+  - id: cat1
+    includes:
+      metrics: [""]
+`,
+		},
+		{
+			name: "category with empty ID",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: ""
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadFromString(t, tt.yaml)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestValidationDuplicateID(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{
+			name: "duplicate function ID",
+			yaml: `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: fn1
+    Match: "Foo"
+  - id: fn1
+    Match: "Bar"
+categories:
+  - id: cat1
+    includes:
+      functions: [fn1]
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "duplicate function group ID",
+			yaml: `
+metrics:
+  - id: nsPerOp
+functionGroups:
+  - id: grp1
+    Match: "Foo"
+  - id: grp1
+    Match: "Bar"
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "duplicate context ID",
+			yaml: `
+metrics:
+  - id: nsPerOp
+contexts:
+  - id: ctx1
+  - id: ctx1
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "duplicate version ID",
+			yaml: `
+metrics:
+  - id: nsPerOp
+versions:
+  - id: v1
+  - id: v1
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "duplicate metric ID",
+			yaml: `
+metrics:
+  - id: nsPerOp
+  - id: nsPerOp
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadFromString(t, tt.yaml)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestValidationInvalidMetricName(t *testing.T) {
+	yamlContent := `
+metrics:
+  - id: invalidMetricName
+categories:
+  - id: cat1
+    includes:
+      metrics: [invalidMetricName]
+`
+	_, err := loadFromString(t, yamlContent)
+	require.Error(t, err)
+}
+
+func TestValidationCustomMetric(t *testing.T) {
+	t.Run("with a valid custom metric", func(t *testing.T) {
+		cfg, err := loadFromString(t, `
+metrics:
+  - id: rss
+    unit: rss-bytes
+categories:
+  - id: cat1
+    includes:
+      metrics: [rss]
+`)
+		require.NoError(t, err)
+
+		m, ok := cfg.GetMetric("rss")
+		require.True(t, ok)
+		assert.True(t, m.IsCustom())
+		assert.Equal(t, "Rss", m.Title)
+	})
+
+	t.Run("with a standard metric ID", func(t *testing.T) {
+		_, err := loadFromString(t, `
+metrics:
+  - id: nsPerOp
+    unit: peak-MB
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`)
+		require.ErrorContains(t, err, "custom metric with a standard metric ID")
+	})
+
+	t.Run("with a standard unit", func(t *testing.T) {
+		_, err := loadFromString(t, `
+metrics:
+  - id: latency
+    unit: ns/op
+categories:
+  - id: cat1
+    includes:
+      metrics: [latency]
+`)
+		require.ErrorContains(t, err, "custom metric with a standard unit")
+	})
+}
+
+func TestIsMemoryUnit(t *testing.T) {
+	for _, unit := range []string{"peak-MB", "rss-bytes", "heap-KiB/op", "B"} {
+		assert.True(t, IsMemoryUnit(unit), unit)
+	}
+	for _, unit := range []string{"ns/op", "hits", "read-MB/s", ""} {
+		assert.False(t, IsMemoryUnit(unit), unit)
+	}
+}
+
+func TestValidationCategoryReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{
+			name: "category references unknown function",
+			yaml: `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: fn1
+    Match: "Foo"
+categories:
+  - id: cat1
+    includes:
+      functions: [unknown]
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category references unknown context",
+			yaml: `
+metrics:
+  - id: nsPerOp
+contexts:
+  - id: ctx1
+categories:
+  - id: cat1
+    includes:
+      contexts: [unknown]
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category references unknown version",
+			yaml: `
+metrics:
+  - id: nsPerOp
+versions:
+  - id: v1
+categories:
+  - id: cat1
+    includes:
+      versions: [unknown]
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category references unknown metric",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    includes:
+      metrics: [allocsPerOp]
+`,
+		},
+		{
+			name: "category with unsupported pivot",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    pivot: functions
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with unsupported context order",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    contextOrder: random
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with unsupported x axis",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    xAxis: time
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with a numeric x axis and a context without value",
+			yaml: `
+metrics:
+  - id: nsPerOp
+contexts:
+  - id: small
+    value: 10
+  - id: large
+categories:
+  - id: cat1
+    xAxis: value
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with a log x axis and a non-positive context value",
+			yaml: `
+metrics:
+  - id: nsPerOp
+contexts:
+  - id: empty
+    value: 0
+categories:
+  - id: cat1
+    xAxis: log
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with a numeric x axis and contexts as series",
+			yaml: `
+metrics:
+  - id: nsPerOp
+contexts:
+  - id: small
+    value: 10
+categories:
+  - id: cat1
+    xAxis: value
+    pivot: contexts
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with complexity on a category x axis",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    complexity: true
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with a difference missing its target",
+			yaml: `
+metrics:
+  - id: nsPerOp
+versions:
+  - id: v1
+categories:
+  - id: cat1
+    difference:
+      base: v1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with a difference between the same version",
+			yaml: `
+metrics:
+  - id: nsPerOp
+versions:
+  - id: v1
+categories:
+  - id: cat1
+    difference:
+      base: v1
+      target: v1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with a difference on a version not included",
+			yaml: `
+metrics:
+  - id: nsPerOp
+versions:
+  - id: v1
+  - id: v2
+  - id: v3
+categories:
+  - id: cat1
+    difference:
+      base: v1
+      target: v3
+    includes:
+      metrics: [nsPerOp]
+      versions: [v1, v2]
+`,
+		},
+		{
+			name: "category with a difference and contexts as series",
+			yaml: `
+metrics:
+  - id: nsPerOp
+versions:
+  - id: v1
+  - id: v2
+categories:
+  - id: cat1
+    pivot: contexts
+    difference:
+      base: v1
+      target: v2
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with an empty annotation",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    annotations:
+      - x: int
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with an annotation not at a number on a numeric x axis",
+			yaml: `
+metrics:
+  - id: nsPerOp
+contexts:
+  - id: small
+    value: 10
+categories:
+  - id: cat1
+    xAxis: value
+    annotations:
+      - text: cache size
+        x: small
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with an unsupported placeholder in label",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    label: '{function} - {metric}'
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "function with an unsupported placeholder in label",
+			yaml: `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: fn1
+    match: 'Benchmark(\w+)'
+    label: '{function} {2}'
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with an unsupported placeholder in series title",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    seriesTitle: '{version} - {function}'
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with a context placeholder in series title, without pivot",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    seriesTitle: '{context} / {metric}'
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with a version placeholder in series title, with pivot",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    pivot: contexts
+    seriesTitle: '{version} / {metric}'
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with an unsupported label overflow",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    axisLabels:
+      width: 20
+      overflow: scroll
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with an invalid abbreviation regexp",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    axisLabels:
+      abbreviations:
+        - match: 'Benchmark('
+          replace: B
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with negative limit",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    limit:
+      n: -1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with limit on a metric not included",
+			yaml: `
+metrics:
+  - id: nsPerOp
+  - id: allocsPerOp
+categories:
+  - id: cat1
+    limit:
+      n: 10
+      by: allocsPerOp
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with unsupported limit order",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    limit:
+      n: 10
+      order: random
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "unsupported kind of changes",
+			yaml: `
+metrics:
+  - id: nsPerOp
+changes:
+  only: regression
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "negative minimum change",
+			yaml: `
+metrics:
+  - id: nsPerOp
+changes:
+  minChange: -5
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "negative history retention",
+			yaml: `
+metrics:
+  - id: nsPerOp
+history:
+  keepRuns: -1
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`,
+		},
+		{
+			name: "category with no metrics",
+			yaml: `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    includes:
+      functions: []
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadFromString(t, tt.yaml)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestValidationCategoryLimitDefaults(t *testing.T) {
+	yamlContent := `
+metrics:
+  - id: allocsPerOp
+  - id: nsPerOp
+categories:
+  - id: cat1
+    limit:
+      n: 10
+    includes:
+      metrics: [nsPerOp, allocsPerOp]
+`
+	cfg, err := loadFromString(t, yamlContent)
+	require.NoError(t, err)
+
+	limit := cfg.Categories[0].Limit
+	assert.Equal(t, 10, limit.N)
+	assert.Equal(t, MetricNsPerOp, limit.By)
+	assert.Equal(t, LimitOrderDesc, limit.Order)
+}
+
+func TestValidationCategoryXAxis(t *testing.T) {
+	yamlContent := `
+metrics:
+  - id: nsPerOp
+contexts:
+  - id: small
+    value: 10
+  - id: large
+    value: 1e6
+  - id: other
+categories:
+  - id: cat1
+    xAxis: log
+    includes:
+      contexts: [small, large]
+      metrics: [nsPerOp]
+`
+	cfg, err := loadFromString(t, yamlContent)
+	require.NoError(t, err)
+
+	assert.EqualT(t, XAxisLog, cfg.Categories[0].XAxis)
+	assert.TrueT(t, cfg.Categories[0].XAxis.IsNumeric())
+
+	large, ok := cfg.GetContext("large")
+	require.True(t, ok)
+	require.NotNil(t, large.Value)
+	assert.InDeltaT(t, 1e6, *large.Value, 0)
+
+	other, ok := cfg.GetContext("other")
+	require.True(t, ok)
+	assert.Nil(t, other.Value)
+}
+
+func TestRenderingIsAnimated(t *testing.T) {
+	for _, tt := range []struct {
+		animation  Animation
+		screenshot bool
+		expected   bool
+	}{
+		{animation: "", screenshot: false, expected: true},
+		{animation: "", screenshot: true, expected: false},
+		{animation: AnimationAuto, screenshot: true, expected: false},
+		{animation: AnimationOn, screenshot: true, expected: true},
+		{animation: AnimationOff, screenshot: false, expected: false},
+	} {
+		t.Run(string(tt.animation), func(t *testing.T) {
+			assert.EqualT(t, tt.expected, Rendering{Animation: tt.animation}.IsAnimated(tt.screenshot))
+		})
+	}
+
+	t.Run("should reject an unsupported animation", func(t *testing.T) {
+		_, err := loadFromString(t, `
+render:
+  animation: sometimes
+metrics:
+  - id: nsPerOp
+`)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject unsupported render settings", func(t *testing.T) {
+		for _, setting := range []string{"chart: piechart", "orientation: diagonal", "scale: linear", "legend: center"} {
+			_, err := loadFromString(t, "render:\n  "+setting+"\nmetrics:\n  - id: nsPerOp\n")
+			require.ErrorContains(t, err, "invalid config: unsupported render.", setting)
+		}
+	})
+}
+
+func TestParseStrictLevel(t *testing.T) {
+	for value, want := range map[string]StrictLevel{
+		"":          StrictNone,
+		"false":     StrictNone,
+		"none":      StrictNone,
+		"true":      StrictAll,
+		"all":       StrictAll,
+		"functions": StrictFunctions,
+		"Metrics":   StrictMetrics,
+	} {
+		level, err := ParseStrictLevel(value)
+		require.NoError(t, err)
+		assert.Equal(t, want, level, value)
+	}
+
+	_, err := ParseStrictLevel("contexts")
+	require.Error(t, err)
+}
+
+func TestIsStrictFor(t *testing.T) {
+	cfg := Config{}
+	assert.False(t, cfg.IsStrictFor(StrictFunctions))
+	assert.False(t, cfg.IsStrictFor(StrictAll))
+
+	cfg.StrictLevel = StrictFunctions
+	assert.True(t, cfg.IsStrictFor(StrictFunctions))
+	assert.False(t, cfg.IsStrictFor(StrictMetrics))
+	assert.False(t, cfg.IsStrictFor(StrictAll))
+
+	cfg.StrictLevel = StrictAll
+	assert.True(t, cfg.IsStrictFor(StrictMetrics))
+	assert.True(t, cfg.IsStrictFor(StrictAll))
+}
+
+func TestValidationGroupEnvironments(t *testing.T) {
+	const yamlContent = `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+groupEnvironments: %s
+`
+	for _, grouping := range []string{"none", "series", "charts"} {
+		cfg, err := loadFromString(t, fmt.Sprintf(yamlContent, grouping))
+		require.NoError(t, err)
+		assert.Equal(t, EnvironmentGrouping(grouping), cfg.GroupEnvironments)
+	}
+
+	_, err := loadFromString(t, fmt.Sprintf(yamlContent, "pages"))
+	require.Error(t, err)
+}
+
+func TestNormalizeEnvironment(t *testing.T) {
+	cfg, err := loadFromString(t, `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+environmentRules:
+  - match: 'Intel\(R\) Core\(TM\) (i\d-\w+) CPU( @ [\d.]+GHz)?'
+    replace: '${1}'
+  - match: '\bgo1\.\S+'
+    replace: ''
+`)
+	require.NoError(t, err)
+
+	for _, env := range []string{
+		"go1.23.4 linux amd64 cpu: Intel(R) Core(TM) i7-9750H CPU @ 2.60GHz",
+		"go1.24.0 linux amd64 cpu: Intel(R) Core(TM) i7-9750H CPU",
+	} {
+		assert.EqualT(t, "linux amd64 cpu: i7-9750H", cfg.NormalizeEnvironment(env))
+	}
+
+	assert.EqualT(t, "linux arm64  cpu: Neoverse-N1", cfg.NormalizeEnvironment("linux arm64  cpu: Neoverse-N1"))
+}
+
+func TestNormalizeName(t *testing.T) {
+	cfg, err := loadFromString(t, `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: encode
+    match: '^BenchmarkEncode/json-\d+$'
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+nameRules:
+  - match: '/(size|workers)=\d+'
+    replace: ''
+  - match: '/n(\d+)'
+    replace: '/${1}'
+`)
+	require.NoError(t, err)
+
+	assert.EqualT(t, "BenchmarkEncode/json-8", cfg.NormalizeName("BenchmarkEncode/size=1024/workers=8/json-8"))
+	assert.EqualT(t, "BenchmarkSort/100-8", cfg.NormalizeName("BenchmarkSort/n100-8"))
+	assert.EqualT(t, "BenchmarkOther-8", cfg.NormalizeName("BenchmarkOther-8"))
+
+	t.Run("should match functions against normalized names", func(t *testing.T) {
+		for _, name := range []string{
+			"BenchmarkEncode/size=16/json-8",
+			"BenchmarkEncode/size=1024/json-8",
+			"BenchmarkEncode/json-8",
+		} {
+			id, ok := cfg.FindFunction(name)
+			require.True(t, ok, name)
+			assert.EqualT(t, "encode", id)
+		}
+	})
+}
+
+func TestMetricLayout(t *testing.T) {
+	const yamlContent = `
+render:
+  dualScale: %t
+metrics:
+  - id: nsPerOp
+  - id: allocsPerOp
+categories:
+  - id: two
+    includes:
+      metrics: [nsPerOp, allocsPerOp]
+  - id: one
+    includes:
+      metrics: [nsPerOp]
+  - id: selector
+    metricLayout: metric-selector
+    includes:
+      metrics: [nsPerOp, allocsPerOp]
+`
+
+	t.Run("should split metrics by default", func(t *testing.T) {
+		cfg, err := loadFromString(t, fmt.Sprintf(yamlContent, false))
+		require.NoError(t, err)
+
+		assert.EqualT(t, MetricLayoutSplit, cfg.MetricLayoutOf(cfg.Categories[0]))
+		assert.EqualT(t, MetricLayoutSplit, cfg.MetricLayoutOf(cfg.Categories[1]))
+		assert.EqualT(t, MetricLayoutSelector, cfg.MetricLayoutOf(cfg.Categories[2]))
+	})
+
+	t.Run("should default to a dual axis with dualScale", func(t *testing.T) {
+		cfg, err := loadFromString(t, fmt.Sprintf(yamlContent, true))
+		require.NoError(t, err)
+
+		assert.EqualT(t, MetricLayoutDualAxis, cfg.MetricLayoutOf(cfg.Categories[0]))
+		assert.EqualT(t, MetricLayoutSplit, cfg.MetricLayoutOf(cfg.Categories[1]))
+		assert.EqualT(t, MetricLayoutSelector, cfg.MetricLayoutOf(cfg.Categories[2]))
+	})
+
+	t.Run("should split metrics on overridden horizontal charts", func(t *testing.T) {
+		cfg, err := loadFromString(t, fmt.Sprintf(yamlContent, true))
+		require.NoError(t, err)
+
+		cfg.Render.Orientation = OrientationHorizontal
+		assert.EqualT(t, MetricLayoutSplit, cfg.MetricLayoutOf(cfg.Categories[0]))
+
+		dualAxis := cfg.Categories[0]
+		dualAxis.MetricLayout = MetricLayoutDualAxis
+		assert.EqualT(t, MetricLayoutSplit, cfg.MetricLayoutOf(dualAxis))
+	})
+}
+
+func TestFilters(t *testing.T) {
+	cfg, err := loadFromString(t, `
+metrics:
+  - id: nsPerOp
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+filters:
+  benchmarks:
+    include: '^BenchmarkEncode'
+    exclude: '/legacy'
+  files:
+    exclude: '_old\.txt$'
+`)
+	require.NoError(t, err)
+	require.False(t, cfg.Filters.IsZero())
+	assert.True(t, cfg.Filters.Environments.IsZero())
+
+	assert.True(t, cfg.Filters.Benchmarks.Retains("BenchmarkEncode/json-8"))
+	assert.False(t, cfg.Filters.Benchmarks.Retains("BenchmarkEncode/legacy-8"))
+	assert.False(t, cfg.Filters.Benchmarks.Retains("BenchmarkDecode/json-8"))
+	assert.True(t, cfg.Filters.Environments.Retains("linux amd64"))
+	assert.True(t, cfg.Filters.Files.Retains("bench.txt"))
+	assert.False(t, cfg.Filters.Files.Retains("bench_old.txt"))
+}
+
+func TestContextParam(t *testing.T) {
+	cfg, err := loadFromString(t, `
+metrics:
+  - id: nsPerOp
+contexts:
+  - id: small
+    param: size=16
+  - id: large
+    param: size=1k
+    value: 1000
+  - id: json
+    param: format=json
+categories:
+  - id: cat1
+    includes:
+      metrics: [nsPerOp]
+`)
+	require.NoError(t, err)
+
+	id, ok := cfg.FindContextFromParams(map[string]string{"workers": "8", "size": "16"})
+	require.True(t, ok)
+	assert.EqualT(t, "small", id)
+
+	_, ok = cfg.FindContextFromParams(map[string]string{"size": "32"})
+	assert.False(t, ok)
+
+	t.Run("should default the value of a context to a numeric param", func(t *testing.T) {
+		small, _ := cfg.GetContext("small")
+		require.NotNil(t, small.Value)
+		assert.InDelta(t, 16.0, *small.Value, 1e-9)
+
+		large, _ := cfg.GetContext("large")
+		require.NotNil(t, large.Value)
+		assert.InDelta(t, 1000.0, *large.Value, 1e-9)
+
+		json, _ := cfg.GetContext("json")
+		assert.Nil(t, json.Value)
+	})
+}
+
+func TestFunctionExpandLabel(t *testing.T) {
+	cfg, err := loadFromString(t, `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: encode
+    match: '^Benchmark(?P<codec>\w+)Encode/(\w+)'
+    label: '{codec} {2} ({context})'
+  - id: plain
+    match: '^BenchmarkPlain'
 categories:
   - id: cat1
     includes:
-      metrics: [""]
-`,
-		},
-		{
-			name: "category with empty ID",
-			yaml: `
+      metrics: [nsPerOp]
+`)
+	require.NoError(t, err)
+
+	encode, ok := cfg.GetFunction("encode")
+	require.True(t, ok)
+	assert.EqualT(t, "JSON small ({context})", encode.ExpandLabel("BenchmarkJSONEncode/small-8"))
+	assert.EqualT(t, "{codec} {2} ({context})", encode.ExpandLabel("BenchmarkOther-8"))
+
+	plain, ok := cfg.GetFunction("plain")
+	require.True(t, ok)
+	assert.Empty(t, plain.ExpandLabel("BenchmarkPlain-8"))
+}
+
+// TestValidationCategoryDefaultIncludes verifies that when a category
+// doesn't specify functions/contexts/versions, all defined ones are injected.
+func TestValidationCategoryDefaultIncludes(t *testing.T) {
+	yamlContent := `
 metrics:
   - id: nsPerOp
+functions:
+  - id: fn1
+    Match: "Foo"
+  - id: fn2
+    Match: "Bar"
+contexts:
+  - id: ctx1
+  - id: ctx2
+versions:
+  - id: v1
+  - id: v2
 categories:
-  - id: ""
+  - id: cat1
     includes:
       metrics: [nsPerOp]
-`,
-		},
-	}
+`
+	cfg, err := loadFromString(t, yamlContent)
+	require.NoError(t, err)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := loadFromString(t, tt.yaml)
-			require.Error(t, err)
-		})
-	}
+	cat := cfg.Categories[0]
+
+	assert.Len(t, cat.Includes.Functions, 2)
+	assert.Len(t, cat.Includes.Contexts, 2)
+	assert.Len(t, cat.Includes.Versions, 2)
 }
 
-func TestValidationDuplicateID(t *testing.T) {
+func TestValidationInvalidRegexp(t *testing.T) {
 	tests := []struct {
 		name string
 		yaml string
 	}{
 		{
-			name: "duplicate function ID",
+			name: "invalid function match regexp",
 			yaml: `
 metrics:
   - id: nsPerOp
 functions:
   - id: fn1
-    Match: "Foo"
-  - id: fn1
-    Match: "Bar"
+    Match: "[invalid"
 categories:
   - id: cat1
     includes:
-      functions: [fn1]
       metrics: [nsPerOp]
 `,
 		},
 		{
-			name: "duplicate context ID",
+			name: "invalid function notMatch regexp",
 			yaml: `
 metrics:
   - id: nsPerOp
-contexts:
-  - id: ctx1
-  - id: ctx1
+functions:
+  - id: fn1
+    Match: "valid"
+    NotMatch: "[invalid"
 categories:
   - id: cat1
     includes:
@@ -496,13 +1542,13 @@ categories:
 `,
 		},
 		{
-			name: "duplicate version ID",
+			name: "invalid function group regexp",
 			yaml: `
 metrics:
   - id: nsPerOp
-versions:
-  - id: v1
-  - id: v1
+functionGroups:
+  - id: grp1
+    Match: "[invalid"
 categories:
   - id: cat1
     includes:
@@ -510,184 +1556,108 @@ categories:
 `,
 		},
 		{
-			name: "duplicate metric ID",
+			name: "invalid environment rule regexp",
 			yaml: `
 metrics:
   - id: nsPerOp
-  - id: nsPerOp
 categories:
   - id: cat1
     includes:
       metrics: [nsPerOp]
+environmentRules:
+  - match: "[invalid"
+    name: cpu
 `,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := loadFromString(t, tt.yaml)
-			require.Error(t, err)
-		})
-	}
-}
-
-func TestValidationInvalidMetricName(t *testing.T) {
-	yamlContent := `
-metrics:
-  - id: invalidMetricName
-categories:
-  - id: cat1
-    includes:
-      metrics: [invalidMetricName]
-`
-	_, err := loadFromString(t, yamlContent)
-	require.Error(t, err)
-}
-
-func TestValidationCategoryReferences(t *testing.T) {
-	tests := []struct {
-		name string
-		yaml string
-	}{
 		{
-			name: "category references unknown function",
+			name: "empty environment rule match",
 			yaml: `
 metrics:
   - id: nsPerOp
-functions:
-  - id: fn1
-    Match: "Foo"
 categories:
   - id: cat1
     includes:
-      functions: [unknown]
       metrics: [nsPerOp]
+environmentRules:
+  - name: cpu
 `,
 		},
 		{
-			name: "category references unknown context",
+			name: "invalid context param",
 			yaml: `
 metrics:
   - id: nsPerOp
 contexts:
-  - id: ctx1
+  - id: small
+    param: size
 categories:
   - id: cat1
     includes:
-      contexts: [unknown]
       metrics: [nsPerOp]
 `,
 		},
 		{
-			name: "category references unknown version",
+			name: "unsupported metric layout",
 			yaml: `
 metrics:
   - id: nsPerOp
-versions:
-  - id: v1
 categories:
   - id: cat1
+    metricLayout: tabs
     includes:
-      versions: [unknown]
       metrics: [nsPerOp]
 `,
 		},
 		{
-			name: "category references unknown metric",
+			name: "dual axis with a single metric",
 			yaml: `
 metrics:
   - id: nsPerOp
 categories:
   - id: cat1
+    metricLayout: dual-axis
     includes:
-      metrics: [allocsPerOp]
+      metrics: [nsPerOp]
 `,
 		},
 		{
-			name: "category with no metrics",
+			name: "invalid name rule regexp",
 			yaml: `
 metrics:
   - id: nsPerOp
 categories:
   - id: cat1
     includes:
-      functions: []
+      metrics: [nsPerOp]
+nameRules:
+  - match: "[invalid"
 `,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := loadFromString(t, tt.yaml)
-			require.Error(t, err)
-		})
-	}
-}
-
-// TestValidationCategoryDefaultIncludes verifies that when a category
-// doesn't specify functions/contexts/versions, all defined ones are injected.
-func TestValidationCategoryDefaultIncludes(t *testing.T) {
-	yamlContent := `
-metrics:
-  - id: nsPerOp
-functions:
-  - id: fn1
-    Match: "Foo"
-  - id: fn2
-    Match: "Bar"
-contexts:
-  - id: ctx1
-  - id: ctx2
-versions:
-  - id: v1
-  - id: v2
-categories:
-  - id: cat1
-    includes:
-      metrics: [nsPerOp]
-`
-	cfg, err := loadFromString(t, yamlContent)
-	require.NoError(t, err)
-
-	cat := cfg.Categories[0]
-
-	assert.Len(t, cat.Includes.Functions, 2)
-	assert.Len(t, cat.Includes.Contexts, 2)
-	assert.Len(t, cat.Includes.Versions, 2)
-}
-
-func TestValidationInvalidRegexp(t *testing.T) {
-	tests := []struct {
-		name string
-		yaml string
-	}{
 		{
-			name: "invalid function match regexp",
+			name: "empty name rule match",
 			yaml: `
 metrics:
   - id: nsPerOp
-functions:
-  - id: fn1
-    Match: "[invalid"
 categories:
   - id: cat1
     includes:
       metrics: [nsPerOp]
+nameRules:
+  - name: size
 `,
 		},
 		{
-			name: "invalid function notMatch regexp",
+			name: "invalid filter regexp",
 			yaml: `
 metrics:
   - id: nsPerOp
-functions:
-  - id: fn1
-    Match: "valid"
-    NotMatch: "[invalid"
 categories:
   - id: cat1
     includes:
       metrics: [nsPerOp]
+filters:
+  environments:
+    exclude: "[invalid"
 `,
 		},
 		{
@@ -969,7 +1939,12 @@ func TestLoadDefaults(t *testing.T) {
 	cfg, err := LoadDefaults()
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
-	assert.Len(t, cfg.Metrics, 4)
+	assert.Len(t, cfg.Metrics, 6)
+
+	peak, ok := cfg.GetMetric("peakMB")
+	require.True(t, ok)
+	assert.True(t, peak.IsCustom())
+	assert.Equal(t, "peak-MB", peak.Unit)
 }
 
 func TestGenerate(t *testing.T) {
@@ -988,11 +1963,17 @@ func TestGenerate(t *testing.T) {
 	require.NotNil(t, cfg)
 
 	// verify functions
-	assert.Len(t, cfg.Functions, 4)
-	assert.Equal(t, "greater-generic-int", cfg.Functions[0].ID)
-	assert.Equal(t, "greater-reflect-int", cfg.Functions[1].ID)
-	assert.Equal(t, "less-generic-int", cfg.Functions[2].ID)
-	assert.Equal(t, "isempty", cfg.Functions[3].ID)
+	require.Len(t, cfg.Functions, 3)
+	assert.Equal(t, "greater", cfg.Functions[0].ID)
+	assert.Equal(t, "less", cfg.Functions[1].ID)
+	assert.Equal(t, "isempty", cfg.Functions[2].ID)
+
+	// verify versions and contexts inferred from shared path segments
+	require.Len(t, cfg.Versions, 2)
+	assert.Equal(t, "generic", cfg.Versions[0].ID)
+	assert.Equal(t, "reflect", cfg.Versions[1].ID)
+	require.Len(t, cfg.Contexts, 1)
+	assert.Equal(t, "int", cfg.Contexts[0].ID)
 
 	// verify metrics come from defaults
 	assert.Len(t, cfg.Metrics, 2)
@@ -1003,12 +1984,15 @@ func TestGenerate(t *testing.T) {
 	// verify category
 	require.Len(t, cfg.Categories, 1)
 	assert.Equal(t, "all", cfg.Categories[0].ID)
-	assert.Len(t, cfg.Categories[0].Includes.Functions, 4)
+	assert.Len(t, cfg.Categories[0].Includes.Functions, 3)
+	assert.Len(t, cfg.Categories[0].Includes.Versions, 2)
+	assert.Len(t, cfg.Categories[0].Includes.Contexts, 1)
 	assert.Len(t, cfg.Categories[0].Includes.Metrics, 2)
+	assert.Equal(t, ContextOrderNatural, cfg.Categories[0].ContextOrder)
 
 	// verify rendering defaults inherited
 	assert.Equal(t, "roma", cfg.Render.Theme)
-	assert.Equal(t, "barchart", cfg.Render.Chart)
+	assert.Equal(t, ChartTypeBar, cfg.Render.Chart)
 }
 
 func TestGenerateDedup(t *testing.T) {
@@ -1023,6 +2007,116 @@ func TestGenerateDedup(t *testing.T) {
 
 	cfg := Generate(input)
 	assert.Len(t, cfg.Functions, 1)
+
+	t.Run("should disambiguate functions with the same ID", func(t *testing.T) {
+		cfg := Generate(GenerateInput{
+			Functions: []string{"BenchmarkA/B-16", "BenchmarkA_B-16"},
+			Metrics:   []MetricName{MetricNsPerOp},
+		})
+		require.NoError(t, cfg.Validate())
+
+		id, ok := cfg.FindFunction("BenchmarkA/B-16")
+		require.True(t, ok)
+		assert.Equal(t, "a-b", id)
+		id, ok = cfg.FindFunction("BenchmarkA_B-16")
+		require.True(t, ok)
+		assert.Equal(t, "a-b-2", id)
+	})
+
+	t.Run("should disambiguate contexts with the same ID", func(t *testing.T) {
+		cfg := Generate(GenerateInput{
+			Functions: []string{"BenchmarkX/large-16", "BenchmarkX/Large-16", "BenchmarkY/large-16"},
+			Metrics:   []MetricName{MetricNsPerOp},
+		})
+		require.NoError(t, cfg.Validate())
+
+		id, ok := cfg.FindContext("BenchmarkX/large-16")
+		require.True(t, ok)
+		assert.Equal(t, "large", id)
+		id, ok = cfg.FindContext("BenchmarkX/Large-16")
+		require.True(t, ok)
+		assert.Equal(t, "large-2", id)
+	})
+}
+
+func TestGenerateFileVersions(t *testing.T) {
+	input := GenerateInput{
+		Functions: []string{
+			"BenchmarkGreater/int-16",
+			"BenchmarkGreater/float64-16",
+			"BenchmarkLess/int-16",
+		},
+		Metrics:  []MetricName{MetricNsPerOp},
+		Versions: []string{"old", "new"},
+	}
+
+	cfg := Generate(input)
+	require.NoError(t, cfg.Validate())
+
+	require.Len(t, cfg.Versions, 2)
+	assert.Equal(t, "old", cfg.Versions[0].ID)
+	assert.Equal(t, "new", cfg.Versions[1].ID)
+	assert.Equal(t, []string{"old", "new"}, cfg.Categories[0].Includes.Versions)
+	assert.Len(t, cfg.Contexts, 2)
+
+	// versions are not matched from benchmark names, but resolved from the labels of input files
+	_, ok := cfg.FindVersion("BenchmarkGreater/int-16")
+	assert.False(t, ok)
+	version, ok := cfg.GetVersion("new")
+	require.True(t, ok)
+	assert.Equal(t, "new", version.Title)
+}
+
+func TestGenerateCategoryPerBenchmark(t *testing.T) {
+	input := GenerateInput{
+		Functions: []string{
+			"BenchmarkGreater/generic/int-16",
+			"BenchmarkGreater/reflect/int-16",
+			"BenchmarkLess/generic/int-16",
+			"BenchmarkLess/reflect/int-16",
+		},
+		Metrics:              []MetricName{MetricNsPerOp},
+		CategoryPerBenchmark: true,
+	}
+
+	cfg := Generate(input)
+	require.NoError(t, cfg.Validate())
+
+	require.Len(t, cfg.Categories, 2)
+	assert.Equal(t, "greater", cfg.Categories[0].ID)
+	assert.Equal(t, "Greater ({metric})", cfg.Categories[0].Title)
+	assert.Equal(t, []string{"greater"}, cfg.Categories[0].Includes.Functions)
+	assert.Equal(t, "less", cfg.Categories[1].ID)
+	assert.Equal(t, []string{"less"}, cfg.Categories[1].Includes.Functions)
+	assert.Len(t, cfg.Categories[1].Includes.Versions, 2)
+}
+
+func TestGenerateCustomMetrics(t *testing.T) {
+	input := GenerateInput{
+		Functions: []string{
+			"BenchmarkGreater/int-16",
+			"BenchmarkLess/int-16",
+		},
+		Metrics: []MetricName{MetricNsPerOp, "peak-MB", "rss-bytes", "hits"},
+	}
+
+	cfg := Generate(input)
+	require.NoError(t, cfg.Validate())
+
+	// the default definition of a custom metric is found by its unit
+	peak, ok := cfg.GetMetric("peakMB")
+	require.True(t, ok)
+	assert.Equal(t, "peak-MB", peak.Unit)
+
+	rss, ok := cfg.GetMetric("rss-bytes")
+	require.True(t, ok)
+	assert.Equal(t, "rss-bytes", rss.Unit)
+
+	// custom memory metrics are charted in a separate category
+	require.Len(t, cfg.Categories, 2)
+	assert.Equal(t, []MetricName{MetricNsPerOp, "hits"}, cfg.Categories[0].Includes.Metrics)
+	assert.Equal(t, "memory-footprint", cfg.Categories[1].ID)
+	assert.Equal(t, []MetricName{"peakMB", "rss-bytes"}, cfg.Categories[1].Includes.Metrics)
 }
 
 func TestEncodeYAML(t *testing.T) {
@@ -1054,6 +2148,93 @@ func TestEncodeYAML(t *testing.T) {
 	assert.Equal(t, "all", loaded.Categories[0].ID)
 }
 
+func TestGenerateSegments(t *testing.T) {
+	input := GenerateInput{
+		Functions: []string{
+			"BenchmarkGreater",
+			"BenchmarkGreater/generic/float64-16",
+			"BenchmarkGreater/generic/int-16",
+			"BenchmarkGreater/reflect/float64-16",
+			"BenchmarkGreater/reflect/int-16",
+			"BenchmarkGreaterOrEqual/generic/int-16",
+			"BenchmarkGreaterOrEqual/reflect/int-16",
+			"BenchmarkSort/quick/small-16",
+			"BenchmarkSort/quick/large-16",
+		},
+		Metrics: []MetricName{MetricNsPerOp},
+	}
+
+	// round-trip the generated config to compile match rules
+	file := filepath.Join(t.TempDir(), "generated.yaml")
+	f, err := os.Create(file)
+	require.NoError(t, err)
+	require.NoError(t, Generate(input).EncodeYAML(f))
+	require.NoError(t, f.Close())
+
+	cfg, err := Load(file)
+	require.NoError(t, err)
+
+	t.Run("should infer dimensions", func(t *testing.T) {
+		assert.Len(t, cfg.Functions, 3)
+		assert.Len(t, cfg.Versions, 3) // generic, reflect, quick
+		assert.Len(t, cfg.Contexts, 4) // float64, int, small, large
+	})
+
+	tests := []struct {
+		name     string
+		function string
+		version  string
+		context  string
+	}{
+		{"BenchmarkGreater/reflect/float64-16", "greater", "reflect", "float64"},
+		{"BenchmarkGreaterOrEqual/generic/int-16", "greaterorequal", "generic", "int"},
+		{"BenchmarkSort/quick/large-16", "sort", "quick", "large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			function, ok := cfg.FindFunction(tt.name)
+			require.True(t, ok)
+			assert.Equal(t, tt.function, function)
+
+			version, ok := cfg.FindVersion(tt.name)
+			require.True(t, ok)
+			assert.Equal(t, tt.version, version)
+
+			context, ok := cfg.FindContext(tt.name)
+			require.True(t, ok)
+			assert.Equal(t, tt.context, context)
+		})
+	}
+}
+
+func TestSharedSegments(t *testing.T) {
+	tests := []struct {
+		name        string
+		functions   []string
+		wantVersion int
+		wantContext int
+	}{
+		{"flat names", []string{"BenchmarkFoo-16", "BenchmarkBar-16"}, -1, -1},
+		{"single dimension", []string{"BenchmarkFoo/small", "BenchmarkFoo/large"}, -1, 1},
+		{"two dimensions", []string{"BenchmarkFoo/a/x", "BenchmarkBar/a/x"}, 1, 2},
+		{"constant segment", []string{"BenchmarkFoo/only/x", "BenchmarkFoo/only/y"}, -1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := make([][]string, 0, len(tt.functions))
+			for _, name := range tt.functions {
+				paths = append(paths, splitBenchName(name))
+			}
+
+			versionPos, contextPos := sharedSegments(paths)
+			assert.Equal(t, tt.wantVersion, versionPos)
+			assert.Equal(t, tt.wantContext, contextPos)
+		})
+	}
+}
+
 func TestBenchNameToID(t *testing.T) {
 	tests := []struct {
 		input string
@@ -1102,3 +2283,15 @@ files:
         Match: "generics"
 `
 }
+
+func TestTrimProcs(t *testing.T) {
+	for name, expected := range map[string]string{
+		"BenchmarkSort/small-16": "BenchmarkSort/small",
+		"BenchmarkSort/small":    "BenchmarkSort/small",
+		"BenchmarkSort/n-":       "BenchmarkSort/n-",
+		"BenchmarkSort/n-x1":     "BenchmarkSort/n-x1",
+		"-16":                    "-16",
+	} {
+		assert.EqualT(t, expected, TrimProcs(name), name)
+	}
+}
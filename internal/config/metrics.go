@@ -1,5 +1,7 @@
 package config
 
+import "strings"
+
 // MetricName identifies a benchmark metric (e.g. "nsPerOp", "allocsPerOp").
 type MetricName string
 
@@ -9,8 +11,28 @@ const (
 	MetricAllocsPerOp MetricName = "allocsPerOp"
 	MetricBytesPerOp  MetricName = "bytesPerOp"
 	MetricMBPerS      MetricName = "MBytesPerS"
+	MetricIterations  MetricName = "iterations" // the number of iterations run by the benchmark framework (b.N)
+)
+
+// Units reported by the go test framework for the standard metrics.
+const (
+	unitNsPerOp     = "ns/op"
+	unitAllocsPerOp = "allocs/op"
+	unitBytesPerOp  = "B/op"
+	unitMBPerS      = "MB/s"
 )
 
+// IsStandardUnit reports whether a unit is reported by the go test framework for a standard metric (e.g. "ns/op"),
+// as opposed to the units of custom metrics reported with testing.B.ReportMetric (e.g. "peak-MB").
+func IsStandardUnit(unit string) bool {
+	switch unit {
+	case unitNsPerOp, unitAllocsPerOp, unitBytesPerOp, unitMBPerS:
+		return true
+	default:
+		return false
+	}
+}
+
 // String returns the metric name as a plain string.
 func (m MetricName) String() string {
 	return string(m)
@@ -19,7 +41,7 @@ func (m MetricName) String() string {
 // IsValid reports whether the metric name is one of the known benchmark metrics.
 func (m MetricName) IsValid() bool {
 	switch m {
-	case MetricNsPerOp, MetricAllocsPerOp, MetricBytesPerOp, MetricMBPerS:
+	case MetricNsPerOp, MetricAllocsPerOp, MetricBytesPerOp, MetricMBPerS, MetricIterations:
 		return true
 	default:
 		return false
@@ -33,5 +55,31 @@ func AllMetricNames() []MetricName {
 		MetricAllocsPerOp,
 		MetricBytesPerOp,
 		MetricMBPerS,
+		MetricIterations,
+	}
+}
+
+// HigherIsBetter reports whether a greater value of the metric denotes a better performance
+// (e.g. a throughput), as opposed to timings and allocations.
+func (m MetricName) HigherIsBetter() bool {
+	return m == MetricMBPerS
+}
+
+// IsMemoryUnit reports whether the unit of a custom metric denotes a memory size,
+// e.g. "peak-MB", "rss-bytes" or "heap-KiB/op" (but not a throughput such as "read-MB/s").
+func IsMemoryUnit(unit string) bool {
+	parts := strings.FieldsFunc(unit, func(r rune) bool { return r == '-' || r == '/' })
+	if len(parts) > 1 && parts[len(parts)-1] == "op" {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) == 0 {
+		return false
+	}
+
+	switch parts[len(parts)-1] {
+	case "B", "KB", "MB", "GB", "KiB", "MiB", "GiB", "bytes":
+		return true
+	default:
+		return false
 	}
 }
@@ -0,0 +1,58 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// FormatCSV is the name of the built-in exporter writing the data points of the scenario as CSV.
+const FormatCSV = "csv"
+
+// csvHeader lists the columns of the CSV export.
+//
+// The last columns trace each value back to its source: the number of benchmark results behind the value,
+// the input files (separated by ";") and the raw benchmark name.
+var csvHeader = []string{
+	"category", "metric", "unit", "function", "version", "context", "series", "label", "value",
+	"samples", "files", "benchmark",
+}
+
+// WriteCSV writes the data points of the scenario as CSV, one row per point.
+func WriteCSV(_ context.Context, w io.Writer, scenario *model.Scenario) error {
+	enc := csv.NewWriter(w)
+
+	if err := enc.Write(csvHeader); err != nil {
+		return fmt.Errorf("encoding scenario as CSV: %w", err)
+	}
+
+	for _, category := range scenario.Categories {
+		for _, data := range category.Data {
+			for _, series := range data.Series {
+				for _, point := range series.Points {
+					record := []string{
+						category.ID, string(point.Metric), data.Metric.Axis,
+						point.Function, point.Version, point.Context,
+						series.Title, point.Label, strconv.FormatFloat(point.Value, 'g', -1, 64),
+						strconv.Itoa(point.Origin.Samples), strings.Join(point.Origin.Files, ";"), point.Origin.Benchmark,
+					}
+					if err := enc.Write(record); err != nil {
+						return fmt.Errorf("encoding scenario as CSV: %w", err)
+					}
+				}
+			}
+		}
+	}
+
+	enc.Flush()
+	if err := enc.Error(); err != nil {
+		return fmt.Errorf("encoding scenario as CSV: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,6 @@
+// Package export writes organized benchmark scenarios to custom output formats.
+//
+// Exporters are registered by name. Besides the built-in "json" and "csv" exporters,
+// external programs may be plugged in with an [Exec] bridge: they receive the scenario as JSON
+// on their standard input.
+package export
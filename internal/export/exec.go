@@ -0,0 +1,49 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// Exec is an [Exporter] bridging to an external program.
+//
+// The program receives the scenario as JSON (see [WriteJSON]) on its standard input.
+// Whatever it writes on its standard output is the exported output.
+type Exec struct {
+	Command []string
+}
+
+// NewExec builds an [Exec] exporter running a command: a program and its arguments.
+func NewExec(command ...string) *Exec {
+	return &Exec{Command: command}
+}
+
+// Export runs the command, feeding it with the scenario.
+func (e *Exec) Export(ctx context.Context, w io.Writer, scenario *model.Scenario) error {
+	if len(e.Command) == 0 {
+		return errors.New("exec exporter: no command")
+	}
+
+	var input, stderr bytes.Buffer
+	if err := WriteJSON(ctx, &input, scenario); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, e.Command[0], e.Command[1:]...) //nolint:gosec // the exporter command is provided by the user
+	cmd.Stdin = &input
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running exporter %q: %w: %s", strings.Join(e.Command, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// FormatJSON is the name of the built-in exporter writing the scenario as JSON.
+const FormatJSON = "json"
+
+// Exporter writes an organized [model.Scenario] in some output format.
+type Exporter interface {
+	Export(ctx context.Context, w io.Writer, scenario *model.Scenario) error
+}
+
+// ExporterFunc is a function that satisfies the [Exporter] interface.
+type ExporterFunc func(ctx context.Context, w io.Writer, scenario *model.Scenario) error
+
+// Export calls the function.
+func (f ExporterFunc) Export(ctx context.Context, w io.Writer, scenario *model.Scenario) error {
+	return f(ctx, w, scenario)
+}
+
+var (
+	mx        sync.RWMutex
+	exporters = map[string]Exporter{
+		FormatJSON: ExporterFunc(WriteJSON),
+		FormatCSV:  ExporterFunc(WriteCSV),
+	}
+)
+
+// Register makes an [Exporter] available by name.
+//
+// It fails if the name is empty or already registered.
+func Register(name string, exporter Exporter) error {
+	if name == "" || exporter == nil {
+		return fmt.Errorf("registering exporter %q: a name and an exporter are required", name)
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if _, exists := exporters[name]; exists {
+		return fmt.Errorf("registering exporter %q: already registered", name)
+	}
+	exporters[name] = exporter
+
+	return nil
+}
+
+// Lookup returns the [Exporter] registered with this name.
+func Lookup(name string) (Exporter, error) {
+	mx.RLock()
+	defer mx.RUnlock()
+
+	exporter, ok := exporters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown exporter %q: expected one of %v", name, names())
+	}
+
+	return exporter, nil
+}
+
+// Names returns the sorted names of all registered exporters.
+func Names() []string {
+	mx.RLock()
+	defer mx.RUnlock()
+
+	return names()
+}
+
+func names() []string {
+	registered := make([]string, 0, len(exporters))
+	for name := range exporters {
+		registered = append(registered, name)
+	}
+	slices.Sort(registered)
+
+	return registered
+}
+
+// WriteJSON writes the scenario as indented JSON.
+func WriteJSON(_ context.Context, w io.Writer, scenario *model.Scenario) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(scenario); err != nil {
+		return fmt.Errorf("encoding scenario as JSON: %w", err)
+	}
+
+	return nil
+}
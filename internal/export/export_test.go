@@ -0,0 +1,130 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func testScenario() *model.Scenario {
+	return &model.Scenario{
+		Name: "test",
+		Categories: []model.Category{
+			{ID: "comparisons", Title: "Comparisons"},
+		},
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("should provide the JSON exporter", func(t *testing.T) {
+		assert.Contains(t, Names(), FormatJSON)
+
+		exporter, err := Lookup(FormatJSON)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, exporter.Export(context.Background(), &buf, testScenario()))
+
+		var scenario model.Scenario
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &scenario))
+		assert.EqualT(t, "test", scenario.Name)
+		require.Len(t, scenario.Categories, 1)
+		assert.EqualT(t, "comparisons", scenario.Categories[0].ID)
+	})
+
+	t.Run("should register a custom exporter", func(t *testing.T) {
+		custom := ExporterFunc(func(_ context.Context, w io.Writer, scenario *model.Scenario) error {
+			_, err := io.WriteString(w, scenario.Name)
+
+			return err
+		})
+		require.NoError(t, Register("test-custom", custom))
+		require.Error(t, Register("test-custom", custom))
+		assert.Contains(t, Names(), "test-custom")
+
+		exporter, err := Lookup("test-custom")
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, exporter.Export(context.Background(), &buf, testScenario()))
+		assert.EqualT(t, "test", buf.String())
+	})
+
+	t.Run("should refuse invalid registrations", func(t *testing.T) {
+		require.Error(t, Register("", ExporterFunc(WriteJSON)))
+		require.Error(t, Register("test-nil", nil))
+	})
+
+	t.Run("should fail on unknown exporter", func(t *testing.T) {
+		_, err := Lookup("unknown")
+		require.ErrorContains(t, err, `unknown exporter "unknown"`)
+	})
+}
+
+func TestExec(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no shell available to run exporters")
+	}
+
+	t.Run("should feed the scenario to the program", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, NewExec("sh", "-c", "wc -c | tr -d ' '").Export(context.Background(), &buf, testScenario()))
+
+		var expected bytes.Buffer
+		require.NoError(t, WriteJSON(context.Background(), &expected, testScenario()))
+		assert.EqualT(t, strconv.Itoa(expected.Len()), strings.TrimSpace(buf.String()))
+	})
+
+	t.Run("should fail when the program fails", func(t *testing.T) {
+		err := NewExec("sh", "-c", "echo dashboard unavailable >&2; exit 1").Export(context.Background(), io.Discard, testScenario())
+		require.ErrorContains(t, err, "dashboard unavailable")
+	})
+
+	t.Run("should fail without a command", func(t *testing.T) {
+		require.Error(t, NewExec().Export(context.Background(), io.Discard, testScenario()))
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	scenario := &model.Scenario{
+		Name: "test",
+		Categories: []model.Category{{
+			ID: "sort",
+			Data: []model.CategoryData{{
+				Metric: config.Metric{ID: config.MetricNsPerOp, Axis: "ns/op"},
+				Series: []model.MetricSeries{{
+					Title: "v1",
+					Points: []model.MetricPoint{{
+						SeriesKey: model.SeriesKey{Function: "sort", Version: "v1", Context: "small", Metric: config.MetricNsPerOp},
+						Label:     "small",
+						Value:     123.5,
+						Origin:    model.Origin{Files: []string{"old.txt", "new.txt"}, Benchmark: "BenchmarkSort/small-8", Samples: 2},
+					}},
+				}},
+			}},
+		}},
+	}
+
+	exporter, err := Lookup(FormatCSV)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(context.Background(), &buf, scenario))
+
+	assert.EqualT(t,
+		"category,metric,unit,function,version,context,series,label,value,samples,files,benchmark\n"+
+			"sort,nsPerOp,ns/op,sort,v1,small,v1,small,123.5,2,old.txt;new.txt,BenchmarkSort/small-8\n",
+		buf.String(),
+	)
+}
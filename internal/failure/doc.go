@@ -0,0 +1,7 @@
+// Package failure describes the errors of benchviz with structured details,
+// so they may be reported in a machine-readable format (e.g. to CI wrappers).
+//
+// An [Error] tells at which stage of the pipeline a failure occurred, and which input file
+// and benchmark are concerned, if any. Errors may be wrapped or joined with other errors:
+// [Details] retrieves all the structured errors from an error tree.
+package failure
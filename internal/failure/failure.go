@@ -0,0 +1,139 @@
+package failure
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Stage of the pipeline where a failure occurred.
+type Stage string
+
+// Stages of the pipeline.
+const (
+	StageConfig   Stage = "config"
+	StageParse    Stage = "parse"
+	StageOrganize Stage = "organize"
+	StageRender   Stage = "render"
+	StageCompare  Stage = "compare"
+)
+
+// Error is an error with structured details.
+//
+// The message of the error is the one of the wrapped error Err, if any, or the Reason otherwise.
+type Error struct {
+	Stage     Stage  `json:"stage,omitempty"`
+	File      string `json:"file,omitempty"`
+	Benchmark string `json:"benchmark,omitempty"`
+	Reason    string `json:"reason"`
+	Err       error  `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return e.Reason
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithStage qualifies an error with the stage of the pipeline where it occurred.
+//
+// Errors that already hold structured details are returned unchanged.
+func WithStage(stage Stage, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var structured *Error
+	if errors.As(err, &structured) {
+		return err
+	}
+
+	return &Error{Stage: stage, Reason: err.Error(), Err: err}
+}
+
+// Join several errors (e.g. one per benchmark) into a single error with a summary message.
+func Join(message string, errs ...error) error {
+	return &joined{message: message, errs: errs}
+}
+
+type joined struct {
+	message string
+	errs    []error
+}
+
+func (j *joined) Error() string {
+	return j.message
+}
+
+func (j *joined) Unwrap() []error {
+	return j.errs
+}
+
+// Details returns all the structured errors found in an error tree, in depth-first order.
+//
+// An error without any structured detail is reported as a single [Error] with its message as the reason.
+func Details(err error) []*Error {
+	if err == nil {
+		return nil
+	}
+
+	var details []*Error
+	collect(err, &details)
+
+	if len(details) == 0 {
+		return []*Error{{Reason: err.Error(), Err: err}}
+	}
+
+	return details
+}
+
+// collect walks the error tree, retaining the innermost structured errors.
+func collect(err error, details *[]*Error) {
+	var nested []*Error
+
+	switch e := err.(type) { //nolint:errorlint // walking the tree explicitly
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			collect(inner, &nested)
+		}
+	case interface{ Unwrap() error }:
+		if inner := e.Unwrap(); inner != nil {
+			collect(inner, &nested)
+		}
+	}
+
+	if len(nested) > 0 {
+		*details = append(*details, nested...)
+
+		return
+	}
+
+	if structured, ok := err.(*Error); ok { //nolint:errorlint // walking the tree explicitly
+		*details = append(*details, structured)
+	}
+}
+
+// Report is the JSON representation of an error.
+type Report struct {
+	Error    string   `json:"error"`
+	Failures []*Error `json:"failures"`
+}
+
+// NewReport builds the report of an error.
+func NewReport(err error) Report {
+	return Report{
+		Error:    err.Error(),
+		Failures: Details(err),
+	}
+}
+
+// WriteJSON writes the report of an error as a single line of JSON.
+func WriteJSON(w io.Writer, err error) error {
+	return json.NewEncoder(w).Encode(NewReport(err))
+}
@@ -0,0 +1,74 @@
+package failure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestDetails(t *testing.T) {
+	t.Run("should find a wrapped error", func(t *testing.T) {
+		cause := errors.New("no such file")
+		err := fmt.Errorf("parsing files: %w", &Error{Stage: StageParse, File: "bench.txt", Reason: cause.Error(), Err: cause})
+
+		details := Details(err)
+		require.Len(t, details, 1)
+		assert.EqualT(t, StageParse, details[0].Stage)
+		assert.EqualT(t, "bench.txt", details[0].File)
+		assert.EqualT(t, "no such file", details[0].Reason)
+		assert.EqualT(t, "no such file", details[0].Error())
+		assert.ErrorIs(t, err, cause)
+	})
+
+	t.Run("should find joined errors", func(t *testing.T) {
+		err := WithStage(StageOrganize, Join("2 benchmarks not ingested",
+			&Error{Stage: StageOrganize, Benchmark: "BenchmarkA", Reason: "no function matched"},
+			&Error{Stage: StageOrganize, Benchmark: "BenchmarkB", Reason: "no configured metric"},
+		))
+
+		assert.EqualT(t, "2 benchmarks not ingested", err.Error())
+		details := Details(err)
+		require.Len(t, details, 2)
+		assert.EqualT(t, "BenchmarkA", details[0].Benchmark)
+		assert.EqualT(t, "BenchmarkB", details[1].Benchmark)
+	})
+
+	t.Run("should qualify a plain error with a stage", func(t *testing.T) {
+		err := WithStage(StageRender, errors.New("rendering page"))
+
+		details := Details(err)
+		require.Len(t, details, 1)
+		assert.EqualT(t, StageRender, details[0].Stage)
+		assert.EqualT(t, "rendering page", details[0].Reason)
+	})
+
+	t.Run("should report a plain error", func(t *testing.T) {
+		details := Details(errors.New("boom"))
+		require.Len(t, details, 1)
+		assert.EqualT(t, Stage(""), details[0].Stage)
+		assert.EqualT(t, "boom", details[0].Reason)
+
+		assert.Empty(t, Details(nil))
+		require.NoError(t, WithStage(StageRender, nil))
+	})
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := fmt.Errorf("strict mode: %w", &Error{Stage: StageOrganize, File: "bench.txt", Benchmark: "BenchmarkA", Reason: "conflicting values"})
+	require.NoError(t, WriteJSON(&buf, err))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.EqualT(t, "strict mode: conflicting values", report.Error)
+	require.Len(t, report.Failures, 1)
+	assert.EqualT(t, StageOrganize, report.Failures[0].Stage)
+	assert.EqualT(t, "bench.txt", report.Failures[0].File)
+	assert.EqualT(t, "BenchmarkA", report.Failures[0].Benchmark)
+	assert.EqualT(t, "conflicting values", report.Failures[0].Reason)
+}
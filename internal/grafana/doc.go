@@ -0,0 +1,6 @@
+// Package grafana serves the history of benchmark results to Grafana.
+//
+// The [Handler] implements the protocol of the Grafana JSON datasource (simpod-json-datasource):
+// each benchmark result saved in baseline snapshots is a metric, with one data point per snapshot.
+// It also serves the history as a flat JSON table, suitable for the Grafana Infinity datasource.
+package grafana
@@ -0,0 +1,230 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+)
+
+// Loader loads the history of benchmark results. It is called for every request.
+type Loader func(ctx context.Context) ([]baseline.Snapshot, error)
+
+// Metric is an entry of the list of metrics returned by /metrics.
+type Metric struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// QueryRequest is the body of a /query request, limited to the fields used here.
+type QueryRequest struct {
+	Range   Range    `json:"range"`
+	Targets []Target `json:"targets"`
+}
+
+// Range is the time range of a query.
+//
+// A zero bound does not limit the range.
+type Range struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Target is a metric queried by a /query request.
+type Target struct {
+	RefID  string `json:"refId"`
+	Target string `json:"target"`
+}
+
+// TimeSeries is the response of a /query request for a single target.
+//
+// Each data point is a pair [value, timestamp in milliseconds].
+type TimeSeries struct {
+	Target     string       `json:"target"`
+	DataPoints [][2]float64 `json:"datapoints"`
+}
+
+// Row is an entry of the flat history table returned by /history.
+type Row struct {
+	Time     time.Time `json:"time"`
+	Snapshot string    `json:"snapshot"`
+	Scenario string    `json:"scenario,omitempty"`
+	Target   string    `json:"target"`
+
+	baseline.Result
+}
+
+// Handler serves the history of benchmark results with the endpoints expected by Grafana datasources.
+//
+// The endpoints are:
+//
+//   - GET /: health check of the JSON datasource
+//   - POST /metrics: the list of all known metrics (i.e. query targets)
+//   - POST /query: the time series of the queried targets over a time range
+//   - GET /history: all results as a flat table, e.g. for the Infinity datasource
+type Handler struct {
+	load Loader
+	mux  *http.ServeMux
+	l    *slog.Logger
+}
+
+// New builds a [Handler] for the history provided by a [Loader].
+func New(load Loader, l *slog.Logger) *Handler {
+	if l == nil {
+		l = slog.Default()
+	}
+
+	h := &Handler{
+		load: load,
+		mux:  http.NewServeMux(),
+		l:    l.With(slog.String("module", "grafana")),
+	}
+
+	h.mux.HandleFunc("GET /{$}", h.handleHealth)
+	h.mux.HandleFunc("POST /metrics", h.handleMetrics)
+	h.mux.HandleFunc("POST /query", h.handleQuery)
+	h.mux.HandleFunc("GET /history", h.handleHistory)
+
+	return h
+}
+
+// ServeHTTP implements [http.Handler].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// TargetName identifies the series of a result in the history, e.g. "greater/reflect/int (nsPerOp)".
+//
+// Results of named scenarios are prefixed by the scenario name, e.g. "Sort: greater/reflect/int (nsPerOp)".
+func TargetName(snapshot baseline.Snapshot, result baseline.Result) string {
+	if snapshot.Scenario == "" {
+		return result.String()
+	}
+
+	return snapshot.Scenario + ": " + result.String()
+}
+
+func (h *Handler) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	snapshots, ok := h.loadSnapshots(w, r)
+	if !ok {
+		return
+	}
+
+	var targets []string
+	for _, snapshot := range snapshots {
+		for _, result := range snapshot.Results {
+			targets = append(targets, TargetName(snapshot, result))
+		}
+	}
+	slices.Sort(targets)
+	targets = slices.Compact(targets)
+
+	metrics := make([]Metric, 0, len(targets))
+	for _, target := range targets {
+		metrics = append(metrics, Metric{Label: target, Value: target})
+	}
+
+	h.writeJSON(w, metrics)
+}
+
+func (h *Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var query QueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		http.Error(w, fmt.Sprintf("invalid query: %v", err), http.StatusBadRequest)
+
+		return
+	}
+
+	snapshots, ok := h.loadSnapshots(w, r)
+	if !ok {
+		return
+	}
+
+	series := make([]TimeSeries, 0, len(query.Targets))
+	for _, target := range query.Targets {
+		if target.Target == "" {
+			continue
+		}
+
+		points := make([][2]float64, 0, len(snapshots))
+		for _, snapshot := range snapshots {
+			if !query.Range.contains(snapshot.Created) {
+				continue
+			}
+
+			for _, result := range snapshot.Results {
+				if TargetName(snapshot, result) == target.Target {
+					points = append(points, [2]float64{result.Value, float64(snapshot.Created.UnixMilli())})
+				}
+			}
+		}
+
+		series = append(series, TimeSeries{Target: target.Target, DataPoints: points})
+	}
+
+	h.writeJSON(w, series)
+}
+
+func (h *Handler) handleHistory(w http.ResponseWriter, r *http.Request) {
+	snapshots, ok := h.loadSnapshots(w, r)
+	if !ok {
+		return
+	}
+
+	rows := make([]Row, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		for _, result := range snapshot.Results {
+			rows = append(rows, Row{
+				Time:     snapshot.Created,
+				Snapshot: snapshot.Name,
+				Scenario: snapshot.Scenario,
+				Target:   TargetName(snapshot, result),
+				Result:   result,
+			})
+		}
+	}
+
+	h.writeJSON(w, rows)
+}
+
+// loadSnapshots loads the history, sorted by creation time, and responds with an error if it fails.
+func (h *Handler) loadSnapshots(w http.ResponseWriter, r *http.Request) ([]baseline.Snapshot, bool) {
+	snapshots, err := h.load(r.Context())
+	if err != nil {
+		h.l.Error("failed to load history", slog.String("path", r.URL.Path), slog.String("error", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return nil, false
+	}
+
+	slices.SortStableFunc(snapshots, func(a, b baseline.Snapshot) int {
+		return a.Created.Compare(b.Created)
+	})
+
+	return snapshots, true
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		h.l.Warn("failed to write response", slog.String("error", err.Error()))
+	}
+}
+
+func (r Range) contains(t time.Time) bool {
+	if !r.From.IsZero() && t.Before(r.From) {
+		return false
+	}
+
+	return r.To.IsZero() || !t.After(r.To)
+}
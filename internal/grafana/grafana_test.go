@@ -0,0 +1,111 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestHandler(t *testing.T) {
+	h := New(func(context.Context) ([]baseline.Snapshot, error) {
+		return testHistory(), nil
+	}, nil)
+
+	t.Run("should answer the health check", func(t *testing.T) {
+		rec := serve(t, h, http.MethodGet, "/", "")
+		assert.EqualT(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should list metrics", func(t *testing.T) {
+		rec := serve(t, h, http.MethodPost, "/metrics", "{}")
+		require.EqualT(t, http.StatusOK, rec.Code)
+
+		var metrics []Metric
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &metrics))
+		assert.Equal(t, []Metric{
+			{Label: "Sort: sort/generics (nsPerOp)", Value: "Sort: sort/generics (nsPerOp)"},
+			{Label: "Sort: sort/reflect (nsPerOp)", Value: "Sort: sort/reflect (nsPerOp)"},
+		}, metrics)
+	})
+
+	t.Run("should query time series over a range", func(t *testing.T) {
+		rec := serve(t, h, http.MethodPost, "/query", `{
+  "range": {"from": "2026-01-01T12:00:00Z", "to": "2026-01-31T00:00:00Z"},
+  "targets": [{"refId": "A", "target": "Sort: sort/reflect (nsPerOp)"}, {"refId": "B", "target": ""}]
+}`)
+		require.EqualT(t, http.StatusOK, rec.Code)
+
+		var series []TimeSeries
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &series))
+		require.Len(t, series, 1)
+		assert.EqualT(t, "Sort: sort/reflect (nsPerOp)", series[0].Target)
+		require.Len(t, series[0].DataPoints, 2)
+		assert.InDeltaT(t, 90, series[0].DataPoints[0][0], 1e-9)
+		assert.InDeltaT(t, float64(day(2).UnixMilli()), series[0].DataPoints[0][1], 1e-9)
+		assert.InDeltaT(t, 80, series[0].DataPoints[1][0], 1e-9)
+	})
+
+	t.Run("should reject invalid queries", func(t *testing.T) {
+		rec := serve(t, h, http.MethodPost, "/query", "{")
+		assert.EqualT(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should serve the history as a table", func(t *testing.T) {
+		rec := serve(t, h, http.MethodGet, "/history", "")
+		require.EqualT(t, http.StatusOK, rec.Code)
+
+		var rows []Row
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rows))
+		require.Len(t, rows, 5)
+		assert.EqualT(t, "v1", rows[0].Snapshot)
+		assert.EqualT(t, "sort", rows[0].Function)
+		assert.EqualT(t, config.MetricNsPerOp, rows[0].Metric)
+	})
+}
+
+func TestHandlerErrors(t *testing.T) {
+	h := New(func(context.Context) ([]baseline.Snapshot, error) {
+		return nil, errors.New("no history")
+	}, nil)
+
+	rec := serve(t, h, http.MethodPost, "/metrics", "{}")
+	assert.EqualT(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "no history")
+}
+
+func serve(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	return rec
+}
+
+func day(d int) time.Time {
+	return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+// testHistory returns snapshots out of order: the handler sorts them by creation time.
+func testHistory() []baseline.Snapshot {
+	result := func(version string, value float64) baseline.Result {
+		return baseline.Result{Function: "sort", Version: version, Metric: config.MetricNsPerOp, Value: value}
+	}
+
+	return []baseline.Snapshot{
+		{Name: "v3", Scenario: "Sort", Created: day(3), Results: []baseline.Result{result("reflect", 80), result("generics", 8)}},
+		{Name: "v1", Scenario: "Sort", Created: day(1), Results: []baseline.Result{result("reflect", 100)}},
+		{Name: "v2", Scenario: "Sort", Created: day(2), Results: []baseline.Result{result("reflect", 90), result("generics", 9)}},
+	}
+}
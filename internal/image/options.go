@@ -1,6 +1,9 @@
 package image //nolint:revive // it's okay for an internal package to use this name
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
 
 // Option to tune image rendering.
 type Option func(*options)
@@ -9,12 +12,18 @@ type options struct {
 	Height        int64
 	Width         int64
 	SleepDuration time.Duration
+	Retries       int
+	Backoff       time.Duration
+	Debug         bool
+	AutoFit       bool
+	logger        *slog.Logger
 }
 
 const (
-	defaultHeight int64 = 1080
-	defaultWidth  int64 = 1920
-	defaultWait         = time.Second
+	defaultHeight  int64 = 1080
+	defaultWidth   int64 = 1920
+	defaultWait          = time.Second
+	defaultBackoff       = time.Second
 )
 
 func optionsWithDefaults(opts []Option) options {
@@ -22,12 +31,17 @@ func optionsWithDefaults(opts []Option) options {
 		Height:        defaultHeight,
 		Width:         defaultWidth,
 		SleepDuration: defaultWait,
+		Backoff:       defaultBackoff,
 	}
 
 	for _, apply := range opts {
 		apply(&o)
 	}
 
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+
 	return o
 }
 
@@ -69,3 +83,58 @@ func WithSleep(sleep time.Duration) Option {
 		o.SleepDuration = sleep
 	}
 }
+
+// WithLogger sets the logger used by the [Renderer].
+//
+// Defaults to [slog.Default].
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithRetries sets the number of times a failed screenshot is retried, e.g. when the browser fails to start.
+//
+// Defaults to 0 (no retry).
+func WithRetries(retries int) Option {
+	return func(o *options) {
+		if retries < 0 {
+			return
+		}
+
+		o.Retries = retries
+	}
+}
+
+// WithBackoff sets the time to wait before the first retry of a failed screenshot.
+// The wait doubles before each subsequent retry.
+//
+// Defaults to 1s.
+func WithBackoff(backoff time.Duration) Option {
+	return func(o *options) {
+		if backoff == 0 {
+			return
+		}
+
+		o.Backoff = backoff
+	}
+}
+
+// WithDebug enables the debug logs of the browser protocol.
+//
+// Logs are very verbose: they report all messages exchanged with the browser.
+func WithDebug(enabled bool) Option {
+	return func(o *options) {
+		o.Debug = enabled
+	}
+}
+
+// WithAutoFit sizes the screenshot to the content of the page, instead of the whole page.
+//
+// The page is laid out in a viewport of the configured width. The screenshot then includes all the charts,
+// without cropping them or leaving large empty margins.
+func WithAutoFit(enabled bool) Option {
+	return func(o *options) {
+		o.AutoFit = enabled
+	}
+}
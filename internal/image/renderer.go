@@ -3,9 +3,16 @@ package image
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
 	"github.com/chromedp/chromedp/device"
 )
@@ -13,18 +20,30 @@ import (
 // Renderer knows how to take a screenshot from a HTML input and writes it as PNG.
 type Renderer struct {
 	options
+
+	l *slog.Logger
 }
 
 // New builds an image [Renderer] from HTML.
 func New(opts ...Option) *Renderer {
+	o := optionsWithDefaults(opts)
+
 	return &Renderer{
-		options: optionsWithDefaults(opts),
+		options: o,
+		l:       o.logger.With(slog.String("module", "image")),
 	}
 }
 
 // Render a PNG image as a screenshot from a HTML input [io.Reader].
+//
+// A failed screenshot is retried as configured with [WithRetries], after a backoff delay.
 func (r *Renderer) Render(ctx context.Context, dest io.Writer, source io.Reader) error {
-	screenshot, err := r.screenshot(ctx, source)
+	content, err := io.ReadAll(source)
+	if err != nil {
+		return fmt.Errorf("taking screenshot: read content: %w", err)
+	}
+
+	screenshot, err := r.screenshotWithRetries(ctx, string(content))
 	if err != nil {
 		return fmt.Errorf("taking screenshot: %w", err)
 	}
@@ -34,37 +53,174 @@ func (r *Renderer) Render(ctx context.Context, dest io.Writer, source io.Reader)
 		return fmt.Errorf("writing screenshot: %w", err)
 	}
 
+	r.l.Info("screenshot rendered",
+		slog.Int64("width", r.Width),
+		slog.Int64("height", r.Height),
+		slog.Int("size", len(screenshot)),
+	)
+
 	return nil
 }
 
-func (r *Renderer) screenshot(ctx context.Context, reader io.Reader) ([]byte, error) {
-	ctx, cancel := chromedp.NewContext(ctx)
+func (r *Renderer) screenshotWithRetries(ctx context.Context, content string) ([]byte, error) {
+	backoff := r.Backoff
+
+	for attempt := 1; ; attempt++ {
+		screenshot, err := r.screenshot(ctx, content)
+		if err == nil {
+			return screenshot, nil
+		}
+
+		if attempt > r.Retries || ctx.Err() != nil {
+			if r.Retries > 0 {
+				return nil, fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+
+			return nil, err
+		}
+
+		r.l.Warn("screenshot failed: retrying",
+			slog.Int("attempt", attempt),
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (r *Renderer) screenshot(ctx context.Context, content string) ([]byte, error) {
+	// capture the output of the browser, to report about failures. This is only possible when
+	// the browser is started here, not by an allocator set by the caller.
+	output := &tailWriter{}
+	if c := chromedp.FromContext(ctx); c == nil || c.Allocator == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = chromedp.NewExecAllocator(ctx, append(chromedp.DefaultExecAllocatorOptions[:],
+			chromedp.CombinedOutput(output),
+		)...)
+		defer cancel()
+	}
+
+	var contextOpts []chromedp.ContextOption
+	if r.Debug {
+		contextOpts = append(contextOpts, chromedp.WithDebugf(func(format string, args ...any) {
+			r.l.Info("browser", slog.String("debug", fmt.Sprintf(format, args...)))
+		}))
+	}
+
+	ctx, cancel := chromedp.NewContext(ctx, contextOpts...)
 	defer cancel()
 
 	var screenshot []byte
-	// capture entire browser viewport, returning png with quality=90
-	// localURL := fmt.Sprintf(`file://./%s`, file)
-	content, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("read content: %w", err)
-	}
-	const qualityPNG = 100 // 100 to force PNG
 
-	err = chromedp.Run(ctx,
+	const qualityPNG = 100 // 100 to force PNG
+	err := chromedp.Run(ctx,
 		chromedp.Emulate(device.Info{
 			Height:    r.Height,
 			Width:     r.Width,
 			Landscape: true,
 		}),
-		chromedp.Navigate("data:text/html,"+string(content)),
-		// chromedp.WaitVisible(`canvas`, chromedp.ByQueryAll),
-		// chromedp.WaitReady(`script  _, opts ...chromedp.QueryOption),
+		// the content is encoded, since characters such as "#" would otherwise end the document
+		chromedp.Navigate("data:text/html;base64,"+base64.StdEncoding.EncodeToString([]byte(content))),
 		chromedp.Sleep(r.SleepDuration), // we need to wait some time to get the rendering done
-		chromedp.FullScreenshot(&screenshot, qualityPNG),
+		r.capture(&screenshot, qualityPNG),
 	)
 	if err != nil {
+		if stderr := output.String(); stderr != "" {
+			return nil, fmt.Errorf("%w (browser output: %s)", err, stderr)
+		}
+
 		return nil, err
 	}
 
 	return screenshot, nil
 }
+
+// contentExtent is a script measuring the extent of the content of a page: the right edge of its charts
+// and the bottom edge of all its elements. Elements with a fixed position (e.g. a "back to top" link)
+// move with the viewport, and are ignored.
+const contentExtent = `(() => {
+  let right = 0, bottom = 0;
+  for (const el of document.body.querySelectorAll('*')) {
+    const box = el.getBoundingClientRect();
+    if (box.width === 0 || box.height === 0 || getComputedStyle(el).position === 'fixed') {
+      continue;
+    }
+    bottom = Math.max(bottom, box.bottom + window.scrollY);
+    if (el.tagName === 'CANVAS' || el.tagName === 'svg') {
+      right = Math.max(right, box.right + window.scrollX);
+    }
+  }
+  return [Math.ceil(right), Math.ceil(bottom)];
+})()`
+
+// fitMargin is the margin (in px) around the content of a page, in auto-fit mode.
+const fitMargin = 16
+
+// capture takes a screenshot of the whole page or, in auto-fit mode, of its content only.
+func (r *Renderer) capture(screenshot *[]byte, quality int) chromedp.Action {
+	if !r.AutoFit {
+		return chromedp.FullScreenshot(screenshot, quality)
+	}
+
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var extent [2]float64
+		if err := chromedp.Evaluate(contentExtent, &extent).Do(ctx); err != nil {
+			return fmt.Errorf("measuring content: %w", err)
+		}
+
+		if extent[0] == 0 || extent[1] == 0 {
+			// nothing to fit, e.g. an empty page
+			return chromedp.FullScreenshot(screenshot, quality).Do(ctx)
+		}
+
+		width := min(extent[0]+fitMargin, float64(r.Width))
+		height := extent[1] + fitMargin
+		r.l.Debug("screenshot fit to content", slog.Float64("width", width), slog.Float64("height", height))
+
+		var err error
+		*screenshot, err = page.CaptureScreenshot().
+			WithCaptureBeyondViewport(true).
+			WithFromSurface(true).
+			WithFormat(page.CaptureScreenshotFormatPng).
+			WithClip(&page.Viewport{Width: width, Height: height, Scale: 1}).
+			Do(ctx)
+
+		return err
+	})
+}
+
+// maxOutput is the maximum size of the browser output reported in errors.
+const maxOutput = 4096
+
+// tailWriter retains the last bytes written to it, at most maxOutput.
+//
+// It is safe for concurrent use.
+type tailWriter struct {
+	mx  sync.Mutex
+	buf []byte
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > maxOutput {
+		w.buf = w.buf[len(w.buf)-maxOutput:]
+	}
+
+	return len(p), nil
+}
+
+func (w *tailWriter) String() string {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	return strings.TrimSpace(string(w.buf))
+}
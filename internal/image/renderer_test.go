@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"image/png"
 	"os"
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/chromedp/chromedp"
 
@@ -79,6 +81,66 @@ func TestRenderEmptyHTML(t *testing.T) {
 		"expected valid PNG output even for empty HTML")
 }
 
+func TestRenderAutoFit(t *testing.T) {
+	skipIfNoBrowser(t)
+
+	r := New(WithAutoFit(true), WithSleep(100*time.Millisecond))
+	html := `<!DOCTYPE html><html><body><canvas width="300" height="200"></canvas></body></html>`
+	dest := &bytes.Buffer{}
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+	require.NoError(t, r.Render(ctx, dest, strings.NewReader(html)))
+
+	cfg, err := png.DecodeConfig(dest)
+	require.NoError(t, err)
+	assert.Greater(t, cfg.Width, 300)
+	assert.Less(t, cfg.Width, 400)
+	assert.Greater(t, cfg.Height, 200)
+	assert.Less(t, cfg.Height, 300)
+}
+
+func TestRenderHashInContent(t *testing.T) {
+	skipIfNoBrowser(t)
+
+	// a "#" must not truncate the page, e.g. in a color
+	r := New(WithAutoFit(true), WithSleep(100*time.Millisecond))
+	html := `<!DOCTYPE html><html><body style="color:#555"><a href="#chart">chart</a><canvas width="300" height="200"></canvas></body></html>`
+	dest := &bytes.Buffer{}
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+	require.NoError(t, r.Render(ctx, dest, strings.NewReader(html)))
+
+	cfg, err := png.DecodeConfig(dest)
+	require.NoError(t, err)
+	assert.Greater(t, cfg.Width, 300)
+	assert.Less(t, cfg.Width, 400)
+}
+
+func TestRenderRetries(t *testing.T) {
+	r := New(WithRetries(2), WithBackoff(time.Millisecond))
+
+	ctx, cancel := chromedp.NewExecAllocator(t.Context(), chromedp.ExecPath("/nonexistent/chrome"))
+	defer cancel()
+
+	err := r.Render(ctx, &bytes.Buffer{}, strings.NewReader("<html></html>"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 3 attempts")
+}
+
+func TestTailWriter(t *testing.T) {
+	w := &tailWriter{}
+	_, err := w.Write([]byte(strings.Repeat("a", maxOutput)))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("chrome failed\n"))
+	require.NoError(t, err)
+
+	output := w.String()
+	assert.Len(t, output, maxOutput-1)
+	assert.True(t, strings.HasSuffix(output, "chrome failed"))
+}
+
 // helpers
 
 type failingReader struct {
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// unknownEnvironment is the environment reported by the parser for benchmarks run without any environment information.
+const unknownEnvironment = "unknown environment"
+
+// knownGOOS and knownGOARCH list the values of GOOS and GOARCH supported by the Go toolchain.
+var (
+	knownGOOS = []string{
+		"aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js", "linux",
+		"nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos",
+	}
+	knownGOARCH = []string{
+		"386", "amd64", "amd64p32", "arm", "arm64", "loong64", "mips", "mips64", "mips64le", "mipsle",
+		"ppc64", "ppc64le", "riscv64", "s390x", "wasm",
+	}
+)
+
+// Fingerprint identifies the environment in which benchmarks were run.
+//
+// It is parsed from an environment string, such as "go1.23.4 linux amd64 cpu: Intel(R) Core(TM) i7-9750H CPU @ 2.60GHz".
+// Parts that are not recognized (e.g. an environment set by configuration) are kept in Other.
+type Fingerprint struct {
+	GoVersion string
+	GOOS      string
+	GOARCH    string
+	CPU       string
+	Other     string
+}
+
+// ParseFingerprint parses an environment string into a [Fingerprint].
+//
+// An empty or unknown environment yields a zero [Fingerprint].
+func ParseFingerprint(env string) Fingerprint {
+	var fingerprint Fingerprint
+
+	env = strings.TrimSpace(env)
+	if env == "" || env == unknownEnvironment {
+		return fingerprint
+	}
+
+	head, cpu, _ := strings.Cut(env, "cpu: ")
+	fingerprint.CPU = strings.TrimSpace(cpu)
+
+	var other []string
+	for part := range strings.FieldsSeq(head) {
+		switch {
+		case fingerprint.GoVersion == "" && isGoVersion(part):
+			fingerprint.GoVersion = part
+		case fingerprint.GOOS == "" && slices.Contains(knownGOOS, part):
+			fingerprint.GOOS = part
+		case fingerprint.GOARCH == "" && slices.Contains(knownGOARCH, part):
+			fingerprint.GOARCH = part
+		default:
+			other = append(other, part)
+		}
+	}
+	fingerprint.Other = strings.Join(other, " ")
+
+	return fingerprint
+}
+
+// Diff lists the differences between two fingerprints that make their results incompatible,
+// e.g. "goarch: amd64 != arm64".
+//
+// The target platform (GOOS and GOARCH), the CPU and any unrecognized part are compared.
+// Parts unknown to either fingerprint are not compared. The version of Go is not compared:
+// comparing versions of Go is a legitimate use case (see [config.Config.CompareGoVersions]).
+func (f Fingerprint) Diff(other Fingerprint) []string {
+	var differences []string
+
+	compare := func(name, left, right string) {
+		if left != "" && right != "" && left != right {
+			differences = append(differences, name+": "+left+" != "+right)
+		}
+	}
+
+	compare("goos", f.GOOS, other.GOOS)
+	compare("goarch", f.GOARCH, other.GOARCH)
+	compare("cpu", f.CPU, other.CPU)
+	compare("environment", f.Other, other.Other)
+
+	return differences
+}
+
+// EnvironmentDiff lists the differences between the first environment and each other one,
+// as found by [Fingerprint.Diff]. Duplicate differences are reported once.
+//
+// It returns nil when all environments are compatible.
+func EnvironmentDiff(environments []string) []string {
+	if len(environments) < 2 { //nolint:mnd // nothing to compare
+		return nil
+	}
+
+	var differences []string
+	reference := ParseFingerprint(environments[0])
+	for _, env := range environments[1:] {
+		for _, difference := range reference.Diff(ParseFingerprint(env)) {
+			if !slices.Contains(differences, difference) {
+				differences = append(differences, difference)
+			}
+		}
+	}
+
+	return differences
+}
+
+// Environments returns the distinct environments of the categories of the scenario, in order of appearance.
+//
+// Categories grouping several environments as series list them all.
+func (s Scenario) Environments() []string {
+	var environments []string
+
+	for _, category := range s.Categories {
+		for env := range strings.SplitSeq(category.Environment, "; ") {
+			if env != "" && !slices.Contains(environments, env) {
+				environments = append(environments, env)
+			}
+		}
+	}
+
+	return environments
+}
+
+func isGoVersion(part string) bool {
+	version, ok := strings.CutPrefix(part, "go")
+	if !ok || version == "" {
+		return false
+	}
+
+	return strings.HasPrefix(version, "devel") || (version[0] >= '0' && version[0] <= '9')
+}
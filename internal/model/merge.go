@@ -0,0 +1,101 @@
+package model
+
+import (
+	"slices"
+	"strings"
+)
+
+// Merge several scenarios into a single one, e.g. scenarios produced by CI jobs on different platforms.
+//
+// Each scenario is identified by a label (e.g. its environment), preserved as a dimension of the merged scenario:
+// categories with the same ID are merged into a single chart, with one series per label,
+// titled like "{series} ({label})". Categories found in a single scenario are kept as is.
+//
+// Merged categories retain the settings of their first occurrence (e.g. their metric layout), and the complexity
+// fits of all occurrences.
+//
+// The metadata of all scenarios is merged: on conflicting keys, the first scenario wins.
+//
+// Labels must have the same length as scenarios.
+func Merge(name string, labels []string, scenarios []*Scenario) *Scenario {
+	occurrences := make(map[string]int)
+	for _, scenario := range scenarios {
+		for _, category := range scenario.Categories {
+			occurrences[category.ID]++
+		}
+	}
+
+	var (
+		ids          []string
+		byID         = make(map[string]*Category)
+		environments = make(map[string][]string)
+	)
+
+	for i, scenario := range scenarios {
+		for _, category := range scenario.Categories {
+			target, ok := byID[category.ID]
+			if !ok {
+				ids = append(ids, category.ID)
+				target = &Category{
+					ID:           category.ID,
+					Title:        category.Title,
+					Project:      category.Project,
+					Pivot:        category.Pivot,
+					XAxis:        category.XAxis,
+					AxisLabels:   category.AxisLabels,
+					Difference:   category.Difference,
+					Annotations:  category.Annotations,
+					MetricLayout: category.MetricLayout,
+				}
+				byID[category.ID] = target
+			}
+
+			target.RunDuration += category.RunDuration
+			if env := category.Environment; env != "" && !slices.Contains(environments[category.ID], env) {
+				environments[category.ID] = append(environments[category.ID], env)
+			}
+
+			for _, data := range category.Data {
+				if occurrences[category.ID] > 1 {
+					data.Series = slices.Clone(data.Series)
+					for si := range data.Series {
+						data.Series[si].Title += " (" + labels[i] + ")"
+					}
+				}
+				target.Data = append(target.Data, data)
+			}
+
+			for _, fit := range category.Fits {
+				if occurrences[category.ID] > 1 {
+					fit.Series += " (" + labels[i] + ")"
+				}
+				target.Fits = append(target.Fits, fit)
+			}
+		}
+	}
+
+	merged := &Scenario{
+		Name:       name,
+		Categories: make([]Category, 0, len(ids)),
+	}
+
+	for _, scenario := range scenarios {
+		for key, value := range scenario.Meta {
+			if _, ok := merged.Meta[key]; ok {
+				continue
+			}
+			if merged.Meta == nil {
+				merged.Meta = make(map[string]string)
+			}
+			merged.Meta[key] = value
+		}
+	}
+
+	for _, id := range ids {
+		category := byID[id]
+		category.Environment = strings.Join(environments[id], "; ")
+		merged.Categories = append(merged.Categories, *category)
+	}
+
+	return merged
+}
@@ -1,17 +1,24 @@
 package model
 
 import (
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/fredbi/benchviz/internal/complexity"
 	"github.com/fredbi/benchviz/internal/config"
 )
 
 // Scenario defines a complete configuration for benchmark visualization on a single page.
 //
 // A [Scenario] exposes several categories, each to be rendered in a separate chart on the page.
+//
+// Meta holds arbitrary key/value metadata about the run (e.g. a feature flag), set with -meta.
 type Scenario struct {
 	Name       string
 	Categories []Category
+	Meta       map[string]string `json:",omitempty"`
 }
 
 // Category defines all the series for one or two metrics, regrouped on a single chart.
@@ -21,11 +28,34 @@ type Scenario struct {
 // Each point of a series corresponds to several contexts for a given function.
 //
 // Notice that dual metric visualization implies a double scale.
+// MetricLayout tells whether the metrics of the category are charted separately, or on a single chart.
+//
+// RunDuration is the total duration of the benchmark runs, when known.
+//
+// Project is the name of the project of the category, on a dashboard combining several projects.
+//
+// When Pivot is [config.PivotContexts], contexts are represented as series and versions on the X axis.
+//
+// When XAxis is numeric, contexts are positioned on the X axis at the value they declare,
+// and Fits may hold the complexity estimated for each function of each series.
+//
+// When Difference is enabled, the category is charted as the relative change between two versions.
+//
+// Annotations document the charts of the category.
 type Category struct {
-	ID          string
-	Title       string
-	Environment string
-	Data        []CategoryData
+	ID           string
+	Title        string
+	Environment  string
+	Project      string `json:",omitempty"`
+	RunDuration  time.Duration
+	Pivot        config.Pivot
+	XAxis        config.XAxis
+	AxisLabels   config.Labels
+	Difference   config.Difference
+	Annotations  []config.Annotation
+	MetricLayout config.MetricLayout
+	Data         []CategoryData
+	Fits         []Fit
 }
 
 // Metrics returns the deduplicated list of metrics present in the category data.
@@ -52,12 +82,13 @@ func (c Category) Labels() (xlabels []string) {
 	for _, data := range c.Data {
 		for _, series := range data.Series {
 			for _, point := range series.Points {
-				_, seen := labelsIdx[SeriesKey{Function: point.Function, Context: point.Context}]
+				key := c.XKey(point)
+				_, seen := labelsIdx[key]
 				if seen {
 					continue
 				}
 				xlabels = append(xlabels, point.Label)
-				labelsIdx[SeriesKey{Function: point.Function, Context: point.Context}] = struct{}{}
+				labelsIdx[key] = struct{}{}
 			}
 		}
 	}
@@ -65,18 +96,107 @@ func (c Category) Labels() (xlabels []string) {
 	return xlabels
 }
 
+// Group is a run of contiguous positions on the X axis, charted under the title of a function group.
+//
+// ID identifies the function group, since several groups may share a title.
+// From and To are the indices of the first and last positions of the group, as returned by [Category.Labels].
+type Group struct {
+	ID       string
+	Title    string
+	From, To int
+}
+
+// Groups returns the function groups of the category, in order of the positions on the X axis.
+//
+// Positions without a group are not part of any [Group]. It returns nil when no point belongs to a group.
+func (c Category) Groups() (groups []Group) {
+	positions := make(map[SeriesKey]int)
+
+	for _, data := range c.Data {
+		for _, series := range data.Series {
+			for _, point := range series.Points {
+				key := c.XKey(point)
+				if _, seen := positions[key]; seen {
+					continue
+				}
+
+				position := len(positions)
+				positions[key] = position
+				if point.GroupID == "" {
+					continue
+				}
+
+				if last := len(groups) - 1; last >= 0 && groups[last].ID == point.GroupID && groups[last].To == position-1 {
+					groups[last].To = position
+
+					continue
+				}
+
+				groups = append(groups, Group{ID: point.GroupID, Title: point.Group, From: position, To: position})
+			}
+		}
+	}
+
+	return groups
+}
+
+// XKey identifies the position of a point on the X axis.
+func (c Category) XKey(point MetricPoint) SeriesKey {
+	if c.Pivot == config.PivotContexts {
+		return SeriesKey{Function: point.Function, Version: point.Version}
+	}
+
+	return SeriesKey{Function: point.Function, Context: point.Context}
+}
+
+// FitFor returns the complexity estimated for a function in a series, if any.
+func (c Category) FitFor(function, series string, metric config.MetricName) (Fit, bool) {
+	for _, fit := range c.Fits {
+		if fit.Function == function && fit.Series == series && fit.Metric == metric {
+			return fit, true
+		}
+	}
+
+	return Fit{}, false
+}
+
 // TitleWithPlaceHolders replaces the "{metric}" placeholder in the title of the chart.
 func (c Category) TitleWithPlaceHolders(metric config.Metric) string {
 	return strings.ReplaceAll(c.Title, "{metric}", metric.Title)
 }
 
+// TitleWithMetrics replaces the "{metric}" placeholder in the title of a chart of several metrics,
+// with the titles of all the metrics, e.g. "Benchmark Timings / Benchmark Allocations".
+func (c Category) TitleWithMetrics(metrics []config.Metric) string {
+	titles := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		titles = append(titles, metric.Title)
+	}
+
+	return strings.ReplaceAll(c.Title, "{metric}", strings.Join(titles, " / "))
+}
+
+// Fit is the complexity curve best fitting the points of a function in a series (identified by its title),
+// against the numeric values of contexts.
+type Fit struct {
+	complexity.Result
+
+	Function string
+	Series   string
+	Metric   config.MetricName
+}
+
 // CategoryData holds the data series for one metric and one version.
 //
 // Each series represented by a [CategoryData] is represented as one single data series on the chart.
 //
 // Each point of the data series corresponds to a context for the measurement.
+//
+// When the category is pivoted, the data series is for one metric and one context, and each point
+// corresponds to a version.
 type CategoryData struct {
 	Version config.Version
+	Context config.Context
 	Metric  config.Metric
 	Series  []MetricSeries
 }
@@ -119,7 +239,78 @@ func (s MetricSeries) Labels() []string {
 type MetricPoint struct {
 	SeriesKey
 
-	Name  string
-	Label string // x-axis label: context title (optionally prefixed by function title)
-	Value float64
+	Name      string
+	Label     string // x-axis label: context title (optionally prefixed by function title)
+	Group     string // title of the function group, if any (see [config.FunctionGroup])
+	GroupID   string // ID of the function group, if any
+	Value     float64
+	Aggregate Aggregate // statistics over duplicate benchmarks, when aggregated
+	Origin    Origin    // source of the value: input files, raw benchmark name and sample count
+}
+
+// Origin traces a [MetricPoint] back to the benchmark results it is computed from.
+//
+// Samples is the number of benchmark results behind the point: more than one when duplicates are aggregated.
+type Origin struct {
+	Files     []string
+	Benchmark string // raw benchmark name, as found in the input (e.g. "BenchmarkSort/small-8")
+	Samples   int
+}
+
+// IsZero reports whether the origin is unknown, e.g. for points of a scenario merged from an older export.
+func (o Origin) IsZero() bool {
+	return len(o.Files) == 0 && o.Benchmark == "" && o.Samples == 0
+}
+
+// Merge the origin of a duplicate benchmark result: files are added once, and samples are summed.
+//
+// The raw benchmark name of the first result is retained.
+func (o Origin) Merge(other Origin) Origin {
+	merged := Origin{
+		Files:     slices.Clone(o.Files),
+		Benchmark: o.Benchmark,
+		Samples:   o.Samples + other.Samples,
+	}
+	if merged.Benchmark == "" {
+		merged.Benchmark = other.Benchmark
+	}
+
+	for _, file := range other.Files {
+		if !slices.Contains(merged.Files, file) {
+			merged.Files = append(merged.Files, file)
+		}
+	}
+
+	return merged
+}
+
+// String renders the origin, e.g. "BenchmarkSort/small-8 (bench.txt, 3 samples)".
+func (o Origin) String() string {
+	if o.IsZero() {
+		return ""
+	}
+
+	details := slices.Clone(o.Files)
+	if o.Samples > 1 {
+		details = append(details, strconv.Itoa(o.Samples)+" samples")
+	}
+
+	if len(details) == 0 {
+		return o.Benchmark
+	}
+
+	return strings.TrimSpace(o.Benchmark + " (" + strings.Join(details, ", ") + ")")
+}
+
+// Aggregate holds the statistics over duplicate benchmarks aggregated into a single [MetricPoint].
+//
+// Mean is the raw mean of the values, whereas WeightedMean is weighted by the number of iterations
+// of each benchmark, so that short noisy runs don't dominate the mean.
+//
+// Samples is zero when the point is not aggregated.
+type Aggregate struct {
+	Samples      int
+	Iterations   int
+	Mean         float64
+	WeightedMean float64
 }
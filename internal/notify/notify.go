@@ -0,0 +1,130 @@
+// Package notify posts notifications about benchmark regressions to a webhook (e.g. Slack).
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+)
+
+// Funcs are the functions available to payload templates.
+var Funcs = template.FuncMap{
+	"json": func(value any) (string, error) {
+		buf, err := json.Marshal(value)
+
+		return string(buf), err
+	},
+}
+
+var defaultTemplate = template.Must(template.New("slack").Funcs(Funcs).Parse(`{"text": {{ json .Text }}}`))
+
+// ParseTemplate parses a payload template, with the functions of [Funcs] available.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(Funcs).Parse(text)
+}
+
+// Payload is the data available to the template that renders a notification.
+//
+// Text is a human-readable summary of all regressions.
+type Payload struct {
+	Baseline    string       `json:"baseline"`
+	Threshold   float64      `json:"threshold"`
+	Regressions []Regression `json:"regressions"`
+	Text        string       `json:"text"`
+}
+
+// Regression describes an offending benchmark.
+type Regression struct {
+	Benchmark string  `json:"benchmark"`
+	Baseline  float64 `json:"baseline"`
+	Current   float64 `json:"current"`
+	Change    string  `json:"change"`
+}
+
+// Notifier posts notifications about regressions to a webhook URL.
+type Notifier struct {
+	options
+
+	url string
+	l   *slog.Logger
+}
+
+// New builds a [Notifier] that posts to a webhook URL.
+func New(url string, opts ...Option) *Notifier {
+	o := optionsWithDefaults(opts)
+
+	return &Notifier{
+		options: o,
+		url:     url,
+		l:       o.logger.With(slog.String("module", "notify")),
+	}
+}
+
+// NewPayload builds the [Payload] of a notification about the regressions found by a comparison against a baseline.
+func NewPayload(comparison baseline.Comparison) Payload {
+	p := Payload{
+		Baseline:  comparison.Baseline,
+		Threshold: comparison.Threshold,
+	}
+
+	var text strings.Builder
+	regressions := comparison.Regressions()
+	fmt.Fprintf(&text, "%d benchmark regression(s) found against baseline %s:", len(regressions), comparison.Baseline)
+
+	for _, d := range regressions {
+		r := Regression{
+			Benchmark: d.Result.String(),
+			Baseline:  d.Baseline,
+			Current:   d.Value,
+			Change:    baseline.FormatChange(d.Change),
+		}
+		p.Regressions = append(p.Regressions, r)
+		fmt.Fprintf(&text, "\n• %s: %s (%.4g -> %.4g)", r.Benchmark, r.Change, r.Baseline, r.Current)
+	}
+	p.Text = text.String()
+
+	return p
+}
+
+// Notify posts a notification about the regressions found by a comparison against a baseline.
+//
+// Nothing is posted when no regression is found.
+func (n *Notifier) Notify(ctx context.Context, comparison baseline.Comparison) error {
+	if len(comparison.Regressions()) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	if err := n.template.Execute(&body, NewPayload(comparison)); err != nil {
+		return fmt.Errorf("rendering notification payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, &body)
+	if err != nil {
+		return fmt.Errorf("preparing notification: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting notification: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("posting notification: unexpected status %s", resp.Status)
+	}
+
+	n.l.Info("notification posted", slog.String("baseline", comparison.Baseline), slog.Int("regressions", len(comparison.Regressions())))
+
+	return nil
+}
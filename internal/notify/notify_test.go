@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestNotify(t *testing.T) {
+	var (
+		received    []byte
+		contentType string
+		calls       int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		contentType = r.Header.Get("Content-Type")
+		received, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	t.Run("should post a Slack-compatible payload", func(t *testing.T) {
+		require.NoError(t, New(server.URL).Notify(context.Background(), testComparison(true)))
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "application/json", contentType)
+
+		var payload map[string]string
+		require.NoError(t, json.Unmarshal(received, &payload))
+		assert.Equal(t,
+			"1 benchmark regression(s) found against baseline main:\n• greater/reflect/int (nsPerOp): +50.0% (100 -> 150)",
+			payload["text"],
+		)
+	})
+
+	t.Run("should post a custom payload", func(t *testing.T) {
+		tpl, err := ParseTemplate("custom", `{"baseline": {{ json .Baseline }}, "regressions": {{ json .Regressions }}}`)
+		require.NoError(t, err)
+
+		require.NoError(t, New(server.URL, WithTemplate(tpl)).Notify(context.Background(), testComparison(true)))
+
+		var payload Payload
+		require.NoError(t, json.Unmarshal(received, &payload))
+		assert.Equal(t, "main", payload.Baseline)
+		assert.Equal(t, []Regression{
+			{Benchmark: "greater/reflect/int (nsPerOp)", Baseline: 100, Current: 150, Change: "+50.0%"},
+		}, payload.Regressions)
+	})
+
+	t.Run("should not post without regression", func(t *testing.T) {
+		calls = 0
+		require.NoError(t, New(server.URL).Notify(context.Background(), testComparison(false)))
+		assert.Zero(t, calls)
+	})
+}
+
+func TestNotifyErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	t.Run("should fail on unexpected status", func(t *testing.T) {
+		err := New(server.URL).Notify(context.Background(), testComparison(true))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "403")
+	})
+
+	t.Run("should fail on invalid template", func(t *testing.T) {
+		tpl, err := ParseTemplate("invalid", `{{ .Unknown }}`)
+		require.NoError(t, err)
+
+		require.Error(t, New(server.URL, WithTemplate(tpl)).Notify(context.Background(), testComparison(true)))
+	})
+}
+
+func testComparison(regression bool) baseline.Comparison {
+	return baseline.Comparison{
+		Baseline:  "main",
+		Threshold: 0.05,
+		Deltas: []baseline.Delta{
+			{
+				Result:     baseline.Result{Function: "greater", Version: "reflect", Context: "int", Metric: config.MetricNsPerOp, Value: 150},
+				Baseline:   100,
+				Change:     0.5,
+				Regression: regression,
+			},
+		},
+	}
+}
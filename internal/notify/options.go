@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"log/slog"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+// Option to tune webhook notifications.
+type Option func(*options)
+
+type options struct {
+	client   *http.Client
+	template *template.Template
+	logger   *slog.Logger
+}
+
+const defaultTimeout = 10 * time.Second
+
+func optionsWithDefaults(opts []Option) options {
+	var o options
+
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	if o.client == nil {
+		o.client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	if o.template == nil {
+		o.template = defaultTemplate
+	}
+
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+
+	return o
+}
+
+// WithHTTPClient sets the HTTP client used to post notifications.
+//
+// Defaults to a client with a 10s timeout.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithTemplate sets the template that renders the JSON payload of notifications, from a [Payload].
+//
+// The template may use the "json" function to render a value as JSON.
+// Defaults to a Slack-compatible payload, i.e. {"text": "..."}.
+func WithTemplate(tpl *template.Template) Option {
+	return func(o *options) {
+		o.template = tpl
+	}
+}
+
+// WithLogger sets the logger used by the [Notifier].
+//
+// Defaults to [slog.Default].
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
@@ -0,0 +1,108 @@
+package organizer
+
+import (
+	"log/slog"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// duplicateKey identifies duplicate benchmarks: benchmarks of the same series, measured in the same environment.
+//
+// The input file is part of the key only for conflicting duplicates, which are not aggregated across files.
+type duplicateKey struct {
+	model.SeriesKey
+
+	Environment string
+	File        string
+}
+
+func (b ParsedBenchmark) duplicateKey() duplicateKey {
+	return duplicateKey{SeriesKey: b.SeriesKey, Environment: b.Environment}
+}
+
+// aggregateBenchmarks merges duplicate benchmarks (i.e. with the same [model.SeriesKey], in the same environment)
+// into a single benchmark, when an aggregation is configured.
+//
+// Duplicates found conflicting across input files (see [Organizer.checkConflicts]) are aggregated per file.
+//
+// Both the raw mean and the mean weighted by the number of iterations are exposed by the aggregated benchmark.
+// The value to plot is the one selected by the configured [config.Aggregation].
+//
+// Aggregated benchmarks retain the position of the first duplicate, and merge the origins of all duplicates.
+func (v *Organizer) aggregateBenchmarks(benchmarks []ParsedBenchmark, conflicts map[duplicateKey]struct{}) []ParsedBenchmark {
+	if v.cfg.Aggregation == "" || v.cfg.Aggregation == config.AggregationNone {
+		return benchmarks
+	}
+
+	positions := make(map[duplicateKey]int, len(benchmarks))
+	aggregated := make([]ParsedBenchmark, 0, len(benchmarks))
+	sums := make([]aggregateSum, 0, len(benchmarks))
+
+	for _, bench := range benchmarks {
+		key := bench.duplicateKey()
+		if _, conflicting := conflicts[key]; conflicting {
+			key.File = bench.File
+		}
+
+		pos, ok := positions[key]
+		if !ok {
+			pos = len(aggregated)
+			positions[key] = pos
+			aggregated = append(aggregated, bench)
+			sums = append(sums, aggregateSum{})
+		} else {
+			aggregated[pos].Origin = aggregated[pos].Origin.Merge(bench.Origin)
+		}
+
+		sums[pos].add(bench.Value, bench.Iterations)
+	}
+
+	weighted := v.cfg.Aggregation == config.AggregationWeighted
+	for i := range aggregated {
+		bench := &aggregated[i]
+		bench.Aggregate = sums[i].aggregate()
+		bench.Iterations = bench.Aggregate.Iterations
+		bench.Value = bench.Aggregate.Mean
+		if weighted {
+			bench.Value = bench.Aggregate.WeightedMean
+		}
+	}
+
+	v.l.Info("aggregated duplicate benchmarks",
+		slog.String("aggregation", string(v.cfg.Aggregation)),
+		slog.Int("benchmarks", len(benchmarks)),
+		slog.Int("aggregated", len(aggregated)),
+	)
+
+	return aggregated
+}
+
+type aggregateSum struct {
+	samples     int
+	iterations  int
+	sum         float64
+	weightedSum float64
+}
+
+func (s *aggregateSum) add(value float64, iterations int) {
+	s.samples++
+	s.iterations += iterations
+	s.sum += value
+	s.weightedSum += value * float64(iterations)
+}
+
+func (s aggregateSum) aggregate() model.Aggregate {
+	mean := s.sum / float64(s.samples)
+	weightedMean := mean // falls back to the raw mean when iterations are unknown
+	if s.iterations > 0 {
+		weightedMean = s.weightedSum / float64(s.iterations)
+	}
+
+	return model.Aggregate{
+		Samples:      s.samples,
+		Iterations:   s.iterations,
+		Mean:         mean,
+		WeightedMean: weightedMean,
+	}
+}
@@ -0,0 +1,120 @@
+package organizer
+
+import (
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestAggregateBenchmarks(t *testing.T) {
+	set := parser.Set{
+		Set: parse.Set{
+			"BenchmarkGreater/reflect/int-16": []*parse.Benchmark{
+				{Name: "BenchmarkGreater/reflect/int-16", N: 1000, NsPerOp: 400, Measured: parse.NsPerOp},
+				{Name: "BenchmarkGreater/reflect/int-16", N: 3000, NsPerOp: 200, Measured: parse.NsPerOp},
+			},
+			"BenchmarkGreater/generic/int-16": []*parse.Benchmark{
+				{Name: "BenchmarkGreater/generic/int-16", N: 100000, NsPerOp: 10, Measured: parse.NsPerOp},
+			},
+		},
+		File: "test.txt",
+	}
+
+	for _, tt := range []struct {
+		aggregation string
+		points      int
+		value       float64
+	}{
+		{aggregation: "", points: 2, value: 400},
+		{aggregation: "none", points: 2, value: 400},
+		{aggregation: "mean", points: 1, value: 300},
+		{aggregation: "weighted", points: 1, value: 250},
+	} {
+		t.Run(tt.aggregation, func(t *testing.T) {
+			yamlContent := genericsConfig()
+			if tt.aggregation != "" {
+				yamlContent += "aggregation: " + tt.aggregation + "\n"
+			}
+			cfg := mustLoadConfig(t, yamlContent)
+
+			benchSet, err := New(cfg).parseBenchmarks([]parser.Set{set})
+			require.NoError(t, err)
+
+			series := benchSet.SeriesFor(config.MetricNsPerOp, "reflect", cfg.Categories[0])
+			require.Len(t, series, 1)
+			require.Len(t, series[0].Points, tt.points)
+
+			point := series[0].Points[0]
+			assert.InDelta(t, tt.value, point.Value, 1e-9)
+			assert.EqualT(t, "BenchmarkGreater/reflect/int-16", point.Origin.Benchmark)
+			assert.Equal(t, []string{"test.txt"}, point.Origin.Files)
+
+			if tt.points > 1 {
+				assert.Zero(t, point.Aggregate.Samples)
+				assert.EqualT(t, 1, point.Origin.Samples)
+
+				return
+			}
+
+			assert.Equal(t, 2, point.Aggregate.Samples)
+			assert.EqualT(t, 2, point.Origin.Samples)
+			assert.Equal(t, 4000, point.Aggregate.Iterations)
+			assert.InDelta(t, 300.0, point.Aggregate.Mean, 1e-9)
+			assert.InDelta(t, 250.0, point.Aggregate.WeightedMean, 1e-9)
+
+			single := benchSet.SeriesFor(config.MetricNsPerOp, "generics", cfg.Categories[0])
+			require.Len(t, single[0].Points, 1)
+			assert.Equal(t, 1, single[0].Points[0].Aggregate.Samples)
+			assert.InDelta(t, 10.0, single[0].Points[0].Value, 1e-9)
+		})
+	}
+}
+
+func TestAggregateDuplicatesSeparately(t *testing.T) {
+	newSet := func(file, environment string, nsPerOp float64) parser.Set {
+		const name = "BenchmarkGreater/reflect/int-16"
+
+		return parser.Set{
+			Set:         parse.Set{name: []*parse.Benchmark{{Name: name, N: 1000, NsPerOp: nsPerOp, Measured: parse.NsPerOp}}},
+			File:        file,
+			Environment: environment,
+		}
+	}
+	cfg := mustLoadConfig(t, genericsConfig()+"aggregation: mean\n")
+
+	for _, tt := range []struct {
+		name   string
+		sets   []parser.Set
+		points int
+	}{
+		{
+			name:   "should aggregate duplicates from several files",
+			sets:   []parser.Set{newSet("a.txt", "linux", 100), newSet("b.txt", "linux", 120)},
+			points: 1,
+		},
+		{
+			name:   "should not aggregate duplicates from several environments",
+			sets:   []parser.Set{newSet("a.txt", "linux", 100), newSet("b.txt", "darwin", 120)},
+			points: 2,
+		},
+		{
+			name:   "should not aggregate conflicting duplicates from several files",
+			sets:   []parser.Set{newSet("a.txt", "linux", 100), newSet("b.txt", "linux", 1000)},
+			points: 2,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			benchSet, err := New(cfg).parseBenchmarks(tt.sets)
+			require.NoError(t, err)
+
+			series := benchSet.SeriesFor(config.MetricNsPerOp, "reflect", cfg.Categories[0])
+			require.Len(t, series, 1)
+			assert.Len(t, series[0].Points, tt.points)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package organizer
+
+import (
+	"log/slog"
+
+	"github.com/fredbi/benchviz/internal/complexity"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// fitComplexity estimates the complexity of each function in each series of a category,
+// from the numeric values declared by contexts.
+//
+// Functions measured for less than two distinct context values are not estimated.
+func (v *Organizer) fitComplexity(category *model.Category) {
+	for _, data := range category.Data {
+		for _, series := range data.Series {
+			var functions []string
+			byFunction := make(map[string][]complexity.Point)
+
+			for _, point := range series.Points {
+				value := v.contextValue(point.Context)
+				if value == nil {
+					continue
+				}
+
+				if _, ok := byFunction[point.Function]; !ok {
+					functions = append(functions, point.Function)
+				}
+				byFunction[point.Function] = append(byFunction[point.Function], complexity.Point{N: *value, Value: point.Value})
+			}
+
+			for _, function := range functions {
+				result, ok := complexity.Fit(byFunction[function])
+				if !ok {
+					v.l.Debug("complexity not estimated",
+						slog.String("category", category.ID),
+						slog.String("function", function),
+						slog.String("series", series.Title),
+					)
+
+					continue
+				}
+
+				category.Fits = append(category.Fits, model.Fit{
+					Result:   result,
+					Function: function,
+					Series:   series.Title,
+					Metric:   series.Metric,
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package organizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/fredbi/benchviz/internal/complexity"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestFitComplexity(t *testing.T) {
+	const complexityConfig = `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: lookup
+    Match: 'Lookup'
+  - id: sort
+    Match: 'Sort'
+contexts:
+  - id: size_10
+    Match: '/size_10$'
+    value: 10
+  - id: size_100
+    Match: '/size_100$'
+    value: 100
+  - id: size_1000
+    Match: '/size_1000$'
+    value: 1000
+versions:
+  - id: v1
+    Match: '/v1/'
+categories:
+  - id: scaling
+    xAxis: log
+    complexity: true
+    includes:
+      metrics: [nsPerOp]
+`
+
+	set := parser.Set{Set: parse.Set{}, File: "scaling.txt"}
+	for _, size := range []int{10, 100, 1000} {
+		lookup := fmt.Sprintf("BenchmarkLookup/v1/size_%d", size)
+		set.Set[lookup] = []*parse.Benchmark{{Name: lookup, N: 1, NsPerOp: 20, Measured: parse.NsPerOp}}
+
+		sort := fmt.Sprintf("BenchmarkSort/v1/size_%d", size)
+		set.Set[sort] = []*parse.Benchmark{{Name: sort, N: 1, NsPerOp: float64(size * size), Measured: parse.NsPerOp}}
+	}
+
+	scenario, err := New(mustLoadConfig(t, complexityConfig)).Scenarize([]parser.Set{set})
+	require.NoError(t, err)
+	require.Len(t, scenario.Categories, 1)
+
+	category := scenario.Categories[0]
+	require.Len(t, category.Fits, 2)
+
+	series := category.Data[0].Series[0]
+	lookup, ok := category.FitFor("lookup", series.Title, series.Metric)
+	require.True(t, ok)
+	assert.EqualT(t, complexity.O1, lookup.Complexity)
+
+	sort, ok := category.FitFor("sort", series.Title, series.Metric)
+	require.True(t, ok)
+	assert.EqualT(t, complexity.ON2, sort.Complexity)
+}
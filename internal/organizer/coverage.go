@@ -0,0 +1,67 @@
+package organizer
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// coverageMaxNames is the maximum number of skipped benchmarks listed by [Coverage.String].
+const coverageMaxNames = 10
+
+// Coverage summarizes how many input benchmarks are matched by the function rules of the configuration.
+//
+// Benchmarks are counted once per input file, regardless of repeated runs.
+type Coverage struct {
+	Total   int
+	Matched int
+	Skipped []Unmatched
+}
+
+// String renders the coverage, e.g. "87/92 benchmarks matched; 5 skipped: BenchmarkA, BenchmarkB, ...".
+func (c Coverage) String() string {
+	summary := fmt.Sprintf("%d/%d benchmarks matched", c.Matched, c.Total)
+	if len(c.Skipped) == 0 {
+		return summary
+	}
+
+	names := make([]string, 0, min(len(c.Skipped), coverageMaxNames))
+	for _, u := range c.Skipped[:min(len(c.Skipped), coverageMaxNames)] {
+		names = append(names, u.Name)
+	}
+	if more := len(c.Skipped) - len(names); more > 0 {
+		names = append(names, fmt.Sprintf("and %d more", more))
+	}
+
+	return fmt.Sprintf("%s; %d skipped: %s", summary, len(c.Skipped), strings.Join(names, ", "))
+}
+
+// Coverage matches the names of all the input benchmarks against the function rules of the configuration,
+// without organizing them.
+//
+// It is a quick pre-flight check of the configuration, before spending time on organizing and rendering.
+func (v *Organizer) Coverage(sets []parser.Set) Coverage {
+	var (
+		coverage  Coverage
+		unmatched unmatchedCollector
+	)
+
+	for _, set := range v.filterSets(sets) {
+		for _, name := range slices.Sorted(maps.Keys(set.Set)) { // iterate over the parsed map in a deterministic order
+			coverage.Total++
+			if _, ok := v.cfg.FindFunction(name); ok {
+				coverage.Matched++
+
+				continue
+			}
+
+			unmatched.add(set.File, name, ReasonNoFunction)
+		}
+	}
+	coverage.Skipped = unmatched.items
+
+	return coverage
+}
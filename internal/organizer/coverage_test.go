@@ -0,0 +1,56 @@
+package organizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/parser"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestCoverage(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig())
+
+	t.Run("should count matched and skipped benchmarks", func(t *testing.T) {
+		sets := []parser.Set{{
+			File: "bench.txt",
+			Set: parse.Set{
+				"BenchmarkGreater/reflect/int-16": []*parse.Benchmark{
+					{Name: "BenchmarkGreater/reflect/int-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
+					{Name: "BenchmarkGreater/reflect/int-16", N: 1000, NsPerOp: 110, Measured: parse.NsPerOp},
+				},
+				"BenchmarkGreater/generic/int-16": []*parse.Benchmark{
+					{Name: "BenchmarkGreater/generic/int-16", N: 1000, NsPerOp: 10, Measured: parse.NsPerOp},
+				},
+				"BenchmarkOther-16": []*parse.Benchmark{
+					{Name: "BenchmarkOther-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
+				},
+			},
+		}}
+
+		coverage := New(cfg).Coverage(sets)
+		assert.Equal(t, 3, coverage.Total)
+		assert.Equal(t, 2, coverage.Matched)
+		require.Len(t, coverage.Skipped, 1)
+		assert.Equal(t, "BenchmarkOther-16", coverage.Skipped[0].Name)
+		assert.Equal(t, ReasonNoFunction, coverage.Skipped[0].Reason)
+		assert.Equal(t, "2/3 benchmarks matched; 1 skipped: BenchmarkOther-16", coverage.String())
+	})
+
+	t.Run("should abbreviate a long list of skipped benchmarks", func(t *testing.T) {
+		set := parse.Set{}
+		for i := range coverageMaxNames + 2 {
+			name := fmt.Sprintf("BenchmarkOther%02d-16", i)
+			set[name] = []*parse.Benchmark{{Name: name, N: 1000, NsPerOp: 100, Measured: parse.NsPerOp}}
+		}
+
+		coverage := New(cfg).Coverage([]parser.Set{{File: "bench.txt", Set: set}})
+		assert.Equal(t, 0, coverage.Matched)
+		assert.Len(t, coverage.Skipped, coverageMaxNames+2)
+		assert.Contains(t, coverage.String(), "0/12 benchmarks matched; 12 skipped: BenchmarkOther00-16, ")
+		assert.Contains(t, coverage.String(), "BenchmarkOther09-16, and 2 more")
+	})
+}
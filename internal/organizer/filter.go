@@ -0,0 +1,44 @@
+package organizer
+
+import (
+	"log/slog"
+
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// filterSets retains the input benchmarks selected by the filters of the config, before they are organized.
+//
+// Sets from a filtered out file or environment are dropped altogether. Sets are not modified: a set is copied
+// when some of its benchmarks are filtered out.
+func (v *Organizer) filterSets(sets []parser.Set) []parser.Set {
+	filters := v.cfg.Filters
+	if filters.IsZero() {
+		return sets
+	}
+
+	filtered := make([]parser.Set, 0, len(sets))
+	for _, set := range sets {
+		if !filters.Files.Retains(set.File) || !filters.Environments.Retains(set.Environment) {
+			v.l.Info("input filtered out", slog.String("file", set.File), slog.String("environment", set.Environment))
+
+			continue
+		}
+
+		retained := make(parse.Set, len(set.Set))
+		for name, runs := range set.Set {
+			if !filters.Benchmarks.Retains(name) {
+				v.l.Debug("benchmark filtered out", slog.String("file", set.File), slog.String("benchmark_name", name))
+
+				continue
+			}
+			retained[name] = runs
+		}
+		set.Set = retained
+
+		filtered = append(filtered, set)
+	}
+
+	return filtered
+}
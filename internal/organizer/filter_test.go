@@ -0,0 +1,86 @@
+package organizer
+
+import (
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestFilters(t *testing.T) {
+	newSet := func(file, environment string, names ...string) parser.Set {
+		set := parser.Set{File: file, Environment: environment, Set: parse.Set{}}
+		for _, name := range names {
+			set.Set[name] = []*parse.Benchmark{{Name: name, N: 1000, NsPerOp: 100, Measured: parse.NsPerOp}}
+		}
+
+		return set
+	}
+
+	sets := []parser.Set{
+		newSet("linux.txt", "linux amd64", "BenchmarkEncode/json-8", "BenchmarkEncode/legacy-8", "BenchmarkDecode/json-8"),
+		newSet("darwin.txt", "darwin arm64", "BenchmarkEncode/json-8"),
+		newSet("linux_old.txt", "linux amd64", "BenchmarkEncode/json-8"),
+	}
+
+	cfg := mustLoadConfig(t, `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: encode
+    match: '^BenchmarkEncode'
+  - id: decode
+    match: '^BenchmarkDecode'
+versions:
+  - id: current
+    match: '^Benchmark'
+contexts:
+  - id: json
+    match: '/json'
+categories:
+  - id: encoding
+    includes:
+      functions: [encode, decode]
+      metrics: [nsPerOp]
+filters:
+  benchmarks:
+    exclude: '/legacy'
+  environments:
+    include: '^linux'
+  files:
+    exclude: '_old\.txt$'
+`)
+	o := New(cfg)
+
+	t.Run("should filter benchmarks before organizing them", func(t *testing.T) {
+		filtered := o.filterSets(sets)
+		require.Len(t, filtered, 1)
+		assert.EqualT(t, "linux.txt", filtered[0].File)
+		assert.Len(t, filtered[0].Set, 2)
+		assert.Len(t, sets[0].Set, 3) // inputs are left untouched
+
+		scenario, err := o.Scenarize(sets)
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		var functions []string
+		for _, data := range scenario.Categories[0].Data {
+			for _, series := range data.Series {
+				for _, point := range series.Points {
+					functions = append(functions, point.Origin.Benchmark)
+					assert.Equal(t, []string{"linux.txt"}, point.Origin.Files)
+				}
+			}
+		}
+		assert.ElementsMatch(t, []string{"BenchmarkEncode/json-8", "BenchmarkDecode/json-8"}, functions)
+	})
+
+	t.Run("should check coverage of filtered benchmarks only", func(t *testing.T) {
+		coverage := o.Coverage(sets)
+		assert.EqualT(t, 2, coverage.Total)
+		assert.EqualT(t, 2, coverage.Matched)
+	})
+}
@@ -0,0 +1,36 @@
+package organizer
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// groupFunctions rearranges the points of a category so that the functions of a group are contiguous on the X axis.
+//
+// Groups come in the order of their configuration, followed by the points without a group.
+// Points retain their order within a group.
+func (v *Organizer) groupFunctions(category *model.Category) {
+	if len(v.cfg.FunctionGroups) == 0 {
+		return
+	}
+
+	rank := func(point model.MetricPoint) int {
+		for i, group := range v.cfg.FunctionGroups {
+			if group.ID == point.GroupID {
+				return i
+			}
+		}
+
+		return len(v.cfg.FunctionGroups)
+	}
+
+	for _, data := range category.Data {
+		for _, series := range data.Series {
+			slices.SortStableFunc(series.Points, func(a, b model.MetricPoint) int {
+				return cmp.Compare(rank(a), rank(b))
+			})
+		}
+	}
+}
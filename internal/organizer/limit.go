@@ -0,0 +1,86 @@
+package organizer
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// applyLimit restricts a category to its top N (or bottom N) benchmarks on the X axis.
+//
+// Benchmarks are ranked by the value of the limit metric: when several series share the same
+// position on the X axis, the largest value is retained for a descending order (e.g. the slowest
+// benchmarks), and the smallest value for an ascending order (e.g. the fastest benchmarks).
+//
+// Retained points keep their original order on the chart.
+func (v *Organizer) applyLimit(category *model.Category, limit config.Limit) {
+	if limit.N <= 0 {
+		return
+	}
+
+	descending := limit.Order != config.LimitOrderAsc
+	scores := make(map[model.SeriesKey]float64)
+	var keys []model.SeriesKey
+
+	for _, data := range category.Data {
+		if data.Metric.ID != limit.By {
+			continue
+		}
+
+		for _, series := range data.Series {
+			for _, point := range series.Points {
+				key := category.XKey(point)
+				score, seen := scores[key]
+				switch {
+				case !seen:
+					keys = append(keys, key)
+					score = point.Value
+				case descending:
+					score = max(score, point.Value)
+				default:
+					score = min(score, point.Value)
+				}
+				scores[key] = score
+			}
+		}
+	}
+
+	if len(keys) <= limit.N {
+		return
+	}
+
+	slices.SortStableFunc(keys, func(a, b model.SeriesKey) int {
+		if descending {
+			return cmp.Compare(scores[b], scores[a])
+		}
+
+		return cmp.Compare(scores[a], scores[b])
+	})
+
+	retained := make(map[model.SeriesKey]struct{}, limit.N)
+	for _, key := range keys[:limit.N] {
+		retained[key] = struct{}{}
+	}
+
+	for di := range category.Data {
+		for si := range category.Data[di].Series {
+			series := &category.Data[di].Series[si]
+			series.Points = slices.DeleteFunc(series.Points, func(point model.MetricPoint) bool {
+				_, ok := retained[category.XKey(point)]
+
+				return !ok
+			})
+		}
+	}
+
+	v.l.Info("limited category",
+		slog.String("category", category.ID),
+		slog.Int("limit", limit.N),
+		slog.String("by", limit.By.String()),
+		slog.String("order", string(limit.Order)),
+		slog.Int("discarded", len(keys)-limit.N),
+	)
+}
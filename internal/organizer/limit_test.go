@@ -0,0 +1,61 @@
+package organizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestApplyLimit(t *testing.T) {
+	limited := func(limit string) string {
+		return strings.Replace(genericsConfig(), "    title: Comparisons\n", "    title: Comparisons\n    limit:\n"+limit, 1)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		limit  string
+		labels []string
+	}{
+		{
+			name:   "slowest by default",
+			limit:  "      n: 1\n",
+			labels: []string{"Float64"},
+		},
+		{
+			name:   "fastest",
+			limit:  "      n: 1\n      order: asc\n",
+			labels: []string{"Int"},
+		},
+		{
+			name:   "by allocations, ties retain the original order",
+			limit:  "      n: 1\n      by: allocsPerOp\n",
+			labels: []string{"Int"},
+		},
+		{
+			name:   "limit larger than the number of benchmarks",
+			limit:  "      n: 10\n",
+			labels: []string{"Int", "Float64"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := mustLoadConfig(t, limited(tt.limit))
+
+			scenario, err := New(cfg).Scenarize([]parser.Set{buildGenericsSet()})
+			require.NoError(t, err)
+			require.Len(t, scenario.Categories, 1)
+
+			category := scenario.Categories[0]
+			assert.Equal(t, tt.labels, category.Labels())
+
+			for _, data := range category.Data {
+				for _, series := range data.Series {
+					assert.Len(t, series.Points, len(tt.labels))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package organizer
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// LintIssueKind qualifies a problem found in a configuration by [Organizer.Lint].
+type LintIssueKind string
+
+// Kinds of configuration issues.
+const (
+	LintUnused        LintIssueKind = "unused"       // the rule never matched any benchmark
+	LintShadowed      LintIssueKind = "shadowed"     // the rule matched benchmarks, but always after an earlier rule (first wins)
+	LintEmptyCategory LintIssueKind = "empty charts" // the category produced charts without any data point
+)
+
+// Kinds of configuration rules checked by [Organizer.Lint].
+const (
+	ruleFunction = "function"
+	ruleVersion  = "version"
+	ruleContext  = "context"
+	ruleFile     = "file"
+	ruleCategory = "category"
+)
+
+// LintIssue describes a problem with a configuration rule, found by matching sample benchmarks.
+//
+// For shadowed rules, ShadowedBy lists the earlier rules that matched instead.
+type LintIssue struct {
+	Kind       LintIssueKind
+	Rule       string
+	ID         string
+	ShadowedBy []string
+}
+
+func (i LintIssue) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %q: %s", i.Rule, i.ID, i.Kind)
+	if len(i.ShadowedBy) > 0 {
+		fmt.Fprintf(&b, " (shadowed by: %s)", strings.Join(i.ShadowedBy, ", "))
+	}
+
+	return b.String()
+}
+
+// Lint checks the configuration against sample benchmark data.
+//
+// It reports function, version and context rules that never matched any benchmark,
+// rules completely shadowed by earlier rules (matching order is first-wins),
+// file rules that never matched any input file, and categories that produced empty charts.
+//
+// Linting is not subject to strict mode: all issues are reported, not returned as errors.
+// Benchmarks that could not be ingested are not issues of the config: they are available from [Organizer.Unmatched].
+func (v *Organizer) Lint(sets []parser.Set) ([]LintIssue, error) {
+	var (
+		names  []string
+		files  []string
+		issues []LintIssue
+	)
+
+	for _, set := range v.filterSets(sets) {
+		files = append(files, set.File)
+		for name := range set.Set {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	issues = append(issues, lintRules(ruleFunction, objectsOf(v.cfg.Functions, func(f config.Function) config.Object { return f.Object }), names)...)
+	issues = append(issues, lintRules(ruleVersion, objectsOf(v.cfg.Versions, func(f config.Version) config.Object { return f.Object }), names)...)
+	issues = append(issues, lintRules(ruleContext, objectsOf(v.cfg.Contexts, func(f config.Context) config.Object { return f.Object }), names)...)
+
+	for _, rule := range v.cfg.Files {
+		if !slices.ContainsFunc(files, func(file string) bool {
+			_, ok := rule.MatchString(file)
+
+			return ok
+		}) {
+			issues = append(issues, LintIssue{Kind: LintUnused, Rule: ruleFile, ID: rule.ID})
+		}
+	}
+
+	strict := config.StrictNone
+	lint := *v
+	lint.strict = &strict
+
+	set, err := lint.parseBenchmarks(sets)
+	if err != nil {
+		return nil, err
+	}
+	v.unmatched = lint.unmatched
+
+	for _, categoryConfig := range v.cfg.Categories {
+		if !v.retainsCategory(categoryConfig) {
+			continue
+		}
+
+		category := lint.populateCategory(categoryConfig, set)
+		lint.applyLimit(&category, categoryConfig.Limit)
+
+		if !slices.ContainsFunc(category.Data, func(data model.CategoryData) bool {
+			return slices.ContainsFunc(data.Series, func(series model.MetricSeries) bool {
+				return len(series.Points) > 0
+			})
+		}) {
+			issues = append(issues, LintIssue{Kind: LintEmptyCategory, Rule: ruleCategory, ID: categoryConfig.ID})
+		}
+	}
+
+	v.l.Info("configuration linted", slog.Int("benchmarks", len(names)), slog.Int("issues", len(issues)))
+
+	return issues, nil
+}
+
+// lintRules checks an ordered list of first-wins matching rules against benchmark names.
+func lintRules(rule string, objects []config.Object, names []string) []LintIssue {
+	var issues []LintIssue
+
+	for i, object := range objects {
+		var (
+			matched    bool
+			won        bool
+			shadowedBy []string
+		)
+
+		for _, name := range names {
+			if _, ok := object.MatchString(name); !ok {
+				continue
+			}
+			matched = true
+
+			winner := slices.IndexFunc(objects, func(o config.Object) bool {
+				_, ok := o.MatchString(name)
+
+				return ok
+			})
+			if winner == i {
+				won = true
+
+				break
+			}
+
+			if !slices.Contains(shadowedBy, objects[winner].ID) {
+				shadowedBy = append(shadowedBy, objects[winner].ID)
+			}
+		}
+
+		switch {
+		case !matched:
+			issues = append(issues, LintIssue{Kind: LintUnused, Rule: rule, ID: object.ID})
+		case !won:
+			issues = append(issues, LintIssue{Kind: LintShadowed, Rule: rule, ID: object.ID, ShadowedBy: shadowedBy})
+		}
+	}
+
+	return issues
+}
+
+func objectsOf[T any](rules []T, object func(T) config.Object) []config.Object {
+	objects := make([]config.Object, 0, len(rules))
+	for _, rule := range rules {
+		objects = append(objects, object(rule))
+	}
+
+	return objects
+}
@@ -0,0 +1,77 @@
+package organizer
+
+import (
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestLint(t *testing.T) {
+	sets := []parser.Set{buildGenericsSet()}
+
+	t.Run("should find no issue", func(t *testing.T) {
+		cfg := mustLoadConfig(t, genericsConfig())
+		cfg.Functions = cfg.Functions[:1]
+		cfg.Categories[0].Includes.Metrics = cfg.Categories[0].Includes.Metrics[:1]
+
+		issues, err := New(cfg).Lint(sets)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("should report unused and shadowed rules and empty charts", func(t *testing.T) {
+		cfg := mustLoadConfig(t, `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: greater
+    match: 'Greater'
+  - id: greater-again
+    match: 'Greater/'
+  - id: less
+    match: 'Less'
+contexts:
+  - id: int
+    match: '/int'
+  - id: float64
+    match: '/float64'
+versions:
+  - id: reflect
+    match: '/reflect/'
+  - id: generics
+    match: '/generic/'
+categories:
+  - id: less
+    includes:
+      functions: [less]
+      versions: [reflect, generics]
+      contexts: [int, float64]
+      metrics: [nsPerOp]
+  - id: greater
+    includes:
+      functions: [greater]
+      versions: [reflect, generics]
+      contexts: [int, float64]
+      metrics: [nsPerOp]
+files:
+  - id: machine-a
+    matchfile: 'machine-a'
+`)
+
+		issues, err := New(cfg, WithStrict(true)).Lint(sets)
+		require.NoError(t, err)
+
+		assert.Equal(t, []LintIssue{
+			{Kind: LintShadowed, Rule: ruleFunction, ID: "greater-again", ShadowedBy: []string{"greater"}},
+			{Kind: LintUnused, Rule: ruleFunction, ID: "less"},
+			{Kind: LintUnused, Rule: ruleFile, ID: "machine-a"},
+			{Kind: LintEmptyCategory, Rule: ruleCategory, ID: "less"},
+		}, issues)
+
+		assert.Equal(t, `function "greater-again": shadowed (shadowed by: greater)`, issues[0].String())
+		assert.Equal(t, `category "less": empty charts`, issues[3].String())
+	})
+}
@@ -0,0 +1,129 @@
+package organizer
+
+import (
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/fredbi/benchviz/internal/color"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// Matching describes how the benchmarks are resolved by the rules of a config,
+// and which function, version or context rules never fired.
+type Matching struct {
+	Benchmarks  []MatchedBenchmark `json:"benchmarks" yaml:"benchmarks"`
+	UnusedRules []UnusedRule       `json:"unused_rules,omitempty" yaml:"unused_rules,omitempty"`
+}
+
+// MatchedBenchmark tells which function, version and context a benchmark of an input file resolved to.
+//
+// An empty Function means that no function rule matched: the benchmark is not ingested.
+type MatchedBenchmark struct {
+	File     string `json:"file" yaml:"file"`
+	Name     string `json:"benchmark_name" yaml:"benchmark_name"`
+	Function string `json:"function,omitempty" yaml:"function,omitempty"`
+	Version  string `json:"version,omitempty" yaml:"version,omitempty"`
+	Context  string `json:"context,omitempty" yaml:"context,omitempty"`
+}
+
+// UnusedRule is a config rule that no benchmark resolved to.
+type UnusedRule struct {
+	Rule string `json:"rule" yaml:"rule"`
+	ID   string `json:"id" yaml:"id"`
+}
+
+// MatchingReport is a [parser.ParsingReport] with the [Matching] of its benchmarks.
+type MatchingReport struct {
+	parser.ParsingReport `yaml:",inline"`
+
+	Matching Matching `json:"matching" yaml:"matching"`
+}
+
+// Write the [MatchingReport] to w in the requested format (see [parser.ParsingReport.Write]).
+func (r MatchingReport) Write(w io.Writer, format parser.ReportFormat) error {
+	return r.WriteColored(w, format, color.Palette{})
+}
+
+// WriteColored writes the [MatchingReport] like [MatchingReport.Write], with colors in the "table" format.
+func (r MatchingReport) WriteColored(w io.Writer, format parser.ReportFormat, palette color.Palette) error {
+	return r.ParsingReport.WriteExtended(w, format, palette, r, r.Matching.sections()...)
+}
+
+// sections lays out the matching of benchmarks against config rules: how each benchmark resolved,
+// then the rules that never fired.
+func (m Matching) sections() []parser.ReportSection {
+	matching := parser.ReportSection{
+		Title:   "Matching",
+		Headers: []string{"Benchmark", "File", "Function", "Version", "Context"},
+	}
+	for _, b := range m.Benchmarks {
+		matching.Rows = append(matching.Rows, []string{
+			b.Name, b.File, defaultCell(b.Function), defaultCell(b.Version), defaultCell(b.Context),
+		})
+	}
+
+	sections := []parser.ReportSection{matching}
+
+	if len(m.UnusedRules) > 0 {
+		unused := parser.ReportSection{
+			Title:   "Unused rules",
+			Headers: []string{"Rule", "ID"},
+		}
+		for _, rule := range m.UnusedRules {
+			unused.Rows = append(unused.Rows, []string{rule.Rule, rule.ID})
+		}
+		sections = append(sections, unused)
+	}
+
+	return sections
+}
+
+// defaultCell renders an unresolved value as "-", so columns remain aligned.
+func defaultCell(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+// Matching resolves the benchmarks of all input sets against the config rules, without organizing them.
+//
+// It reports the function, version and context that each benchmark resolved to, and the function,
+// version and context rules that no benchmark resolved to, so configs may be audited.
+func (v *Organizer) Matching(sets []parser.Set) Matching {
+	var (
+		matching Matching
+		fired    = make(map[[2]string]struct{})
+	)
+
+	for _, set := range v.filterSets(sets) {
+		for _, name := range slices.Sorted(maps.Keys(set.Set)) { // iterate over the parsed map in a deterministic order
+			matched := MatchedBenchmark{File: set.File, Name: name}
+			if key, ok := v.resolveSeriesKey(name, set.File, set.Label); ok {
+				matched.Function, matched.Version, matched.Context = key.Function, key.Version, key.Context
+				fired[[2]string{ruleFunction, key.Function}] = struct{}{}
+				fired[[2]string{ruleVersion, key.Version}] = struct{}{}
+				fired[[2]string{ruleContext, key.Context}] = struct{}{}
+			}
+
+			matching.Benchmarks = append(matching.Benchmarks, matched)
+		}
+	}
+
+	unused := func(rule string, objects []config.Object) {
+		for _, object := range objects {
+			if _, ok := fired[[2]string{rule, object.ID}]; !ok {
+				matching.UnusedRules = append(matching.UnusedRules, UnusedRule{Rule: rule, ID: object.ID})
+			}
+		}
+	}
+
+	unused(ruleFunction, objectsOf(v.cfg.Functions, func(f config.Function) config.Object { return f.Object }))
+	unused(ruleVersion, objectsOf(v.cfg.Versions, func(f config.Version) config.Object { return f.Object }))
+	unused(ruleContext, objectsOf(v.cfg.Contexts, func(f config.Context) config.Object { return f.Object }))
+
+	return matching
+}
@@ -0,0 +1,83 @@
+package organizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/parser"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestMatching(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig())
+	sets := []parser.Set{{
+		File: "bench.txt",
+		Set: parse.Set{
+			"BenchmarkGreater/reflect/int-16": []*parse.Benchmark{
+				{Name: "BenchmarkGreater/reflect/int-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
+			},
+			"BenchmarkLess/generic/int-16": []*parse.Benchmark{
+				{Name: "BenchmarkLess/generic/int-16", N: 1000, NsPerOp: 10, Measured: parse.NsPerOp},
+			},
+			"BenchmarkOther-16": []*parse.Benchmark{
+				{Name: "BenchmarkOther-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
+			},
+		},
+	}}
+
+	matching := New(cfg).Matching(sets)
+
+	t.Run("should tell how each benchmark resolved", func(t *testing.T) {
+		require.Len(t, matching.Benchmarks, 3)
+		assert.Equal(t, MatchedBenchmark{
+			File: "bench.txt", Name: "BenchmarkGreater/reflect/int-16", Function: "greater", Version: "reflect", Context: "int",
+		}, matching.Benchmarks[0])
+		assert.Equal(t, MatchedBenchmark{
+			File: "bench.txt", Name: "BenchmarkLess/generic/int-16", Function: "less", Version: "generics", Context: "int",
+		}, matching.Benchmarks[1])
+		assert.Equal(t, MatchedBenchmark{File: "bench.txt", Name: "BenchmarkOther-16"}, matching.Benchmarks[2])
+	})
+
+	t.Run("should list the rules that never fired", func(t *testing.T) {
+		assert.Equal(t, []UnusedRule{
+			{Rule: ruleFunction, ID: "negative"},
+			{Rule: ruleContext, ID: "float64"},
+		}, matching.UnusedRules)
+	})
+	t.Run("should extend the parsing report", func(t *testing.T) {
+		report := MatchingReport{
+			ParsingReport: parser.ParsingReport{NumberOfSets: 1, Functions: []string{"BenchmarkGreater/reflect/int-16"}},
+			Matching:      matching,
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, report.Write(&buf, parser.ReportFormatTable))
+
+		out := buf.String()
+		assert.Contains(t, out, "SUMMARY")
+		assert.Contains(t, out, "MATCHING")
+		assert.Contains(t, out, "UNUSED RULES")
+		assert.Contains(t, out, "negative")
+
+		for _, format := range []parser.ReportFormat{parser.ReportFormatJSON, parser.ReportFormatYAML} {
+			buf.Reset()
+			require.NoError(t, report.Write(&buf, format))
+
+			// the parsing report is inlined, so that it may be read back
+			decoded, err := parser.ReadReport(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, report.Functions, decoded.Functions)
+		}
+
+		buf.Reset()
+		require.NoError(t, report.Write(&buf, parser.ReportFormatJSON))
+
+		var decoded MatchingReport
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, matching, decoded.Matching)
+	})
+}
@@ -1,8 +1,94 @@
 package organizer
 
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
 // Option configures an [Organizer].
 type Option func(*options)
 
 type options struct {
-	// reserved for future use
+	logger         *slog.Logger
+	strict         *config.StrictLevel
+	metricFilter   func(config.MetricName) bool
+	categoryFilter func(config.Category) bool
+}
+
+// WithLogger sets the logger used by the [Organizer].
+//
+// By default, the organizer logs with [slog.Default].
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithStrict overrides the strict mode set in the configuration.
+//
+// In strict mode, the [Organizer] fails whenever some benchmark data may not be rendered.
+func WithStrict(enabled bool) Option {
+	level := config.StrictNone
+	if enabled {
+		level = config.StrictAll
+	}
+
+	return WithStrictLevel(level)
+}
+
+// WithStrictLevel overrides the strict mode set in the configuration with a granular strictness level.
+//
+// For example, [config.StrictFunctions] fails only on benchmarks that match no function.
+func WithStrictLevel(level config.StrictLevel) Option {
+	return func(o *options) {
+		o.strict = &level
+	}
+}
+
+// WithMetricFilter retains only the metrics accepted by the filter when populating categories.
+//
+// A nil filter retains all metrics.
+func WithMetricFilter(filter func(config.MetricName) bool) Option {
+	return func(o *options) {
+		o.metricFilter = filter
+	}
+}
+
+// WithCategoryFilter retains only the categories accepted by the filter.
+//
+// A nil filter retains all categories.
+func WithCategoryFilter(filter func(config.Category) bool) Option {
+	return func(o *options) {
+		o.categoryFilter = filter
+	}
+}
+
+// retainsMetric reports whether a metric passes the metric filter.
+func (o options) retainsMetric(metric config.MetricName) bool {
+	return o.metricFilter == nil || o.metricFilter(metric)
+}
+
+// retainsCategory reports whether a category passes the category filter, and
+// has at least one metric that passes the metric filter.
+func (o options) retainsCategory(category config.Category) bool {
+	if o.categoryFilter != nil && !o.categoryFilter(category) {
+		return false
+	}
+
+	return slices.ContainsFunc(category.Includes.Metrics, o.retainsMetric)
+}
+
+func optionsWithDefaults(opts []Option) options {
+	var o options
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+
+	return o
 }
@@ -0,0 +1,86 @@
+package organizer
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestWithLogger(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig())
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, nil))
+
+	_, err := New(cfg, WithLogger(l)).Scenarize([]parser.Set{buildGenericsSet()})
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "resolved categories")
+	assert.Contains(t, buf.String(), "module=organizer")
+}
+
+func TestWithStrict(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig())
+	failed := buildGenericsSet()
+	failed.Failures = []string{"FAIL\texample.com/pkg"}
+	sets := []parser.Set{failed}
+
+	t.Run("should override config with strict mode", func(t *testing.T) {
+		_, err := New(cfg, WithStrict(true)).parseBenchmarks(sets)
+		require.Error(t, err)
+		assert.EqualT(t, config.StrictNone, cfg.StrictLevel)
+	})
+
+	t.Run("should override config with lenient mode", func(t *testing.T) {
+		cfg.StrictLevel = config.StrictAll
+		defer func() { cfg.StrictLevel = config.StrictNone }()
+
+		_, err := New(cfg, WithStrict(false)).parseBenchmarks(sets)
+		require.NoError(t, err)
+	})
+}
+
+func TestWithFilters(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig()+`
+  - id: timings
+    includes:
+      metrics: [nsPerOp]
+`)
+	sets := []parser.Set{buildGenericsSet()}
+
+	t.Run("should retain all categories and metrics by default", func(t *testing.T) {
+		scenario, err := New(cfg).Scenarize(sets)
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 2)
+		assert.Len(t, scenario.Categories[0].Metrics(), 2)
+	})
+
+	t.Run("should filter metrics", func(t *testing.T) {
+		scenario, err := New(cfg, WithMetricFilter(func(metric config.MetricName) bool {
+			return metric == config.MetricAllocsPerOp
+		})).Scenarize(sets)
+		require.NoError(t, err)
+
+		// the "timings" category has no metric left
+		require.Len(t, scenario.Categories, 1)
+		assert.Equal(t, "comparisons", scenario.Categories[0].ID)
+		metrics := scenario.Categories[0].Metrics()
+		require.Len(t, metrics, 1)
+		assert.Equal(t, config.MetricAllocsPerOp, metrics[0].ID)
+	})
+
+	t.Run("should filter categories", func(t *testing.T) {
+		scenario, err := New(cfg, WithCategoryFilter(func(category config.Category) bool {
+			return category.ID == "timings"
+		})).Scenarize(sets)
+		require.NoError(t, err)
+
+		require.Len(t, scenario.Categories, 1)
+		assert.Equal(t, "timings", scenario.Categories[0].ID)
+	})
+}
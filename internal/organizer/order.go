@@ -0,0 +1,74 @@
+package organizer
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// orderContexts sorts the contexts of a category in a natural order, when required by the category config.
+//
+// Context IDs are compared by their embedded numbers, so that sizes read left-to-right
+// (e.g. "10" < "100" < "1000", "small_200" < "small_1000").
+// Contexts declaring a numeric value are compared by value, and come before those that don't.
+//
+// With versions as series, contexts are sorted within the points of each function, and functions retain their order.
+// With contexts as series, the series are sorted.
+func (v *Organizer) orderContexts(category *model.Category, order config.ContextOrder) {
+	if order != config.ContextOrderNatural {
+		return
+	}
+
+	if category.Pivot == config.PivotContexts {
+		slices.SortStableFunc(category.Data, func(a, b model.CategoryData) int {
+			return v.compareContexts(a.Context.ID, b.Context.ID)
+		})
+
+		return
+	}
+
+	for _, data := range category.Data {
+		for _, series := range data.Series {
+			points := series.Points
+			for len(points) > 0 {
+				// points of the same function are contiguous
+				end := 1
+				for end < len(points) && points[end].Function == points[0].Function {
+					end++
+				}
+
+				slices.SortStableFunc(points[:end], func(a, b model.MetricPoint) int {
+					return v.compareContexts(a.Context, b.Context)
+				})
+				points = points[end:]
+			}
+		}
+	}
+}
+
+// compareContexts compares two context IDs by their declared value when they have one, or in a natural order.
+func (v *Organizer) compareContexts(a, b string) int {
+	valueA, valueB := v.contextValue(a), v.contextValue(b)
+
+	switch {
+	case valueA != nil && valueB != nil:
+		return cmp.Compare(*valueA, *valueB)
+	case valueA != nil:
+		return -1
+	case valueB != nil:
+		return 1
+	default:
+		return naturalCompare(a, b)
+	}
+}
+
+func (v *Organizer) contextValue(id string) *float64 {
+	context, ok := v.cfg.GetContext(id)
+	if !ok {
+		return nil
+	}
+
+	return context.Value
+}
@@ -0,0 +1,133 @@
+package organizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestOrderContexts(t *testing.T) {
+	// contexts are listed in lexicographic order
+	sizesConfig := func(order, pivot string) string {
+		return `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: encode
+    Match: 'Encode'
+  - id: decode
+    Match: 'Decode'
+contexts:
+  - id: size_1000
+    Match: '/size_1000$'
+  - id: size_10
+    Match: '/size_10$'
+  - id: size_100
+    Match: '/size_100$'
+versions:
+  - id: v1
+    Match: '/v1/'
+categories:
+  - id: sizes
+    contextOrder: ` + order + `
+    pivot: ` + pivot + `
+    includes:
+      metrics: [nsPerOp]
+`
+	}
+
+	set := parser.Set{Set: parse.Set{}, File: "sizes.txt"}
+	for _, function := range []string{"Encode", "Decode"} {
+		for _, size := range []int{10, 100, 1000} {
+			name := fmt.Sprintf("Benchmark%s/v1/size_%d", function, size)
+			set.Set[name] = []*parse.Benchmark{{Name: name, N: 1, NsPerOp: float64(size), Measured: parse.NsPerOp}}
+		}
+	}
+
+	t.Run("should retain the config order by default", func(t *testing.T) {
+		scenario, err := New(mustLoadConfig(t, sizesConfig("config", "versions"))).Scenarize([]parser.Set{set})
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		points := scenario.Categories[0].Data[0].Series[0].Points
+		contexts := make([]string, 0, len(points))
+		for _, point := range points {
+			contexts = append(contexts, point.Function+":"+point.Context)
+		}
+		assert.Equal(t, []string{
+			"encode:size_1000", "encode:size_10", "encode:size_100",
+			"decode:size_1000", "decode:size_10", "decode:size_100",
+		}, contexts)
+	})
+
+	t.Run("should sort contexts in a natural order within functions", func(t *testing.T) {
+		scenario, err := New(mustLoadConfig(t, sizesConfig("natural", "versions"))).Scenarize([]parser.Set{set})
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		points := scenario.Categories[0].Data[0].Series[0].Points
+		contexts := make([]string, 0, len(points))
+		for _, point := range points {
+			contexts = append(contexts, point.Function+":"+point.Context)
+		}
+		assert.Equal(t, []string{
+			"encode:size_10", "encode:size_100", "encode:size_1000",
+			"decode:size_10", "decode:size_100", "decode:size_1000",
+		}, contexts)
+	})
+
+	t.Run("should sort series in a natural order when pivoted", func(t *testing.T) {
+		scenario, err := New(mustLoadConfig(t, sizesConfig("natural", "contexts"))).Scenarize([]parser.Set{set})
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		var contexts []string
+		for _, data := range scenario.Categories[0].Data {
+			contexts = append(contexts, data.Context.ID)
+		}
+		assert.Equal(t, []string{"size_10", "size_100", "size_1000"}, contexts)
+	})
+
+	t.Run("should sort contexts by their declared value", func(t *testing.T) {
+		const valuesConfig = `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: encode
+    Match: 'Encode'
+contexts:
+  - id: large
+    Match: '/size_1000$'
+    value: 1000
+  - id: small
+    Match: '/size_10$'
+    value: 10
+  - id: medium
+    Match: '/size_100$'
+    value: 100
+versions:
+  - id: v1
+    Match: '/v1/'
+categories:
+  - id: sizes
+    contextOrder: natural
+    includes:
+      metrics: [nsPerOp]
+`
+		scenario, err := New(mustLoadConfig(t, valuesConfig)).Scenarize([]parser.Set{set})
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		points := scenario.Categories[0].Data[0].Series[0].Points
+		contexts := make([]string, 0, len(points))
+		for _, point := range points {
+			contexts = append(contexts, point.Context)
+		}
+		assert.Equal(t, []string{"small", "medium", "large"}, contexts)
+	})
+}
@@ -1,34 +1,61 @@
 package organizer
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
 	"github.com/fredbi/benchviz/internal/model"
 	"github.com/fredbi/benchviz/internal/parser"
 )
 
+// standardMetrics are the metrics reported by the benchmark framework, in order of resolution.
+var standardMetrics = []config.MetricName{
+	config.MetricNsPerOp, config.MetricAllocsPerOp, config.MetricBytesPerOp, config.MetricMBPerS, config.MetricIterations,
+}
+
 // Organizer rearranges parsed benchmark data into a configured visualization scenario.
 type Organizer struct {
-	options //nolint:unused // reserved for future extensions
+	options
 
-	cfg *config.Config
-	l   *slog.Logger
+	cfg       *config.Config
+	l         *slog.Logger
+	unmatched []Unmatched
 }
 
 // New builds an [Organizer] ready to reshuffle parsed benchmark data.
-func New(cfg *config.Config, _ ...Option) *Organizer {
+func New(cfg *config.Config, opts ...Option) *Organizer {
+	o := optionsWithDefaults(opts)
+
 	return &Organizer{
-		cfg: cfg,
-		l:   slog.Default().With(slog.String("module", "organizer")),
+		options: o,
+		cfg:     cfg,
+		l:       o.logger.With(slog.String("module", "organizer")),
+	}
+}
+
+// isStrictFor reports whether the requirements of a strictness level are enforced, either by option or by configuration.
+func (v *Organizer) isStrictFor(level config.StrictLevel) bool {
+	if v.strict != nil {
+		return v.strict.Covers(level)
 	}
+
+	return v.cfg.IsStrictFor(level)
 }
 
 // Scenarize a set of parsed benchmark data into a visualization [model.Scenario].
+//
+// Only the benchmarks retained by the filters of the config are organized.
 func (v *Organizer) Scenarize(sets []parser.Set) (*model.Scenario, error) {
-	newSet, err := v.parseBenchmarks(sets)
+	newSet, err := v.parseBenchmarks(v.filterSets(sets))
 	if err != nil {
 		return nil, err
 	}
@@ -43,63 +70,263 @@ func (v *Organizer) Scenarize(sets []parser.Set) (*model.Scenario, error) {
 
 // parseBenchmarks extracts structured data from raw benchmark results.
 func (v *Organizer) parseBenchmarks(sets []parser.Set) (*BenchmarkSet, error) {
-	var benchmarks []ParsedBenchmark
+	var (
+		runDuration time.Duration
+		unmatched   unmatchedCollector
+		count       int
+	)
 
 	for _, set := range sets {
+		for _, runs := range set.Set {
+			count += len(runs)
+		}
+	}
+
+	// each benchmark yields at most one parsed benchmark per configured metric
+	benchmarks := make([]ParsedBenchmark, 0, count*len(v.cfg.Metrics))
+
+	// benchmarks repeated in a file (e.g. with -count) are classified once
+	type classification struct {
+		parsed ParsedBenchmark
+		ok     bool
+	}
+	classified := make(map[[5]string]classification)
+
+	for _, set := range sets {
+		runDuration += set.Duration()
 		file := set.File
-		env := set.Environment
+		label := set.Label
+		env := v.cfg.NormalizeEnvironment(set.Environment)
+		goVersion := set.GoVersion
+		if !v.cfg.CompareGoVersions {
+			goVersion = ""
+		}
 
-		for _, benchs := range set.Set {
-			for _, bench := range benchs {
-				parsed, ok := v.parseBenchmarkName(bench.Name, file, env)
-				if !ok {
-					v.l.Warn("benchmark not ingested", slog.String("file", file), slog.String("benchmark_name", bench.Name))
-					if v.cfg.IsStrict {
-						err := fmt.Errorf("strict requirement not met for benchmark %q: not ingested. Stopping here", bench.Name)
-						v.l.Error("strict requirement not met", slog.String("error", err.Error()))
+		if set.Failed() {
+			v.l.Warn("benchmark run failed or was interrupted", slog.String("file", file), slog.Any("failures", set.Failures))
+			if v.isStrictFor(config.StrictAll) {
+				err := &failure.Error{
+					Stage:  failure.StageOrganize,
+					File:   file,
+					Reason: "benchmark run failed",
+					Err:    fmt.Errorf("strict requirement not met for input %q: benchmark run failed. Stopping here", file),
+				}
+				v.l.Error("strict requirement not met", slog.String("error", err.Error()))
+
+				return nil, err
+			}
+		}
 
-						return nil, err
+		for _, name := range slices.Sorted(maps.Keys(set.Set)) { // iterate over the parsed map in a deterministic order
+			for _, bench := range set.Set[name] {
+				key := [5]string{bench.Name, file, label, env, goVersion}
+				c, seen := classified[key]
+				if !seen {
+					c.parsed, c.ok = v.parseBenchmarkName(bench.Name, file, label, env)
+					if c.ok && goVersion != "" {
+						// a Go version no version matched keeps the version resolved otherwise, if any
+						if version := v.versionFromGo(bench.Name, goVersion); version != "" {
+							c.parsed.Version = version
+						}
 					}
+					classified[key] = c
+				}
+
+				parsed, ok := c.parsed, c.ok
+				if !ok {
+					v.l.Warn("benchmark not ingested", slog.String("file", file), slog.String("benchmark_name", bench.Name))
+					unmatched.add(file, bench.Name, ReasonNoFunction)
 
 					continue
 				}
+				parsed.Iterations = bench.N
+				parsed.Origin = model.Origin{Files: []string{file}, Benchmark: bench.Name, Samples: 1}
 
 				var resolved bool
-				benchmarks, ok = v.resolveMetric(config.MetricNsPerOp, parsed, bench.NsPerOp, benchmarks)
-				resolved = resolved || ok
-				benchmarks, ok = v.resolveMetric(config.MetricAllocsPerOp, parsed, float64(bench.AllocsPerOp), benchmarks)
-				resolved = resolved || ok
-				benchmarks, ok = v.resolveMetric(config.MetricBytesPerOp, parsed, float64(bench.AllocedBytesPerOp), benchmarks)
-				resolved = resolved || ok
-				benchmarks, ok = v.resolveMetric(config.MetricMBPerS, parsed, bench.MBPerS, benchmarks)
-				resolved = resolved || ok
+				for _, metric := range standardMetrics {
+					// metrics the benchmark didn't report (e.g. MB/s without SetBytes) are missing, not zero
+					value, measured := parser.MeasuredValue(bench, metric)
+					if !measured {
+						continue
+					}
+
+					benchmarks, ok = v.resolveMetric(metric, parsed, value, benchmarks)
+					resolved = resolved || ok
+				}
+				if custom := set.CustomMetrics(bench); len(custom) > 0 {
+					for _, metric := range v.cfg.Metrics {
+						value, measured := custom[metric.Unit]
+						if !metric.IsCustom() || !measured {
+							continue
+						}
+
+						benchmarks, ok = v.resolveMetric(metric.ID, parsed, value, benchmarks)
+						resolved = resolved || ok
+					}
+				}
 
 				if !resolved {
 					v.l.Warn("no benchmark metric ingested", slog.String("file", file), slog.String("benchmark_name", bench.Name))
-					if v.cfg.IsStrict {
-						err := fmt.Errorf("strict requirement not met for benchmark %q: empty series. Stopping here", bench.Name)
-						v.l.Error("strict requirement not met", slog.String("error", err.Error()))
-
-						return nil, err
-					}
+					unmatched.add(file, bench.Name, ReasonNoMetric)
 				}
 			}
 		}
 	}
 
+	v.unmatched = unmatched.items
+	if err := v.reportUnmatched(); err != nil {
+		return nil, err
+	}
+
+	sortBenchmarks(benchmarks)
+
+	conflicts, err := v.checkConflicts(benchmarks)
+	if err != nil {
+		return nil, err
+	}
+
+	benchmarks = v.aggregateBenchmarks(benchmarks, conflicts)
+
 	if len(benchmarks) == 0 {
 		v.l.Warn("benchmark set is empty")
-		if v.cfg.IsStrict {
-			err := errors.New("strict requirement not met for benchmark %q: empty benchmark set. Stopping here")
+		if v.isStrictFor(config.StrictAll) {
+			err := &failure.Error{
+				Stage:  failure.StageOrganize,
+				Reason: "empty benchmark set",
+				Err:    errors.New("strict requirement not met: empty benchmark set. Stopping here"),
+			}
 			v.l.Error("strict requirement not met", slog.String("error", err.Error()))
 
 			return nil, err
 		}
 	}
 
-	return &BenchmarkSet{
-		Set: benchmarks,
-	}, nil
+	set := NewBenchmarkSet(benchmarks)
+	set.RunDuration = runDuration
+
+	return set, nil
+}
+
+// sortBenchmarks sorts benchmarks deterministically by function, context and version, using a natural order
+// (e.g. "size-2" < "size-10").
+//
+// The sort is stable: benchmarks with the same key retain the order of the input files.
+func sortBenchmarks(benchmarks []ParsedBenchmark) {
+	slices.SortStableFunc(benchmarks, func(a, b ParsedBenchmark) int {
+		if c := naturalCompare(a.Function, b.Function); c != 0 {
+			return c
+		}
+
+		if c := naturalCompare(a.Context, b.Context); c != 0 {
+			return c
+		}
+
+		return naturalCompare(a.Version, b.Version)
+	})
+}
+
+// naturalCompare compares strings such that embedded sequences of digits are compared by their numerical value.
+func naturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da != "" && db != "" {
+			na, nb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if c := cmp.Compare(len(na), len(nb)); c != 0 {
+				return c
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			a, b = a[len(da):], b[len(db):]
+
+			continue
+		}
+
+		if a[0] != b[0] {
+			return cmp.Compare(a[0], b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	return cmp.Compare(len(a), len(b))
+}
+
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+
+	return s[:i]
+}
+
+// conflictRatio is the ratio between two measurements of the same series beyond which
+// duplicate benchmarks from different files are considered conflicting.
+const conflictRatio = 2.0
+
+// checkConflicts detects benchmarks from different input files that resolve to the same series
+// (function, version, context, metric) in the same environment, with wildly different values.
+//
+// Such benchmarks would be silently mixed on the same chart. It returns the duplicates found conflicting.
+func (v *Organizer) checkConflicts(benchmarks []ParsedBenchmark) (map[duplicateKey]struct{}, error) {
+	seen := make(map[duplicateKey]ParsedBenchmark)
+	conflicts := make(map[duplicateKey]struct{})
+
+	for _, bench := range benchmarks {
+		key := bench.duplicateKey()
+		previous, ok := seen[key]
+		if !ok {
+			seen[key] = bench
+
+			continue
+		}
+
+		if previous.File == bench.File || !isConflicting(previous.Value, bench.Value) {
+			continue
+		}
+		conflicts[key] = struct{}{}
+
+		v.l.Warn("conflicting duplicate benchmarks",
+			slog.String("function", bench.Function),
+			slog.String("version", bench.Version),
+			slog.String("context", bench.Context),
+			slog.String("metric", bench.Metric.String()),
+			slog.String("file", previous.File),
+			slog.Float64("value", previous.Value),
+			slog.String("other_file", bench.File),
+			slog.Float64("other_value", bench.Value),
+		)
+
+		if v.isStrictFor(config.StrictAll) {
+			err := &failure.Error{
+				Stage:     failure.StageOrganize,
+				File:      bench.File,
+				Benchmark: fmt.Sprintf("%s - %s - %s (%s)", bench.Function, bench.Version, bench.Context, bench.Metric),
+				Reason:    fmt.Sprintf("conflicting values in files %q and %q", previous.File, bench.File),
+				Err: fmt.Errorf(
+					"strict requirement not met for benchmark %s - %s - %s (%s): conflicting values in files %q and %q. Stopping here",
+					bench.Function, bench.Version, bench.Context, bench.Metric, previous.File, bench.File,
+				),
+			}
+			v.l.Error("strict requirement not met", slog.String("error", err.Error()))
+
+			return nil, err
+		}
+	}
+
+	return conflicts, nil
+}
+
+// isConflicting reports whether two measurements differ by more than [conflictRatio].
+func isConflicting(a, b float64) bool {
+	a, b = math.Abs(a), math.Abs(b)
+	low, high := min(a, b), max(a, b)
+
+	if low == 0 {
+		return high > 0
+	}
+
+	return high/low > conflictRatio
 }
 
 func (v *Organizer) resolveMetric(search config.MetricName, parsed ParsedBenchmark, value float64, benchmarks []ParsedBenchmark) ([]ParsedBenchmark, bool) {
@@ -145,8 +372,8 @@ func (v *Organizer) resolveMetric(search config.MetricName, parsed ParsedBenchma
 // the series legend is the version Title (else its id), and each point's x-axis
 // Label is the context Title (else its id), prefixed by the function Title only
 // when that Title is non-empty — so an empty function Title yields a context-only
-// label (no redundant "<function> - " prefix).
-func (v *Organizer) resolveLabels(series []model.MetricSeries, version config.Version, showFunction bool) {
+// label (no redundant "<function> - " prefix). A label template overrides this composition.
+func (v *Organizer) resolveLabels(series []model.MetricSeries, version config.Version, categoryConfig config.Category, showFunction bool) {
 	legend := version.Title
 	if legend == "" {
 		legend = version.ID
@@ -157,69 +384,185 @@ func (v *Organizer) resolveLabels(series []model.MetricSeries, version config.Ve
 
 		for pi := range series[si].Points {
 			p := &series[si].Points[pi]
+			p.Label = v.pointLabel(categoryConfig.Label, p.SeriesKey, p.Origin.Benchmark, v.contextTitle(p.Context), showFunction)
+		}
+	}
+}
 
-			ctxLabel := p.Context
-			if ctx, ok := v.cfg.GetContext(p.Context); ok && ctx.Title != "" {
-				ctxLabel = ctx.Title
-			}
+// resolvePivotLabels fills display strings for a pivoted category: the series legend is the context Title
+// (else its id), and each point's x-axis Label is the version Title (else its id), possibly prefixed by the function Title.
+func (v *Organizer) resolvePivotLabels(series []model.MetricSeries, context config.Context, categoryConfig config.Category, showFunction bool) {
+	legend := context.Title
+	if legend == "" {
+		legend = context.ID
+	}
 
-			// The function is redundant in the label when a chart plots a single
-			// function (the common case): show it only to disambiguate >1 function.
-			if showFunction {
-				fnLabel := p.Function
-				if fn, ok := v.cfg.GetFunction(p.Function); ok && fn.Title != "" {
-					fnLabel = fn.Title
-				}
-				p.Label = fnLabel + " - " + ctxLabel
-			} else {
-				p.Label = ctxLabel
-			}
+	for si := range series {
+		series[si].Title = legend
+
+		for pi := range series[si].Points {
+			p := &series[si].Points[pi]
+			p.Label = v.pointLabel(categoryConfig.Label, p.SeriesKey, p.Origin.Benchmark, v.versionTitle(p.Version), showFunction)
 		}
 	}
 }
 
+// resolveSeriesTitles applies the series title template of a category to the series of its data,
+// replacing placeholders with the titles of the version, context and metric, and with the environment.
+//
+// Without a template, series retain their default title: the version, or the context when pivoted.
+func (v *Organizer) resolveSeriesTitles(data model.CategoryData, template, environment string) {
+	if template == "" {
+		return
+	}
+
+	title := strings.NewReplacer(
+		config.LabelVersion, v.versionTitle(data.Version.ID),
+		config.LabelContext, v.contextTitle(data.Context.ID),
+		config.SeriesMetric, cmp.Or(data.Metric.Title, data.Metric.ID.String()),
+		config.SeriesEnvironment, environment,
+	).Replace(template)
+
+	for si := range data.Series {
+		data.Series[si].Title = title
+	}
+}
+
+// pointLabel composes the x-axis label of a point.
+//
+// A label template configured for the category replaces its placeholders with the titles
+// of the function, version and context of the point. A literal "\n" (e.g. in a single-quoted YAML string)
+// breaks the label into several lines. The label template of the function of the point, if any,
+// takes precedence, with the submatches of the function regexp in the benchmark name expanded.
+//
+// Otherwise, the function is redundant in the label when a chart plots a single
+// function (the common case): show it only to disambiguate >1 function.
+func (v *Organizer) pointLabel(template string, key model.SeriesKey, benchmark, label string, showFunction bool) string {
+	if fn, ok := v.cfg.GetFunction(key.Function); ok && fn.Label != "" {
+		template = fn.ExpandLabel(v.cfg.NormalizeName(benchmark))
+	}
+
+	if template != "" {
+		return strings.NewReplacer(
+			config.LabelFunction, v.functionTitle(key.Function),
+			config.LabelVersion, v.versionTitle(key.Version),
+			config.LabelContext, v.contextTitle(key.Context),
+			`\n`, "\n",
+		).Replace(template)
+	}
+
+	if !showFunction {
+		return label
+	}
+
+	return v.functionTitle(key.Function) + " - " + label
+}
+
+func (v *Organizer) functionTitle(id string) string {
+	if fn, ok := v.cfg.GetFunction(id); ok && fn.Title != "" {
+		return fn.Title
+	}
+
+	return id
+}
+
+func (v *Organizer) contextTitle(id string) string {
+	if ctx, ok := v.cfg.GetContext(id); ok && ctx.Title != "" {
+		return ctx.Title
+	}
+
+	return id
+}
+
+func (v *Organizer) versionTitle(id string) string {
+	if version, ok := v.cfg.GetVersion(id); ok && version.Title != "" {
+		return version.Title
+	}
+
+	return id
+}
+
 func (v *Organizer) populateCategories(set *BenchmarkSet) (*model.Scenario, error) {
 	scenario := &model.Scenario{
 		Name:       v.cfg.Name,
 		Categories: make([]model.Category, 0, len(v.cfg.Categories)),
 	}
 
-	environment := v.cfg.Environment
+	environments := set.Environments()
+	grouping := v.cfg.GroupEnvironments
+	if len(environments) < 2 { //nolint:mnd // no grouping needed for a single environment
+		grouping = config.GroupEnvironmentsNone
+	}
+
+	if grouping != config.GroupEnvironmentsSeries && grouping != config.GroupEnvironmentsCharts {
+		if err := v.checkEnvironments(environments); err != nil {
+			return nil, err
+		}
+	}
 
 	for _, categoryConfig := range v.cfg.Categories {
-		category := model.Category{
-			ID:    categoryConfig.ID,
-			Title: categoryConfig.Title,
-			Data:  make([]model.CategoryData, 0, len(categoryConfig.Includes.Metrics)),
+		if !v.retainsCategory(categoryConfig) {
+			v.l.Debug("category filtered out", slog.String("category", categoryConfig.ID))
+
+			continue
 		}
 
-		var data model.CategoryData
-		for _, metricID := range categoryConfig.Includes.Metrics {
-			metric, _ := v.cfg.GetMetric(metricID)
-			for _, versionID := range categoryConfig.Includes.Versions {
-				version, _ := v.cfg.GetVersion(versionID)
-				data.Metric = metric
-				data.Version = version
-				data.Series = set.SeriesFor(metric.ID, version.ID, categoryConfig)
-				v.resolveLabels(data.Series, version, len(categoryConfig.Includes.Functions) > 1)
-				category.Data = append(category.Data, data)
-				category.Environment = stringDefault(environment, set.Environment())
+		var categories []model.Category
+
+		switch grouping {
+		case config.GroupEnvironmentsCharts:
+			for i, env := range environments {
+				category := v.populateCategory(categoryConfig, set.ForEnvironment(env))
+				category.ID = fmt.Sprintf("%s-%d", category.ID, i+1)
+				category.Environment = env
+				categories = append(categories, category)
 			}
+		case config.GroupEnvironmentsSeries:
+			category := v.populateCategory(categoryConfig, set)
+			category.Data = category.Data[:0]
+			for _, env := range environments {
+				byEnv := v.populateCategory(categoryConfig, set.ForEnvironment(env))
+				for _, data := range byEnv.Data {
+					if !strings.Contains(categoryConfig.SeriesTitle, config.SeriesEnvironment) {
+						for si := range data.Series {
+							data.Series[si].Title += " (" + env + ")"
+						}
+					}
+					category.Data = append(category.Data, data)
+				}
+			}
+			category.Environment = strings.Join(environments, "; ")
+			categories = append(categories, category)
+		default:
+			categories = append(categories, v.populateCategory(categoryConfig, set))
 		}
 
-		if len(category.Data) == 0 {
-			v.l.Warn("no data resolved for category", slog.String("category", category.ID))
-			if v.cfg.IsStrict {
-				err := fmt.Errorf("strict requirement not met for category %q: no data for category. Stopping here", category.ID)
-				v.l.Error("strict requirement not met", slog.String("error", err.Error()))
+		for _, category := range categories {
+			v.orderContexts(&category, categoryConfig.ContextOrder)
+			v.applyLimit(&category, categoryConfig.Limit)
+			v.groupFunctions(&category)
+			if categoryConfig.Complexity {
+				v.fitComplexity(&category)
+			}
 
-				return nil, err
+			if len(category.Data) == 0 {
+				v.l.Warn("no data resolved for category", slog.String("category", category.ID))
+				if v.isStrictFor(config.StrictAll) {
+					err := &failure.Error{
+						Stage:  failure.StageOrganize,
+						Reason: fmt.Sprintf("no data for category %q", category.ID),
+						Err:    fmt.Errorf("strict requirement not met for category %q: no data for category. Stopping here", category.ID),
+					}
+					v.l.Error("strict requirement not met", slog.String("error", err.Error()))
+
+					return nil, err
+				}
+
+				continue
 			}
 
-			continue
+			scenario.Categories = append(scenario.Categories, category)
 		}
-
-		scenario.Categories = append(scenario.Categories, category)
 	}
 
 	v.l.Info("resolved categories", slog.Int("categories", len(scenario.Categories)))
@@ -227,6 +570,87 @@ func (v *Organizer) populateCategories(set *BenchmarkSet) (*model.Scenario, erro
 	return scenario, nil
 }
 
+// checkEnvironments warns when benchmarks run in incompatible environments are mixed on the same charts,
+// e.g. results collected on different CPUs. It fails when strict requirements are enforced.
+//
+// Environments are not mixed when they are grouped as separate series or charts (see [config.EnvironmentGrouping]).
+func (v *Organizer) checkEnvironments(environments []string) error {
+	differences := model.EnvironmentDiff(environments)
+	if len(differences) == 0 {
+		return nil
+	}
+
+	v.l.Warn("results from incompatible environments are mixed on the same charts: consider grouping environments",
+		slog.Any("differences", differences),
+		slog.Any("environments", environments),
+	)
+
+	if v.isStrictFor(config.StrictAll) {
+		err := &failure.Error{
+			Stage:  failure.StageOrganize,
+			Reason: "incompatible environments",
+			Err:    fmt.Errorf("strict requirement not met: results from incompatible environments are mixed (%s). Stopping here", strings.Join(differences, ", ")),
+		}
+		v.l.Error("strict requirement not met", slog.String("error", err.Error()))
+
+		return err
+	}
+
+	return nil
+}
+
+// populateCategory resolves the data series of a single category from a set of benchmarks.
+func (v *Organizer) populateCategory(categoryConfig config.Category, set *BenchmarkSet) model.Category {
+	category := model.Category{
+		ID:           categoryConfig.ID,
+		Title:        categoryConfig.Title,
+		Pivot:        categoryConfig.Pivot,
+		XAxis:        categoryConfig.XAxis,
+		AxisLabels:   categoryConfig.AxisLabels,
+		Difference:   categoryConfig.Difference,
+		Annotations:  categoryConfig.Annotations,
+		MetricLayout: v.cfg.MetricLayoutOf(categoryConfig),
+		Data:         make([]model.CategoryData, 0, len(categoryConfig.Includes.Metrics)),
+	}
+	showFunction := len(categoryConfig.Includes.Functions) > 1
+
+	for _, metricID := range categoryConfig.Includes.Metrics {
+		if !v.retainsMetric(metricID) {
+			continue
+		}
+		metric, _ := v.cfg.GetMetric(metricID)
+
+		if categoryConfig.Pivot == config.PivotContexts {
+			for _, contextID := range categoryConfig.Includes.Contexts {
+				var data model.CategoryData
+				context, _ := v.cfg.GetContext(contextID)
+				data.Metric = metric
+				data.Context = context
+				data.Series = set.SeriesForContext(metric.ID, context.ID, categoryConfig)
+				v.resolvePivotLabels(data.Series, context, categoryConfig, showFunction)
+				v.resolveSeriesTitles(data, categoryConfig.SeriesTitle, set.Environment())
+				category.Data = append(category.Data, data)
+			}
+		} else {
+			for _, versionID := range categoryConfig.Includes.Versions {
+				var data model.CategoryData
+				version, _ := v.cfg.GetVersion(versionID)
+				data.Metric = metric
+				data.Version = version
+				data.Series = set.SeriesFor(metric.ID, version.ID, categoryConfig)
+				v.resolveLabels(data.Series, version, categoryConfig, showFunction)
+				v.resolveSeriesTitles(data, categoryConfig.SeriesTitle, set.Environment())
+				category.Data = append(category.Data, data)
+			}
+		}
+
+		category.Environment = stringDefault(v.cfg.Environment, set.Environment())
+		category.RunDuration = set.RunDuration
+	}
+
+	return category
+}
+
 // parseBenchmarkName extracts function, version, and context from a benchmark name.
 //
 // Supports multiple formats:
@@ -236,38 +660,123 @@ func (v *Organizer) populateCategories(set *BenchmarkSet) (*model.Scenario, erro
 //   - Generics: "BenchmarkPositive/reflect/int-16" → (Positive, reflect, int)
 //   - EasyJSON: "BenchmarkReadJSON_small" → (ReadJSON, stdlib, small)
 //   - EasyJSON: "BenchmarkReadJSON_easyjson_large" → (ReadJSON, easyjson, large)
-func (v *Organizer) parseBenchmarkName(name, file, env string) (ParsedBenchmark, bool) {
-	function, ok := v.cfg.FindFunction(name)
+//
+// When the version cannot be resolved from the name or from a file rule, the user-defined label
+// of the input file is used as a version: either as a version ID, or as a string to match against
+// version regexps.
+func (v *Organizer) parseBenchmarkName(name, file, label, env string) (ParsedBenchmark, bool) {
+	key, ok := v.resolveSeriesKey(name, file, label)
 	if !ok {
 		v.l.Warn("no function matched", slog.String("function", name))
 
 		return ParsedBenchmark{}, false // exclude benchmarks with non-identified functions
 	}
 
+	if key.Version == "" && key.Context == "" {
+		v.l.Warn("no version, no context matched", slog.String("function", name))
+	}
+
+	v.l.Debug("benchmark matched",
+		slog.String("benchmark_name", name),
+		slog.String("file", file),
+		slog.String("function", key.Function),
+		slog.String("version", key.Version),
+		slog.String("context", key.Context),
+	)
+
+	var group model.MetricPoint
+	if def, ok := v.cfg.FindFunctionGroup(name); ok {
+		group.Group, group.GroupID = def.Title, def.ID
+	}
+
+	return ParsedBenchmark{
+		SeriesKey:   key,
+		MetricPoint: group,
+		Environment: defaultString(v.cfg.Environment, env),
+		File:        file,
+		Params:      parser.Params(name),
+	}, true
+}
+
+// resolveSeriesKey resolves the function, version and context of a benchmark from the config rules.
+//
+// Versions and contexts that don't match the benchmark name fall back on file-based rules,
+// then versions fall back on the label of the input file. Contexts may also be bound to the parameters
+// of the benchmark (e.g. "size=1024"), before file-based rules. It returns false if no function matched.
+func (v *Organizer) resolveSeriesKey(name, file, label string) (model.SeriesKey, bool) {
+	function, ok := v.cfg.FindFunction(name)
+	if !ok {
+		return model.SeriesKey{}, false
+	}
+
 	version, ok := v.cfg.FindVersion(name)
 	if !ok {
 		// fall back on file-based rule
-		version, _ = v.cfg.FindVersionFromFile(file)
+		version, ok = v.cfg.FindVersionFromFile(file)
+	}
+
+	if !ok && label != "" {
+		// fall back on the label provided for the input file
+		version = v.versionFromLabel(label)
+		if version == "" {
+			version = v.addLabelVersion(label)
+		}
 	}
 
 	context, ok := v.cfg.FindContext(name)
+	if !ok {
+		// fall back on the parameters of the benchmark
+		context, ok = v.cfg.FindContextFromParams(parser.Params(name))
+	}
 	if !ok {
 		// fall back on file-based rule
 		context, _ = v.cfg.FindContextFromFile(file)
 	}
 
-	if version == "" && context == "" {
-		v.l.Warn("no version, no context matched", slog.String("function", name))
+	return model.SeriesKey{
+		Function: function,
+		Version:  version,
+		Context:  context,
+	}, true
+}
+
+// versionFromLabel resolves a version ID from a user-defined input label.
+func (v *Organizer) versionFromLabel(label string) string {
+	if version, ok := v.cfg.GetVersion(label); ok {
+		return version.ID
 	}
 
-	return ParsedBenchmark{
-		SeriesKey: model.SeriesKey{
-			Function: function,
-			Version:  version,
-			Context:  context,
-		},
-		Environment: defaultString(v.cfg.Environment, env),
-	}, true
+	version, _ := v.cfg.FindVersion(label)
+
+	return version
+}
+
+// addLabelVersion declares a user-defined input label which matches no version as a version of its own,
+// with the label as ID and title.
+func (v *Organizer) addLabelVersion(label string) string {
+	if v.cfg.AddVersion(config.Version{Object: config.Object{ID: label, Title: label}}) {
+		v.l.Info("no version matched the input label: the label is a new version",
+			slog.String("label", label),
+		)
+	}
+
+	return label
+}
+
+// versionFromGo resolves a version ID from the version of the Go toolchain that ran a benchmark,
+// when Go versions are compared (see [config.Config.CompareGoVersions]).
+//
+// The Go version is resolved like a label: either as a version ID, or as a string to match against version regexps.
+func (v *Organizer) versionFromGo(name, goVersion string) string {
+	version := v.versionFromLabel(goVersion)
+	if version == "" {
+		v.l.Warn("no version matched the Go version",
+			slog.String("benchmark_name", name),
+			slog.String("go_version", goVersion),
+		)
+	}
+
+	return version
 }
 
 func defaultString(in, def string) string {
@@ -283,12 +792,55 @@ type ParsedBenchmark struct {
 	model.SeriesKey
 	model.MetricPoint
 
-	Environment string // benchmark-specific environment // TODO: we may have 1 or several values for environment - rendering to be figured out
+	Environment string // benchmark-specific environment (see [config.EnvironmentGrouping] for rendering several environments)
+	File        string // input file the benchmark originates from
+	Iterations  int    // number of iterations of the benchmark (b.N)
+
+	Params map[string]string // parameters of the benchmark, parsed from the "key=value" segments of its name
 }
 
 // BenchmarkSet holds parsed benchmarks organized for chart generation.
+//
+// RunDuration is the total duration of the benchmark runs, when known.
 type BenchmarkSet struct {
-	Set []ParsedBenchmark
+	Set         []ParsedBenchmark
+	RunDuration time.Duration
+
+	index map[model.SeriesKey][]int // positions in Set, by series key
+}
+
+// NewBenchmarkSet builds a [BenchmarkSet], with benchmarks indexed by their [model.SeriesKey].
+func NewBenchmarkSet(benchmarks []ParsedBenchmark) *BenchmarkSet {
+	return &BenchmarkSet{
+		Set:   benchmarks,
+		index: indexBenchmarks(benchmarks),
+	}
+}
+
+// indexBenchmarks builds the positions of benchmarks by series key.
+//
+// All positions are allocated at once: each series key gets a slice of a single backing array.
+func indexBenchmarks(benchmarks []ParsedBenchmark) map[model.SeriesKey][]int {
+	counts := make(map[model.SeriesKey]int)
+	for _, bench := range benchmarks {
+		counts[bench.SeriesKey]++
+	}
+
+	index := make(map[model.SeriesKey][]int, len(counts))
+	positions := make([]int, len(benchmarks))
+	var offset int
+
+	for i, bench := range benchmarks {
+		indexed, ok := index[bench.SeriesKey]
+		if !ok {
+			count := counts[bench.SeriesKey]
+			indexed = positions[offset : offset : offset+count]
+			offset += count
+		}
+		index[bench.SeriesKey] = append(indexed, i)
+	}
+
+	return index
 }
 
 // Environment returns the first non-empty environment string found in the benchmark set.
@@ -302,6 +854,34 @@ func (s BenchmarkSet) Environment() string {
 	return ""
 }
 
+// Environments returns the distinct non-empty environments found in the benchmark set, in order of appearance.
+func (s BenchmarkSet) Environments() []string {
+	var environments []string
+
+	for _, bench := range s.Set {
+		if env := bench.Environment; env != "" && !slices.Contains(environments, env) {
+			environments = append(environments, env)
+		}
+	}
+
+	return environments
+}
+
+// ForEnvironment returns the subset of the benchmark set collected in the given environment.
+func (s BenchmarkSet) ForEnvironment(env string) *BenchmarkSet {
+	benchmarks := make([]ParsedBenchmark, 0, len(s.Set))
+	for _, bench := range s.Set {
+		if bench.Environment == env {
+			benchmarks = append(benchmarks, bench)
+		}
+	}
+
+	set := NewBenchmarkSet(benchmarks)
+	set.RunDuration = s.RunDuration
+
+	return set
+}
+
 // SeriesFor extracts a single series for 1 metric, 1 version for the filtered category.
 //
 // The points of the series correspond to different context values.
@@ -315,33 +895,108 @@ func (s BenchmarkSet) SeriesFor(metric config.MetricName, version string, filter
 			Title: version, // the version gives the series name (e.g. to display as a legend)
 		},
 	}
-	var points []model.MetricPoint
+	index := s.index
+	if index == nil {
+		// the set has not been built with NewBenchmarkSet
+		index = indexBenchmarks(s.Set)
+	}
+
+	var groups [][]int // positions in the set, by series key
 
 	for _, wantFunction := range filter.Includes.Functions {
 		for _, wantContext := range filter.Includes.Contexts {
-			for _, bench := range s.Set {
-				if bench.Metric != metric || bench.Function != wantFunction || bench.Version != version || bench.Context != wantContext {
-					continue
-				}
+			key := model.SeriesKey{
+				Function: wantFunction,
+				Version:  version,
+				Context:  wantContext,
+				Metric:   metric,
+			}
+
+			if positions := index[key]; len(positions) > 0 {
+				groups = append(groups, positions)
+			}
+		}
+	}
+	series[0].Points = pointsOf(s.Set, groups)
+
+	return series
+}
+
+// SeriesForContext extracts a single series for 1 metric, 1 context for the filtered category.
+//
+// This is the pivoted version of [BenchmarkSet.SeriesFor]: the points of the series correspond to different versions.
+func (s BenchmarkSet) SeriesForContext(metric config.MetricName, context string, filter config.Category) []model.MetricSeries {
+	series := []model.MetricSeries{
+		{
+			SeriesKey: model.SeriesKey{
+				Context: context,
+				Metric:  metric,
+			},
+			Title: context, // the context gives the series name (e.g. to display as a legend)
+		},
+	}
+
+	index := s.index
+	if index == nil {
+		// the set has not been built with NewBenchmarkSet
+		index = indexBenchmarks(s.Set)
+	}
+
+	var groups [][]int // positions in the set, by series key
+
+	for _, wantFunction := range filter.Includes.Functions {
+		for _, wantVersion := range filter.Includes.Versions {
+			key := model.SeriesKey{
+				Function: wantFunction,
+				Version:  wantVersion,
+				Context:  context,
+				Metric:   metric,
+			}
 
-				points = append(points, model.MetricPoint{
-					SeriesKey: model.SeriesKey{
-						Function: bench.Function,
-						Version:  bench.Version,
-						Context:  bench.Context,
-						Metric:   bench.Metric,
-					},
-					Name:  bench.Function + " - " + bench.Version + " - " + bench.Context, // the point name (e.g. to display as a tooltip)
-					Value: bench.Value,
-				})
+			if positions := index[key]; len(positions) > 0 {
+				groups = append(groups, positions)
 			}
 		}
 	}
-	series[0].Points = points
+	series[0].Points = pointsOf(s.Set, groups)
 
 	return series
 }
 
+// pointsOf builds the data points of groups of benchmarks in the set, each group sharing the same series key.
+//
+// Points are allocated at once, and the point name (e.g. to display as a tooltip) is shared by all the points of a group.
+func pointsOf(benchmarks []ParsedBenchmark, groups [][]int) []model.MetricPoint {
+	var count int
+	for _, positions := range groups {
+		count += len(positions)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	points := make([]model.MetricPoint, 0, count)
+	for _, positions := range groups {
+		first := benchmarks[positions[0]]
+		name := first.Function + " - " + first.Version + " - " + first.Context
+
+		for _, i := range positions {
+			bench := benchmarks[i]
+			points = append(points, model.MetricPoint{
+				SeriesKey: bench.SeriesKey,
+				Name:      name,
+				Group:     bench.Group,
+				GroupID:   bench.GroupID,
+				Value:     bench.Value,
+				Aggregate: bench.Aggregate,
+				Origin:    bench.Origin,
+			})
+		}
+	}
+
+	return points
+}
+
 func stringDefault(in, def string) string {
 	if in == "" {
 		return def
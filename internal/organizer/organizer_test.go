@@ -1,11 +1,14 @@
 package organizer
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
 	"github.com/fredbi/benchviz/internal/parser"
 	"golang.org/x/tools/benchmark/parse"
 
@@ -82,7 +85,7 @@ func TestParseBenchmarkName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			parsed, ok := o.parseBenchmarkName(tt.benchName, tt.file, tt.env)
+			parsed, ok := o.parseBenchmarkName(tt.benchName, tt.file, "", tt.env)
 			require.Equal(t, tt.wantOk, ok, "parseBenchmarkName(%q) ok", tt.benchName)
 			if !ok {
 				return
@@ -105,6 +108,7 @@ func TestParseBenchmarkNameContextFallbackBug(t *testing.T) {
 	parsed, ok := o.parseBenchmarkName(
 		"BenchmarkGreater-16",       // no version/context in name
 		"bench_reflect_int_test.go", // file should match version=reflect, context=int
+		"",
 		"linux amd64",
 	)
 	require.True(t, ok, "expected parseBenchmarkName to succeed")
@@ -112,6 +116,34 @@ func TestParseBenchmarkNameContextFallbackBug(t *testing.T) {
 	assert.Equal(t, "int", parsed.Context, "context file fallback")
 }
 
+func TestParseBenchmarkNameLabelFallback(t *testing.T) {
+	cfg := mustLoadConfig(t, configWithFileFallback())
+	o := New(cfg)
+
+	t.Run("label as version ID", func(t *testing.T) {
+		parsed, ok := o.parseBenchmarkName("BenchmarkGreater-16", "old.txt", "generics", "")
+		require.True(t, ok)
+		assert.Equal(t, "generics", parsed.Version)
+	})
+
+	t.Run("file rule takes precedence over label", func(t *testing.T) {
+		parsed, ok := o.parseBenchmarkName("BenchmarkGreater-16", "bench_reflect_int_test.go", "generics", "")
+		require.True(t, ok)
+		assert.Equal(t, "reflect", parsed.Version)
+	})
+
+	t.Run("unknown label as a new version", func(t *testing.T) {
+		parsed, ok := o.parseBenchmarkName("BenchmarkGreater-16", "old.txt", "v1.2", "")
+		require.True(t, ok)
+		assert.Equal(t, "v1.2", parsed.Version)
+
+		version, ok := cfg.GetVersion("v1.2")
+		require.True(t, ok)
+		assert.Equal(t, "v1.2", version.Title)
+		assert.Contains(t, cfg.Categories[0].Includes.Versions, "v1.2")
+	})
+}
+
 func TestParseBenchmarks(t *testing.T) {
 	cfg := mustLoadConfig(t, genericsConfig())
 	o := New(cfg)
@@ -138,6 +170,63 @@ func TestParseBenchmarks(t *testing.T) {
 	assert.Equal(t, 4, metrics[config.MetricAllocsPerOp])
 }
 
+func TestParseBenchmarksIterations(t *testing.T) {
+	yamlContent := strings.Replace(genericsConfig(), "metrics:\n", "metrics:\n  - id: iterations\n", 1)
+	cfg := mustLoadConfig(t, yamlContent)
+	o := New(cfg)
+
+	benchSet, err := o.parseBenchmarks([]parser.Set{buildGenericsSet()})
+	require.NoError(t, err)
+
+	iterations := make(map[string]float64)
+	for _, b := range benchSet.Set {
+		if b.Metric == config.MetricIterations {
+			iterations[b.Version+"/"+b.Context] = b.Value
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"reflect/int":      5000000,
+		"generics/int":     150000000,
+		"reflect/float64":  4500000,
+		"generics/float64": 140000000,
+	}, iterations)
+}
+
+func TestParseBenchmarksMissingMetrics(t *testing.T) {
+	yamlContent := strings.Replace(genericsConfig(), "metrics:\n", "metrics:\n  - id: MBytesPerS\n", 1)
+	cfg := mustLoadConfig(t, yamlContent)
+	o := New(cfg)
+
+	set := buildGenericsSet()
+	throughput := set.Set["BenchmarkGreater/reflect/int-16"][0]
+	throughput.MBPerS = 120.5
+	throughput.Measured |= parse.MBPerS
+
+	benchSet, err := o.parseBenchmarks([]parser.Set{set})
+	require.NoError(t, err)
+
+	t.Run("should not emit metrics that were not reported", func(t *testing.T) {
+		var points []string
+		for _, b := range benchSet.Set {
+			if b.Metric == config.MetricMBPerS {
+				points = append(points, b.Version+"/"+b.Context)
+				assert.InDelta(t, 120.5, b.Value, 1e-9)
+			}
+		}
+		assert.Equal(t, []string{"reflect/int"}, points)
+	})
+
+	t.Run("should emit metrics reported with a zero value", func(t *testing.T) {
+		var allocs int
+		for _, b := range benchSet.Set {
+			if b.Metric == config.MetricAllocsPerOp {
+				allocs++
+			}
+		}
+		assert.Equal(t, 4, allocs)
+	})
+}
+
 func TestParseBenchmarksEmpty(t *testing.T) {
 	cfg := mustLoadConfig(t, genericsConfig())
 	o := New(cfg)
@@ -154,7 +243,7 @@ func TestParseBenchmarksSkipsUnmatched(t *testing.T) {
 	sets := []parser.Set{{
 		Set: parse.Set{
 			"BenchmarkUnknown-16": []*parse.Benchmark{
-				{Name: "BenchmarkUnknown-16", N: 1000, NsPerOp: 100},
+				{Name: "BenchmarkUnknown-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
 			},
 		},
 	}}
@@ -164,6 +253,132 @@ func TestParseBenchmarksSkipsUnmatched(t *testing.T) {
 	assert.Empty(t, benchSet.Set)
 }
 
+func TestParseBenchmarksFailedRun(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig())
+	failed := buildGenericsSet()
+	failed.Failures = []string{"FAIL\texample.com/pkg"}
+	sets := []parser.Set{failed}
+
+	t.Run("should warn and ingest", func(t *testing.T) {
+		o := New(cfg)
+		benchSet, err := o.parseBenchmarks(sets)
+		require.NoError(t, err)
+		assert.NotEmpty(t, benchSet.Set)
+	})
+
+	t.Run("should refuse in strict mode", func(t *testing.T) {
+		cfg.StrictLevel = config.StrictAll
+		defer func() { cfg.StrictLevel = config.StrictNone }()
+
+		o := New(cfg)
+		_, err := o.parseBenchmarks(sets)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "benchmark run failed")
+	})
+}
+
+func TestParseBenchmarksConflicts(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig())
+
+	other := buildGenericsSet()
+	other.File = "other.json"
+	other.Set["BenchmarkGreater/reflect/int-16"][0] = &parse.Benchmark{
+		Name: "BenchmarkGreater/reflect/int-16", N: 5000000, NsPerOp: 2453.0, AllocsPerOp: 2,
+		Measured: parse.NsPerOp | parse.AllocsPerOp | parse.AllocedBytesPerOp,
+	}
+	sets := []parser.Set{buildGenericsSet(), other}
+
+	t.Run("should warn and ingest", func(t *testing.T) {
+		o := New(cfg)
+		benchSet, err := o.parseBenchmarks(sets)
+		require.NoError(t, err)
+		assert.Len(t, benchSet.Set, 16)
+	})
+
+	t.Run("should fail in strict mode", func(t *testing.T) {
+		cfg.StrictLevel = config.StrictAll
+		defer func() { cfg.StrictLevel = config.StrictNone }()
+
+		o := New(cfg)
+		_, err := o.parseBenchmarks(sets)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "conflicting values")
+		assert.Contains(t, err.Error(), "other.json")
+	})
+
+	t.Run("should accept close values in strict mode", func(t *testing.T) {
+		cfg.StrictLevel = config.StrictAll
+		defer func() { cfg.StrictLevel = config.StrictNone }()
+
+		similar := buildGenericsSet()
+		similar.File = "similar.json"
+
+		o := New(cfg)
+		_, err := o.parseBenchmarks([]parser.Set{buildGenericsSet(), similar})
+		require.NoError(t, err)
+	})
+}
+
+func TestIsConflicting(t *testing.T) {
+	assert.False(t, isConflicting(100, 150))
+	assert.False(t, isConflicting(100, 200))
+	assert.True(t, isConflicting(100, 201))
+	assert.True(t, isConflicting(300, 100))
+	assert.False(t, isConflicting(0, 0))
+	assert.True(t, isConflicting(0, 1))
+}
+
+func TestParseBenchmarksDeterministicOrder(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig())
+	o := New(cfg)
+
+	sets := []parser.Set{buildGenericsSet()}
+	first, err := o.parseBenchmarks(sets)
+	require.NoError(t, err)
+
+	for range 10 {
+		again, err := o.parseBenchmarks(sets)
+		require.NoError(t, err)
+		require.Equal(t, first.Set, again.Set)
+	}
+
+	keys := make([]string, 0, len(first.Set))
+	for _, bench := range first.Set {
+		keys = append(keys, bench.Function+"/"+bench.Context+"/"+bench.Version+"/"+bench.Metric.String())
+	}
+	assert.Equal(t, []string{
+		"greater/float64/generics/nsPerOp",
+		"greater/float64/generics/allocsPerOp",
+		"greater/float64/reflect/nsPerOp",
+		"greater/float64/reflect/allocsPerOp",
+		"greater/int/generics/nsPerOp",
+		"greater/int/generics/allocsPerOp",
+		"greater/int/reflect/nsPerOp",
+		"greater/int/reflect/allocsPerOp",
+	}, keys)
+}
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"size-2", "size-10", -1},
+		{"size-10", "size-2", 1},
+		{"size-10", "size-10", 0},
+		{"size-010", "size-10", 0},
+		{"a", "b", -1},
+		{"abc", "ab", 1},
+		{"", "", 0},
+		{"v1.2.10", "v1.2.9", 1},
+		{"int", "int64", -1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, naturalCompare(tt.a, tt.b), "naturalCompare(%q, %q)", tt.a, tt.b)
+	}
+}
+
 func TestSeriesFor(t *testing.T) {
 	cfg := mustLoadConfig(t, genericsConfig())
 	o := New(cfg)
@@ -190,6 +405,23 @@ func TestSeriesFor(t *testing.T) {
 	}
 }
 
+func TestSeriesForWithoutIndex(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig())
+	o := New(cfg)
+
+	sets := []parser.Set{buildGenericsSet()}
+	benchSet, err := o.parseBenchmarks(sets)
+	require.NoError(t, err)
+
+	category := cfg.Categories[0]
+	unindexed := BenchmarkSet{Set: benchSet.Set}
+
+	assert.Equal(t,
+		benchSet.SeriesFor(config.MetricNsPerOp, "reflect", category),
+		unindexed.SeriesFor(config.MetricNsPerOp, "reflect", category),
+	)
+}
+
 func TestSeriesForNoMatch(t *testing.T) {
 	cfg := mustLoadConfig(t, genericsConfig())
 	o := New(cfg)
@@ -268,6 +500,11 @@ func TestScenarizeEmptySets(t *testing.T) {
 	scenario, err := o.Scenarize(nil)
 	require.NoError(t, err)
 	require.NotNil(t, scenario)
+	t.Run("should fail on an empty set in strict mode", func(t *testing.T) {
+		_, err := New(cfg, WithStrictLevel(config.StrictAll)).Scenarize(nil)
+		require.ErrorContains(t, err, "strict requirement not met: empty benchmark set")
+		assert.NotContains(t, err.Error(), "%q")
+	})
 }
 
 func TestDefaultString(t *testing.T) {
@@ -289,13 +526,13 @@ func TestParseBenchmarkNameEnvironment(t *testing.T) {
 	cfg := mustLoadConfig(t, genericsConfig())
 	o := New(cfg)
 
-	parsed, ok := o.parseBenchmarkName("BenchmarkGreater/reflect/int-16", "file.txt", "linux amd64")
+	parsed, ok := o.parseBenchmarkName("BenchmarkGreater/reflect/int-16", "file.txt", "", "linux amd64")
 	require.True(t, ok)
 	assert.Equal(t, "linux amd64", parsed.Environment)
 
 	// Config environment takes precedence
 	cfg.Environment = "override-env"
-	parsed, ok = o.parseBenchmarkName("BenchmarkGreater/reflect/int-16", "file.txt", "linux amd64")
+	parsed, ok = o.parseBenchmarkName("BenchmarkGreater/reflect/int-16", "file.txt", "", "linux amd64")
 	require.True(t, ok)
 	assert.Equal(t, "override-env", parsed.Environment)
 }
@@ -350,9 +587,566 @@ func TestMultipleVersionSeries(t *testing.T) {
 	}
 }
 
+func TestPivotContexts(t *testing.T) {
+	cfg := mustLoadConfig(t, strings.Replace(genericsConfig(), "    title: Comparisons\n", "    title: Comparisons\n    pivot: contexts\n", 1))
+	o := New(cfg)
+
+	scenario, err := o.Scenarize([]parser.Set{buildGenericsSet()})
+	require.NoError(t, err)
+	require.Len(t, scenario.Categories, 1)
+
+	category := scenario.Categories[0]
+	assert.Equal(t, config.PivotContexts, category.Pivot)
+	// 2 metrics x 2 contexts
+	require.Len(t, category.Data, 4)
+
+	data := category.Data[0]
+	assert.Equal(t, "int", data.Context.ID)
+	assert.Empty(t, data.Version.ID)
+	require.Len(t, data.Series, 1)
+	assert.Equal(t, "Int", data.Series[0].Title) // auto-generated context title
+
+	// points are versions, in the order of the category includes
+	require.Len(t, data.Series[0].Points, 2)
+	assert.Equal(t, "Reflect", data.Series[0].Points[0].Label)
+	assert.Equal(t, "Generics", data.Series[0].Points[1].Label)
+	assert.InDelta(t, 245.3, data.Series[0].Points[0].Value, 1e-9)
+
+	assert.Equal(t, []string{"Reflect", "Generics"}, category.Labels())
+}
+
+func TestCompareGoVersions(t *testing.T) {
+	const yaml = `
+compareGoVersions: true
+metrics:
+  - id: nsPerOp
+functions:
+  - id: greater
+    Match: 'Greater'
+contexts:
+  - id: int
+    Match: '/int'
+versions:
+  - id: old
+    title: Go 1.22
+    Match: 'go1\.22'
+  - id: go1.23
+categories:
+  - id: toolchains
+    includes:
+      metrics: [nsPerOp]
+`
+	set := func(goVersion string, value float64) parser.Set {
+		return parser.Set{
+			Set: parse.Set{
+				"BenchmarkGreater/int-16": []*parse.Benchmark{
+					{Name: "BenchmarkGreater/int-16", N: 1000, NsPerOp: value, Measured: parse.NsPerOp},
+				},
+			},
+			File:      goVersion + ".txt",
+			GoVersion: goVersion,
+		}
+	}
+	sets := []parser.Set{set("go1.22.5", 100), set("go1.23", 80), set("go1.24.0", 60)}
+
+	t.Run("should use Go versions as versions", func(t *testing.T) {
+		scenario, err := New(mustLoadConfig(t, yaml)).Scenarize(sets)
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		values := make(map[string]float64)
+		for _, data := range scenario.Categories[0].Data {
+			for _, series := range data.Series {
+				for _, point := range series.Points {
+					values[point.Version] = point.Value
+				}
+			}
+		}
+		assert.Equal(t, map[string]float64{"old": 100, "go1.23": 80}, values)
+	})
+
+	t.Run("should keep the version resolved otherwise when no version matches the Go version", func(t *testing.T) {
+		labeled := set("go1.24.0", 60)
+		labeled.Label = "go1.23"
+
+		set, err := New(mustLoadConfig(t, yaml)).parseBenchmarks([]parser.Set{labeled})
+		require.NoError(t, err)
+		require.NotEmpty(t, set.Set)
+		for _, bench := range set.Set {
+			assert.EqualT(t, "go1.23", bench.Version)
+		}
+	})
+
+	t.Run("should ignore Go versions by default", func(t *testing.T) {
+		cfg := mustLoadConfig(t, strings.Replace(yaml, "compareGoVersions: true", "", 1))
+
+		set, err := New(cfg).parseBenchmarks(sets[:1])
+		require.NoError(t, err)
+		require.NotEmpty(t, set.Set)
+		for _, bench := range set.Set {
+			assert.Empty(t, bench.Version)
+		}
+	})
+}
+
+func TestLabelTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		template string
+		pivot    bool
+		expected []string
+	}{
+		{template: "", expected: []string{"Int", "Float64"}},
+		{template: `'{function}\n{context}'`, expected: []string{"Greater\nInt", "Greater\nFloat64"}},
+		{template: `"{function}\n{context}"`, expected: []string{"Greater\nInt", "Greater\nFloat64"}},
+		{template: "'{version} {context}'", pivot: true, expected: []string{"Reflect Int", "Generics Int"}},
+	} {
+		t.Run(tt.template, func(t *testing.T) {
+			category := "    title: Comparisons\n"
+			if tt.template != "" {
+				category += "    label: " + tt.template + "\n"
+			}
+			if tt.pivot {
+				category += "    pivot: contexts\n"
+			}
+			cfg := mustLoadConfig(t, strings.Replace(genericsConfig(), "    title: Comparisons\n", category, 1))
+
+			scenario, err := New(cfg).Scenarize([]parser.Set{buildGenericsSet()})
+			require.NoError(t, err)
+			require.Len(t, scenario.Categories, 1)
+
+			var labels []string
+			for _, point := range scenario.Categories[0].Data[0].Series[0].Points {
+				labels = append(labels, point.Label)
+			}
+			assert.Equal(t, tt.expected, labels)
+		})
+	}
+}
+
+func TestFunctionLabel(t *testing.T) {
+	yamlContent := strings.Replace(genericsConfig(),
+		"    Match: 'GreaterT?'\n",
+		"    Match: '(?P<op>Greater)T?/(\\w+)/'\n    label: '{op} {2} ({context})'\n", 1)
+	yamlContent = strings.Replace(yamlContent, "    title: Comparisons\n", "    title: Comparisons\n    label: '{function}'\n", 1)
+	cfg := mustLoadConfig(t, yamlContent)
+
+	scenario, err := New(cfg).Scenarize([]parser.Set{buildGenericsSet()})
+	require.NoError(t, err)
+	require.Len(t, scenario.Categories, 1)
+
+	// the label of the function overrides the label of the category
+	var labels []string
+	for _, point := range scenario.Categories[0].Data[0].Series[0].Points {
+		labels = append(labels, point.Label)
+	}
+	assert.Equal(t, []string{"Greater reflect (Int)", "Greater reflect (Float64)"}, labels)
+}
+
+func TestLabelAbbreviations(t *testing.T) {
+	category := "    title: Comparisons\n" +
+		"    label: '{function} {context}'\n" +
+		"    axisLabels:\n" +
+		"      width: 10\n" +
+		"      abbreviations:\n" +
+		"        - match: 'Greater'\n" +
+		"          replace: 'Gt'\n" +
+		"        - match: 'Float(\\d+)'\n" +
+		"          replace: 'F${1}'\n"
+	cfg := mustLoadConfig(t, strings.Replace(genericsConfig(), "    title: Comparisons\n", category, 1))
+
+	scenario, err := New(cfg).Scenarize([]parser.Set{buildGenericsSet()})
+	require.NoError(t, err)
+	require.Len(t, scenario.Categories, 1)
+
+	// abbreviations only apply to the axis: labels remain complete in tooltips and annotations
+	labels := scenario.Categories[0].Labels()
+	assert.Equal(t, []string{"Greater Int", "Greater Float64"}, labels)
+	assert.Equal(t, []string{"Gt Int", "Gt F64"}, []string{
+		scenario.Categories[0].AxisLabels.Abbreviate(labels[0]),
+		scenario.Categories[0].AxisLabels.Abbreviate(labels[1]),
+	})
+	assert.EqualT(t, 10, scenario.Categories[0].AxisLabels.Width)
+	assert.Equal(t, config.LabelOverflowTruncate, scenario.Categories[0].AxisLabels.Overflow)
+}
+
+func TestSeriesTitle(t *testing.T) {
+	category := "    title: Comparisons\n" +
+		"    seriesTitle: '{version} / {metric}'\n"
+	cfg := mustLoadConfig(t, strings.Replace(genericsConfig(), "    title: Comparisons\n", category, 1))
+
+	scenario, err := New(cfg).Scenarize([]parser.Set{buildGenericsSet()})
+	require.NoError(t, err)
+	require.Len(t, scenario.Categories, 1)
+
+	var titles []string
+	for _, data := range scenario.Categories[0].Data {
+		titles = append(titles, data.Series[0].Title)
+	}
+	assert.Equal(t, []string{
+		"Reflect / Benchmark Timings", "Generics / Benchmark Timings",
+		"Reflect / Benchmark Allocations", "Generics / Benchmark Allocations",
+	}, titles)
+}
+
+func TestCustomMetrics(t *testing.T) {
+	cfg := mustLoadConfig(t, `
+metrics:
+  - id: nsPerOp
+  - id: peakMB
+    title: Peak Memory
+    axis: MB
+    unit: peak-MB
+functions:
+  - id: greater
+    match: 'Greater'
+versions:
+  - id: reflect
+    match: '/reflect/'
+  - id: generics
+    match: '/generic/'
+contexts:
+  - id: int
+    match: '/int'
+categories:
+  - id: memory
+    includes:
+      functions: [greater]
+      versions: [reflect, generics]
+      contexts: [int]
+      metrics: [peakMB]
+`)
+	reflect := &parse.Benchmark{Name: "BenchmarkGreater/reflect/int-16", N: 1000, NsPerOp: 100, Ord: 0, Measured: parse.NsPerOp}
+	generic := &parse.Benchmark{Name: "BenchmarkGreater/generic/int-16", N: 1000, NsPerOp: 10, Ord: 1, Measured: parse.NsPerOp}
+	sets := []parser.Set{{
+		File: "bench.txt",
+		Set: parse.Set{
+			reflect.Name: []*parse.Benchmark{reflect},
+			generic.Name: []*parse.Benchmark{generic},
+		},
+		Custom: map[int]map[string]float64{
+			reflect.Ord: {"peak-MB": 12.5},
+		},
+	}}
+
+	scenario, err := New(cfg).Scenarize(sets)
+	require.NoError(t, err)
+	require.Len(t, scenario.Categories, 1)
+
+	var values []float64
+	for _, data := range scenario.Categories[0].Data {
+		assert.Equal(t, config.MetricName("peakMB"), data.Metric.ID)
+		for _, series := range data.Series {
+			for _, point := range series.Points {
+				values = append(values, point.Value)
+			}
+		}
+	}
+	// the benchmark that didn't report the custom metric has no data point
+	assert.Equal(t, []float64{12.5}, values)
+}
+
+func TestGroupEnvironments(t *testing.T) {
+	setA := buildGenericsSet()
+	setA.File = "machine-a.json"
+	setA.Environment = "linux amd64 cpu: CPU A"
+	setB := buildGenericsSet()
+	setB.File = "machine-b.json"
+	setB.Environment = "linux arm64 cpu: CPU B"
+	sets := []parser.Set{setA, setB}
+
+	t.Run("flattened by default", func(t *testing.T) {
+		cfg := mustLoadConfig(t, genericsConfig())
+
+		scenario, err := New(cfg).Scenarize(sets)
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		category := scenario.Categories[0]
+		assert.Equal(t, "linux amd64 cpu: CPU A", category.Environment)
+		require.Len(t, category.Data, 4) // 2 metrics x 2 versions
+		assert.Len(t, category.Data[0].Series[0].Points, 4)
+	})
+
+	t.Run("flattened environments fail when strict", func(t *testing.T) {
+		cfg := mustLoadConfig(t, genericsConfig())
+
+		_, err := New(cfg, WithStrictLevel(config.StrictAll)).Scenarize(sets)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "incompatible environments")
+		assert.ErrorContains(t, err, "goarch: amd64 != arm64")
+		assert.ErrorContains(t, err, "cpu: CPU A != CPU B")
+	})
+
+	t.Run("grouped environments don't fail when strict", func(t *testing.T) {
+		cfg := mustLoadConfig(t, genericsConfig()+"groupEnvironments: series\n")
+
+		_, err := New(cfg, WithStrictLevel(config.StrictAll)).Scenarize(sets)
+		require.NoError(t, err)
+	})
+
+	t.Run("environments differing by the version of Go only are compatible", func(t *testing.T) {
+		cfg := mustLoadConfig(t, genericsConfig())
+		setC, setD := setA, setA
+		setC.Environment = "go1.22.0 linux amd64 cpu: CPU A"
+		setD.Environment = "go1.23.4 linux amd64 cpu: CPU A"
+
+		_, err := New(cfg, WithStrictLevel(config.StrictAll)).Scenarize([]parser.Set{setC, setD})
+		require.NoError(t, err)
+	})
+
+	t.Run("series per environment", func(t *testing.T) {
+		cfg := mustLoadConfig(t, genericsConfig()+"groupEnvironments: series\n")
+
+		scenario, err := New(cfg).Scenarize(sets)
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		category := scenario.Categories[0]
+		assert.Equal(t, "linux amd64 cpu: CPU A; linux arm64 cpu: CPU B", category.Environment)
+		require.Len(t, category.Data, 8) // 2 environments x 2 metrics x 2 versions
+
+		first := category.Data[0].Series[0]
+		assert.Equal(t, "Reflect (linux amd64 cpu: CPU A)", first.Title)
+		assert.Len(t, first.Points, 2)
+
+		last := category.Data[7].Series[0]
+		assert.Equal(t, "Generics (linux arm64 cpu: CPU B)", last.Title)
+		assert.Len(t, last.Points, 2)
+
+		assert.Len(t, category.Labels(), 2)
+	})
+
+	t.Run("series per environment with a title template", func(t *testing.T) {
+		category := "    title: Comparisons\n" +
+			"    seriesTitle: '{version} on {environment}'\n"
+		cfg := mustLoadConfig(t, strings.Replace(genericsConfig(), "    title: Comparisons\n", category, 1)+"groupEnvironments: series\n")
+
+		scenario, err := New(cfg).Scenarize(sets)
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		grouped := scenario.Categories[0]
+		require.Len(t, grouped.Data, 8)
+		assert.Equal(t, "Reflect on linux amd64 cpu: CPU A", grouped.Data[0].Series[0].Title)
+		assert.Equal(t, "Generics on linux arm64 cpu: CPU B", grouped.Data[7].Series[0].Title)
+	})
+
+	t.Run("chart per environment", func(t *testing.T) {
+		cfg := mustLoadConfig(t, genericsConfig()+"groupEnvironments: charts\n")
+
+		scenario, err := New(cfg).Scenarize(sets)
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 2)
+
+		assert.Equal(t, "comparisons-1", scenario.Categories[0].ID)
+		assert.Equal(t, "linux amd64 cpu: CPU A", scenario.Categories[0].Environment)
+		assert.Equal(t, "comparisons-2", scenario.Categories[1].ID)
+		assert.Equal(t, "linux arm64 cpu: CPU B", scenario.Categories[1].Environment)
+
+		for _, category := range scenario.Categories {
+			require.Len(t, category.Data, 4)
+			assert.Len(t, category.Data[0].Series[0].Points, 2)
+		}
+	})
+}
+
+func TestNormalizeEnvironments(t *testing.T) {
+	setA := buildGenericsSet()
+	setA.File = "run-1.json"
+	setA.Environment = "linux amd64 cpu: Intel(R) Core(TM) i7-9750H CPU @ 2.60GHz"
+	setB := buildGenericsSet()
+	setB.File = "run-2.json"
+	setB.Environment = "linux amd64 cpu: Intel(R) Core(TM) i7-9750H CPU"
+
+	cfg := mustLoadConfig(t, genericsConfig()+`groupEnvironments: series
+environmentRules:
+  - match: 'Intel\(R\) Core\(TM\) (\S+) CPU.*$'
+    replace: '${1}'
+`)
+
+	scenario, err := New(cfg, WithStrictLevel(config.StrictAll)).Scenarize([]parser.Set{setA, setB})
+	require.NoError(t, err)
+	require.Len(t, scenario.Categories, 1)
+
+	// both variations collapse into a single environment: no grouping is needed
+	category := scenario.Categories[0]
+	assert.EqualT(t, "linux amd64 cpu: i7-9750H", category.Environment)
+	require.Len(t, category.Data, 4)
+	assert.Equal(t, "Reflect", category.Data[0].Series[0].Title)
+}
+
+func TestNameRules(t *testing.T) {
+	set := parser.Set{File: "bench.txt", Set: parse.Set{}}
+	for _, name := range []string{"BenchmarkEncode/size=16/json-8", "BenchmarkEncode/size=1024/json-8"} {
+		set.Set[name] = []*parse.Benchmark{{Name: name, N: 1000, NsPerOp: 100, Measured: parse.NsPerOp}}
+	}
+
+	cfg := mustLoadConfig(t, `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: encode
+    match: '^BenchmarkEncode/json-\d+$'
+contexts:
+  - id: small
+    match: '/size=16/'
+  - id: large
+    match: '/size=1024/'
+categories:
+  - id: encoding
+    includes:
+      functions: [encode]
+      metrics: [nsPerOp]
+nameRules:
+  - match: '/size=\d+'
+    replace: ''
+`)
+
+	benchSet, err := New(cfg, WithStrictLevel(config.StrictAll)).parseBenchmarks([]parser.Set{set})
+	require.NoError(t, err)
+	require.Len(t, benchSet.Set, 2)
+
+	// parameters are stripped to match functions, but still resolve contexts
+	contexts := make([]string, 0, len(benchSet.Set))
+	for _, b := range benchSet.Set {
+		assert.EqualT(t, "encode", b.Function)
+		contexts = append(contexts, b.Context)
+	}
+	assert.ElementsMatch(t, []string{"small", "large"}, contexts)
+}
+
+func TestContextParams(t *testing.T) {
+	set := parser.Set{File: "bench.txt", Set: parse.Set{}}
+	for _, name := range []string{"BenchmarkEncode/size=16/workers=4-8", "BenchmarkEncode/size=1024/workers=4-8"} {
+		set.Set[name] = []*parse.Benchmark{{Name: name, N: 1000, NsPerOp: 100, Measured: parse.NsPerOp}}
+	}
+
+	cfg := mustLoadConfig(t, `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: encode
+    match: '^BenchmarkEncode'
+contexts:
+  - id: small
+    param: size=16
+  - id: large
+    param: size=1024
+categories:
+  - id: encoding
+    xAxis: log
+    includes:
+      functions: [encode]
+      metrics: [nsPerOp]
+`)
+
+	benchSet, err := New(cfg, WithStrictLevel(config.StrictAll)).parseBenchmarks([]parser.Set{set})
+	require.NoError(t, err)
+	require.Len(t, benchSet.Set, 2)
+
+	contexts := make([]string, 0, len(benchSet.Set))
+	for _, b := range benchSet.Set {
+		contexts = append(contexts, b.Context)
+		assert.EqualT(t, "4", b.Params["workers"])
+	}
+	assert.ElementsMatch(t, []string{"small", "large"}, contexts)
+
+	large, ok := cfg.GetContext("large")
+	require.True(t, ok)
+	require.NotNil(t, large.Value)
+	assert.InDelta(t, 1024.0, *large.Value, 1e-9)
+}
+
+func TestFunctionGroups(t *testing.T) {
+	set := parser.Set{
+		Set: parse.Set{
+			"BenchmarkGreater/reflect/int-16":  []*parse.Benchmark{{Name: "BenchmarkGreater/reflect/int-16", N: 1000, NsPerOp: 245.3, Measured: parse.NsPerOp}},
+			"BenchmarkNegative/reflect/int-16": []*parse.Benchmark{{Name: "BenchmarkNegative/reflect/int-16", N: 1000, NsPerOp: 12.5, Measured: parse.NsPerOp}},
+			"BenchmarkLess/reflect/int-16":     []*parse.Benchmark{{Name: "BenchmarkLess/reflect/int-16", N: 1000, NsPerOp: 240.1, Measured: parse.NsPerOp}},
+		},
+		File: "test.json",
+	}
+
+	cfg := mustLoadConfig(t, `
+metrics:
+  - id: nsPerOp
+functions:
+  - id: greater
+    Match: 'Greater'
+  - id: negative
+    Match: 'Negative'
+  - id: less
+    Match: 'Less'
+functionGroups:
+  - id: comparisons
+    Match: 'Greater|Less'
+  - id: signs
+    title: Signs of numbers
+    Match: 'Negative'
+contexts:
+  - id: int
+    Match: '/int'
+versions:
+  - id: reflect
+    Match: '/reflect/'
+categories:
+  - id: assertions
+    includes:
+      functions: [greater, negative, less]
+      metrics: [nsPerOp]
+`)
+
+	scenario, err := New(cfg).Scenarize([]parser.Set{set})
+	require.NoError(t, err)
+	require.Len(t, scenario.Categories, 1)
+
+	category := scenario.Categories[0]
+	points := category.Data[0].Series[0].Points
+	require.Len(t, points, 3)
+
+	// the functions of a group are contiguous, in the order of groups
+	var functions []string
+	for _, point := range points {
+		functions = append(functions, point.Function)
+	}
+	assert.Equal(t, []string{"greater", "less", "negative"}, functions)
+
+	assert.Equal(t, []model.Group{
+		{ID: "comparisons", Title: "Comparisons", From: 0, To: 1},
+		{ID: "signs", Title: "Signs of numbers", From: 2, To: 2},
+	}, category.Groups())
+
+	t.Run("should tell apart groups with the same title", func(t *testing.T) {
+		sameTitles := *cfg
+		sameTitles.FunctionGroups = []config.FunctionGroup{
+			{Object: config.Object{ID: "lesser", Title: "Comparisons", Match: "Less"}},
+			{Object: config.Object{ID: "negatives", Title: "Signs of numbers", Match: "Negative"}},
+			{Object: config.Object{ID: "greater", Title: "Comparisons", Match: "Greater"}},
+		}
+		require.NoError(t, sameTitles.Validate())
+
+		scenario, err := New(&sameTitles).Scenarize([]parser.Set{set})
+		require.NoError(t, err)
+		require.Len(t, scenario.Categories, 1)
+
+		category := scenario.Categories[0]
+		var functions []string
+		for _, point := range category.Data[0].Series[0].Points {
+			functions = append(functions, point.Function)
+		}
+		assert.Equal(t, []string{"less", "negative", "greater"}, functions)
+
+		assert.Equal(t, []model.Group{
+			{ID: "lesser", Title: "Comparisons", From: 0, To: 0},
+			{ID: "negatives", Title: "Signs of numbers", From: 1, To: 1},
+			{ID: "greater", Title: "Comparisons", From: 2, To: 2},
+		}, category.Groups())
+	})
+}
+
 // helpers
 
-func mustLoadConfig(t *testing.T, yamlContent string) *config.Config {
+func mustLoadConfig(t testing.TB, yamlContent string) *config.Config {
 	t.Helper()
 	dir := t.TempDir()
 	file := filepath.Join(dir, "config.yaml")
@@ -366,16 +1160,16 @@ func buildGenericsSet() parser.Set {
 	return parser.Set{
 		Set: parse.Set{
 			"BenchmarkGreater/reflect/int-16": []*parse.Benchmark{
-				{Name: "BenchmarkGreater/reflect/int-16", N: 5000000, NsPerOp: 245.3, AllocedBytesPerOp: 64, AllocsPerOp: 2},
+				{Name: "BenchmarkGreater/reflect/int-16", N: 5000000, NsPerOp: 245.3, AllocedBytesPerOp: 64, AllocsPerOp: 2, Measured: parse.NsPerOp | parse.AllocsPerOp | parse.AllocedBytesPerOp},
 			},
 			"BenchmarkGreater/generic/int-16": []*parse.Benchmark{
-				{Name: "BenchmarkGreater/generic/int-16", N: 150000000, NsPerOp: 7.89, AllocedBytesPerOp: 0, AllocsPerOp: 0},
+				{Name: "BenchmarkGreater/generic/int-16", N: 150000000, NsPerOp: 7.89, AllocedBytesPerOp: 0, AllocsPerOp: 0, Measured: parse.NsPerOp | parse.AllocsPerOp | parse.AllocedBytesPerOp},
 			},
 			"BenchmarkGreater/reflect/float64-16": []*parse.Benchmark{
-				{Name: "BenchmarkGreater/reflect/float64-16", N: 4500000, NsPerOp: 267.8, AllocedBytesPerOp: 64, AllocsPerOp: 2},
+				{Name: "BenchmarkGreater/reflect/float64-16", N: 4500000, NsPerOp: 267.8, AllocedBytesPerOp: 64, AllocsPerOp: 2, Measured: parse.NsPerOp | parse.AllocsPerOp | parse.AllocedBytesPerOp},
 			},
 			"BenchmarkGreater/generic/float64-16": []*parse.Benchmark{
-				{Name: "BenchmarkGreater/generic/float64-16", N: 140000000, NsPerOp: 8.12, AllocedBytesPerOp: 0, AllocsPerOp: 0},
+				{Name: "BenchmarkGreater/generic/float64-16", N: 140000000, NsPerOp: 8.12, AllocedBytesPerOp: 0, AllocsPerOp: 0, Measured: parse.NsPerOp | parse.AllocsPerOp | parse.AllocedBytesPerOp},
 			},
 		},
 		File:        "test.json",
@@ -478,3 +1272,73 @@ files:
         Match: '_generics_'
 `
 }
+
+func BenchmarkSeriesFor(b *testing.B) {
+	const (
+		functions = 50
+		contexts  = 20
+		versions  = 4
+	)
+
+	category := config.Category{ID: "bench"}
+	var benchmarks []ParsedBenchmark
+	for f := range functions {
+		function := fmt.Sprintf("function-%d", f)
+		category.Includes.Functions = append(category.Includes.Functions, function)
+		for c := range contexts {
+			context := fmt.Sprintf("context-%d", c)
+			if f == 0 {
+				category.Includes.Contexts = append(category.Includes.Contexts, context)
+			}
+			for v := range versions {
+				bench := ParsedBenchmark{}
+				bench.SeriesKey = model.SeriesKey{
+					Function: function,
+					Version:  fmt.Sprintf("version-%d", v),
+					Context:  context,
+					Metric:   config.MetricNsPerOp,
+				}
+				bench.Value = float64(f*c + v)
+				benchmarks = append(benchmarks, bench)
+			}
+		}
+	}
+	set := NewBenchmarkSet(benchmarks)
+
+	b.Run("with index", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for v := range versions {
+				_ = set.SeriesFor(config.MetricNsPerOp, fmt.Sprintf("version-%d", v), category)
+			}
+		}
+	})
+
+	b.Run("with linear scan", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for v := range versions {
+				_ = seriesForLinearScan(set, config.MetricNsPerOp, fmt.Sprintf("version-%d", v), category)
+			}
+		}
+	})
+}
+
+// seriesForLinearScan is the reference implementation of [BenchmarkSet.SeriesFor], without index.
+func seriesForLinearScan(s *BenchmarkSet, metric config.MetricName, version string, filter config.Category) []model.MetricPoint {
+	var points []model.MetricPoint
+
+	for _, wantFunction := range filter.Includes.Functions {
+		for _, wantContext := range filter.Includes.Contexts {
+			for _, bench := range s.Set {
+				if bench.Metric != metric || bench.Function != wantFunction || bench.Version != version || bench.Context != wantContext {
+					continue
+				}
+
+				points = append(points, model.MetricPoint{SeriesKey: bench.SeriesKey, Value: bench.Value})
+			}
+		}
+	}
+
+	return points
+}
@@ -0,0 +1,141 @@
+package organizer
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/parser"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+// Dimensions of the stress input: 50 functions x 20 contexts x 4 versions, run 50 times each,
+// i.e. 200k benchmarks.
+const (
+	stressFunctions = 50
+	stressContexts  = 20
+	stressVersions  = 4
+	stressCount     = 50
+	stressTotal     = stressFunctions * stressContexts * stressVersions * stressCount
+)
+
+// maxAllocsPerBenchmark is the maximum number of allocations to organize a single benchmark,
+// all metrics included.
+//
+// It guards against regressions in memory usage when ingesting very large result sets
+// (about 3.3 allocations per benchmark at the time of writing). Allocations are counted rather than
+// bytes, since the number of bytes allocated depends on the build, e.g. it is much larger with -race.
+const maxAllocsPerBenchmark = 6
+
+func TestStressScenarize(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test skipped in short mode")
+	}
+
+	cfg := mustLoadConfig(t, stressConfig())
+	sets := stressSets(t)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	o := New(cfg, WithLogger(slog.New(slog.DiscardHandler)))
+	scenario, err := o.Scenarize(sets)
+	require.NoError(t, err)
+
+	runtime.ReadMemStats(&after)
+
+	require.Len(t, scenario.Categories, 1)
+	var points int
+	for _, data := range scenario.Categories[0].Data {
+		for _, series := range data.Series {
+			points += len(series.Points)
+		}
+	}
+	assert.EqualT(t, stressTotal*2, points) // one point per benchmark, per metric
+
+	allocs := float64(after.Mallocs-before.Mallocs) / stressTotal
+	t.Logf("organized %d benchmarks: %d bytes allocated, %.2f allocations per benchmark",
+		stressTotal, after.TotalAlloc-before.TotalAlloc, allocs,
+	)
+	assert.LessOrEqual(t, allocs, float64(maxAllocsPerBenchmark))
+}
+
+func BenchmarkScenarize(b *testing.B) {
+	cfg := mustLoadConfig(b, stressConfig())
+	sets := stressSets(b)
+	o := New(cfg, WithLogger(slog.New(slog.DiscardHandler)))
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := o.Scenarize(sets); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// stressSets parses a generated input with [stressTotal] benchmarks, in the text format of go test.
+func stressSets(tb testing.TB) []parser.Set {
+	tb.Helper()
+
+	var input strings.Builder
+	input.WriteString("goos: linux\ngoarch: amd64\n")
+	for range stressCount {
+		for f := range stressFunctions {
+			for c := range stressContexts {
+				for v := range stressVersions {
+					fmt.Fprintf(&input, "BenchmarkF%d/v%d/c%d-16   1000   %d ns/op   64 B/op   2 allocs/op\n", f, v, c, 100+f+c+v)
+				}
+			}
+		}
+	}
+
+	p := parser.New(nil, parser.WithLogger(slog.New(slog.DiscardHandler)))
+	require.NoError(tb, p.ParseReader("stress.txt", strings.NewReader(input.String())))
+
+	return p.Sets()
+}
+
+func stressConfig() string {
+	var b strings.Builder
+
+	b.WriteString(`
+name: stress
+metrics:
+  - id: nsPerOp
+    title: Timings
+    axis: 'ns/op'
+  - id: allocsPerOp
+    title: Allocations
+    axis: 'allocs/op'
+functions:
+`)
+	for f := range stressFunctions {
+		fmt.Fprintf(&b, "  - id: f%d\n    Match: '^BenchmarkF%d/'\n", f, f)
+	}
+	b.WriteString("contexts:\n")
+	for c := range stressContexts {
+		fmt.Fprintf(&b, "  - id: c%d\n    Match: '/c%d-'\n", c, c)
+	}
+	b.WriteString("versions:\n")
+	for v := range stressVersions {
+		fmt.Fprintf(&b, "  - id: v%d\n    Match: '/v%d/'\n", v, v)
+	}
+	b.WriteString(`categories:
+  - id: all
+    includes:
+      functions: [`)
+	for f := range stressFunctions {
+		if f > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "f%d", f)
+	}
+	b.WriteString("]\n      metrics: [nsPerOp, allocsPerOp]\n")
+
+	return b.String()
+}
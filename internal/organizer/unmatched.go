@@ -0,0 +1,232 @@
+package organizer
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
+)
+
+// UnmatchedReason explains why a benchmark could not be ingested.
+type UnmatchedReason string
+
+// Reasons for not ingesting a benchmark.
+const (
+	ReasonNoFunction UnmatchedReason = "no function matched"
+	ReasonNoMetric   UnmatchedReason = "no configured metric"
+)
+
+// strictLevel returns the strictness level that enforces a benchmark to be ingested for this reason.
+func (r UnmatchedReason) strictLevel() config.StrictLevel {
+	if r == ReasonNoMetric {
+		return config.StrictMetrics
+	}
+
+	return config.StrictFunctions
+}
+
+// Unmatched describes a benchmark that could not be ingested by the organizer.
+//
+// When no function matched, a Suggestion proposes a regexp to match this benchmark.
+type Unmatched struct {
+	File       string
+	Name       string
+	Reason     UnmatchedReason
+	Suggestion string
+}
+
+// Unmatched returns all the benchmarks that could not be ingested during the last call to [Organizer.Scenarize].
+func (v *Organizer) Unmatched() []Unmatched {
+	return v.unmatched
+}
+
+// reportUnmatched logs a consolidated summary of all unmatched benchmarks.
+//
+// In strict mode, it fails once with a summary of the benchmarks that are required to be ingested,
+// so all config rules may be fixed at once.
+func (v *Organizer) reportUnmatched() error {
+	if len(v.unmatched) == 0 {
+		return nil
+	}
+
+	summary := summarizeUnmatched(v.unmatched)
+	attrs := []any{
+		slog.Int("unmatched", len(v.unmatched)),
+		slog.String("summary", summary),
+	}
+	if suggestions := SuggestFunctions(v.unmatched); len(suggestions) > 0 {
+		attrs = append(attrs, slog.Any("suggested_functions", suggestions))
+	}
+	v.l.Warn("benchmarks not ingested", attrs...)
+
+	required := slices.DeleteFunc(slices.Clone(v.unmatched), func(u Unmatched) bool {
+		return !v.isStrictFor(u.Reason.strictLevel())
+	})
+	if len(required) == 0 {
+		return nil
+	}
+
+	failures := make([]error, 0, len(required))
+	for _, u := range required {
+		reason := string(u.Reason)
+		if u.Suggestion != "" {
+			reason += fmt.Sprintf(" (suggested function match: '%s')", u.Suggestion)
+		}
+		failures = append(failures, &failure.Error{Stage: failure.StageOrganize, File: u.File, Benchmark: u.Name, Reason: reason})
+	}
+
+	err := failure.Join(
+		fmt.Sprintf("strict requirement not met: %d benchmark(s) not ingested. Stopping here:\n%s", len(required), summarizeUnmatched(required)),
+		failures...,
+	)
+	v.l.Error("strict requirement not met", slog.String("error", err.Error()))
+
+	return err
+}
+
+// summarizeUnmatched renders a human-readable summary of unmatched benchmarks, one per line.
+func summarizeUnmatched(unmatched []Unmatched) string {
+	var b strings.Builder
+
+	for _, u := range unmatched {
+		fmt.Fprintf(&b, "  - %s (file: %s): %s", u.Name, u.File, u.Reason)
+		if u.Suggestion != "" {
+			fmt.Fprintf(&b, " (suggested function match: '%s')", u.Suggestion)
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// FunctionSuggestion is a candidate function match for several unmatched benchmarks.
+type FunctionSuggestion struct {
+	Match      string
+	Benchmarks int
+}
+
+func (s FunctionSuggestion) String() string {
+	return fmt.Sprintf("'%s' (%d benchmark(s))", s.Match, s.Benchmarks)
+}
+
+// SuggestFunctions proposes candidate function matches for the benchmarks that matched no function,
+// so that config rules may be added at once.
+//
+// Unmatched benchmarks are grouped by their top-level benchmark function. Each group yields a regexp
+// matching the longest path prefix common to its benchmarks, e.g. "BenchmarkJSON/easyjson/Read-16" and
+// "BenchmarkJSON/easyjson/Write-16" → "^BenchmarkJSON/easyjson(/|-\d+$|$)".
+//
+// Suggestions are sorted by decreasing number of benchmarks, then by match.
+func SuggestFunctions(unmatched []Unmatched) []FunctionSuggestion {
+	var (
+		functions []string
+		prefixes  = make(map[string][]string)
+		counts    = make(map[string]int)
+		seen      = make(map[string]struct{})
+	)
+
+	for _, u := range unmatched {
+		if _, ok := seen[u.Name]; ok || u.Reason != ReasonNoFunction {
+			continue // the same benchmark may be unmatched in several files
+		}
+		seen[u.Name] = struct{}{}
+
+		segments := strings.Split(config.TrimProcs(u.Name), "/")
+		function := segments[0]
+		prefix, ok := prefixes[function]
+		if !ok {
+			functions = append(functions, function)
+			prefixes[function] = segments
+			counts[function] = 1
+
+			continue
+		}
+
+		prefixes[function] = prefix[:commonPrefixLen(prefix, segments)]
+		counts[function]++
+	}
+
+	suggestions := make([]FunctionSuggestion, 0, len(functions))
+	for _, function := range functions {
+		prefix := prefixes[function]
+		if counts[function] == 1 {
+			// a single benchmark: match its top-level function rather than this benchmark only
+			prefix = prefix[:1]
+		}
+
+		suggestions = append(suggestions, FunctionSuggestion{
+			Match:      prefixRegexp(strings.Join(prefix, "/")),
+			Benchmarks: counts[function],
+		})
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b FunctionSuggestion) int {
+		if a.Benchmarks != b.Benchmarks {
+			return b.Benchmarks - a.Benchmarks
+		}
+
+		return strings.Compare(a.Match, b.Match)
+	})
+
+	return suggestions
+}
+
+// suggestFunctionRegexp proposes a regexp that matches the top-level benchmark function of a benchmark name.
+//
+// Example: "BenchmarkReadJSON/small-16" → "^BenchmarkReadJSON(/|-\d+$|$)".
+func suggestFunctionRegexp(name string) string {
+	function, _, _ := strings.Cut(name, "/")
+
+	return prefixRegexp(config.TrimProcs(function))
+}
+
+// prefixRegexp builds a regexp matching benchmark names starting with a path prefix, e.g. "BenchmarkJSON/easyjson":
+// the prefix must be followed by a sub-benchmark, a GOMAXPROCS suffix or nothing.
+func prefixRegexp(prefix string) string {
+	return "^" + regexp.QuoteMeta(prefix) + `(/|-\d+$|$)`
+}
+
+// commonPrefixLen returns the number of leading path segments common to a and b.
+func commonPrefixLen(a, b []string) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+
+	return n
+}
+
+// unmatchedCollector collects unmatched benchmarks, deduplicated by file and name.
+type unmatchedCollector struct {
+	items []Unmatched
+	seen  map[string]struct{}
+}
+
+func (c *unmatchedCollector) add(file, name string, reason UnmatchedReason) {
+	if c.seen == nil {
+		c.seen = make(map[string]struct{})
+	}
+
+	key := file + "\x00" + name
+	if _, ok := c.seen[key]; ok {
+		return
+	}
+	c.seen[key] = struct{}{}
+
+	u := Unmatched{
+		File:   file,
+		Name:   name,
+		Reason: reason,
+	}
+	if reason == ReasonNoFunction {
+		u.Suggestion = suggestFunctionRegexp(name)
+	}
+
+	c.items = append(c.items, u)
+}
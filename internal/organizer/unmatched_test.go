@@ -0,0 +1,170 @@
+package organizer
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/parser"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestUnmatchedSummary(t *testing.T) {
+	cfg := mustLoadConfig(t, genericsConfig())
+	sets := []parser.Set{{
+		File: "unmatched.txt",
+		Set: parse.Set{
+			"BenchmarkUnknown/reflect/int-16": []*parse.Benchmark{
+				{Name: "BenchmarkUnknown/reflect/int-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
+				{Name: "BenchmarkUnknown/reflect/int-16", N: 1000, NsPerOp: 110, Measured: parse.NsPerOp},
+			},
+			"BenchmarkOther-16": []*parse.Benchmark{
+				{Name: "BenchmarkOther-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
+			},
+			"BenchmarkGreater/reflect/int-16": []*parse.Benchmark{
+				{Name: "BenchmarkGreater/reflect/int-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
+			},
+		},
+	}}
+
+	t.Run("should collect all unmatched benchmarks", func(t *testing.T) {
+		o := New(cfg)
+		benchSet, err := o.parseBenchmarks(sets)
+		require.NoError(t, err)
+		assert.NotEmpty(t, benchSet.Set)
+
+		unmatched := o.Unmatched()
+		require.Len(t, unmatched, 2)
+		assert.Equal(t, "BenchmarkOther-16", unmatched[0].Name)
+		assert.Equal(t, ReasonNoFunction, unmatched[0].Reason)
+		assert.Equal(t, "unmatched.txt", unmatched[0].File)
+		assert.Equal(t, "BenchmarkUnknown/reflect/int-16", unmatched[1].Name)
+	})
+
+	t.Run("should fail once with a summary in strict mode", func(t *testing.T) {
+		cfg.StrictLevel = config.StrictAll
+		defer func() { cfg.StrictLevel = config.StrictNone }()
+
+		o := New(cfg)
+		_, err := o.parseBenchmarks(sets)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 benchmark(s) not ingested")
+		assert.Contains(t, err.Error(), "BenchmarkOther-16")
+		assert.Contains(t, err.Error(), "BenchmarkUnknown/reflect/int-16")
+		assert.Contains(t, err.Error(), "suggested function match")
+	})
+}
+
+func TestUnmatchedStrictLevels(t *testing.T) {
+	sets := []parser.Set{{
+		File: "unmatched.txt",
+		Set: parse.Set{
+			"BenchmarkOther-16": []*parse.Benchmark{
+				{Name: "BenchmarkOther-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
+			},
+			"BenchmarkGreater/reflect/int-16": []*parse.Benchmark{
+				{Name: "BenchmarkGreater/reflect/int-16", N: 1000, NsPerOp: 100, Measured: parse.NsPerOp},
+			},
+		},
+	}}
+
+	t.Run("with unmatched functions", func(t *testing.T) {
+		cfg := mustLoadConfig(t, genericsConfig())
+
+		_, err := New(cfg, WithStrictLevel(config.StrictMetrics)).parseBenchmarks(sets)
+		require.NoError(t, err)
+
+		_, err = New(cfg, WithStrictLevel(config.StrictFunctions)).parseBenchmarks(sets)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 benchmark(s) not ingested")
+
+		cfg.StrictLevel = config.StrictFunctions
+		_, err = New(cfg).parseBenchmarks(sets)
+		require.Error(t, err)
+	})
+
+	t.Run("with no configured metric", func(t *testing.T) {
+		cfg := mustLoadConfig(t, `
+metrics: []
+functions:
+  - id: greater
+    Match: 'Greater'
+  - id: other
+    Match: 'Other'
+`)
+
+		_, err := New(cfg, WithStrictLevel(config.StrictFunctions)).parseBenchmarks(sets)
+		require.NoError(t, err)
+
+		_, err = New(cfg, WithStrictLevel(config.StrictMetrics)).parseBenchmarks(sets)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 benchmark(s) not ingested")
+		assert.Contains(t, err.Error(), string(ReasonNoMetric))
+	})
+}
+
+func TestSuggestFunctionRegexp(t *testing.T) {
+	tests := []struct {
+		name      string
+		want      string
+		matches   []string
+		noMatches []string
+	}{
+		{
+			name:      "BenchmarkReadJSON/small-16",
+			want:      `^BenchmarkReadJSON(/|-\d+$|$)`,
+			matches:   []string{"BenchmarkReadJSON/small-16", "BenchmarkReadJSON/large-16", "BenchmarkReadJSON-8"},
+			noMatches: []string{"BenchmarkReadJSONStream/small-16"},
+		},
+		{
+			name:    "BenchmarkFoo-16",
+			want:    `^BenchmarkFoo(/|-\d+$|$)`,
+			matches: []string{"BenchmarkFoo-16", "BenchmarkFoo"},
+		},
+		{
+			name:    "Benchmark_is.Empty",
+			want:    `^Benchmark_is\.Empty(/|-\d+$|$)`,
+			matches: []string{"Benchmark_is.Empty-4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion := suggestFunctionRegexp(tt.name)
+			assert.Equal(t, tt.want, suggestion)
+
+			rex := regexp.MustCompile(suggestion)
+			for _, name := range tt.matches {
+				assert.True(t, rex.MatchString(name), "expected %q to match %q", suggestion, name)
+			}
+			for _, name := range tt.noMatches {
+				assert.False(t, rex.MatchString(name), "expected %q not to match %q", suggestion, name)
+			}
+		})
+	}
+}
+
+func TestSuggestFunctions(t *testing.T) {
+	unmatched := []Unmatched{
+		{File: "a.txt", Name: "BenchmarkJSON/easyjson/Read-16", Reason: ReasonNoFunction},
+		{File: "a.txt", Name: "BenchmarkJSON/easyjson/Write-16", Reason: ReasonNoFunction},
+		{File: "b.txt", Name: "BenchmarkJSON/easyjson/Write-16", Reason: ReasonNoFunction},
+		{File: "a.txt", Name: "BenchmarkFoo/small-16", Reason: ReasonNoFunction},
+		{File: "a.txt", Name: "BenchmarkGreater-16", Reason: ReasonNoMetric},
+	}
+
+	suggestions := SuggestFunctions(unmatched)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, FunctionSuggestion{Match: `^BenchmarkJSON/easyjson(/|-\d+$|$)`, Benchmarks: 2}, suggestions[0])
+	assert.Equal(t, FunctionSuggestion{Match: `^BenchmarkFoo(/|-\d+$|$)`, Benchmarks: 1}, suggestions[1])
+	assert.Equal(t, `'^BenchmarkFoo(/|-\d+$|$)' (1 benchmark(s))`, suggestions[1].String())
+
+	rex := regexp.MustCompile(suggestions[0].Match)
+	assert.True(t, rex.MatchString("BenchmarkJSON/easyjson/Read-16"))
+	assert.False(t, rex.MatchString("BenchmarkJSON/stdlib/Read-16"))
+
+	assert.Empty(t, SuggestFunctions(unmatched[4:]))
+}
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"golang.org/x/tools/benchmark/parse"
+)
+
+// Files of the criterion.rs directory layout, for each benchmark: "{group}/{function}/{value}/new/estimates.json".
+const (
+	criterionLatest        = "new"
+	criterionEstimatesFile = "estimates.json"
+	criterionBenchmarkFile = "benchmark.json"
+	criterionSampleFile    = "sample.json"
+)
+
+// criterionEstimatesJSON holds the statistics estimated by criterion.rs, in ns.
+type criterionEstimatesJSON struct {
+	Mean  criterionEstimate  `json:"mean"`
+	Slope *criterionEstimate `json:"slope"`
+}
+
+type criterionEstimate struct {
+	PointEstimate float64 `json:"point_estimate"`
+}
+
+// criterionBenchmarkJSON identifies a benchmark in criterion.rs results.
+type criterionBenchmarkJSON struct {
+	FullID     string                   `json:"full_id"`
+	Throughput *criterionThroughputJSON `json:"throughput"`
+}
+
+type criterionThroughputJSON struct {
+	Bytes        *uint64 `json:"Bytes"`
+	BytesDecimal *uint64 `json:"BytesDecimal"`
+}
+
+type criterionSampleJSON struct {
+	Iters []float64 `json:"iters"`
+}
+
+// parseCriterion parses the results of criterion.rs benchmarks, found in a directory tree (e.g. target/criterion).
+//
+// The latest run of each benchmark (i.e. the "new" estimates) is converted to a go benchmark:
+//
+//   - the name is composed from the full ID of the benchmark, like "Benchmark{group}/{function}/{value}",
+//     with blanks replaced by underscores, as go does;
+//   - the slope estimate (or the mean, whenever the slope is not estimated) is the time per iteration in ns/op;
+//   - a throughput in bytes is converted to MB/s.
+func (p *BenchmarkParser) parseCriterion(fsys fs.FS) (Set, error) {
+	builder := newSetBuilder(p.retains)
+
+	err := fs.WalkDir(fsys, ".", func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || d.Name() != criterionEstimatesFile || path.Base(path.Dir(pth)) != criterionLatest {
+			return nil
+		}
+
+		bench, err := criterionBenchmark(fsys, path.Dir(pth))
+		if err != nil {
+			return fmt.Errorf("criterion benchmark %q: %w", pth, err)
+		}
+		builder.addBenchmark(bench)
+
+		return nil
+	})
+	if err != nil {
+		return Set{}, err
+	}
+
+	if builder.ord == 0 {
+		return Set{}, errors.New("no criterion benchmark found")
+	}
+
+	return builder.build(), nil
+}
+
+// criterionBenchmark converts the files of the latest run of a criterion.rs benchmark to a go benchmark.
+func criterionBenchmark(fsys fs.FS, dir string) (*parse.Benchmark, error) {
+	var estimates criterionEstimatesJSON
+	if err := readJSON(fsys, path.Join(dir, criterionEstimatesFile), &estimates); err != nil {
+		return nil, err
+	}
+
+	// the benchmark is identified by its directory, unless described
+	id := path.Dir(dir)
+	var benchmark criterionBenchmarkJSON
+	if err := readJSON(fsys, path.Join(dir, criterionBenchmarkFile), &benchmark); err == nil && benchmark.FullID != "" {
+		id = benchmark.FullID
+	}
+
+	bench := &parse.Benchmark{
+		Name:     "Benchmark" + strings.ReplaceAll(id, " ", "_"),
+		NsPerOp:  estimates.Mean.PointEstimate,
+		Measured: parse.NsPerOp,
+	}
+	if estimates.Slope != nil {
+		bench.NsPerOp = estimates.Slope.PointEstimate
+	}
+
+	var sample criterionSampleJSON
+	if err := readJSON(fsys, path.Join(dir, criterionSampleFile), &sample); err == nil {
+		for _, iters := range sample.Iters {
+			bench.N += int(iters)
+		}
+	}
+
+	if throughput := benchmark.Throughput; throughput != nil && bench.NsPerOp > 0 {
+		bytes := throughput.Bytes
+		if bytes == nil {
+			bytes = throughput.BytesDecimal
+		}
+		if bytes != nil {
+			bench.MBPerS = float64(*bytes) / bench.NsPerOp * 1e3 //nolint:mnd // bytes per ns to MB/s
+			bench.Measured |= parse.MBPerS
+		}
+	}
+
+	return bench, nil
+}
+
+func readJSON(fsys fs.FS, file string, target any) error {
+	content, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, target)
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fredbi/benchviz/internal/config"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+)
+
+func criterionTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"fib/iterative/20/new/estimates.json": {Data: []byte(`{
+			"mean": {"point_estimate": 25.5, "standard_error": 0.1},
+			"median": {"point_estimate": 25.1},
+			"slope": {"point_estimate": 24.8}
+		}`)},
+		"fib/iterative/20/new/benchmark.json": {Data: []byte(`{
+			"group_id": "fib", "function_id": "iterative", "value_str": "20", "full_id": "fib/iterative/20", "throughput": null
+		}`)},
+		"fib/iterative/20/new/sample.json":       {Data: []byte(`{"sampling_mode": "Linear", "iters": [10, 20, 30], "times": [250, 500, 750]}`)},
+		"fib/iterative/20/base/estimates.json":   {Data: []byte(`{"mean": {"point_estimate": 99}}`)},
+		"fib/iterative/20/change/estimates.json": {Data: []byte(`{"mean": {"point_estimate": 0.01}}`)},
+		"parse json/new/estimates.json": {Data: []byte(`{
+			"mean": {"point_estimate": 2000},
+			"slope": null
+		}`)},
+		"parse json/new/benchmark.json": {Data: []byte(`{"full_id": "parse json", "throughput": {"Bytes": 1000}}`)},
+		"report/index.html":             {Data: []byte(`<html></html>`)},
+	}
+}
+
+func TestParseCriterion(t *testing.T) {
+	p := New(&config.Config{}, WithFormat(FormatCriterion))
+
+	set, err := p.parseCriterion(criterionTestFS())
+	require.NoError(t, err)
+	require.Len(t, set.Set, 2)
+	assert.EqualT(t, unk, set.Environment)
+
+	fib := set.Set["Benchmarkfib/iterative/20"]
+	require.Len(t, fib, 1)
+	assert.InDeltaT(t, 24.8, fib[0].NsPerOp, 1e-9, "the slope is preferred to the mean")
+	assert.EqualT(t, 60, fib[0].N)
+	assert.EqualT(t, parse.NsPerOp, fib[0].Measured)
+
+	parseJSON := set.Set["Benchmarkparse_json"]
+	require.Len(t, parseJSON, 1)
+	assert.InDeltaT(t, 2000, parseJSON[0].NsPerOp, 1e-9)
+	assert.InDeltaT(t, 500, parseJSON[0].MBPerS, 1e-9, "1000 bytes in 2µs is 500 MB/s")
+	assert.EqualT(t, parse.NsPerOp|parse.MBPerS, parseJSON[0].Measured)
+
+	t.Run("should identify benchmarks by directory when not described", func(t *testing.T) {
+		fsys := criterionTestFS()
+		delete(fsys, "fib/iterative/20/new/benchmark.json")
+
+		set, err := p.parseCriterion(fsys)
+		require.NoError(t, err)
+		assert.Contains(t, set.Set, "Benchmarkfib/iterative/20")
+	})
+
+	t.Run("should fail on invalid estimates", func(t *testing.T) {
+		fsys := criterionTestFS()
+		fsys["fib/iterative/20/new/estimates.json"] = &fstest.MapFile{Data: []byte(`{`)}
+
+		_, err := p.parseCriterion(fsys)
+		require.Error(t, err)
+	})
+
+	t.Run("should fail without any benchmark", func(t *testing.T) {
+		_, err := p.parseCriterion(fstest.MapFS{"report/index.html": {Data: []byte(`<html></html>`)}})
+		require.ErrorContains(t, err, "no criterion benchmark found")
+	})
+}
+
+func TestParseFilesCriterion(t *testing.T) {
+	dir := t.TempDir()
+	for name, file := range criterionTestFS() {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), file.Data, 0o600))
+	}
+
+	p := New(&config.Config{}, WithFormat(FormatCriterion), WithLabels(map[string]string{dir: "rust"}))
+	require.NoError(t, p.ParseFiles(dir))
+
+	sets := p.Sets()
+	require.Len(t, sets, 1)
+	assert.EqualT(t, dir, sets[0].File)
+	assert.EqualT(t, "rust", sets[0].Label)
+	assert.Len(t, sets[0].Set, 2)
+
+	_, err := p.ParseInput(strings.NewReader(""))
+	require.ErrorContains(t, err, "stored in a directory")
+
+	require.Error(t, p.ParseFiles(filepath.Join(dir, "missing")))
+}
@@ -0,0 +1,32 @@
+package parser
+
+// Format is the format of benchmark inputs.
+type Format string
+
+// Supported input formats.
+const (
+	FormatGo        Format = "go"        // output of go test -bench, as text or JSON (see [WithParseJSON])
+	FormatJMH       Format = "jmh"       // JSON results of the Java Microbenchmark Harness (-rf json)
+	FormatCriterion Format = "criterion" // directory of results of criterion.rs (e.g. target/criterion)
+	FormatHyperfine Format = "hyperfine" // JSON results of hyperfine (--export-json)
+)
+
+// IsValid reports whether the input format is supported.
+func (f Format) IsValid() bool {
+	switch f {
+	case "", FormatGo, FormatJMH, FormatCriterion, FormatHyperfine:
+		return true
+	default:
+		return false
+	}
+}
+
+// AllFormats returns all supported input formats.
+func AllFormats() []Format {
+	return []Format{
+		FormatGo,
+		FormatJMH,
+		FormatCriterion,
+		FormatHyperfine,
+	}
+}
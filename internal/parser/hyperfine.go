@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/benchmark/parse"
+)
+
+// hyperfineExport is the JSON output of hyperfine, exported with --export-json.
+type hyperfineExport struct {
+	Results []hyperfineResult `json:"results"`
+}
+
+// hyperfineResult holds the timings of a command, in seconds.
+type hyperfineResult struct {
+	Command    string            `json:"command"`
+	Mean       float64           `json:"mean"`
+	StdDev     *float64          `json:"stddev"` // null with a single run
+	Times      []float64         `json:"times"`
+	ExitCodes  []*int            `json:"exit_codes"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// hyperfineStdDevUnit is the unit of the custom metric holding the standard deviation of the runs of a command, in ns.
+const hyperfineStdDevUnit = "stddev-ns/op"
+
+// parseHyperfine parses the JSON output of hyperfine (--export-json).
+//
+// Each command is converted to a go benchmark:
+//
+//   - the name is composed from the command (or its name, set with --command-name) and its parameters,
+//     like "Benchmark{command}/{parameter}={value}", with blanks replaced by underscores, as go does,
+//     and slashes in the command replaced by underscores, since they separate sub-benchmarks;
+//   - the mean time of the runs is the time per operation in ns/op, and the number of runs the number of iterations;
+//   - the standard deviation of the runs is the custom metric with the unit "stddev-ns/op".
+//
+// Runs that exited with a non-zero code are reported as failures.
+func (p *BenchmarkParser) parseHyperfine(r io.Reader) (Set, error) {
+	var export hyperfineExport
+
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return Set{}, fmt.Errorf("decoding hyperfine results: %w", err)
+	}
+
+	if len(export.Results) == 0 {
+		return Set{}, errors.New("decoding hyperfine results: no result found")
+	}
+
+	builder := newSetBuilder(p.retains)
+	for _, result := range export.Results {
+		bench := &parse.Benchmark{
+			Name:     result.name(),
+			N:        len(result.Times),
+			NsPerOp:  result.Mean * 1e9, //nolint:mnd // seconds to ns
+			Measured: parse.NsPerOp,
+		}
+		if builder.addBenchmark(bench) && result.StdDev != nil {
+			builder.addCustomMetrics(bench, map[string]float64{hyperfineStdDevUnit: *result.StdDev * 1e9}) //nolint:mnd // seconds to ns
+		}
+
+		for _, code := range result.ExitCodes {
+			if code != nil && *code != 0 {
+				builder.addFailure(fmt.Sprintf("command %q exited with code %d", result.Command, *code))
+
+				break
+			}
+		}
+	}
+
+	return builder.build(), nil
+}
+
+// name composes a go benchmark name from the command and its parameters.
+func (h hyperfineResult) name() string {
+	var b strings.Builder
+
+	b.WriteString("Benchmark" + strings.ReplaceAll(strings.Join(strings.Fields(h.Command), "_"), "/", "_"))
+	for _, param := range slices.Sorted(maps.Keys(h.Parameters)) {
+		b.WriteString("/" + param + "=" + h.Parameters[param])
+	}
+
+	return b.String()
+}
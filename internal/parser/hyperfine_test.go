@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestParseHyperfine(t *testing.T) {
+	p := New(&config.Config{}, WithFormat(FormatHyperfine))
+	require.NoError(t, p.ParseFiles(testdataPath("hyperfine.json")))
+
+	sets := p.Sets()
+	require.Len(t, sets, 1)
+	set := sets[0]
+	require.Len(t, set.Set, 2)
+
+	sort := set.Set["Benchmarksort_-n_input.txt/size=1000"]
+	require.Len(t, sort, 1)
+	assert.EqualT(t, 4, sort[0].N)
+	assert.InDeltaT(t, 12.3e6, sort[0].NsPerOp, 1e-3)
+	assert.InDeltaT(t, 0.4e6, set.CustomMetrics(sort[0])[hyperfineStdDevUnit], 1e-3)
+
+	gsort := set.Set["Benchmarkgsort/size=1000"]
+	require.Len(t, gsort, 1)
+	assert.EqualT(t, 1, gsort[0].Ord)
+
+	require.Len(t, set.Failures, 1)
+	assert.Contains(t, set.Failures[0], `command "gsort" exited with code 1`)
+}
+
+func TestParseHyperfineCommandName(t *testing.T) {
+	p := New(&config.Config{}, WithFormat(FormatHyperfine))
+
+	set, err := p.ParseInput(strings.NewReader(`{"results": [{"command": "./bin/foo -x", "mean": 0.001, "stddev": null, "times": [0.001]}]}`))
+	require.NoError(t, err)
+
+	foo := set.Set["Benchmark._bin_foo_-x"]
+	require.Len(t, foo, 1)
+	assert.Empty(t, set.CustomMetrics(foo[0]))
+}
+
+func TestParseHyperfineErrors(t *testing.T) {
+	p := New(&config.Config{}, WithFormat(FormatHyperfine))
+
+	_, err := p.ParseInput(strings.NewReader(`[]`))
+	require.ErrorContains(t, err, "decoding hyperfine results")
+
+	_, err = p.ParseInput(strings.NewReader(`{"results": []}`))
+	require.ErrorContains(t, err, "no result found")
+}
@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/benchmark/parse"
+)
+
+// jmhResult is a benchmark result in the JSON output of the Java Microbenchmark Harness (JMH).
+type jmhResult struct {
+	Benchmark             string               `json:"benchmark"`
+	Mode                  string               `json:"mode"`
+	Forks                 int                  `json:"forks"`
+	MeasurementIterations int                  `json:"measurementIterations"`
+	JDKVersion            string               `json:"jdkVersion"`
+	VMName                string               `json:"vmName"`
+	Params                map[string]string    `json:"params"`
+	PrimaryMetric         jmhMetric            `json:"primaryMetric"`
+	SecondaryMetrics      map[string]jmhMetric `json:"secondaryMetrics"`
+}
+
+type jmhMetric struct {
+	Score     float64 `json:"score"`
+	ScoreUnit string  `json:"scoreUnit"`
+}
+
+// jmhAllocRate is the (suffix of the) secondary metric reported by the JMH GC profiler for allocated bytes per operation.
+const jmhAllocRate = "gc.alloc.rate.norm"
+
+// nanoseconds per time unit used by JMH scores.
+var jmhTimeUnits = map[string]float64{
+	"ns": 1,
+	"us": 1e3,
+	"ms": 1e6,
+	"s":  1e9,
+}
+
+// parseJMH parses the JSON results of JMH.
+//
+// Benchmarks are converted to go benchmarks:
+//
+//   - the name is composed like "Benchmark{class}/{method}/{param}={value}", e.g. "org.sample.Sort.quick" with
+//     param "size=100" becomes "BenchmarkSort/quick/size=100";
+//   - scores in time per operation (average time, sampling or single shot modes) and throughput scores
+//     (operations per time unit) are both converted to ns/op;
+//   - the normalized allocation rate from the GC profiler is converted to B/op.
+func (p *BenchmarkParser) parseJMH(r io.Reader) (Set, error) {
+	var results []jmhResult
+
+	if err := json.NewDecoder(r).Decode(&results); err != nil {
+		return Set{}, fmt.Errorf("decoding JMH results: %w", err)
+	}
+
+	builder := newSetBuilder(p.retains)
+	for _, result := range results {
+		bench, ok := result.benchmark()
+		if !ok {
+			p.l.Warn("JMH benchmark skipped: unsupported score unit",
+				slog.String("benchmark", result.Benchmark),
+				slog.String("unit", result.PrimaryMetric.ScoreUnit),
+			)
+
+			continue
+		}
+
+		builder.addBenchmark(bench)
+
+		if len(builder.environment) == 0 && result.JDKVersion != "" {
+			builder.environment = append(builder.environment, "jdk "+result.JDKVersion)
+			if result.VMName != "" {
+				builder.environment = append(builder.environment, result.VMName)
+			}
+		}
+	}
+
+	return builder.build(), nil
+}
+
+// benchmark converts a JMH result to a go benchmark.
+func (j jmhResult) benchmark() (*parse.Benchmark, bool) {
+	nsPerOp, ok := jmhNsPerOp(j.PrimaryMetric)
+	if !ok {
+		return nil, false
+	}
+
+	bench := &parse.Benchmark{
+		Name:     j.name(),
+		N:        max(1, j.Forks) * max(1, j.MeasurementIterations),
+		NsPerOp:  nsPerOp,
+		Measured: parse.NsPerOp,
+	}
+
+	for key, metric := range j.SecondaryMetrics {
+		if strings.HasSuffix(key, jmhAllocRate) && metric.ScoreUnit == "B/op" {
+			bench.AllocedBytesPerOp = uint64(metric.Score)
+			bench.Measured |= parse.AllocedBytesPerOp
+		}
+	}
+
+	return bench, true
+}
+
+// name composes a go benchmark name from the fully qualified name of the JMH benchmark method and its parameters.
+func (j jmhResult) name() string {
+	parts := strings.Split(j.Benchmark, ".")
+	if len(parts) > 2 { //nolint:mnd // keep the class and method
+		parts = parts[len(parts)-2:]
+	}
+
+	var b strings.Builder
+	b.WriteString("Benchmark" + strings.Join(parts, "/"))
+	for _, param := range slices.Sorted(maps.Keys(j.Params)) {
+		b.WriteString("/" + param + "=" + j.Params[param])
+	}
+
+	return b.String()
+}
+
+// jmhNsPerOp converts a JMH score to ns/op, from time per operation or throughput.
+func jmhNsPerOp(metric jmhMetric) (float64, bool) {
+	if unit, ok := strings.CutSuffix(metric.ScoreUnit, "/op"); ok {
+		factor, ok := jmhTimeUnits[unit]
+
+		return metric.Score * factor, ok
+	}
+
+	if unit, ok := strings.CutPrefix(metric.ScoreUnit, "ops/"); ok {
+		factor, ok := jmhTimeUnits[unit]
+		if !ok || metric.Score == 0 {
+			return 0, false
+		}
+
+		return factor / metric.Score, true
+	}
+
+	return 0, false
+}
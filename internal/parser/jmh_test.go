@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+)
+
+func TestParseJMH(t *testing.T) {
+	p := New(&config.Config{}, WithFormat(FormatJMH))
+	require.NoError(t, p.ParseFiles(testdataPath("jmh.json")))
+
+	sets := p.Sets()
+	require.Len(t, sets, 1)
+	set := sets[0]
+
+	assert.EqualT(t, "jdk 21.0.2 OpenJDK 64-Bit Server VM", set.Environment)
+	require.Len(t, set.Set, 2, "the benchmark with an unsupported unit should be skipped")
+
+	quick := set.Set["BenchmarkSortBenchmark/quickSort/size=100"]
+	require.Len(t, quick, 1)
+	assert.EqualT(t, 10, quick[0].N)
+	assert.InDeltaT(t, 1234, quick[0].NsPerOp, 1e-9)
+	assert.EqualT(t, uint64(416), quick[0].AllocedBytesPerOp)
+	assert.EqualT(t, parse.NsPerOp|parse.AllocedBytesPerOp, quick[0].Measured)
+
+	merge := set.Set["BenchmarkSortBenchmark/mergeSort/size=100"]
+	require.Len(t, merge, 1)
+	assert.InDeltaT(t, 500, merge[0].NsPerOp, 1e-9, "2000 ops/ms is 500 ns/op")
+	assert.EqualT(t, parse.NsPerOp, merge[0].Measured)
+}
+
+func TestParseJMHErrors(t *testing.T) {
+	p := New(&config.Config{}, WithFormat(FormatJMH))
+
+	_, err := p.ParseInput(strings.NewReader(`{"benchmark": "not an array"}`))
+	require.ErrorContains(t, err, "decoding JMH results")
+
+	_, err = New(&config.Config{}, WithFormat("unknown")).ParseInput(strings.NewReader(""))
+	require.ErrorContains(t, err, "unsupported input format")
+}
+
+func TestJMHNsPerOp(t *testing.T) {
+	for _, test := range []struct {
+		metric   jmhMetric
+		expected float64
+		ok       bool
+	}{
+		{jmhMetric{Score: 2, ScoreUnit: "ns/op"}, 2, true},
+		{jmhMetric{Score: 2, ScoreUnit: "ms/op"}, 2e6, true},
+		{jmhMetric{Score: 2, ScoreUnit: "s/op"}, 2e9, true},
+		{jmhMetric{Score: 4, ScoreUnit: "ops/us"}, 250, true},
+		{jmhMetric{Score: 1e6, ScoreUnit: "ops/s"}, 1000, true},
+		{jmhMetric{Score: 0, ScoreUnit: "ops/s"}, 0, false},
+		{jmhMetric{Score: 1, ScoreUnit: "min/op"}, 0, false},
+		{jmhMetric{Score: 1, ScoreUnit: "counts"}, 0, false},
+	} {
+		nsPerOp, ok := jmhNsPerOp(test.metric)
+		assert.EqualT(t, test.ok, ok, test.metric.ScoreUnit)
+		if test.ok {
+			assert.InDeltaT(t, test.expected, nsPerOp, 1e-9, test.metric.ScoreUnit)
+		}
+	}
+}
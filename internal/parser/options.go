@@ -1,10 +1,25 @@
 package parser //nolint:revive // it's okay for an internal package to use this name
 
+import (
+	"log/slog"
+	"regexp"
+)
+
 // Option configures a [BenchmarkParser].
 type Option func(*options)
 
 type options struct {
-	isJSON bool
+	isJSON       bool
+	format       Format
+	labels       map[string]string
+	environments map[string]string
+	goVersion    string
+	keepZero     bool
+	match        *regexp.Regexp
+	exclude      *regexp.Regexp
+	plugin       []string
+	progress     func(Progress)
+	logger       *slog.Logger
 }
 
 // WithParseJSON enables JSON input parsing instead of the default text format.
@@ -14,11 +29,121 @@ func WithParseJSON(enabled bool) Option {
 	}
 }
 
+// WithFormat sets the format of inputs. The default is [FormatGo].
+//
+// Inputs in other formats are converted to go benchmarks, so they may be organized by the same configuration.
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// WithLabels attaches a user-defined label to the [Set] parsed from each input file.
+//
+// The map is keyed by input file name (as passed to [BenchmarkParser.ParseFiles]).
+func WithLabels(labels map[string]string) Option {
+	return func(o *options) {
+		o.labels = labels
+	}
+}
+
+// WithEnvironments sets the environment of the [Set] parsed from each input file,
+// overriding the environment extracted from the input.
+//
+// The map is keyed by input file name (as passed to [BenchmarkParser.ParseFiles]).
+func WithEnvironments(environments map[string]string) Option {
+	return func(o *options) {
+		o.environments = environments
+	}
+}
+
+// WithGoVersion sets the version of the Go toolchain of the [Set] parsed from each input, e.g. "go1.23.4",
+// when the input doesn't tell.
+func WithGoVersion(version string) Option {
+	return func(o *options) {
+		o.goVersion = version
+	}
+}
+
+// WithZeroMetrics retains the metrics measured with a zero value (e.g. "0 allocs/op") in a [ParsingReport].
+//
+// By default, zero values are considered missing.
+func WithZeroMetrics(enabled bool) Option {
+	return func(o *options) {
+		o.keepZero = enabled
+	}
+}
+
+// WithMatch retains only the benchmarks whose name matches the regexp.
+//
+// Other benchmark lines are dropped at parse time. A nil regexp retains all benchmarks.
+func WithMatch(match *regexp.Regexp) Option {
+	return func(o *options) {
+		o.match = match
+	}
+}
+
+// WithExclude drops the benchmarks whose name matches the regexp at parse time.
+//
+// A nil regexp excludes nothing.
+func WithExclude(exclude *regexp.Regexp) Option {
+	return func(o *options) {
+		o.exclude = exclude
+	}
+}
+
+// WithPlugin converts inputs with an external program, instead of parsing the output of go test.
+//
+// The command (a program and its arguments) receives each input on its standard input,
+// and writes benchmark results on its standard output, as a [CanonicalSet] in JSON.
+//
+// This allows to visualize benchmarks in any format.
+func WithPlugin(command ...string) Option {
+	return func(o *options) {
+		o.plugin = command
+	}
+}
+
+// WithProgress calls notify as [BenchmarkParser.ParseFiles] goes through its inputs.
+//
+// The callback is called often (i.e. after every read): it should be cheap, or throttle its output.
+func WithProgress(notify func(Progress)) Option {
+	return func(o *options) {
+		o.progress = notify
+	}
+}
+
+// WithLogger sets the logger used by the [BenchmarkParser].
+//
+// By default, the parser logs with [slog.Default].
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// retains reports whether a benchmark name passes the match and exclude filters.
+func (o options) retains(name string) bool {
+	if o.match != nil && !o.match.MatchString(name) {
+		return false
+	}
+
+	if o.exclude != nil && o.exclude.MatchString(name) {
+		return false
+	}
+
+	return true
+}
+
 func optionsWithDefaults(opts []Option) options {
 	var o options
 	for _, apply := range opts {
 		apply(&o)
 	}
 
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+
 	return o
 }
@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// procsSuffix is the GOMAXPROCS suffix appended by the testing package to benchmark names, e.g. "-16".
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// Params extracts the parameters of a benchmark from the segments of its name of the form "key=value",
+// as named by sub-benchmarks with b.Run (e.g. "BenchmarkEncode/size=1024/workers=8-16").
+//
+// It returns nil when the name has no parameter. When a parameter is repeated, the last value wins.
+func Params(name string) map[string]string {
+	var params map[string]string
+
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		if i == len(segments)-1 {
+			if trimmed := procsSuffix.ReplaceAllString(segment, ""); !strings.HasSuffix(trimmed, "=") {
+				segment = trimmed
+			}
+		}
+
+		key, value, ok := strings.Cut(segment, "=")
+		if !ok || key == "" {
+			continue
+		}
+
+		if params == nil {
+			params = make(map[string]string)
+		}
+		params[key] = value
+	}
+
+	return params
+}
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+)
+
+func TestParams(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected map[string]string
+	}{
+		{name: "BenchmarkEncode-16"},
+		{name: "BenchmarkEncode/json/small-16"},
+		{
+			name:     "BenchmarkEncode/size=1024/workers=8-16",
+			expected: map[string]string{"size": "1024", "workers": "8"},
+		},
+		{
+			name:     "BenchmarkEncode/size=1024/json",
+			expected: map[string]string{"size": "1024"},
+		},
+		{
+			name:     "BenchmarkEncode/size=1024/size=2048-8",
+			expected: map[string]string{"size": "2048"},
+		},
+		{
+			name:     "BenchmarkDelta/delta=-1",
+			expected: map[string]string{"delta": "-1"},
+		},
+		{
+			name:     "BenchmarkEncode/mode=fast-mode-4",
+			expected: map[string]string{"mode": "fast-mode"},
+		},
+		{name: "BenchmarkEncode/=1024-8"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Params(tc.name))
+		})
+	}
+}
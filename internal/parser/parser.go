@@ -2,51 +2,125 @@ package parser
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"math"
 	"os"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/failure"
 	"golang.org/x/tools/benchmark/parse"
 )
 
 // Set wraps [parse.Set] to include file and benchmark environment information.
+//
+// The Label is an optional user-defined tag attached to the input file (e.g. from the CLI).
+//
+// Failures collect the messages that denote a failed or interrupted benchmark run.
+//
+// Start and End are the timestamps of the first and last events of the run, when available
+// (i.e. only for JSON input).
 type Set struct {
 	parse.Set
 
 	File        string
+	Label       string
 	Environment string
+	GoVersion   string // version of the Go toolchain that ran the benchmarks (e.g. "go1.23.4"), if known
+	Failures    []string
+	Start       time.Time
+	End         time.Time
+
+	// Custom holds the measurements of custom metrics reported with testing.B.ReportMetric (e.g. "12.5 peak-MB"),
+	// by unit, for each benchmark of the set by its Ord.
+	Custom map[int]map[string]float64
+}
+
+// CustomMetrics returns the measurements of custom metrics of a benchmark of the set, by unit.
+func (s Set) CustomMetrics(bench *parse.Benchmark) map[string]float64 {
+	return s.Custom[bench.Ord]
+}
+
+// Duration of the benchmark run, or zero if the run timestamps are unknown.
+func (s Set) Duration() time.Duration {
+	if s.Start.IsZero() || s.End.IsZero() {
+		return 0
+	}
+
+	return s.End.Sub(s.Start)
+}
+
+// Failed reports whether the benchmark run that produced this [Set] failed or was interrupted.
+func (s Set) Failed() bool {
+	return len(s.Failures) > 0
 }
 
 // ParsingReport allows to inspect the contents of a parsed benchmark.
 type ParsingReport struct {
-	NumberOfSets  int           `json:"sets"`
-	AnalyzedFiles []string      `json:"analyzed_files"`
-	Functions     []string      `json:"benchmark_functions"`
-	Metrics       []MinMaxRange `json:"benchmark_metrics"`
-	Signatures    []Signature   `json:"benchmark_signatures"`
+	NumberOfSets  int           `json:"sets" yaml:"sets"`
+	AnalyzedFiles []string      `json:"analyzed_files" yaml:"analyzed_files"`
+	Functions     []string      `json:"benchmark_functions" yaml:"benchmark_functions"`
+	Metrics       []MinMaxRange `json:"benchmark_metrics" yaml:"benchmark_metrics"`
+	Signatures    []Signature   `json:"benchmark_signatures" yaml:"benchmark_signatures"`
+	Failures      []Failure     `json:"failures,omitempty" yaml:"failures,omitempty"`
+	Runs          []Run         `json:"runs,omitempty" yaml:"runs,omitempty"`
+	TotalDuration string        `json:"total_run_duration,omitempty" yaml:"total_run_duration,omitempty"`
+}
+
+// Run describes the timing of the benchmark run captured in an input file.
+type Run struct {
+	File     string    `json:"file" yaml:"file"`
+	Start    time.Time `json:"start" yaml:"start"`
+	End      time.Time `json:"end" yaml:"end"`
+	Duration string    `json:"duration" yaml:"duration"`
+}
+
+// Failure describes a failed or interrupted benchmark run detected in an input file.
+type Failure struct {
+	File    string `json:"file" yaml:"file"`
+	Message string `json:"message" yaml:"message"`
 }
 
 // Signature describes a single benchmark function with its available metrics and environment.
+//
+// When a benchmark has been measured several times (e.g. with -count), Statistics
+// summarize the samples for each metric. MissingMetrics lists the known metrics that
+// were never measured for this benchmark (e.g. allocations without -benchmem).
 type Signature struct {
-	Name             string        `json:"benchmark_name"`
-	AvailableMetrics []MinMaxRange `json:"available_metrics"`
-	Environment      string        `json:"environment"`
+	Name             string              `json:"benchmark_name" yaml:"benchmark_name"`
+	Samples          int                 `json:"samples_count" yaml:"samples_count"`
+	AvailableMetrics []MinMaxRange       `json:"available_metrics" yaml:"available_metrics"`
+	MissingMetrics   []config.MetricName `json:"missing_metrics,omitempty" yaml:"missing_metrics,omitempty"`
+	Statistics       []MetricStatistics  `json:"statistics,omitempty" yaml:"statistics,omitempty"`
+	Environment      string              `json:"environment" yaml:"environment"`
+	GoVersion        string              `json:"go_version,omitempty" yaml:"go_version,omitempty"`
+	Label            string              `json:"label,omitempty" yaml:"label,omitempty"`
+}
+
+// MetricStatistics summarizes the samples of a single metric for a benchmark.
+type MetricStatistics struct {
+	Metric config.MetricName `json:"metric" yaml:"metric"`
+	Count  int               `json:"samples_count" yaml:"samples_count"`
+	Mean   float64           `json:"mean" yaml:"mean"`
+	StdDev float64           `json:"stddev" yaml:"stddev"`
 }
 
 // MinMaxRange captures the value range and measurement count for a single metric.
 type MinMaxRange struct {
-	Metric  config.MetricName `json:"metric"`
-	Count   int               `json:"measurements_count"`
-	Min     float64           `json:"min_value"`
-	Max     float64           `json:"max_value"`
-	Origins []string          `json:"origin_files"`
+	Metric  config.MetricName `json:"metric" yaml:"metric"`
+	Count   int               `json:"measurements_count" yaml:"measurements_count"`
+	Min     float64           `json:"min_value" yaml:"min_value"`
+	Max     float64           `json:"max_value" yaml:"max_value"`
+	Origins []string          `json:"origin_files" yaml:"origin_files"`
 }
 
 // Report produces a [ParsingReport], which allows for closer inspection of the content
@@ -59,6 +133,7 @@ func (p *BenchmarkParser) Report() ParsingReport {
 	seenFiles := make(map[string]struct{})
 	seenSignatures := make(map[string]struct{})
 	seenMetrics := make(map[config.MetricName]int)
+	var total time.Duration
 
 	for _, set := range p.sets {
 		r.NumberOfSets++
@@ -68,20 +143,32 @@ func (p *BenchmarkParser) Report() ParsingReport {
 			r.AnalyzedFiles = append(r.AnalyzedFiles, set.File)
 		}
 
-		for _, benchmarks := range set.Set {
-			for _, bench := range benchmarks {
-				_, seenSignature := seenSignatures[bench.Name]
-				if !seenSignature {
-					seenSignatures[bench.Name] = struct{}{}
-					r.Functions = append(r.Functions, bench.Name)
-				}
-
-				r.Signatures = append(r.Signatures, Signature{
-					Name:             bench.Name,
-					Environment:      set.Environment,
-					AvailableMetrics: extractMetrics(bench, set.File),
-				})
+		for _, message := range set.Failures {
+			r.Failures = append(r.Failures, Failure{File: set.File, Message: message})
+		}
+
+		if duration := set.Duration(); duration > 0 {
+			total += duration
+			r.Runs = append(r.Runs, Run{
+				File:     set.File,
+				Start:    set.Start,
+				End:      set.End,
+				Duration: duration.String(),
+			})
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(set.Set)) {
+			_, seenSignature := seenSignatures[name]
+			if !seenSignature {
+				seenSignatures[name] = struct{}{}
+				r.Functions = append(r.Functions, name)
 			}
+
+			signature := newSignature(name, set.Set[name], set.Custom, set.File, p.keepZero)
+			signature.Environment = set.Environment
+			signature.GoVersion = set.GoVersion
+			signature.Label = set.Label
+			r.Signatures = append(r.Signatures, signature)
 		}
 	}
 
@@ -95,67 +182,192 @@ func (p *BenchmarkParser) Report() ParsingReport {
 				continue
 			}
 
-			previous := r.Metrics[idx]
-			if m.Min < previous.Min {
-				previous.Min = m.Min
-			}
-			if m.Max > previous.Max {
-				previous.Max = m.Max
-			}
-			if len(m.Origins) > 0 && !slices.Contains(previous.Origins, m.Origins[0]) {
-				previous.Origins = append(previous.Origins, m.Origins[0])
-			}
-			previous.Count++
-			r.Metrics[idx] = previous
+			r.Metrics[idx] = mergeRange(r.Metrics[idx], m)
 		}
 	}
 
 	sort.Strings(r.Functions)
 
+	if total > 0 {
+		r.TotalDuration = total.String()
+	}
+
 	return r
 }
 
-func extractMetrics(bench *parse.Benchmark, file string) (metrics []MinMaxRange) {
-	if bench.NsPerOp > 0 {
-		metrics = append(metrics, MinMaxRange{
-			Metric:  config.MetricNsPerOp,
-			Min:     bench.NsPerOp,
-			Max:     bench.NsPerOp,
-			Origins: []string{file},
-			Count:   1,
-		})
+// newSignature builds the [Signature] of a benchmark from all its measured samples,
+// including custom metrics (by Ord of the benchmark). Zero values are retained with keepZero.
+func newSignature(name string, benchmarks []*parse.Benchmark, custom map[int]map[string]float64, file string, keepZero bool) Signature {
+	signature := Signature{
+		Name:    name,
+		Samples: len(benchmarks),
 	}
-	if bench.AllocsPerOp > 0 {
-		metrics = append(metrics, MinMaxRange{
-			Metric:  config.MetricAllocsPerOp,
-			Min:     float64(bench.AllocsPerOp),
-			Max:     float64(bench.AllocsPerOp),
-			Origins: []string{file},
-			Count:   1,
-		})
+	seenMetrics := make(map[config.MetricName]int)
+	values := make(map[config.MetricName][]float64)
+	var measured int
+
+	for _, bench := range benchmarks {
+		measured |= bench.Measured
+
+		for _, m := range extractMetrics(bench, custom[bench.Ord], file, keepZero) {
+			values[m.Metric] = append(values[m.Metric], m.Min)
+
+			idx, seenMetric := seenMetrics[m.Metric]
+			if !seenMetric {
+				seenMetrics[m.Metric] = len(signature.AvailableMetrics)
+				signature.AvailableMetrics = append(signature.AvailableMetrics, m)
+
+				continue
+			}
+
+			signature.AvailableMetrics[idx] = mergeRange(signature.AvailableMetrics[idx], m)
+		}
 	}
-	if bench.AllocedBytesPerOp > 0 {
-		metrics = append(metrics, MinMaxRange{
-			Metric:  config.MetricBytesPerOp,
-			Min:     float64(bench.AllocedBytesPerOp),
-			Max:     float64(bench.AllocedBytesPerOp),
-			Origins: []string{file},
-			Count:   1,
+
+	for _, metric := range config.AllMetricNames() {
+		flag := measuredFlag(metric)
+		if flag != 0 && measured&flag == 0 {
+			signature.MissingMetrics = append(signature.MissingMetrics, metric)
+		}
+	}
+
+	if len(benchmarks) < 2 {
+		return signature
+	}
+
+	for _, m := range signature.AvailableMetrics {
+		mean, stddev := meanStdDev(values[m.Metric])
+		signature.Statistics = append(signature.Statistics, MetricStatistics{
+			Metric: m.Metric,
+			Count:  len(values[m.Metric]),
+			Mean:   mean,
+			StdDev: stddev,
 		})
 	}
-	if bench.MBPerS > 0 {
+
+	return signature
+}
+
+// mergeRange merges the measurements of m into previous.
+func mergeRange(previous, m MinMaxRange) MinMaxRange {
+	if m.Min < previous.Min {
+		previous.Min = m.Min
+	}
+	if m.Max > previous.Max {
+		previous.Max = m.Max
+	}
+	for _, origin := range m.Origins {
+		if !slices.Contains(previous.Origins, origin) {
+			previous.Origins = append(previous.Origins, origin)
+		}
+	}
+	previous.Count += m.Count
+
+	return previous
+}
+
+// measuredFlag maps a metric to the corresponding [parse.Benchmark] Measured flag.
+//
+// The number of iterations has no flag: it is always reported by the benchmark framework.
+func measuredFlag(metric config.MetricName) int {
+	switch metric {
+	case config.MetricNsPerOp:
+		return parse.NsPerOp
+	case config.MetricAllocsPerOp:
+		return parse.AllocsPerOp
+	case config.MetricBytesPerOp:
+		return parse.AllocedBytesPerOp
+	case config.MetricMBPerS:
+		return parse.MBPerS
+	default:
+		return 0
+	}
+}
+
+// meanStdDev computes the mean and the sample standard deviation of values.
+func meanStdDev(values []float64) (mean, stddev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	if len(values) < 2 { //nolint:mnd // a standard deviation requires at least 2 samples
+		return mean, 0
+	}
+
+	var variance float64
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	variance /= float64(len(values) - 1)
+
+	return mean, math.Sqrt(variance)
+}
+
+// extractMetrics returns the measurements of a benchmark, as ranges of a single sample.
+//
+// Custom metrics are named after their unit (e.g. "peak-MB").
+// Zero values are considered missing, unless keepZero is enabled: then metrics measured with a zero value
+// (e.g. "0 allocs/op") are retained.
+func extractMetrics(bench *parse.Benchmark, custom map[string]float64, file string, keepZero bool) (metrics []MinMaxRange) {
+	add := func(metric config.MetricName, value float64, measured bool) {
+		if value == 0 && (!keepZero || !measured) {
+			return
+		}
+
 		metrics = append(metrics, MinMaxRange{
-			Metric:  config.MetricMBPerS,
-			Min:     bench.MBPerS,
-			Max:     bench.MBPerS,
+			Metric:  metric,
+			Min:     value,
+			Max:     value,
 			Origins: []string{file},
 			Count:   1,
 		})
 	}
 
+	for _, metric := range []config.MetricName{config.MetricNsPerOp, config.MetricAllocsPerOp, config.MetricBytesPerOp, config.MetricMBPerS} {
+		add(metric, metricValue(bench, metric), bench.Measured&measuredFlag(metric) != 0)
+	}
+	for _, unit := range slices.Sorted(maps.Keys(custom)) {
+		add(config.MetricName(unit), custom[unit], true)
+	}
+
 	return metrics
 }
 
+// MeasuredValue returns the value of a standard metric of a benchmark,
+// and whether this metric was actually reported (e.g. MB/s is only reported by benchmarks calling SetBytes).
+//
+// The number of iterations is always reported.
+func MeasuredValue(bench *parse.Benchmark, metric config.MetricName) (float64, bool) {
+	if flag := measuredFlag(metric); flag != 0 && bench.Measured&flag == 0 {
+		return 0, false
+	}
+
+	return metricValue(bench, metric), true
+}
+
+// metricValue returns the value of a standard metric of a benchmark.
+func metricValue(bench *parse.Benchmark, metric config.MetricName) float64 {
+	switch metric {
+	case config.MetricNsPerOp:
+		return bench.NsPerOp
+	case config.MetricAllocsPerOp:
+		return float64(bench.AllocsPerOp)
+	case config.MetricBytesPerOp:
+		return float64(bench.AllocedBytesPerOp)
+	case config.MetricMBPerS:
+		return bench.MBPerS
+	case config.MetricIterations:
+		return float64(bench.N)
+	default:
+		return 0
+	}
+}
+
 type BenchmarkParser struct {
 	options
 
@@ -166,52 +378,110 @@ type BenchmarkParser struct {
 
 // New [BenchmarkParser] ready to parse benchmark files.
 func New(cfg *config.Config, opts ...Option) *BenchmarkParser {
+	o := optionsWithDefaults(opts)
+
 	return &BenchmarkParser{
-		options: optionsWithDefaults(opts),
+		options: o,
 		config:  cfg,
-		l:       slog.Default().With(slog.String("module", "parser")),
+		l:       o.logger.With(slog.String("module", "parser")),
 	}
 }
 
+// ParseFiles parses benchmark files. The file name "-" stands for standard input.
+//
+// Errors are reported as a [failure.Error] for the input file.
 func (p *BenchmarkParser) ParseFiles(files ...string) error {
+	tracker := newProgressTracker(files, p.progress)
+
 	for _, file := range files {
-		var (
-			reader io.ReadCloser
-			err    error
-		)
-
-		if file == "-" {
-			reader = os.Stdin
-		} else {
-			reader, err = os.Open(file)
-			if err != nil {
-				return fmt.Errorf("input file %q: %w", file, err)
-			}
-		}
+		tracker.start(file)
 
-		set, err := p.ParseInput(reader)
+		set, err := p.parseFile(file, tracker)
 		if err != nil {
-			if file != "-" {
-				_ = reader.Close()
-			}
-
-			return err
+			return &failure.Error{Stage: failure.StageParse, File: file, Reason: err.Error(), Err: err}
 		}
 
-		set.File = file
-		p.sets = append(p.sets, set)
+		p.addSet(file, set)
+		tracker.done(set)
+	}
+
+	p.l.Info("benchmark input parsed", slog.Int("parsed_files", len(files)))
+
+	return nil
+}
 
-		if file != "-" {
-			_ = reader.Close()
+func (p *BenchmarkParser) parseFile(file string, tracker *progressTracker) (Set, error) {
+	if file == "-" {
+		return p.ParseInput(os.Stdin)
+	}
+
+	if p.format == FormatCriterion {
+		// criterion results are stored as a directory tree
+		set, err := p.parseCriterion(os.DirFS(file))
+		if err != nil {
+			return Set{}, fmt.Errorf("input directory %q: %w", file, err)
 		}
+
+		return set, nil
 	}
 
-	p.l.Info("benchmark input parsed", slog.Int("parsed_files", len(files)))
+	reader, err := os.Open(file)
+	if err != nil {
+		return Set{}, fmt.Errorf("input file %q: %w", file, err)
+	}
+	defer reader.Close()
+
+	return p.ParseInput(tracker.reader(reader))
+}
+
+// ParseReader parses benchmark input from a stream and adds it to the parsed sets.
+//
+// The name identifies the input, like a file name would do.
+func (p *BenchmarkParser) ParseReader(name string, r io.Reader) error {
+	set, err := p.ParseInput(r)
+	if err != nil {
+		return err
+	}
+	p.addSet(name, set)
 
 	return nil
 }
 
+// AddSets adds sets parsed earlier (e.g. restored from a cache) as is.
+func (p *BenchmarkParser) AddSets(sets ...Set) {
+	p.sets = append(p.sets, sets...)
+}
+
+// addSet adds a parsed set to the parsed sets, identified by its input name.
+func (p *BenchmarkParser) addSet(name string, set Set) {
+	set.File = name
+	set.Label = p.labels[name]
+	if env, ok := p.environments[name]; ok {
+		set.Environment = env
+	}
+	if set.GoVersion == "" {
+		set.GoVersion = p.goVersion
+	}
+	p.sets = append(p.sets, set)
+}
+
 func (p *BenchmarkParser) ParseInput(r io.Reader) (Set, error) {
+	if len(p.plugin) > 0 {
+		return p.parsePlugin(r)
+	}
+
+	switch p.format {
+	case FormatJMH:
+		return p.parseJMH(r)
+	case FormatHyperfine:
+		return p.parseHyperfine(r)
+	case FormatCriterion:
+		return Set{}, errors.New("criterion results are stored in a directory: they may not be parsed from a stream")
+	case FormatGo, "":
+	default:
+		return Set{}, fmt.Errorf("unsupported input format %q (should be one of %v)", p.format, AllFormats())
+	}
+
 	if p.isJSON {
 		return p.parseJSON(r)
 	}
@@ -223,36 +493,38 @@ func (p *BenchmarkParser) Sets() []Set {
 	return p.sets
 }
 
+// parseText parses the text output of `go test -bench`.
+//
+// The input is streamed line by line: environment lines and benchmark lines are extracted
+// in a single pass, without retaining the full input in memory.
 func (p *BenchmarkParser) parseText(r io.Reader) (Set, error) {
-	// Use TeeReader to capture input while parsing: the benchmark parser
-	// consumes the reader, and the buffer captures a copy for environment extraction.
-	var buf bytes.Buffer
-	tee := io.TeeReader(r, &buf)
+	builder := newSetBuilder(p.retains)
+	builder.detectFailures = true
+	scanner := bufio.NewScanner(r)
 
-	// Parse benchmarks from the tee reader
-	set, err := parse.ParseSet(tee)
-	if err != nil {
-		return Set{}, err
+	for scanner.Scan() {
+		builder.addLine(scanner.Text())
 	}
 
-	// Extract environment info from the captured copy
-	environment := extractEnvironment(buf.String())
-
-	s := Set{
-		Set:         set,
-		Environment: environment,
+	if err := scanner.Err(); err != nil {
+		return Set{}, fmt.Errorf("scanning input: %w", err)
 	}
 
-	return s, nil
+	return builder.build(), nil
 }
 
 // parseJSON parses JSON output from `go test -json -bench`.
 // It extracts the Output fields from "output" events and feeds them
-// to the standard benchmark parser.
+// line by line to the benchmark line parser.
+//
+// The output of a single benchmark may be split over several events:
+// partial lines are reassembled before being parsed.
+//
+// Failures are detected from "fail" action events.
 func (p *BenchmarkParser) parseJSON(r io.Reader) (Set, error) {
-	// Read JSON events line by line and extract Output fields
-	var textOutput strings.Builder
+	builder := newSetBuilder(p.retains)
 	scanner := bufio.NewScanner(r)
+	var pending strings.Builder // partial line, not terminated yet
 
 	for scanner.Scan() {
 		line := scanner.Bytes()
@@ -266,32 +538,223 @@ func (p *BenchmarkParser) parseJSON(r io.Reader) (Set, error) {
 			continue
 		}
 
+		builder.observe(event.Time)
+
+		if event.Action == "fail" {
+			builder.addFailure(failedEventMessage(event))
+
+			continue
+		}
+
 		// Only collect output from "output" action events
-		if event.Action == "output" && event.Output != "" {
-			textOutput.WriteString(event.Output)
+		if event.Action != "output" || event.Output == "" {
+			continue
 		}
+
+		output := event.Output
+		for {
+			before, after, found := strings.Cut(output, "\n")
+			if !found {
+				break
+			}
+
+			pending.WriteString(before)
+			builder.addLine(pending.String())
+			pending.Reset()
+			output = after
+		}
+		pending.WriteString(output)
 	}
 
 	if err := scanner.Err(); err != nil {
 		return Set{}, fmt.Errorf("scanning input: %w", err)
 	}
 
-	// Extract environment info
-	outputText := textOutput.String()
-	environment := extractEnvironment(outputText)
+	if pending.Len() > 0 {
+		builder.addLine(pending.String())
+	}
+
+	return builder.build(), nil
+}
+
+// setBuilder accumulates benchmark results and environment information from lines of benchmark output.
+type setBuilder struct {
+	set            parse.Set
+	custom         map[int]map[string]float64
+	ord            int
+	environment    []string
+	goVersion      string
+	failures       []string
+	start          time.Time
+	end            time.Time
+	retains        func(string) bool
+	detectFailures bool // detect failures from the text output
+}
+
+func newSetBuilder(retains func(string) bool) *setBuilder {
+	return &setBuilder{
+		set:     make(parse.Set),
+		retains: retains,
+	}
+}
+
+// addLine processes a single line of benchmark output.
+func (b *setBuilder) addLine(line string) {
+	if version, ok := goVersionPart(line); ok && b.goVersion == "" {
+		b.goVersion = version
+	}
+
+	if part, ok := environmentPart(line); ok {
+		b.environment = append(b.environment, part)
+
+		return
+	}
+
+	if b.detectFailures && isFailure(line) {
+		b.addFailure(strings.TrimSpace(line))
+
+		return
+	}
+
+	bench, err := parse.ParseLine(line)
+	if err != nil {
+		// not a benchmark line
+		return
+	}
+
+	if b.addBenchmark(bench) {
+		b.addCustomMetrics(bench, customMetrics(line))
+	}
+}
+
+// addBenchmark adds a benchmark to the set, unless filtered out. It reports whether the benchmark was added.
+func (b *setBuilder) addBenchmark(bench *parse.Benchmark) bool {
+	if !b.retains(bench.Name) {
+		return false
+	}
+
+	// intern the name: repeated runs of a benchmark (e.g. with -count) share a single string,
+	// and the name no longer retains the line it was parsed from
+	if runs := b.set[bench.Name]; len(runs) > 0 {
+		bench.Name = runs[0].Name
+	} else {
+		bench.Name = strings.Clone(bench.Name)
+	}
+
+	bench.Ord = b.ord
+	b.ord++
+	b.set[bench.Name] = append(b.set[bench.Name], bench)
+
+	return true
+}
+
+// addCustomMetrics records the measurements of custom metrics of a benchmark added to the set.
+func (b *setBuilder) addCustomMetrics(bench *parse.Benchmark, custom map[string]float64) {
+	if len(custom) == 0 {
+		return
+	}
+
+	if b.custom == nil {
+		b.custom = make(map[int]map[string]float64)
+	}
+	b.custom[bench.Ord] = custom
+}
+
+// customMetrics extracts the measurements of custom metrics from a benchmark line, by unit.
+//
+// Custom metrics are reported with testing.B.ReportMetric, as pairs of a value and a unit other than
+// the standard ones, e.g. "BenchmarkFoo-16  1000  1234 ns/op  12.5 peak-MB".
+func customMetrics(line string) map[string]float64 {
+	var custom map[string]float64
+
+	fields := strings.Fields(line)
+	// the name and the number of iterations come first, then pairs of value and unit
+	for i := 2; i+1 < len(fields); i += 2 {
+		unit := fields[i+1]
+		if config.IsStandardUnit(unit) {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			continue
+		}
+
+		if custom == nil {
+			custom = make(map[string]float64)
+		}
+		custom[unit] = value
+	}
+
+	return custom
+}
 
-	// Now parse the collected text output using the standard parser
-	set, err := parse.ParseSet(strings.NewReader(outputText))
+// observe the timestamp of an event, formatted as RFC3339. Invalid timestamps are ignored.
+func (b *setBuilder) observe(timestamp string) {
+	if timestamp == "" {
+		return
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
 	if err != nil {
-		return Set{}, fmt.Errorf("parsing benchmark output: %w", err)
+		return
+	}
+
+	if b.start.IsZero() || t.Before(b.start) {
+		b.start = t
 	}
 
-	s := Set{
-		Set:         set,
-		Environment: environment,
+	if t.After(b.end) {
+		b.end = t
+	}
+}
+
+func (b *setBuilder) addFailure(message string) {
+	b.failures = append(b.failures, message)
+}
+
+func (b *setBuilder) build() Set {
+	return Set{
+		Set:         b.set,
+		Custom:      b.custom,
+		Environment: joinEnvironment(b.environment),
+		GoVersion:   b.goVersion,
+		Failures:    b.failures,
+		Start:       b.start,
+		End:         b.end,
+	}
+}
+
+// isFailure detects lines in the text output of go test that denote a failed or interrupted run.
+func isFailure(line string) bool {
+	line = strings.TrimSpace(line)
+
+	for _, prefix := range []string{
+		"FAIL",            // FAIL, FAIL\t{package}
+		"--- FAIL:",       // failed benchmark or test
+		"panic:",          // panic in benchmark
+		"signal: ",        // interrupted, killed
+		"*** Test killed", // timeout
+		"exit status ",    // non-zero exit
+	} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
 	}
 
-	return s, nil
+	return false
+}
+
+// failedEventMessage builds a failure message from a "fail" event.
+func failedEventMessage(event testEvent) string {
+	switch {
+	case event.Test != "":
+		return "--- FAIL: " + event.Test
+	case event.Package != "":
+		return "FAIL\t" + event.Package
+	default:
+		return "FAIL"
+	}
 }
 
 // extractEnvironment extracts environment information from benchmark output.
@@ -299,22 +762,58 @@ func (p *BenchmarkParser) parseJSON(r io.Reader) (Set, error) {
 func extractEnvironment(text string) string {
 	var parts []string
 	for line := range strings.SplitSeq(text, "\n") {
-		line = strings.TrimSpace(line)
-
-		switch {
-		case strings.HasPrefix(line, "goversion: "):
-			parts = append(parts, strings.TrimPrefix(line, "goversion: "))
-		case strings.HasPrefix(line, "goos: "):
-			parts = append(parts, strings.TrimPrefix(line, "goos: "))
-		case strings.HasPrefix(line, "goarch: "):
-			parts = append(parts, strings.TrimPrefix(line, "goarch: "))
-		case strings.HasPrefix(line, "cpu: "):
-			cpu := strings.TrimPrefix(line, "cpu: ")
-			cpu = strings.TrimSpace(cpu)
-			parts = append(parts, "cpu: "+cpu)
+		if part, ok := environmentPart(line); ok {
+			parts = append(parts, part)
 		}
 	}
 
+	return joinEnvironment(parts)
+}
+
+// environmentPart extracts the environment information carried by a single line of output, if any.
+func environmentPart(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+
+	switch {
+	case strings.HasPrefix(line, "goversion: "):
+		return strings.TrimPrefix(line, "goversion: "), true
+	case strings.HasPrefix(line, "goos: "):
+		return strings.TrimPrefix(line, "goos: "), true
+	case strings.HasPrefix(line, "goarch: "):
+		return strings.TrimPrefix(line, "goarch: "), true
+	case strings.HasPrefix(line, "cpu: "):
+		cpu := strings.TrimPrefix(line, "cpu: ")
+		cpu = strings.TrimSpace(cpu)
+
+		return "cpu: " + cpu, true
+	default:
+		return "", false
+	}
+}
+
+// goVersionPart extracts the version of the Go toolchain from a single line of output, if any.
+//
+// The version is found in a "goversion: go1.23.4" line, or in the output of the "go version" command
+// (e.g. "go version go1.23.4 linux/amd64"), often captured along with benchmarks.
+func goVersionPart(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+
+	var version string
+	switch {
+	case strings.HasPrefix(line, "goversion: "):
+		version = strings.TrimPrefix(line, "goversion: ")
+	case strings.HasPrefix(line, "go version go"):
+		version = strings.TrimPrefix(line, "go version ")
+	default:
+		return "", false
+	}
+
+	version, _, _ = strings.Cut(strings.TrimSpace(version), " ")
+
+	return version, version != ""
+}
+
+func joinEnvironment(parts []string) string {
 	if len(parts) == 0 {
 		return "unknown environment"
 	}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+	"unsafe"
 
 	"github.com/fredbi/benchviz/internal/config"
 
@@ -92,6 +94,128 @@ func TestParseTextMultipleFiles(t *testing.T) {
 	}
 }
 
+func TestParseFilesWithLabels(t *testing.T) {
+	cfg := &config.Config{}
+	p := New(cfg, WithLabels(map[string]string{
+		testdataPath("run.txt"): "v1.2",
+	}))
+
+	require.NoError(t, p.ParseFiles(testdataPath("run.txt"), testdataPath("run1.txt")))
+
+	sets := p.Sets()
+	require.Len(t, sets, 2)
+	assert.Equal(t, "v1.2", sets[0].Label)
+	assert.Empty(t, sets[1].Label)
+
+	report := p.Report()
+	require.NotEmpty(t, report.Signatures)
+	assert.Equal(t, "v1.2", report.Signatures[0].Label)
+}
+
+func TestParseFilesWithEnvironments(t *testing.T) {
+	cfg := &config.Config{}
+	p := New(cfg, WithEnvironments(map[string]string{
+		testdataPath("run.txt"): "AMD 5800X",
+	}))
+
+	require.NoError(t, p.ParseFiles(testdataPath("run.txt"), testdataPath("run1.txt")))
+
+	sets := p.Sets()
+	require.Len(t, sets, 2)
+	assert.Equal(t, "AMD 5800X", sets[0].Environment)
+	assert.NotEqual(t, "AMD 5800X", sets[1].Environment) // extracted from the input
+}
+
+func TestParseFilesWithFilters(t *testing.T) {
+	cfg := &config.Config{}
+
+	t.Run("with match", func(t *testing.T) {
+		p := New(cfg, WithMatch(regexp.MustCompile(`ReadJSON`)))
+		require.NoError(t, p.ParseFiles(testdataPath("run.txt")))
+
+		set := p.Sets()[0]
+		require.NotEmpty(t, set.Set)
+		for name := range set.Set {
+			assert.Contains(t, name, "ReadJSON")
+		}
+		assert.Contains(t, set.Environment, "linux")
+	})
+
+	t.Run("with exclude", func(t *testing.T) {
+		p := New(cfg, WithExclude(regexp.MustCompile(`easyjson`)))
+		require.NoError(t, p.ParseFiles(testdataPath("run.txt")))
+
+		set := p.Sets()[0]
+		require.NotEmpty(t, set.Set)
+		for name := range set.Set {
+			assert.NotContains(t, name, "easyjson")
+		}
+	})
+
+	t.Run("with match and exclude", func(t *testing.T) {
+		p := New(cfg,
+			WithParseJSON(true),
+			WithMatch(regexp.MustCompile(`Greater`)),
+			WithExclude(regexp.MustCompile(`/reflect/`)),
+		)
+		require.NoError(t, p.ParseFiles(testdataPath("sample_generics.json")))
+
+		set := p.Sets()[0]
+		assert.Contains(t, set.Set, "BenchmarkGreater/generic/int-16")
+		assert.NotContains(t, set.Set, "BenchmarkGreater/reflect/int-16")
+		assert.NotContains(t, set.Set, "BenchmarkLess/generic/int-16")
+	})
+}
+
+func TestReportStatistics(t *testing.T) {
+	cfg := &config.Config{}
+	p := New(cfg)
+
+	input := `BenchmarkFoo-8   1000   100 ns/op   56 B/op   3 allocs/op
+BenchmarkFoo-8   1000   200 ns/op   56 B/op   3 allocs/op
+BenchmarkFoo-8   1000   300 ns/op   56 B/op   3 allocs/op
+BenchmarkBar-8   1000   100 ns/op
+`
+	require.NoError(t, p.ParseReader("input.txt", strings.NewReader(input)))
+
+	report := p.Report()
+	require.Len(t, report.Signatures, 2)
+
+	bar := report.Signatures[0]
+	assert.Equal(t, "BenchmarkBar-8", bar.Name)
+	assert.Equal(t, 1, bar.Samples)
+	assert.Empty(t, bar.Statistics)
+	assert.Equal(t, []config.MetricName{
+		config.MetricAllocsPerOp,
+		config.MetricBytesPerOp,
+		config.MetricMBPerS,
+	}, bar.MissingMetrics)
+
+	foo := report.Signatures[1]
+	assert.Equal(t, "BenchmarkFoo-8", foo.Name)
+	assert.Equal(t, 3, foo.Samples)
+	assert.Equal(t, []config.MetricName{config.MetricMBPerS}, foo.MissingMetrics)
+	require.Len(t, foo.AvailableMetrics, 3)
+	assert.Equal(t, config.MetricNsPerOp, foo.AvailableMetrics[0].Metric)
+	assert.Equal(t, 3, foo.AvailableMetrics[0].Count)
+	assert.InDelta(t, 100.0, foo.AvailableMetrics[0].Min, 1e-9)
+	assert.InDelta(t, 300.0, foo.AvailableMetrics[0].Max, 1e-9)
+
+	require.Len(t, foo.Statistics, 3)
+	timings := foo.Statistics[0]
+	assert.Equal(t, config.MetricNsPerOp, timings.Metric)
+	assert.Equal(t, 3, timings.Count)
+	assert.InDelta(t, 200.0, timings.Mean, 1e-9)
+	assert.InDelta(t, 100.0, timings.StdDev, 1e-9)
+
+	allocs := foo.Statistics[1]
+	assert.InDelta(t, 3.0, allocs.Mean, 1e-9)
+	assert.InDelta(t, 0.0, allocs.StdDev, 1e-9)
+
+	require.NotEmpty(t, report.Metrics)
+	assert.Equal(t, 4, report.Metrics[0].Count)
+}
+
 func TestParseJSON(t *testing.T) {
 	cfg := &config.Config{}
 	p := New(cfg, WithParseJSON(true))
@@ -118,6 +242,33 @@ func TestParseJSON(t *testing.T) {
 	assert.Positive(t, benchmarks[0].NsPerOp)
 }
 
+func TestParseJSONRunDuration(t *testing.T) {
+	cfg := &config.Config{}
+	p := New(cfg, WithParseJSON(true))
+
+	require.NoError(t, p.ParseFiles(testdataPath("sample_json.txt")))
+
+	set := p.Sets()[0]
+	assert.False(t, set.Start.IsZero())
+	assert.True(t, set.End.After(set.Start))
+	assert.Positive(t, set.Duration())
+
+	report := p.Report()
+	require.Len(t, report.Runs, 1)
+	assert.Equal(t, set.Duration().String(), report.Runs[0].Duration)
+	assert.Equal(t, set.Duration().String(), report.TotalDuration)
+}
+
+func TestParseTextNoRunDuration(t *testing.T) {
+	cfg := &config.Config{}
+	p := New(cfg)
+
+	require.NoError(t, p.ParseFiles(testdataPath("run.txt")))
+
+	assert.Zero(t, p.Sets()[0].Duration())
+	assert.Empty(t, p.Report().TotalDuration)
+}
+
 func TestParseJSONMultipleBenchmarks(t *testing.T) {
 	cfg := &config.Config{}
 	p := New(cfg, WithParseJSON(true))
@@ -229,6 +380,137 @@ BenchmarkFoo-8   1000   1234 ns/op   56 B/op   3 allocs/op
 	assert.Contains(t, set.Environment, "linux")
 }
 
+func TestParseJSONSplitOutput(t *testing.T) {
+	p := New(&config.Config{}, WithParseJSON(true))
+
+	// the output of a benchmark may be split over several events, and an event may hold several lines
+	input := `{"Action":"output","Output":"goos: linux\nBenchmark"}
+{"Action":"output","Output":"Foo-8   1000"}
+{"Action":"output","Output":"   1234 ns/op\nBenchmarkBar-8   2000"}
+{"Action":"output","Output":"   567.8 ns/op"}
+`
+	set, err := p.ParseInput(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Contains(t, set.Set, "BenchmarkFoo-8")
+	require.Contains(t, set.Set, "BenchmarkBar-8")
+	assert.InDelta(t, 1234, set.Set["BenchmarkFoo-8"][0].NsPerOp, 1e-9)
+	assert.InDelta(t, 567.8, set.Set["BenchmarkBar-8"][0].NsPerOp, 1e-9)
+	assert.Contains(t, set.Environment, "linux")
+}
+
+func TestParseInputGoVersion(t *testing.T) {
+	t.Run("should capture the Go version from a goversion line", func(t *testing.T) {
+		p := New(&config.Config{}, WithParseJSON(true))
+
+		input := `{"Action":"output","Output":"goversion: go1.23.4\n"}
+{"Action":"output","Output":"goos: linux\n"}
+{"Action":"output","Output":"BenchmarkBar-4   2000   567.8 ns/op\n"}
+`
+		set, err := p.ParseInput(strings.NewReader(input))
+		require.NoError(t, err)
+		assert.EqualT(t, "go1.23.4", set.GoVersion)
+		assert.StringContainsT(t, set.Environment, "go1.23.4")
+	})
+
+	t.Run("should capture the Go version from the output of go version", func(t *testing.T) {
+		p := New(&config.Config{})
+
+		input := `go version go1.22.10 linux/amd64
+goos: linux
+BenchmarkFoo-8   1000   1234 ns/op
+`
+		set, err := p.ParseInput(strings.NewReader(input))
+		require.NoError(t, err)
+		assert.EqualT(t, "go1.22.10", set.GoVersion)
+		assert.NotContains(t, set.Environment, "go1.22.10")
+	})
+
+	t.Run("should default to the Go version of options", func(t *testing.T) {
+		p := New(&config.Config{}, WithGoVersion("go1.26.0"))
+
+		require.NoError(t, p.ParseReader("a", strings.NewReader("BenchmarkFoo-8   1000   1234 ns/op\n")))
+		require.NoError(t, p.ParseReader("b", strings.NewReader("goversion: go1.25.1\nBenchmarkFoo-8   1000   1234 ns/op\n")))
+
+		sets := p.Sets()
+		require.Len(t, sets, 2)
+		assert.EqualT(t, "go1.26.0", sets[0].GoVersion)
+		assert.EqualT(t, "go1.25.1", sets[1].GoVersion)
+		assert.EqualT(t, "go1.26.0", p.Report().Signatures[0].GoVersion)
+	})
+}
+
+func TestParseInputCustomMetrics(t *testing.T) {
+	p := New(&config.Config{})
+
+	input := `BenchmarkFoo-8   1000   1234 ns/op   56 B/op   12.5 peak-MB   3 allocs/op
+BenchmarkFoo-8   1000   1200 ns/op   56 B/op   14.5 peak-MB   3 allocs/op
+BenchmarkBar-8   1000   567 ns/op
+`
+	require.NoError(t, p.ParseReader("input.txt", strings.NewReader(input)))
+	set := p.Sets()[0]
+
+	runs := set.Set["BenchmarkFoo-8"]
+	require.Len(t, runs, 2)
+	assert.Equal(t, map[string]float64{"peak-MB": 12.5}, set.CustomMetrics(runs[0]))
+	assert.Equal(t, map[string]float64{"peak-MB": 14.5}, set.CustomMetrics(runs[1]))
+	assert.Empty(t, set.CustomMetrics(set.Set["BenchmarkBar-8"][0]))
+
+	report := p.Report()
+	require.Len(t, report.Metrics, 4)
+	assert.Equal(t, config.MetricName("peak-MB"), report.Metrics[3].Metric)
+	assert.InDelta(t, 12.5, report.Metrics[3].Min, 1e-9)
+	assert.InDelta(t, 14.5, report.Metrics[3].Max, 1e-9)
+}
+
+func TestReportZeroMetrics(t *testing.T) {
+	input := `BenchmarkFoo-8   1000   1234 ns/op   0 B/op   0 allocs/op
+BenchmarkBar-8   1000   567 ns/op
+`
+
+	t.Run("should consider zero values missing by default", func(t *testing.T) {
+		p := New(&config.Config{})
+		require.NoError(t, p.ParseReader("input.txt", strings.NewReader(input)))
+
+		report := p.Report()
+		require.Len(t, report.Metrics, 1)
+		assert.Equal(t, config.MetricNsPerOp, report.Metrics[0].Metric)
+	})
+
+	t.Run("should retain measured zero values", func(t *testing.T) {
+		p := New(&config.Config{}, WithZeroMetrics(true))
+		require.NoError(t, p.ParseReader("input.txt", strings.NewReader(input)))
+
+		report := p.Report()
+		require.Len(t, report.Metrics, 3)
+		assert.Equal(t, config.MetricAllocsPerOp, report.Metrics[1].Metric)
+		assert.Zero(t, report.Metrics[1].Max)
+
+		// metrics that were not measured remain missing
+		require.Len(t, report.Signatures, 2)
+		assert.Equal(t, "BenchmarkBar-8", report.Signatures[0].Name)
+		assert.Len(t, report.Signatures[0].AvailableMetrics, 1)
+		assert.Len(t, report.Signatures[1].AvailableMetrics, 3)
+	})
+}
+
+func TestParseInputInternsNames(t *testing.T) {
+	p := New(&config.Config{})
+
+	input := `BenchmarkFoo-8   1000   1234 ns/op
+BenchmarkFoo-8   1000   1200 ns/op
+BenchmarkFoo-8   1000   1250 ns/op
+`
+	set, err := p.ParseInput(strings.NewReader(input))
+	require.NoError(t, err)
+
+	runs := set.Set["BenchmarkFoo-8"]
+	require.Len(t, runs, 3)
+	for _, run := range runs[1:] {
+		// repeated runs share the storage of a single name
+		assert.True(t, unsafe.StringData(runs[0].Name) == unsafe.StringData(run.Name))
+	}
+}
+
 func TestParseInputJSON(t *testing.T) {
 	cfg := &config.Config{}
 	p := New(cfg, WithParseJSON(true))
@@ -244,6 +526,73 @@ func TestParseInputJSON(t *testing.T) {
 	assert.Contains(t, set.Set, "BenchmarkBar-4")
 }
 
+func TestParseInputJSONSplitLines(t *testing.T) {
+	cfg := &config.Config{}
+	p := New(cfg, WithParseJSON(true))
+
+	// test2json emits the benchmark name and its measurements as separate output events
+	input := `{"Action":"output","Output":"goos: linux\n"}
+{"Action":"output","Output":"BenchmarkSplit-4   \t"}
+{"Action":"output","Output":"    2000\t       567.8 ns/op\t      32 B/op\t       1 allocs/op\n"}
+{"Action":"output","Output":"BenchmarkTail-4   1000   100.0 ns/op"}
+`
+	set, err := p.ParseInput(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Contains(t, set.Set, "BenchmarkSplit-4")
+	require.Contains(t, set.Set, "BenchmarkTail-4")
+	assert.InDelta(t, 567.8, set.Set["BenchmarkSplit-4"][0].NsPerOp, 1e-6)
+	assert.Equal(t, uint64(32), set.Set["BenchmarkSplit-4"][0].AllocedBytesPerOp)
+	assert.Equal(t, "linux", set.Environment)
+}
+
+func TestParseInputFailures(t *testing.T) {
+	cfg := &config.Config{}
+
+	t.Run("text run without failure", func(t *testing.T) {
+		p := New(cfg)
+		require.NoError(t, p.ParseFiles(testdataPath("run.txt")))
+		assert.False(t, p.Sets()[0].Failed())
+		assert.Empty(t, p.Report().Failures)
+	})
+
+	t.Run("text run with panic", func(t *testing.T) {
+		p := New(cfg)
+		input := `goos: linux
+BenchmarkFoo-8   1000   1234 ns/op
+panic: runtime error: index out of range
+exit status 2
+FAIL	example.com/pkg	0.012s
+`
+		set, err := p.ParseInput(strings.NewReader(input))
+		require.NoError(t, err)
+		assert.True(t, set.Failed())
+		assert.Equal(t, []string{
+			"panic: runtime error: index out of range",
+			"exit status 2",
+			"FAIL\texample.com/pkg\t0.012s",
+		}, set.Failures)
+		assert.Contains(t, set.Set, "BenchmarkFoo-8")
+	})
+
+	t.Run("JSON run with fail actions", func(t *testing.T) {
+		p := New(cfg, WithParseJSON(true))
+		input := `{"Action":"output","Output":"BenchmarkBar-4   2000   567.8 ns/op\n"}
+{"Action":"output","Test":"BenchmarkBaz","Output":"--- FAIL: BenchmarkBaz\n"}
+{"Action":"fail","Package":"example.com/pkg","Test":"BenchmarkBaz"}
+{"Action":"fail","Package":"example.com/pkg"}
+`
+		require.NoError(t, p.ParseReader("bench.json", strings.NewReader(input)))
+
+		set := p.Sets()[0]
+		assert.True(t, set.Failed())
+		assert.Equal(t, []string{"--- FAIL: BenchmarkBaz", "FAIL\texample.com/pkg"}, set.Failures)
+
+		report := p.Report()
+		require.Len(t, report.Failures, 2)
+		assert.Equal(t, "bench.json", report.Failures[0].File)
+	})
+}
+
 func TestParseInputJSONSkipsNonOutputActions(t *testing.T) {
 	cfg := &config.Config{}
 	p := New(cfg, WithParseJSON(true))
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/benchmark/parse"
+)
+
+// CanonicalSet is the canonical representation of benchmark results in JSON.
+//
+// Input plugins (see [WithPlugin]) convert arbitrary benchmark formats to this representation.
+//
+// Example:
+//
+//	{
+//	  "environment": "linux amd64",
+//	  "benchmarks": [
+//	    {"name": "BenchmarkSort/small", "iterations": 1000, "nsPerOp": 123.4, "allocsPerOp": 2}
+//	  ]
+//	}
+type CanonicalSet struct {
+	Environment string               `json:"environment,omitempty"`
+	Benchmarks  []CanonicalBenchmark `json:"benchmarks"`
+	Failures    []string             `json:"failures,omitempty"`
+}
+
+// CanonicalBenchmark is a single benchmark measurement in a [CanonicalSet].
+//
+// Metrics are named like metric IDs in the configuration. Missing metrics are not measured.
+type CanonicalBenchmark struct {
+	Name        string   `json:"name"`
+	Iterations  int      `json:"iterations,omitempty"`
+	NsPerOp     *float64 `json:"nsPerOp,omitempty"`
+	AllocsPerOp *uint64  `json:"allocsPerOp,omitempty"`
+	BytesPerOp  *uint64  `json:"bytesPerOp,omitempty"`
+	MBytesPerS  *float64 `json:"MBytesPerS,omitempty"`
+}
+
+// parsePlugin converts the input with the plugin command, then parses its output as a [CanonicalSet].
+func (p *BenchmarkParser) parsePlugin(r io.Reader) (Set, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(p.plugin[0], p.plugin[1:]...) //nolint:gosec,noctx // the plugin command is provided by the user
+	cmd.Stdin = r
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return Set{}, fmt.Errorf("running input plugin %q: %w: %s", strings.Join(p.plugin, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	set, err := p.parseCanonical(&stdout)
+	if err != nil {
+		return Set{}, fmt.Errorf("input plugin %q: %w", strings.Join(p.plugin, " "), err)
+	}
+
+	p.l.Debug("input converted by plugin", slog.String("plugin", p.plugin[0]), slog.Int("benchmarks", len(set.Set)))
+
+	return set, nil
+}
+
+// parseCanonical parses benchmark results represented as a [CanonicalSet].
+func (p *BenchmarkParser) parseCanonical(r io.Reader) (Set, error) {
+	var canonical CanonicalSet
+
+	if err := json.NewDecoder(r).Decode(&canonical); err != nil {
+		return Set{}, fmt.Errorf("decoding canonical benchmark set: %w", err)
+	}
+
+	builder := newSetBuilder(p.retains)
+	for _, b := range canonical.Benchmarks {
+		if b.Name == "" {
+			return Set{}, errors.New("decoding canonical benchmark set: benchmark without a name")
+		}
+
+		bench := &parse.Benchmark{
+			Name: b.Name,
+			N:    b.Iterations,
+		}
+		if b.NsPerOp != nil {
+			bench.NsPerOp = *b.NsPerOp
+			bench.Measured |= parse.NsPerOp
+		}
+		if b.AllocsPerOp != nil {
+			bench.AllocsPerOp = *b.AllocsPerOp
+			bench.Measured |= parse.AllocsPerOp
+		}
+		if b.BytesPerOp != nil {
+			bench.AllocedBytesPerOp = *b.BytesPerOp
+			bench.Measured |= parse.AllocedBytesPerOp
+		}
+		if b.MBytesPerS != nil {
+			bench.MBPerS = *b.MBytesPerS
+			bench.Measured |= parse.MBPerS
+		}
+
+		builder.addBenchmark(bench)
+	}
+
+	for _, failure := range canonical.Failures {
+		builder.addFailure(failure)
+	}
+
+	if canonical.Environment != "" {
+		builder.environment = append(builder.environment, canonical.Environment)
+	}
+
+	return builder.build(), nil
+}
+
+// Canonical returns the [CanonicalSet] representation of the parsed benchmarks.
+//
+// Benchmarks are sorted by name, then by their ordinal position in the run.
+func (s Set) Canonical() CanonicalSet {
+	canonical := CanonicalSet{
+		Environment: s.Environment,
+		Benchmarks:  make([]CanonicalBenchmark, 0, len(s.Set)),
+		Failures:    s.Failures,
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(s.Set)) {
+		for _, bench := range s.Set[name] {
+			b := CanonicalBenchmark{
+				Name:       bench.Name,
+				Iterations: bench.N,
+			}
+			if bench.Measured&parse.NsPerOp != 0 {
+				b.NsPerOp = &bench.NsPerOp
+			}
+			if bench.Measured&parse.AllocsPerOp != 0 {
+				b.AllocsPerOp = &bench.AllocsPerOp
+			}
+			if bench.Measured&parse.AllocedBytesPerOp != 0 {
+				b.BytesPerOp = &bench.AllocedBytesPerOp
+			}
+			if bench.Measured&parse.MBPerS != 0 {
+				b.MBytesPerS = &bench.MBPerS
+			}
+
+			canonical.Benchmarks = append(canonical.Benchmarks, b)
+		}
+	}
+
+	return canonical
+}
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+)
+
+const canonicalInput = `{
+  "environment": "linux amd64",
+  "benchmarks": [
+    {"name": "BenchmarkSort/small-8", "iterations": 1000, "nsPerOp": 123.4, "allocsPerOp": 2},
+    {"name": "BenchmarkSort/large-8", "iterations": 10, "nsPerOp": 9876.5, "bytesPerOp": 4096, "MBytesPerS": 12.5},
+    {"name": "BenchmarkOther-8", "nsPerOp": 1}
+  ],
+  "failures": ["timeout"]
+}`
+
+func TestParseCanonical(t *testing.T) {
+	p := New(&config.Config{}, WithExclude(regexp.MustCompile(`Other`)))
+
+	set, err := p.parseCanonical(strings.NewReader(canonicalInput))
+	require.NoError(t, err)
+
+	assert.EqualT(t, "linux amd64", set.Environment)
+	assert.Len(t, set.Set, 2)
+	assert.NotContains(t, set.Set, "BenchmarkOther-8")
+
+	small := set.Set["BenchmarkSort/small-8"]
+	require.Len(t, small, 1)
+	assert.EqualT(t, 1000, small[0].N)
+	assert.InDeltaT(t, 123.4, small[0].NsPerOp, 1e-9)
+	assert.EqualT(t, uint64(2), small[0].AllocsPerOp)
+	assert.EqualT(t, parse.NsPerOp|parse.AllocsPerOp, small[0].Measured)
+
+	large := set.Set["BenchmarkSort/large-8"]
+	require.Len(t, large, 1)
+	assert.EqualT(t, 1, large[0].Ord)
+	assert.EqualT(t, uint64(4096), large[0].AllocedBytesPerOp)
+	assert.InDeltaT(t, 12.5, large[0].MBPerS, 1e-9)
+
+	require.Len(t, set.Failures, 1)
+}
+
+func TestSetCanonical(t *testing.T) {
+	p := New(&config.Config{})
+
+	set, err := p.parseCanonical(strings.NewReader(canonicalInput))
+	require.NoError(t, err)
+
+	canonical := set.Canonical()
+	assert.EqualT(t, "linux amd64", canonical.Environment)
+	assert.Equal(t, []string{"timeout"}, canonical.Failures)
+	require.Len(t, canonical.Benchmarks, 3)
+
+	t.Run("should sort benchmarks by name", func(t *testing.T) {
+		assert.EqualT(t, "BenchmarkOther-8", canonical.Benchmarks[0].Name)
+		assert.EqualT(t, "BenchmarkSort/large-8", canonical.Benchmarks[1].Name)
+	})
+
+	t.Run("should retain measured metrics only", func(t *testing.T) {
+		small := canonical.Benchmarks[2]
+		assert.EqualT(t, 1000, small.Iterations)
+		require.NotNil(t, small.NsPerOp)
+		assert.InDeltaT(t, 123.4, *small.NsPerOp, 1e-9)
+		require.NotNil(t, small.AllocsPerOp)
+		assert.Nil(t, small.BytesPerOp)
+		assert.Nil(t, small.MBytesPerS)
+	})
+}
+
+func TestParseCanonicalErrors(t *testing.T) {
+	p := New(&config.Config{})
+
+	t.Run("with invalid JSON", func(t *testing.T) {
+		_, err := p.parseCanonical(strings.NewReader(`not json`))
+		require.Error(t, err)
+	})
+
+	t.Run("with unnamed benchmark", func(t *testing.T) {
+		_, err := p.parseCanonical(strings.NewReader(`{"benchmarks": [{"nsPerOp": 1}]}`))
+		require.ErrorContains(t, err, "without a name")
+	})
+}
+
+func TestParseInputPlugin(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no shell available to run plugins")
+	}
+
+	t.Run("with converting plugin", func(t *testing.T) {
+		// the input is already canonical: the plugin passes it through
+		p := New(&config.Config{}, WithPlugin("sh", "-c", "cat"))
+
+		set, err := p.ParseInput(strings.NewReader(canonicalInput))
+		require.NoError(t, err)
+		assert.Len(t, set.Set, 3)
+		assert.EqualT(t, "linux amd64", set.Environment)
+	})
+
+	t.Run("with failing plugin", func(t *testing.T) {
+		p := New(&config.Config{}, WithPlugin("sh", "-c", "echo unsupported format >&2; exit 2"))
+
+		_, err := p.ParseInput(strings.NewReader(canonicalInput))
+		require.ErrorContains(t, err, "unsupported format")
+	})
+
+	t.Run("with plugin writing garbage", func(t *testing.T) {
+		p := New(&config.Config{}, WithPlugin("sh", "-c", "echo garbage"))
+
+		_, err := p.ParseInput(strings.NewReader(canonicalInput))
+		require.ErrorContains(t, err, "decoding canonical benchmark set")
+	})
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"io"
+	"os"
+)
+
+// Progress reports how far [BenchmarkParser.ParseFiles] went through its input files.
+//
+// Bytes are counted as inputs are read, so that the progress of a single huge file is reported too.
+// Standard input and criterion directories have no known size: they don't count in TotalBytes.
+type Progress struct {
+	File       string // the input being parsed
+	Files      int    // number of inputs completely parsed
+	TotalFiles int    // number of inputs to parse
+	Benchmarks int    // number of benchmarks parsed from complete inputs
+	Bytes      int64  // number of bytes read from inputs with a known size
+	TotalBytes int64  // total size of inputs with a known size
+}
+
+// Done reports whether all inputs have been parsed.
+func (p Progress) Done() bool {
+	return p.Files >= p.TotalFiles
+}
+
+// Fraction of the inputs parsed so far, between 0 and 1.
+//
+// It is based on the bytes read when the size of inputs is known, and on the number of parsed files otherwise.
+func (p Progress) Fraction() float64 {
+	switch {
+	case p.TotalBytes > 0:
+		return min(float64(p.Bytes)/float64(p.TotalBytes), 1)
+	case p.TotalFiles > 0:
+		return float64(p.Files) / float64(p.TotalFiles)
+	default:
+		return 1
+	}
+}
+
+// progressTracker notifies the progress callback of a [BenchmarkParser].
+//
+// Without a callback, it does nothing.
+type progressTracker struct {
+	Progress
+
+	notify func(Progress)
+}
+
+func newProgressTracker(files []string, notify func(Progress)) *progressTracker {
+	if notify == nil {
+		return &progressTracker{notify: func(Progress) {}}
+	}
+
+	t := &progressTracker{
+		Progress: Progress{TotalFiles: len(files)},
+		notify:   notify,
+	}
+
+	for _, file := range files {
+		if file == "-" {
+			continue
+		}
+
+		if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() {
+			t.TotalBytes += info.Size()
+		}
+	}
+
+	return t
+}
+
+// start notifies that the parsing of a new input begins.
+func (t *progressTracker) start(file string) {
+	t.File = file
+	t.notify(t.Progress)
+}
+
+// done notifies that an input has been completely parsed.
+func (t *progressTracker) done(set Set) {
+	t.Files++
+	for _, benchmarks := range set.Set {
+		t.Benchmarks += len(benchmarks)
+	}
+	t.notify(t.Progress)
+}
+
+// reader wraps an input with a known size, so that the progress is notified as it is read.
+func (t *progressTracker) reader(r io.Reader) io.Reader {
+	return &progressReader{Reader: r, tracker: t}
+}
+
+type progressReader struct {
+	io.Reader
+
+	tracker *progressTracker
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.Reader.Read(b)
+	if n > 0 {
+		r.tracker.Bytes += int64(n)
+		r.tracker.notify(r.tracker.Progress)
+	}
+
+	return n, err
+}
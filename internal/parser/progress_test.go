@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"os"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestParseFilesWithProgress(t *testing.T) {
+	files := []string{testdataPath("run.txt"), testdataPath("run1.txt")}
+
+	var size int64
+	for _, file := range files {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		size += info.Size()
+	}
+
+	var notified []Progress
+	p := New(&config.Config{}, WithProgress(func(progress Progress) {
+		notified = append(notified, progress)
+	}))
+	require.NoError(t, p.ParseFiles(files...))
+
+	require.NotEmpty(t, notified)
+	first := notified[0]
+	assert.EqualT(t, files[0], first.File)
+	assert.EqualT(t, 0, first.Files)
+	assert.EqualT(t, 2, first.TotalFiles)
+	assert.EqualT(t, size, first.TotalBytes)
+	assert.False(t, first.Done())
+
+	last := notified[len(notified)-1]
+	assert.EqualT(t, files[1], last.File)
+	assert.True(t, last.Done())
+	assert.EqualT(t, size, last.Bytes)
+	assert.InDeltaT(t, 1.0, last.Fraction(), 1e-9)
+
+	var benchmarks int
+	for _, set := range p.Sets() {
+		for _, runs := range set.Set {
+			benchmarks += len(runs)
+		}
+	}
+	assert.EqualT(t, benchmarks, last.Benchmarks)
+
+	for i := 1; i < len(notified); i++ {
+		assert.GreaterOrEqualT(t, notified[i].Bytes, notified[i-1].Bytes)
+	}
+}
+
+func TestProgressFraction(t *testing.T) {
+	assert.InDeltaT(t, 0.25, Progress{Bytes: 25, TotalBytes: 100}.Fraction(), 1e-9)
+	assert.InDeltaT(t, 0.5, Progress{Files: 1, TotalFiles: 2}.Fraction(), 1e-9)
+	assert.InDeltaT(t, 1.0, Progress{}.Fraction(), 1e-9)
+	assert.True(t, Progress{}.Done())
+}
@@ -0,0 +1,268 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+	"github.com/fredbi/benchviz/internal/color"
+	"github.com/fredbi/benchviz/internal/config"
+	"go.yaml.in/yaml/v3"
+)
+
+// ReportFormat is the output format of a [ParsingReport].
+type ReportFormat string
+
+// Supported report formats.
+const (
+	ReportFormatJSON     ReportFormat = "json"
+	ReportFormatYAML     ReportFormat = "yaml"
+	ReportFormatTable    ReportFormat = "table"
+	ReportFormatMarkdown ReportFormat = "markdown"
+)
+
+// IsValid reports whether the report format is supported.
+func (f ReportFormat) IsValid() bool {
+	switch f {
+	case ReportFormatJSON, ReportFormatYAML, ReportFormatTable, ReportFormatMarkdown:
+		return true
+	default:
+		return false
+	}
+}
+
+// AllReportFormats returns all supported report formats.
+func AllReportFormats() []ReportFormat {
+	return []ReportFormat{
+		ReportFormatJSON,
+		ReportFormatYAML,
+		ReportFormatTable,
+		ReportFormatMarkdown,
+	}
+}
+
+// Write the [ParsingReport] to w in the requested format.
+//
+// The "table" format is an aligned, human-readable text layout intended for a terminal.
+// The "markdown" format may be pasted into an issue or a pull request.
+func (r ParsingReport) Write(w io.Writer, format ReportFormat) error {
+	return r.WriteColored(w, format, color.Palette{})
+}
+
+// WriteColored writes the [ParsingReport] like [ParsingReport.Write], with colors in the "table" format:
+// section titles in bold and failures in red.
+func (r ParsingReport) WriteColored(w io.Writer, format ReportFormat, palette color.Palette) error {
+	return r.WriteExtended(w, format, palette, r)
+}
+
+// WriteExtended writes the [ParsingReport] like [ParsingReport.WriteColored], extended with the results
+// of other packages (e.g. the matching of benchmarks against config rules).
+//
+// The extended value, which should embed the report, is encoded in the "json" and "yaml" formats,
+// while the extra sections are laid out after the sections of the report in the "table" and "markdown" formats.
+func (r ParsingReport) WriteExtended(w io.Writer, format ReportFormat, palette color.Palette, extended any, extra ...ReportSection) error {
+	switch format {
+	case ReportFormatJSON, "":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", " ")
+
+		return enc.Encode(extended)
+	case ReportFormatYAML:
+		enc := yaml.NewEncoder(w)
+		if err := enc.Encode(extended); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	case ReportFormatTable:
+		return writeTable(w, append(r.sections(), extra...), palette)
+	case ReportFormatMarkdown:
+		return writeMarkdown(w, append(r.sections(), extra...))
+	default:
+		return fmt.Errorf("unsupported report format %q (should be one of %v)", format, AllReportFormats())
+	}
+}
+
+// ReadReport reads a [ParsingReport] previously produced by [ParsingReport.Write],
+// in either JSON or YAML format.
+func ReadReport(r io.Reader) (ParsingReport, error) {
+	var report ParsingReport
+
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return report, err
+	}
+
+	if trimmed := bytes.TrimSpace(buf); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(trimmed, &report)
+	} else {
+		err = yaml.Unmarshal(buf, &report)
+	}
+	if err != nil {
+		return report, fmt.Errorf("decoding report: %w", err)
+	}
+
+	if len(report.Functions) == 0 {
+		return report, errors.New("no benchmark function found in report")
+	}
+
+	return report, nil
+}
+
+// ReportSection is a titled table of the report, independent of the output format.
+//
+// Failures tells that the rows of the section report failures.
+type ReportSection struct {
+	Title    string
+	Headers  []string
+	Rows     [][]string
+	Failures bool
+}
+
+// sections lays out the report as a list of tables.
+func (r ParsingReport) sections() []ReportSection {
+	summary := ReportSection{
+		Title:   "Summary",
+		Headers: []string{"Item", "Value"},
+		Rows: [][]string{
+			{"Sets", strconv.Itoa(r.NumberOfSets)},
+			{"Files", strings.Join(r.AnalyzedFiles, ", ")},
+			{"Benchmarks", strconv.Itoa(len(r.Functions))},
+		},
+	}
+	if r.TotalDuration != "" {
+		summary.Rows = append(summary.Rows, []string{"Run duration", r.TotalDuration})
+	}
+
+	metrics := ReportSection{
+		Title:   "Metrics",
+		Headers: []string{"Metric", "Count", "Min", "Max", "Files"},
+	}
+	for _, m := range r.Metrics {
+		metrics.Rows = append(metrics.Rows, []string{
+			m.Metric.String(), strconv.Itoa(m.Count), formatFloat(m.Min), formatFloat(m.Max), strings.Join(m.Origins, ", "),
+		})
+	}
+
+	signatures := ReportSection{
+		Title:   "Benchmarks",
+		Headers: []string{"Benchmark", "Samples", "Metrics", "Missing", "Label", "Environment"},
+	}
+	for _, s := range r.Signatures {
+		signatures.Rows = append(signatures.Rows, []string{
+			s.Name, strconv.Itoa(s.Samples), formatSignatureMetrics(s), joinMetricNames(s.MissingMetrics), s.Label, s.Environment,
+		})
+	}
+
+	sections := []ReportSection{summary, metrics, signatures}
+
+	if len(r.Failures) > 0 {
+		failures := ReportSection{
+			Title:    "Failures",
+			Headers:  []string{"File", "Message"},
+			Failures: true,
+		}
+		for _, f := range r.Failures {
+			failures.Rows = append(failures.Rows, []string{f.File, f.Message})
+		}
+		sections = append(sections, failures)
+	}
+
+	return sections
+}
+
+func writeTable(w io.Writer, sections []ReportSection, palette color.Palette) error {
+	for i, section := range sections {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		var rowStyle color.Style
+		if section.Failures {
+			rowStyle = palette.Red
+		}
+
+		// columns are aligned per section
+		lines := []color.Line{
+			{Text: strings.ToUpper(section.Title), Style: palette.Bold},
+			{Text: strings.Join(section.Headers, "\t")},
+		}
+		for _, row := range section.Rows {
+			lines = append(lines, color.Line{Text: strings.Join(row, "\t"), Style: rowStyle})
+		}
+
+		if err := color.WriteTable(w, lines); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeMarkdown(w io.Writer, sections []ReportSection) error {
+	var b strings.Builder
+
+	b.WriteString("# Benchmark report\n")
+	for _, section := range sections {
+		b.WriteString("\n## " + baseline.EscapeMarkdown(section.Title) + "\n\n")
+		b.WriteString(markdownRow(section.Headers))
+		b.WriteString("|" + strings.Repeat(" --- |", len(section.Headers)) + "\n")
+		for _, row := range section.Rows {
+			b.WriteString(markdownRow(row))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// markdownRow renders a row of a Markdown table, with escaped cells.
+func markdownRow(cells []string) string {
+	escaped := make([]string, 0, len(cells))
+	for _, cell := range cells {
+		escaped = append(escaped, baseline.EscapeMarkdown(cell))
+	}
+
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+// formatSignatureMetrics renders the metrics of a benchmark signature, with mean ± stddev when
+// several samples are available.
+func formatSignatureMetrics(s Signature) string {
+	parts := make([]string, 0, len(s.AvailableMetrics))
+
+	if len(s.Statistics) > 0 {
+		for _, st := range s.Statistics {
+			parts = append(parts, st.Metric.String()+"="+formatFloat(st.Mean)+"±"+formatFloat(st.StdDev))
+		}
+
+		return strings.Join(parts, " ")
+	}
+
+	for _, m := range s.AvailableMetrics {
+		parts = append(parts, m.Metric.String()+"="+formatFloat(m.Min))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func joinMetricNames(metrics []config.MetricName) string {
+	names := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		names = append(names, m.String())
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', 6, 64) //nolint:mnd // 6 significant digits
+}
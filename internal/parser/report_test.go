@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestReportWrite(t *testing.T) {
+	cfg := &config.Config{}
+	p := New(cfg)
+
+	input := `goos: linux
+BenchmarkFoo-8   1000   100 ns/op   56 B/op   3 allocs/op
+BenchmarkFoo-8   1000   300 ns/op   56 B/op   3 allocs/op
+BenchmarkBar|Baz-8   1000   100 ns/op
+FAIL	example.com/pkg	0.012s
+`
+	require.NoError(t, p.ParseReader("input.txt", strings.NewReader(input)))
+	report := p.Report()
+
+	t.Run("as JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.Write(&buf, ReportFormatJSON))
+
+		var decoded ParsingReport
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, report.Functions, decoded.Functions)
+	})
+
+	t.Run("as YAML", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.Write(&buf, ReportFormatYAML))
+		assert.Contains(t, buf.String(), "benchmark_signatures:")
+
+		var decoded ParsingReport
+		require.NoError(t, yaml.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, report.Functions, decoded.Functions)
+		assert.Equal(t, report.Signatures[1].Statistics, decoded.Signatures[1].Statistics)
+	})
+
+	t.Run("as table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.Write(&buf, ReportFormatTable))
+
+		out := buf.String()
+		assert.Contains(t, out, "SUMMARY")
+		assert.Contains(t, out, "BENCHMARKS")
+		assert.Contains(t, out, "FAILURES")
+		assert.Contains(t, out, "nsPerOp=200±141.421")
+	})
+
+	t.Run("as markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.Write(&buf, ReportFormatMarkdown))
+
+		out := buf.String()
+		assert.True(t, strings.HasPrefix(out, "# Benchmark report\n"))
+		assert.Contains(t, out, "## Benchmarks")
+		assert.Contains(t, out, "| Benchmark | Samples |")
+		assert.Contains(t, out, `BenchmarkBar\|Baz-8`)
+	})
+
+	t.Run("as markdown with multi-line labels", func(t *testing.T) {
+		multiline := report
+		multiline.Signatures = append([]Signature(nil), report.Signatures...)
+		multiline.Signatures[0].Label = "old\nrun"
+
+		var buf bytes.Buffer
+		require.NoError(t, multiline.Write(&buf, ReportFormatMarkdown))
+
+		out := buf.String()
+		assert.Contains(t, out, "| old run |")
+		assert.NotContains(t, out, "old\nrun")
+	})
+
+	t.Run("with unsupported format", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Error(t, report.Write(&buf, ReportFormat("xml")))
+		assert.False(t, ReportFormat("xml").IsValid())
+	})
+}
+
+func TestReadReport(t *testing.T) {
+	cfg := &config.Config{}
+	p := New(cfg)
+
+	input := `goos: linux
+BenchmarkFoo/a-8   1000   100 ns/op   56 B/op   3 allocs/op
+BenchmarkFoo/b-8   1000   300 ns/op   56 B/op   3 allocs/op
+`
+	require.NoError(t, p.ParseReader("run.txt", strings.NewReader(input)))
+	report := p.Report()
+
+	for _, format := range []ReportFormat{ReportFormatJSON, ReportFormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, report.Write(&buf, format))
+
+			read, err := ReadReport(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, report.Functions, read.Functions)
+			require.Len(t, read.Metrics, len(report.Metrics))
+			assert.Equal(t, report.Metrics[0].Metric, read.Metrics[0].Metric)
+		})
+	}
+
+	t.Run("should reject an empty report", func(t *testing.T) {
+		_, err := ReadReport(strings.NewReader(`{"sets": 0}`))
+		require.Error(t, err)
+	})
+
+	t.Run("should reject invalid content", func(t *testing.T) {
+		_, err := ReadReport(strings.NewReader(`{"sets":`))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,5 @@
+// Package profiling collects CPU, memory and execution trace profiles of benchviz itself,
+// so users may report performance problems on huge inputs.
+//
+// Profiles are written in the formats expected by "go tool pprof" and "go tool trace".
+package profiling
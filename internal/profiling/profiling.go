@@ -0,0 +1,119 @@
+package profiling
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// Files holds the files where profiles are written. An empty file disables the corresponding profile.
+type Files struct {
+	CPU    string // CPU profile, collected from [Start] to [Profiler.Stop]
+	Memory string // heap profile, written by [Profiler.Stop]
+	Trace  string // execution trace, collected from [Start] to [Profiler.Stop]
+}
+
+// Profiler collects the profiles of a run.
+type Profiler struct {
+	files Files
+	cpu   *os.File
+	trace *os.File
+}
+
+// Start collecting the profiles requested by files.
+//
+// The returned [Profiler] must be stopped to write all profiles.
+func Start(files Files) (*Profiler, error) {
+	p := &Profiler{files: files}
+
+	if files.CPU != "" {
+		f, err := os.Create(files.CPU)
+		if err != nil {
+			return nil, fmt.Errorf("creating CPU profile: %w", err)
+		}
+
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+
+			return nil, fmt.Errorf("starting CPU profile: %w", err)
+		}
+		p.cpu = f
+	}
+
+	if files.Trace != "" {
+		f, err := os.Create(files.Trace)
+		if err != nil {
+			_ = p.stopCPU()
+
+			return nil, fmt.Errorf("creating trace: %w", err)
+		}
+
+		if err := trace.Start(f); err != nil {
+			_ = f.Close()
+			_ = p.stopCPU()
+
+			return nil, fmt.Errorf("starting trace: %w", err)
+		}
+		p.trace = f
+	}
+
+	return p, nil
+}
+
+// Stop collecting profiles, and write the heap profile if requested.
+//
+// Stopping a nil or already stopped [Profiler] is a no-op.
+func (p *Profiler) Stop() error {
+	if p == nil {
+		return nil
+	}
+
+	errs := []error{p.stopCPU()}
+
+	if p.trace != nil {
+		trace.Stop()
+		errs = append(errs, p.trace.Close())
+		p.trace = nil
+	}
+
+	if p.files.Memory != "" {
+		errs = append(errs, writeHeapProfile(p.files.Memory))
+		p.files.Memory = ""
+	}
+
+	return errors.Join(errs...)
+}
+
+// stopCPU stops the CPU profile, if started.
+//
+// It is used alone when [Start] fails, so that no heap profile is written for a run that was never profiled.
+func (p *Profiler) stopCPU() error {
+	if p.cpu == nil {
+		return nil
+	}
+
+	pprof.StopCPUProfile()
+	err := p.cpu.Close()
+	p.cpu = nil
+
+	return err
+}
+
+func writeHeapProfile(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("creating memory profile: %w", err)
+	}
+	defer f.Close()
+
+	runtime.GC() // get up-to-date statistics about live objects
+
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("writing memory profile: %w", err)
+	}
+
+	return f.Close()
+}
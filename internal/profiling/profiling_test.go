@@ -0,0 +1,69 @@
+package profiling
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestProfiler(t *testing.T) {
+	t.Run("should write all requested profiles", func(t *testing.T) {
+		dir := t.TempDir()
+		files := Files{
+			CPU:    filepath.Join(dir, "cpu.pprof"),
+			Memory: filepath.Join(dir, "mem.pprof"),
+			Trace:  filepath.Join(dir, "trace.out"),
+		}
+
+		p, err := Start(files)
+		require.NoError(t, err)
+
+		require.NoError(t, p.Stop())
+		require.NoError(t, p.Stop()) // stopping twice is a no-op
+
+		for _, file := range []string{files.CPU, files.Memory, files.Trace} {
+			info, err := os.Stat(file)
+			require.NoError(t, err)
+			assert.Positive(t, info.Size(), file)
+		}
+	})
+
+	t.Run("should do nothing without files", func(t *testing.T) {
+		p, err := Start(Files{})
+		require.NoError(t, err)
+		require.NoError(t, p.Stop())
+	})
+
+	t.Run("should stop a nil profiler", func(t *testing.T) {
+		var p *Profiler
+		require.NoError(t, p.Stop())
+	})
+
+	t.Run("should fail on an invalid file", func(t *testing.T) {
+		_, err := Start(Files{CPU: filepath.Join(t.TempDir(), "missing", "cpu.pprof")})
+		require.ErrorContains(t, err, "creating CPU profile")
+	})
+
+	t.Run("should not write the heap profile when failing to start", func(t *testing.T) {
+		dir := t.TempDir()
+		files := Files{
+			CPU:    filepath.Join(dir, "cpu.pprof"),
+			Memory: filepath.Join(dir, "mem.pprof"),
+			Trace:  filepath.Join(dir, "missing", "trace.out"),
+		}
+
+		_, err := Start(files)
+		require.ErrorContains(t, err, "creating trace")
+
+		_, err = os.Stat(files.Memory)
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		// the CPU profile was stopped, so that it may start again
+		p, err := Start(Files{CPU: files.CPU})
+		require.NoError(t, err)
+		require.NoError(t, p.Stop())
+	})
+}
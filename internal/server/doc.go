@@ -0,0 +1,5 @@
+// Package server exposes organized benchmark results over HTTP, as a JSON API.
+//
+// Results are loaded again for every request, so that other dashboards (e.g. the Grafana JSON
+// datasource) may consume benchviz output live, while benchmark inputs evolve.
+package server
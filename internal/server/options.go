@@ -0,0 +1,49 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+// Option to tune the HTTP [Server].
+type Option func(*options)
+
+type options struct {
+	page    func(io.Writer, *model.Scenario) error
+	changes *Changes
+	logger  *slog.Logger
+}
+
+func optionsWithDefaults(opts []Option) options {
+	var o options
+
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+
+	return o
+}
+
+// WithPage serves the scenario rendered as an HTML page on the root path.
+//
+// By default, no page is served: only the JSON API is available.
+func WithPage(render func(io.Writer, *model.Scenario) error) Option {
+	return func(o *options) {
+		o.page = render
+	}
+}
+
+// WithLogger sets the logger used by the [Server].
+//
+// Defaults to [slog.Default].
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
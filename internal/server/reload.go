@@ -0,0 +1,118 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// reloadScript reloads the HTML page when the server sends a reload event.
+//
+// The browser reconnects the event stream on its own, e.g. when the server is restarted.
+const reloadScript = `<script>
+(function () {
+  var events = new EventSource("api/events");
+  events.addEventListener("reload", function () { window.location.reload(); });
+})();
+</script>`
+
+// Changes notifies the pages served with live reload that the served data changed (see [WithLiveReload]).
+//
+// A [Changes] is safe for concurrent use.
+type Changes struct {
+	mu      sync.Mutex
+	version uint64
+	changed chan struct{}
+}
+
+// NewChanges builds a [Changes] notifier.
+func NewChanges() *Changes {
+	return &Changes{
+		changed: make(chan struct{}),
+	}
+}
+
+// Notify tells all the pages currently served to reload.
+func (c *Changes) Notify() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// Version returns the number of changes notified so far.
+func (c *Changes) Version() uint64 {
+	version, _ := c.next()
+
+	return version
+}
+
+// next returns the current version, and a channel closed at the next change.
+func (c *Changes) next() (uint64, <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.version, c.changed
+}
+
+// WithLiveReload reloads the HTML page in the browser whenever changes are notified.
+//
+// The page embeds a small script listening to the server-sent events of /api/events.
+//
+// By default, pages are not reloaded.
+func WithLiveReload(changes *Changes) Option {
+	return func(o *options) {
+		o.changes = changes
+	}
+}
+
+// handleEvents streams a reload event whenever changes are notified, until the client disconnects.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+
+		return
+	}
+
+	// changes notified once the stream is open are not missed
+	_, changed := s.changes.next()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+
+		var version uint64
+		version, changed = s.changes.next()
+		if _, err := fmt.Fprintf(w, "event: reload\ndata: %d\n\n", version); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// injectReloadScript inserts the live reload script at the end of the body of a page, if any.
+func injectReloadScript(page []byte) []byte {
+	const body = "</body>"
+	i := bytes.LastIndex(page, []byte(body))
+	if i < 0 {
+		return append(page, reloadScript...)
+	}
+
+	injected := make([]byte, 0, len(page)+len(reloadScript))
+	injected = append(injected, page[:i]...)
+	injected = append(injected, reloadScript...)
+
+	return append(injected, page[i:]...)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+func TestLiveReload(t *testing.T) {
+	changes := NewChanges()
+	s := New(func(context.Context) (Data, error) {
+		return testData(), nil
+	}, WithPage(func(w io.Writer, scenario *model.Scenario) error {
+		_, err := io.WriteString(w, "<html><body>"+scenario.Name+"</body></html>")
+
+		return err
+	}), WithLiveReload(changes))
+
+	t.Run("should inject the reload script in the page", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.EqualT(t, http.StatusOK, rec.Code)
+
+		page := rec.Body.String()
+		assert.True(t, strings.HasPrefix(page, "<html><body>test<script>"))
+		assert.True(t, strings.HasSuffix(page, "</script></body></html>"))
+		assert.Contains(t, page, `new EventSource("api/events")`)
+	})
+
+	t.Run("should stream reload events", func(t *testing.T) {
+		srv := httptest.NewServer(s)
+		defer srv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/events", nil)
+		require.NoError(t, err)
+		resp, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.EqualT(t, http.StatusOK, resp.StatusCode)
+		assert.EqualT(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		changes.Notify()
+
+		lines := bufio.NewScanner(resp.Body)
+		require.True(t, lines.Scan())
+		assert.EqualT(t, "event: reload", lines.Text())
+		require.True(t, lines.Scan())
+		assert.EqualT(t, "data: 1", lines.Text())
+	})
+
+	t.Run("should not serve events unless enabled", func(t *testing.T) {
+		s := New(func(context.Context) (Data, error) { return testData(), nil })
+
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/events", nil))
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+	})
+}
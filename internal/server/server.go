@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+// Data holds the results served by the API: the parsed benchmarks, and the scenario organized from them.
+type Data struct {
+	Sets     []parser.Set
+	Scenario *model.Scenario
+}
+
+// Loader loads the data to serve. It is called for every request.
+type Loader func(ctx context.Context) (Data, error)
+
+// RawSet is the representation of parsed benchmarks served by /api/raw.
+type RawSet struct {
+	File  string `json:"file"`
+	Label string `json:"label,omitempty"`
+
+	parser.CanonicalSet
+}
+
+// CategorySummary is the representation of a category listed by /api/categories.
+type CategorySummary struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Server is an [http.Handler] serving benchmark results.
+//
+// The endpoints are:
+//
+//   - GET /api/scenario: the organized scenario
+//   - GET /api/categories: the IDs and titles of all categories
+//   - GET /api/categories/{id}: a single category of the scenario
+//   - GET /api/raw: the parsed benchmarks, per input file
+//   - GET /api/events: server-sent events telling pages to reload, when enabled with [WithLiveReload]
+//   - GET /: the HTML page, when enabled with [WithPage]
+type Server struct {
+	options
+
+	load Loader
+	mux  *http.ServeMux
+	l    *slog.Logger
+}
+
+// New builds a [Server] for the data provided by a [Loader].
+func New(load Loader, opts ...Option) *Server {
+	s := &Server{
+		options: optionsWithDefaults(opts),
+		load:    load,
+		mux:     http.NewServeMux(),
+	}
+	s.l = s.logger.With(slog.String("module", "server"))
+
+	s.mux.HandleFunc("GET /api/scenario", s.handleScenario)
+	s.mux.HandleFunc("GET /api/categories", s.handleCategories)
+	s.mux.HandleFunc("GET /api/categories/{id}", s.handleCategory)
+	s.mux.HandleFunc("GET /api/raw", s.handleRaw)
+	if s.page != nil {
+		s.mux.HandleFunc("GET /{$}", s.handlePage)
+	}
+	if s.changes != nil {
+		s.mux.HandleFunc("GET /api/events", s.handleEvents)
+	}
+
+	return s
+}
+
+// ServeHTTP implements [http.Handler].
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleScenario(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.loadData(w, r)
+	if !ok {
+		return
+	}
+
+	s.writeJSON(w, data.Scenario)
+}
+
+func (s *Server) handleCategories(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.loadData(w, r)
+	if !ok {
+		return
+	}
+
+	categories := make([]CategorySummary, 0, len(data.Scenario.Categories))
+	for _, category := range data.Scenario.Categories {
+		categories = append(categories, CategorySummary{ID: category.ID, Title: category.Title})
+	}
+
+	s.writeJSON(w, categories)
+}
+
+func (s *Server) handleCategory(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.loadData(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	for _, category := range data.Scenario.Categories {
+		if category.ID == id {
+			s.writeJSON(w, category)
+
+			return
+		}
+	}
+
+	http.Error(w, "category not found: "+id, http.StatusNotFound)
+}
+
+func (s *Server) handleRaw(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.loadData(w, r)
+	if !ok {
+		return
+	}
+
+	sets := make([]RawSet, 0, len(data.Sets))
+	for _, set := range data.Sets {
+		sets = append(sets, RawSet{File: set.File, Label: set.Label, CanonicalSet: set.Canonical()})
+	}
+
+	s.writeJSON(w, sets)
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.loadData(w, r)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := s.page(&buf, data.Scenario); err != nil {
+		s.fail(w, r, err)
+
+		return
+	}
+
+	page := buf.Bytes()
+	if s.changes != nil {
+		page = injectReloadScript(page)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(page)
+}
+
+// loadData loads the data to serve, and responds with an error if it fails.
+func (s *Server) loadData(w http.ResponseWriter, r *http.Request) (Data, bool) {
+	data, err := s.load(r.Context())
+	if err == nil && data.Scenario == nil {
+		err = errors.New("no scenario loaded")
+	}
+	if err != nil {
+		s.fail(w, r, err)
+
+		return Data{}, false
+	}
+
+	return data, true
+}
+
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, err error) {
+	s.l.Error("failed to serve request", slog.String("path", r.URL.Path), slog.String("error", err.Error()))
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		s.l.Warn("failed to write response", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestServer(t *testing.T) {
+	var loads int
+	s := New(func(context.Context) (Data, error) {
+		loads++
+
+		return testData(), nil
+	}, WithPage(func(w io.Writer, scenario *model.Scenario) error {
+		_, err := io.WriteString(w, "<html>"+scenario.Name+"</html>")
+
+		return err
+	}))
+
+	t.Run("should serve the scenario", func(t *testing.T) {
+		var scenario model.Scenario
+		get(t, s, "/api/scenario", http.StatusOK, &scenario)
+		assert.EqualT(t, "test", scenario.Name)
+		require.Len(t, scenario.Categories, 2)
+	})
+
+	t.Run("should list categories", func(t *testing.T) {
+		var categories []CategorySummary
+		get(t, s, "/api/categories", http.StatusOK, &categories)
+		assert.Equal(t, []CategorySummary{{ID: "greater", Title: "Greater"}, {ID: "sort", Title: "Sort"}}, categories)
+	})
+
+	t.Run("should serve a category", func(t *testing.T) {
+		var category model.Category
+		get(t, s, "/api/categories/sort", http.StatusOK, &category)
+		assert.EqualT(t, "Sort", category.Title)
+
+		get(t, s, "/api/categories/unknown", http.StatusNotFound, nil)
+	})
+
+	t.Run("should serve parsed benchmarks", func(t *testing.T) {
+		var sets []RawSet
+		get(t, s, "/api/raw", http.StatusOK, &sets)
+		require.Len(t, sets, 1)
+		assert.EqualT(t, "bench.txt", sets[0].File)
+		assert.EqualT(t, "linux amd64", sets[0].Environment)
+		require.Len(t, sets[0].Benchmarks, 1)
+		require.NotNil(t, sets[0].Benchmarks[0].NsPerOp)
+		assert.InDeltaT(t, 12.5, *sets[0].Benchmarks[0].NsPerOp, 1e-9)
+	})
+
+	t.Run("should serve the HTML page", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.EqualT(t, http.StatusOK, rec.Code)
+		assert.EqualT(t, "<html>test</html>", rec.Body.String())
+	})
+
+	t.Run("should load data for every request", func(t *testing.T) {
+		before := loads
+		get(t, s, "/api/scenario", http.StatusOK, nil)
+		get(t, s, "/api/scenario", http.StatusOK, nil)
+		assert.EqualT(t, before+2, loads)
+	})
+}
+
+func TestServerErrors(t *testing.T) {
+	s := New(func(context.Context) (Data, error) {
+		return Data{}, errors.New("parsing failed")
+	})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/scenario", nil))
+	assert.EqualT(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "parsing failed")
+
+	t.Run("should not serve a page unless enabled", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.EqualT(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should only serve GET requests", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/scenario", nil))
+		assert.EqualT(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func get(t *testing.T, s *Server, path string, status int, value any) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.EqualT(t, status, rec.Code)
+
+	if value == nil {
+		return
+	}
+
+	assert.EqualT(t, "application/json", rec.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), value))
+}
+
+func testData() Data {
+	return Data{
+		Sets: []parser.Set{
+			{
+				Set: parse.Set{
+					"BenchmarkSort": {{Name: "BenchmarkSort", N: 100, NsPerOp: 12.5, Measured: parse.NsPerOp}},
+				},
+				File:        "bench.txt",
+				Environment: "linux amd64",
+			},
+		},
+		Scenario: &model.Scenario{
+			Name: "test",
+			Categories: []model.Category{
+				{ID: "greater", Title: "Greater"},
+				{
+					ID:    "sort",
+					Title: "Sort",
+					Data: []model.CategoryData{
+						{Metric: config.Metric{ID: config.MetricNsPerOp, Title: "Timings", Axis: "ns/op"}},
+					},
+				},
+			},
+		},
+	}
+}
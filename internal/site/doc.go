@@ -0,0 +1,5 @@
+// Package site generates a static website about the history of benchmark results,
+// from baseline snapshots saved over successive runs.
+//
+// The website is ready to be published as is, e.g. on GitHub Pages.
+package site
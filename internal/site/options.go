@@ -0,0 +1,74 @@
+package site
+
+import (
+	"log/slog"
+)
+
+// Option to tune the generation of a website.
+type Option func(*options)
+
+type options struct {
+	title     string
+	theme     string
+	threshold float64
+	logger    *slog.Logger
+}
+
+const (
+	defaultTitle     = "Benchmark history"
+	defaultThreshold = 0.05
+)
+
+func optionsWithDefaults(opts []Option) options {
+	o := options{
+		title:     defaultTitle,
+		threshold: defaultThreshold,
+	}
+
+	for _, apply := range opts {
+		apply(&o)
+	}
+
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+
+	return o
+}
+
+// WithTitle sets the title of the website.
+//
+// Defaults to "Benchmark history".
+func WithTitle(title string) Option {
+	return func(o *options) {
+		if title != "" {
+			o.title = title
+		}
+	}
+}
+
+// WithTheme sets the ECharts theme of trend charts.
+func WithTheme(theme string) Option {
+	return func(o *options) {
+		o.theme = theme
+	}
+}
+
+// WithThreshold sets the relative change (e.g. 0.05 for 5%) beyond which a worse result is a regression,
+// when comparing the latest run of a project with the previous one.
+//
+// Defaults to 5%.
+func WithThreshold(threshold float64) Option {
+	return func(o *options) {
+		o.threshold = threshold
+	}
+}
+
+// WithLogger sets the logger used by the [Generator].
+//
+// Defaults to [slog.Default].
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
@@ -0,0 +1,132 @@
+package site
+
+import (
+	"html/template"
+	"strconv"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+)
+
+// pageData is the data of a page of the website.
+type pageData struct {
+	Title     string
+	SiteTitle string
+	Home      string
+	Scripts   []string
+	Content   any
+}
+
+// trendChart is a rendered trend chart.
+type trendChart struct {
+	Element template.HTML
+	Script  template.HTML
+}
+
+var pages = template.Must(template.New("site").Funcs(template.FuncMap{
+	"change":  baseline.FormatChange,
+	"date":    func(s baseline.Snapshot) string { return s.Created.Format(dateLayout) },
+	"percent": func(v float64) string { return strconv.FormatFloat(v*100, 'f', 1, 64) + "%" }, //nolint:mnd // percentage
+	"value":   func(v float64) string { return strconv.FormatFloat(v, 'g', 4, 64) },           //nolint:mnd // 4 significant digits
+}).Parse(`
+{{- define "header" -}}
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+{{- range .Scripts }}
+<script src="{{ . }}"></script>
+{{- end }}
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+td.number { text-align: right; }
+tr.regression { background: #fdd; }
+.box { justify-content: center; display: flex; flex-wrap: wrap; }
+</style>
+</head>
+<body>
+{{- if .Home }}
+<nav><a href="{{ .Home }}">{{ .SiteTitle }}</a></nav>
+{{- end }}
+<h1>{{ .Title }}</h1>
+{{- end }}
+
+{{- define "footer" }}
+<footer><p>Generated by benchviz</p></footer>
+</body>
+</html>
+{{ end }}
+
+{{- define "index" }}
+{{- template "header" . }}
+<table>
+<tr><th>Project</th><th>Runs</th><th>Latest run</th><th>Date</th><th>Regressions</th></tr>
+{{- range .Content }}
+<tr>
+<td><a href="{{ .Slug }}/index.html">{{ .Name }}</a></td>
+<td class="number">{{ len .Snapshots }}</td>
+<td>{{ .Latest.Name }}</td>
+<td>{{ date .Latest }}</td>
+<td class="number">{{ if .Comparison }}{{ len .Comparison.Regressions }}{{ else }}-{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+{{- template "footer" . }}
+{{- end }}
+
+{{- define "project" }}
+{{- template "header" . }}
+{{- with .Content }}
+<h2>Latest comparison</h2>
+{{- with .Comparison }}
+<p>{{ $.Content.Latest.Name }} against {{ .Baseline }} (threshold: {{ percent .Threshold }})</p>
+<table>
+<tr><th>Benchmark</th><th>Previous</th><th>Latest</th><th>Change</th><th>Status</th></tr>
+{{- range .Deltas }}
+<tr{{ if .Regression }} class="regression"{{ end }}>
+<td>{{ .Result }}</td>
+<td class="number">{{ value .Baseline }}</td>
+<td class="number">{{ value .Value }}</td>
+<td class="number">{{ change .Change }}</td>
+<td>{{ if .Regression }}regression{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+{{- if .Missing }}
+<p>Missing in the latest run: {{ len .Missing }}</p>
+{{- end }}
+{{- if .Added }}
+<p>New in the latest run: {{ len .Added }}</p>
+{{- end }}
+{{- else }}
+<p>A single run is available: there is nothing to compare yet.</p>
+{{- end }}
+<h2>Benchmarks</h2>
+<ul>
+{{- range .Functions }}
+<li><a href="{{ .Slug }}.html">{{ .Name }}</a></li>
+{{- end }}
+</ul>
+<h2>Runs</h2>
+<ul>
+{{- range .Snapshots }}
+<li>{{ .Name }} ({{ date . }})</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- template "footer" . }}
+{{- end }}
+
+{{- define "trend" }}
+{{- template "header" . }}
+<div class="box">
+{{- range .Content }}
+{{ .Element }}
+{{ .Script }}
+{{- end }}
+</div>
+{{- template "footer" . }}
+{{- end }}
+`))
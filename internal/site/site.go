@@ -0,0 +1,335 @@
+package site
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+	"github.com/fredbi/benchviz/internal/chart"
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+)
+
+const (
+	indexPage       = "index.html"
+	defaultProject  = "default"
+	dateLayout      = "2006-01-02 15:04"
+	noJekyllFile    = ".nojekyll"
+	dirPermissions  = 0o755
+	filePermissions = 0o644
+)
+
+// Generator builds a static website from the history of benchmark results.
+//
+// Snapshots are grouped in projects by scenario name. The website features:
+//
+//   - an index of all projects;
+//   - for each project, the comparison of the latest run against the previous one;
+//   - for each benchmarked function, the trend of all metrics over successive runs.
+type Generator struct {
+	options
+
+	l *slog.Logger
+}
+
+// New builds a website [Generator].
+func New(opts ...Option) *Generator {
+	g := &Generator{
+		options: optionsWithDefaults(opts),
+	}
+	g.l = g.logger.With(slog.String("module", "site"))
+
+	return g
+}
+
+type project struct {
+	Name       string
+	Slug       string
+	Snapshots  []baseline.Snapshot
+	Functions  []function
+	Comparison *baseline.Comparison
+}
+
+// Latest returns the most recent snapshot of the project.
+func (p project) Latest() baseline.Snapshot {
+	return p.Snapshots[len(p.Snapshots)-1]
+}
+
+type function struct {
+	Name string
+	Slug string
+}
+
+// Generate writes the website to a directory, from snapshots ordered by creation time (see [baseline.ReadDir]).
+func (g *Generator) Generate(dir string, snapshots []baseline.Snapshot) error {
+	if len(snapshots) == 0 {
+		return errors.New("generating site: no snapshot found")
+	}
+
+	if err := os.MkdirAll(dir, dirPermissions); err != nil {
+		return fmt.Errorf("creating site directory: %w", err)
+	}
+
+	projects := g.projects(snapshots)
+
+	if err := g.writePage(filepath.Join(dir, indexPage), "index", pageData{
+		Title:   g.title,
+		Content: projects,
+	}); err != nil {
+		return err
+	}
+
+	for _, p := range projects {
+		if err := g.generateProject(filepath.Join(dir, p.Slug), p); err != nil {
+			return err
+		}
+	}
+
+	// publishing on GitHub Pages: serve files as is
+	if err := os.WriteFile(filepath.Join(dir, noJekyllFile), nil, filePermissions); err != nil {
+		return fmt.Errorf("writing %s: %w", noJekyllFile, err)
+	}
+
+	g.l.Info("site generated", slog.String("dir", dir), slog.Int("projects", len(projects)), slog.Int("snapshots", len(snapshots)))
+
+	return nil
+}
+
+func (g *Generator) generateProject(dir string, p project) error {
+	if err := os.MkdirAll(dir, dirPermissions); err != nil {
+		return fmt.Errorf("creating project directory: %w", err)
+	}
+
+	if err := g.writePage(filepath.Join(dir, indexPage), "project", pageData{
+		Title:     p.Name,
+		SiteTitle: g.title,
+		Home:      "../" + indexPage,
+		Content:   p,
+	}); err != nil {
+		return err
+	}
+
+	for _, f := range p.Functions {
+		trends := g.trends(p, f.Name)
+
+		charts := make([]trendChart, 0, len(trends))
+		var scripts []string
+		for _, trend := range trends {
+			line := trend.Build()
+			snippet := line.RenderSnippet()
+			charts = append(charts, trendChart{
+				Element: template.HTML(snippet.Element), //nolint:gosec // rendered by go-echarts
+				Script:  template.HTML(snippet.Script),  //nolint:gosec // rendered by go-echarts
+			})
+
+			for _, script := range line.JSAssets.Values {
+				if !slices.Contains(scripts, script) {
+					scripts = append(scripts, script)
+				}
+			}
+		}
+
+		if err := g.writePage(filepath.Join(dir, f.Slug+".html"), "trend", pageData{
+			Title:     p.Name + ": " + f.Name,
+			SiteTitle: g.title,
+			Home:      "../" + indexPage,
+			Scripts:   scripts,
+			Content:   charts,
+		}); err != nil {
+			return err
+		}
+	}
+
+	g.l.Debug("project pages written", slog.String("project", p.Name), slog.Int("functions", len(p.Functions)))
+
+	return nil
+}
+
+// projects groups snapshots by scenario, and compares the latest run of each project with the previous one.
+func (g *Generator) projects(snapshots []baseline.Snapshot) []project {
+	var projects []project
+
+	for _, snapshot := range snapshots {
+		name := cmp.Or(snapshot.Scenario, defaultProject)
+		idx := slices.IndexFunc(projects, func(p project) bool { return p.Name == name })
+		if idx < 0 {
+			projects = append(projects, project{Name: name})
+			idx = len(projects) - 1
+		}
+		projects[idx].Snapshots = append(projects[idx].Snapshots, snapshot)
+	}
+
+	slices.SortFunc(projects, func(a, b project) int { return cmp.Compare(a.Name, b.Name) })
+
+	// project directories live next to the index page of the site
+	projectSlugs := newSlugs(indexPage, noJekyllFile)
+	for i := range projects {
+		p := &projects[i]
+		p.Slug = projectSlugs.assign(p.Name)
+
+		// function pages live next to the index page of the project
+		functionSlugs := newSlugs(strings.TrimSuffix(indexPage, ".html"))
+
+		var names []string
+		for _, snapshot := range p.Snapshots {
+			for _, result := range snapshot.Results {
+				names = append(names, result.Function)
+			}
+		}
+		slices.Sort(names)
+		for _, name := range slices.Compact(names) {
+			p.Functions = append(p.Functions, function{Name: name, Slug: functionSlugs.assign(name)})
+		}
+
+		if n := len(p.Snapshots); n > 1 {
+			comparison := baseline.Compare(p.Snapshots[n-2], p.Snapshots[n-1], g.threshold)
+			p.Comparison = &comparison
+		}
+	}
+
+	return projects
+}
+
+// trends builds one trend chart per metric for a function: each series is a version and context of the function.
+func (g *Generator) trends(p project, functionName string) []*chart.Trend {
+	labels := make([]string, 0, len(p.Snapshots))
+	for _, snapshot := range p.Snapshots {
+		labels = append(labels, snapshot.Name)
+	}
+
+	var (
+		metrics []config.MetricName
+		keys    []model.SeriesKey
+		values  = make(map[model.SeriesKey][]float64)
+	)
+
+	for i, snapshot := range p.Snapshots {
+		for _, result := range snapshot.Results {
+			if result.Function != functionName {
+				continue
+			}
+
+			key := result.Key()
+			if _, ok := values[key]; !ok {
+				keys = append(keys, key)
+				values[key] = nanValues(len(p.Snapshots))
+				if !slices.Contains(metrics, result.Metric) {
+					metrics = append(metrics, result.Metric)
+				}
+			}
+			values[key][i] = result.Value
+		}
+	}
+
+	slices.Sort(metrics)
+	slices.SortFunc(keys, func(a, b model.SeriesKey) int {
+		return cmp.Or(cmp.Compare(a.Version, b.Version), cmp.Compare(a.Context, b.Context))
+	})
+
+	trends := make([]*chart.Trend, 0, len(metrics))
+	for _, metric := range metrics {
+		trend := &chart.Trend{
+			Title:      functionName + " (" + string(metric) + ")",
+			YAxisLabel: string(metric),
+			Theme:      g.theme,
+			Labels:     labels,
+		}
+
+		for _, key := range keys {
+			if key.Metric != metric {
+				continue
+			}
+			trend.Series = append(trend.Series, chart.TrendSeries{
+				Name:   seriesName(key),
+				Values: values[key],
+			})
+		}
+
+		trends = append(trends, trend)
+	}
+
+	return trends
+}
+
+func (g *Generator) writePage(file, name string, data pageData) error {
+	out, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("creating page: %w", err)
+	}
+
+	if err := pages.ExecuteTemplate(out, name, data); err != nil {
+		_ = out.Close()
+
+		return fmt.Errorf("writing page %q: %w", file, err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("writing page %q: %w", file, err)
+	}
+
+	return nil
+}
+
+func seriesName(key model.SeriesKey) string {
+	var parts []string
+	for _, part := range []string{key.Version, key.Context} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) == 0 {
+		return key.Function
+	}
+
+	return strings.Join(parts, "/")
+}
+
+func nanValues(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.NaN()
+	}
+
+	return values
+}
+
+// slugs assigns distinct file names to names.
+//
+// File names are compared regardless of case, for case-insensitive file systems.
+type slugs map[string]struct{}
+
+// newSlugs builds a set of file names, with some reserved names (e.g. the name of an index page).
+func newSlugs(reserved ...string) slugs {
+	s := make(slugs, len(reserved))
+	for _, name := range reserved {
+		s[strings.ToLower(name)] = struct{}{}
+	}
+
+	return s
+}
+
+// assign returns a file name for a name, suffixed with a number when it collides with another file name,
+// e.g. "a-b-2" for "a:b" after "a/b".
+func (s slugs) assign(name string) string {
+	base := config.SanitizeFileName(name)
+	slug := base
+	for i := 2; ; i++ {
+		if _, taken := s[strings.ToLower(slug)]; !taken {
+			break
+		}
+		slug = base + "-" + strconv.Itoa(i)
+	}
+	s[strings.ToLower(slug)] = struct{}{}
+
+	return slug
+}
@@ -0,0 +1,133 @@
+package site
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/baseline"
+	"github.com/fredbi/benchviz/internal/config"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func testSnapshot(name, scenario string, hour int, reflectNsPerOp float64) baseline.Snapshot {
+	return baseline.Snapshot{
+		Name:     name,
+		Scenario: scenario,
+		Created:  time.Date(2026, 1, 1, hour, 0, 0, 0, time.UTC),
+		Results: []baseline.Result{
+			{Function: "greater", Version: "generics", Context: "int", Metric: config.MetricNsPerOp, Value: 10},
+			{Function: "greater", Version: "reflect", Context: "int", Metric: config.MetricNsPerOp, Value: reflectNsPerOp},
+			{Function: "greater", Version: "reflect", Context: "int", Metric: config.MetricAllocsPerOp, Value: 2},
+			{Function: "sort/slice", Version: "generics", Metric: config.MetricNsPerOp, Value: 1000},
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "site")
+	snapshots := []baseline.Snapshot{
+		testSnapshot("v1", "compare", 1, 100),
+		testSnapshot("v2", "compare", 2, 120),
+		testSnapshot("nightly", "", 3, 100),
+	}
+
+	require.NoError(t, New(WithTitle("ACME benchmarks")).Generate(dir, snapshots))
+
+	read := func(t *testing.T, parts ...string) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(append([]string{dir}, parts...)...))
+		require.NoError(t, err)
+
+		return string(content)
+	}
+
+	t.Run("should index projects", func(t *testing.T) {
+		index := read(t, "index.html")
+		assert.Contains(t, index, "<h1>ACME benchmarks</h1>")
+		assert.Contains(t, index, `<a href="compare/index.html">compare</a>`)
+		assert.Contains(t, index, `<a href="default/index.html">default</a>`)
+
+		_, err := os.Stat(filepath.Join(dir, ".nojekyll"))
+		require.NoError(t, err)
+	})
+
+	t.Run("should compare the latest run of a project", func(t *testing.T) {
+		project := read(t, "compare", "index.html")
+		assert.Contains(t, project, "v2 against v1 (threshold: 5.0%)")
+		assert.Contains(t, project, `<tr class="regression">`)
+		assert.Contains(t, project, "&#43;20.0%") // html/template escapes "+"
+		assert.Contains(t, project, `<a href="greater.html">greater</a>`)
+		assert.Contains(t, project, `<a href="sort-slice.html">sort/slice</a>`)
+
+		single := read(t, "default", "index.html")
+		assert.Contains(t, single, "nothing to compare yet")
+	})
+
+	t.Run("should chart trends per function", func(t *testing.T) {
+		trend := read(t, "compare", "greater.html")
+		assert.Contains(t, trend, "echarts.min.js")
+		assert.Contains(t, trend, "greater (nsPerOp)")
+		assert.Contains(t, trend, "greater (allocsPerOp)")
+		assert.Contains(t, trend, "reflect/int")
+		assert.Contains(t, trend, `<a href="../index.html">ACME benchmarks</a>`)
+	})
+
+	t.Run("should fail without snapshots", func(t *testing.T) {
+		require.Error(t, New().Generate(dir, nil))
+	})
+}
+
+func TestTrendsMissingValues(t *testing.T) {
+	older := testSnapshot("v1", "compare", 1, 100)
+	older.Results = older.Results[:1]
+	g := New()
+	projects := g.projects([]baseline.Snapshot{older, testSnapshot("v2", "compare", 2, 120)})
+	require.Len(t, projects, 1)
+
+	trends := g.trends(projects[0], "greater")
+	require.Len(t, trends, 2)
+
+	nsPerOp := trends[1]
+	assert.Equal(t, []string{"v1", "v2"}, nsPerOp.Labels)
+	require.Len(t, nsPerOp.Series, 2)
+	assert.Equal(t, "generics/int", nsPerOp.Series[0].Name)
+	assert.Equal(t, []float64{10, 10}, nsPerOp.Series[0].Values)
+	assert.Equal(t, "reflect/int", nsPerOp.Series[1].Name)
+	assert.True(t, math.IsNaN(nsPerOp.Series[1].Values[0]))
+	assert.InDelta(t, 120, nsPerOp.Series[1].Values[1], 1e-9)
+}
+
+func TestSlugs(t *testing.T) {
+	snapshot := baseline.Snapshot{
+		Name: "v1",
+		Results: []baseline.Result{
+			{Function: "a/b", Metric: config.MetricNsPerOp, Value: 1},
+			{Function: "a:b", Metric: config.MetricNsPerOp, Value: 2},
+			{Function: "index", Metric: config.MetricNsPerOp, Value: 3},
+		},
+	}
+	other := snapshot
+	other.Scenario = "x/y"
+	colliding := snapshot
+	colliding.Scenario = "x:y"
+
+	projects := New().projects([]baseline.Snapshot{snapshot, other, colliding})
+	require.Len(t, projects, 3)
+
+	slugs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		slugs = append(slugs, p.Slug)
+	}
+	assert.Equal(t, []string{"default", "x-y", "x-y-2"}, slugs)
+
+	functions := make(map[string]string)
+	for _, f := range projects[0].Functions {
+		functions[f.Name] = f.Slug
+	}
+	assert.Equal(t, map[string]string{"a/b": "a-b", "a:b": "a-b-2", "index": "index-2"}, functions)
+}
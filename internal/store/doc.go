@@ -0,0 +1,16 @@
+// Package store dumps parsed and organized benchmark results into an SQLite database.
+//
+// The database has a stable schema, so results may be accumulated over many runs
+// and analyzed with ad-hoc SQL queries:
+//
+//   - runs: one row per invocation, with its creation time, scenario name, environment and branch
+//   - run_meta: arbitrary key/value metadata of a run
+//   - benchmarks: the parsed benchmarks of a run, as found in the input files
+//   - samples: the raw measurements of each parsed benchmark, one row per metric
+//   - metrics: the organized data points of a run, as rendered on charts
+//
+// The history of the data points of a function may be queried over successive runs.
+// Runs may be pruned according to a retention policy, so the database doesn't grow unbounded.
+//
+// The driver is a pure Go implementation of SQLite: no cgo is required.
+package store
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+// Query selects the stored data points of a function.
+//
+// Optional filters restrict data points to some metrics, to the runs of a branch, to runs created since a given time,
+// and to runs tagged with all the given metadata.
+type Query struct {
+	Function string
+	Metrics  []config.MetricName
+	Branch   string
+	Since    time.Time
+	Meta     map[string]string
+}
+
+// Point is an organized data point of a stored run.
+type Point struct {
+	Run     int64
+	Created time.Time
+	Branch  string
+	Metric  config.MetricName
+	Unit    string
+	Version string
+	Context string
+	Series  string
+	Value   float64
+}
+
+// Key identifies the series of a data point over successive runs, e.g. "reflect/int".
+func (p Point) Key() string {
+	parts := make([]string, 0, 2) //nolint:mnd // version and context
+	for _, part := range []string{p.Version, p.Context} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// History returns the data points selected by a query, ordered by creation time of their run.
+//
+// A function rendered in several categories yields the same data points several times: only the first one is retained.
+func (s *Store) History(ctx context.Context, query Query) ([]Point, error) {
+	stmt := `
+SELECT r.id, r.created, r.branch, m.metric, m.unit, m.version, m.context, m.series, m.value
+FROM metrics m JOIN runs r ON r.id = m.run_id
+WHERE m.function = ?`
+	args := []any{query.Function}
+
+	if query.Branch != "" {
+		stmt += " AND r.branch = ?"
+		args = append(args, query.Branch)
+	}
+
+	for key, value := range query.Meta {
+		stmt += " AND r.id IN (SELECT run_id FROM run_meta WHERE key = ? AND value = ?)"
+		args = append(args, key, value)
+	}
+
+	if len(query.Metrics) > 0 {
+		stmt += " AND m.metric IN (?" + strings.Repeat(", ?", len(query.Metrics)-1) + ")"
+		for _, metric := range query.Metrics {
+			args = append(args, string(metric))
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, stmt+" ORDER BY m.id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying history of %q: %w", query.Function, err)
+	}
+	defer rows.Close()
+
+	type pointKey struct {
+		run                      int64
+		metric, version, context string
+	}
+	var (
+		points []Point
+		seen   = make(map[pointKey]struct{})
+	)
+
+	for rows.Next() {
+		var (
+			point  Point
+			stamp  string
+			metric string
+		)
+		if err := rows.Scan(&point.Run, &stamp, &point.Branch, &metric, &point.Unit,
+			&point.Version, &point.Context, &point.Series, &point.Value,
+		); err != nil {
+			return nil, err
+		}
+
+		point.Metric = config.MetricName(metric)
+		point.Created, err = time.Parse(time.RFC3339Nano, stamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid creation time of run %d: %w", point.Run, err)
+		}
+
+		if !query.Since.IsZero() && point.Created.Before(query.Since) {
+			continue
+		}
+
+		key := pointKey{run: point.Run, metric: metric, version: point.Version, context: point.Context}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(points, func(a, b Point) int {
+		return cmp.Or(a.Created.Compare(b.Created), cmp.Compare(a.Run, b.Run))
+	})
+
+	return points, nil
+}
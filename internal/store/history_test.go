@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+
+	"github.com/fredbi/benchviz/internal/config"
+)
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	s, err := Open(ctx, filepath.Join(t.TempDir(), "bench.db"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	// the latest run is written first
+	for _, run := range []struct {
+		days   int
+		branch string
+	}{{0, "main"}, {2, "main"}, {1, "feature"}} {
+		_, err := s.Write(ctx, Run{
+			Created:  now.AddDate(0, 0, -run.days),
+			Branch:   run.branch,
+			Meta:     map[string]string{"days": strconv.Itoa(run.days), "go": "1.26"},
+			Scenario: testScenario(),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("should return data points ordered by time", func(t *testing.T) {
+		points, err := s.History(ctx, Query{Function: "greater"})
+		require.NoError(t, err)
+		require.Len(t, points, 6)
+
+		assert.Equal(t, now.AddDate(0, 0, -2), points[0].Created)
+		assert.EqualT(t, "reflect/int", points[0].Key())
+		assert.EqualT(t, "ns/op", points[0].Unit)
+		assert.InDeltaT(t, 100, points[0].Value, 1e-9)
+		assert.EqualT(t, "feature", points[2].Branch)
+		assert.Equal(t, now, points[5].Created)
+	})
+
+	t.Run("should filter data points", func(t *testing.T) {
+		points, err := s.History(ctx, Query{Function: "greater", Branch: "main", Since: now.AddDate(0, 0, -1)})
+		require.NoError(t, err)
+		require.Len(t, points, 2)
+		assert.Equal(t, now, points[0].Created)
+
+		points, err = s.History(ctx, Query{Function: "greater", Metrics: []config.MetricName{config.MetricAllocsPerOp}})
+		require.NoError(t, err)
+		assert.Empty(t, points)
+
+		points, err = s.History(ctx, Query{Function: "greater", Meta: map[string]string{"go": "1.26", "days": "1"}})
+		require.NoError(t, err)
+		require.Len(t, points, 2)
+		assert.EqualT(t, "feature", points[0].Branch)
+
+		points, err = s.History(ctx, Query{Function: "unknown"})
+		require.NoError(t, err)
+		assert.Empty(t, points)
+	})
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Retention is a retention policy of the runs in the database.
+//
+// Limits apply to each branch separately: a run beyond any of the limits is pruned.
+// A zero limit is not enforced.
+type Retention struct {
+	Runs int // Runs is the number of most recent runs kept for each branch
+	Days int // Days is the number of days runs are kept for
+}
+
+// IsZero reports whether the retention policy keeps all runs.
+func (r Retention) IsZero() bool {
+	return r.Runs <= 0 && r.Days <= 0
+}
+
+type storedRun struct {
+	id      int64
+	branch  string
+	created time.Time
+}
+
+// Prune deletes the runs beyond the retention policy, with their benchmarks, samples and metrics.
+//
+// When a branch is specified, only the runs of this branch are pruned. Ages are relative to now.
+// The database file is compacted when runs are deleted. It returns the number of deleted runs.
+func (s *Store) Prune(ctx context.Context, retention Retention, branch string, now time.Time) (int, error) {
+	if retention.IsZero() {
+		return 0, nil
+	}
+
+	runs, err := s.runs(ctx, branch)
+	if err != nil {
+		return 0, err
+	}
+
+	// most recent runs first, for each branch
+	slices.SortFunc(runs, func(a, b storedRun) int {
+		return cmp.Or(
+			cmp.Compare(a.branch, b.branch),
+			b.created.Compare(a.created),
+			cmp.Compare(b.id, a.id),
+		)
+	})
+
+	cutoff := now.AddDate(0, 0, -retention.Days)
+	var (
+		pruned []int64
+		rank   int
+	)
+
+	for i, run := range runs {
+		if i == 0 || run.branch != runs[i-1].branch {
+			rank = 0
+		}
+		rank++
+
+		if (retention.Runs > 0 && rank > retention.Runs) || (retention.Days > 0 && run.created.Before(cutoff)) {
+			pruned = append(pruned, run.id)
+		}
+	}
+
+	if len(pruned) == 0 {
+		return 0, nil
+	}
+
+	if err := s.deleteRuns(ctx, pruned); err != nil {
+		return 0, err
+	}
+
+	// deleted rows are only given back to the file system by vacuuming
+	if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+		return len(pruned), fmt.Errorf("compacting database: %w", err)
+	}
+
+	return len(pruned), nil
+}
+
+// runs lists the runs in the database, possibly of a single branch.
+func (s *Store) runs(ctx context.Context, branch string) ([]storedRun, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, branch, created FROM runs WHERE ? = '' OR branch = ?", branch, branch)
+	if err != nil {
+		return nil, fmt.Errorf("listing runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []storedRun
+	for rows.Next() {
+		var (
+			run   storedRun
+			stamp string
+		)
+		if err := rows.Scan(&run.id, &run.branch, &stamp); err != nil {
+			return nil, err
+		}
+
+		run.created, err = time.Parse(time.RFC3339Nano, stamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid creation time of run %d: %w", run.id, err)
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// deleteRuns deletes runs in a single transaction: benchmarks, samples and metrics are deleted in cascade.
+func (s *Store) deleteRuns(ctx context.Context, ids []int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, "DELETE FROM runs WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, id := range ids {
+		if _, err := stmt.ExecContext(ctx, id); err != nil {
+			return fmt.Errorf("deleting run %d: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
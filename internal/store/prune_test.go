@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+)
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	// runs of 2 branches, one a day for 5 days, in random order: IDs are not chronological
+	open := func(t *testing.T) *Store {
+		t.Helper()
+
+		s, err := Open(ctx, filepath.Join(t.TempDir(), "bench.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = s.Close() })
+
+		for _, days := range []int{2, 0, 4, 1, 3} {
+			for _, branch := range []string{"main", "feature"} {
+				_, err := s.Write(ctx, Run{Created: now.AddDate(0, 0, -days), Branch: branch, Scenario: testScenario()})
+				require.NoError(t, err)
+			}
+		}
+
+		return s
+	}
+
+	remaining := func(t *testing.T, s *Store, branch string) []time.Time {
+		t.Helper()
+
+		runs, err := s.runs(ctx, branch)
+		require.NoError(t, err)
+
+		created := make([]time.Time, 0, len(runs))
+		for _, run := range runs {
+			created = append(created, run.created)
+		}
+
+		return created
+	}
+
+	t.Run("should keep the last runs of each branch", func(t *testing.T) {
+		s := open(t)
+
+		pruned, err := s.Prune(ctx, Retention{Runs: 2}, "", now)
+		require.NoError(t, err)
+		assert.EqualT(t, 6, pruned)
+
+		for _, branch := range []string{"main", "feature"} {
+			assert.ElementsMatch(t, []time.Time{now, now.AddDate(0, 0, -1)}, remaining(t, s, branch))
+		}
+
+		var metrics int
+		require.NoError(t, s.db.QueryRowContext(ctx, "SELECT count(*) FROM metrics").Scan(&metrics))
+		assert.EqualT(t, 4*2, metrics)
+	})
+
+	t.Run("should keep the runs of the last days", func(t *testing.T) {
+		s := open(t)
+
+		pruned, err := s.Prune(ctx, Retention{Days: 3}, "", now)
+		require.NoError(t, err)
+		assert.EqualT(t, 2, pruned)
+		assert.Len(t, remaining(t, s, ""), 8)
+	})
+
+	t.Run("should only prune a branch", func(t *testing.T) {
+		s := open(t)
+
+		pruned, err := s.Prune(ctx, Retention{Runs: 1, Days: 30}, "feature", now)
+		require.NoError(t, err)
+		assert.EqualT(t, 4, pruned)
+		assert.Len(t, remaining(t, s, "main"), 5)
+		assert.Equal(t, []time.Time{now}, remaining(t, s, "feature"))
+	})
+
+	t.Run("should keep everything without retention", func(t *testing.T) {
+		s := open(t)
+
+		pruned, err := s.Prune(ctx, Retention{}, "", now)
+		require.NoError(t, err)
+		assert.Zero(t, pruned)
+		assert.Len(t, remaining(t, s, ""), 10)
+	})
+}
@@ -0,0 +1,374 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// SchemaVersion is the version of the database schema, recorded as the SQLite user_version.
+//
+// Any incompatible change to the schema bumps this version.
+const SchemaVersion = 3
+
+const driverName = "sqlite"
+
+// schema creates the tables of the database, if they don't exist yet.
+//
+// Timestamps are stored as RFC 3339 text, metrics by their ID (e.g. "nsPerOp").
+// The samples of custom metrics reported with testing.B.ReportMetric are stored by unit (e.g. "peak-MB").
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id          INTEGER PRIMARY KEY,
+	created     TEXT NOT NULL,
+	scenario    TEXT NOT NULL DEFAULT '',
+	environment TEXT NOT NULL DEFAULT '',
+	branch      TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS run_meta (
+	run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+	key    TEXT NOT NULL,
+	value  TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (run_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS benchmarks (
+	id          INTEGER PRIMARY KEY,
+	run_id      INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+	file        TEXT NOT NULL DEFAULT '',
+	label       TEXT NOT NULL DEFAULT '',
+	environment TEXT NOT NULL DEFAULT '',
+	name        TEXT NOT NULL,
+	ord         INTEGER NOT NULL DEFAULT 0,
+	iterations  INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS samples (
+	benchmark_id INTEGER NOT NULL REFERENCES benchmarks(id) ON DELETE CASCADE,
+	metric       TEXT NOT NULL,
+	value        REAL NOT NULL,
+	PRIMARY KEY (benchmark_id, metric)
+);
+
+CREATE TABLE IF NOT EXISTS metrics (
+	id       INTEGER PRIMARY KEY,
+	run_id   INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+	category TEXT NOT NULL,
+	metric   TEXT NOT NULL,
+	unit     TEXT NOT NULL DEFAULT '',
+	function TEXT NOT NULL,
+	version  TEXT NOT NULL DEFAULT '',
+	context  TEXT NOT NULL DEFAULT '',
+	series   TEXT NOT NULL DEFAULT '',
+	label    TEXT NOT NULL DEFAULT '',
+	value    REAL NOT NULL,
+	samples  INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS runs_branch ON runs(branch);
+CREATE INDEX IF NOT EXISTS benchmarks_run ON benchmarks(run_id);
+CREATE INDEX IF NOT EXISTS benchmarks_name ON benchmarks(name);
+CREATE INDEX IF NOT EXISTS metrics_run ON metrics(run_id);
+CREATE INDEX IF NOT EXISTS metrics_series ON metrics(function, version, context, metric);
+`
+
+// migrations upgrade the schema from a previous version to the next one, by version.
+var migrations = map[int]string{
+	1: `
+ALTER TABLE runs ADD COLUMN branch TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS runs_branch ON runs(branch);
+`,
+	2: `
+CREATE TABLE IF NOT EXISTS run_meta (
+	run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+	key    TEXT NOT NULL,
+	value  TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (run_id, key)
+);
+`,
+}
+
+// Run holds the results of a benchmark run to be stored.
+//
+// Sets are the parsed benchmarks and Scenario the organized ones. Either may be empty,
+// e.g. when rendering merged scenarios there are no parsed benchmarks.
+//
+// Branch is the branch of the benchmarked code, if known: retention policies apply to each branch separately.
+// Meta is arbitrary key/value metadata about the run, e.g. feature flags.
+type Run struct {
+	Created  time.Time
+	Branch   string
+	Meta     map[string]string
+	Sets     []parser.Set
+	Scenario *model.Scenario
+}
+
+// Environment returns the distinct environments of the run, joined with "; ".
+//
+// Environments are taken from the parsed benchmarks, or from the organized categories
+// if no parsed benchmark is available.
+func (r Run) Environment() string {
+	var environments []string
+
+	add := func(env string) {
+		if env != "" && !slices.Contains(environments, env) {
+			environments = append(environments, env)
+		}
+	}
+
+	for _, set := range r.Sets {
+		add(set.Environment)
+	}
+
+	if len(environments) == 0 && r.Scenario != nil {
+		for _, category := range r.Scenario.Categories {
+			add(category.Environment)
+		}
+	}
+
+	return strings.Join(environments, "; ")
+}
+
+// Store is an SQLite database of benchmark runs.
+type Store struct {
+	db *sql.DB
+}
+
+// Open an SQLite database file, creating it and its schema when needed.
+//
+// A database created with a previous version of the schema is upgraded.
+// It fails if the database was created with a more recent version of the schema.
+func Open(ctx context.Context, file string) (*Store, error) {
+	db, err := sql.Open(driverName, file)
+	if err != nil {
+		return nil, fmt.Errorf("opening database %q: %w", file, err)
+	}
+
+	// pragmas such as foreign_keys apply to a single connection
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db}
+	if err := s.init(ctx); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("initializing database %q: %w", file, err)
+	}
+
+	return s, nil
+}
+
+// Close the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) init(ctx context.Context) error {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	switch {
+	case version == SchemaVersion:
+	case version == 0:
+		if _, err := s.db.ExecContext(ctx, schema); err != nil {
+			return err
+		}
+
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion)); err != nil {
+			return err
+		}
+	case version < SchemaVersion:
+		for ; version < SchemaVersion; version++ {
+			if _, err := s.db.ExecContext(ctx, migrations[version]); err != nil {
+				return fmt.Errorf("upgrading schema version %d: %w", version, err)
+			}
+		}
+
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported schema version %d (expected %d)", version, SchemaVersion)
+	}
+
+	_, err := s.db.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	return err
+}
+
+// Write a benchmark run into the database, in a single transaction.
+//
+// It returns the ID of the new row in the runs table.
+func (s *Store) Write(ctx context.Context, run Run) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	created := run.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	var scenario string
+	if run.Scenario != nil {
+		scenario = run.Scenario.Name
+	}
+
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO runs (created, scenario, environment, branch) VALUES (?, ?, ?, ?)",
+		created.UTC().Format(time.RFC3339Nano), scenario, run.Environment(), run.Branch,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("inserting run: %w", err)
+	}
+
+	runID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for key, value := range run.Meta {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO run_meta (run_id, key, value) VALUES (?, ?, ?)", runID, key, value); err != nil {
+			return 0, fmt.Errorf("inserting metadata %q: %w", key, err)
+		}
+	}
+
+	if err := writeBenchmarks(ctx, tx, runID, run.Sets); err != nil {
+		return 0, err
+	}
+
+	if err := writeMetrics(ctx, tx, runID, run.Scenario); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return runID, nil
+}
+
+func writeBenchmarks(ctx context.Context, tx *sql.Tx, runID int64, sets []parser.Set) error {
+	benchmarkStmt, err := tx.PrepareContext(ctx,
+		"INSERT INTO benchmarks (run_id, file, label, environment, name, ord, iterations) VALUES (?, ?, ?, ?, ?, ?, ?)",
+	)
+	if err != nil {
+		return err
+	}
+	defer benchmarkStmt.Close()
+
+	sampleStmt, err := tx.PrepareContext(ctx, "INSERT INTO samples (benchmark_id, metric, value) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer sampleStmt.Close()
+
+	for _, set := range sets {
+		// the set is a map: sort names so that rows are inserted in a reproducible order
+		names := make([]string, 0, len(set.Set))
+		for name := range set.Set {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			for _, bench := range set.Set[name] {
+				result, err := benchmarkStmt.ExecContext(ctx, runID, set.File, set.Label, set.Environment, bench.Name, bench.Ord, bench.N)
+				if err != nil {
+					return fmt.Errorf("inserting benchmark %q: %w", bench.Name, err)
+				}
+
+				benchmarkID, err := result.LastInsertId()
+				if err != nil {
+					return err
+				}
+
+				for _, sample := range samplesOf(bench, set.CustomMetrics(bench)) {
+					if _, err := sampleStmt.ExecContext(ctx, benchmarkID, string(sample.metric), sample.value); err != nil {
+						return fmt.Errorf("inserting sample for benchmark %q: %w", bench.Name, err)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeMetrics(ctx context.Context, tx *sql.Tx, runID int64, scenario *model.Scenario) error {
+	if scenario == nil {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx,
+		"INSERT INTO metrics (run_id, category, metric, unit, function, version, context, series, label, value, samples) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, category := range scenario.Categories {
+		for _, data := range category.Data {
+			for _, series := range data.Series {
+				for _, point := range series.Points {
+					if _, err := stmt.ExecContext(ctx, runID,
+						category.ID, string(point.Metric), data.Metric.Axis,
+						point.Function, point.Version, point.Context,
+						series.Title, point.Label, point.Value, point.Aggregate.Samples,
+					); err != nil {
+						return fmt.Errorf("inserting metric for %q: %w", point.Name, err)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+type sample struct {
+	metric config.MetricName
+	value  float64
+}
+
+// samplesOf returns the measurements recorded for a parsed benchmark, including its custom metrics by unit.
+func samplesOf(bench *parse.Benchmark, custom map[string]float64) []sample {
+	samples := make([]sample, 0, len(config.AllMetricNames())+len(custom))
+
+	if bench.Measured&parse.NsPerOp != 0 {
+		samples = append(samples, sample{metric: config.MetricNsPerOp, value: bench.NsPerOp})
+	}
+	if bench.Measured&parse.AllocsPerOp != 0 {
+		samples = append(samples, sample{metric: config.MetricAllocsPerOp, value: float64(bench.AllocsPerOp)})
+	}
+	if bench.Measured&parse.AllocedBytesPerOp != 0 {
+		samples = append(samples, sample{metric: config.MetricBytesPerOp, value: float64(bench.AllocedBytesPerOp)})
+	}
+	if bench.Measured&parse.MBPerS != 0 {
+		samples = append(samples, sample{metric: config.MetricMBPerS, value: bench.MBPerS})
+	}
+	for _, unit := range slices.Sorted(maps.Keys(custom)) {
+		samples = append(samples, sample{metric: config.MetricName(unit), value: custom[unit]})
+	}
+
+	return samples
+}
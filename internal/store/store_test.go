@@ -0,0 +1,176 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/testify/v2/assert"
+	"github.com/go-openapi/testify/v2/require"
+	"golang.org/x/tools/benchmark/parse"
+
+	"github.com/fredbi/benchviz/internal/config"
+	"github.com/fredbi/benchviz/internal/model"
+	"github.com/fredbi/benchviz/internal/parser"
+)
+
+func TestWrite(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "bench.db")
+
+	s, err := Open(ctx, file)
+	require.NoError(t, err)
+
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	id, err := s.Write(ctx, Run{Created: created, Branch: "main", Sets: testSets(), Scenario: testScenario()})
+	require.NoError(t, err)
+	assert.EqualT(t, int64(1), id)
+	require.NoError(t, s.Close())
+
+	t.Run("should append runs to an existing database", func(t *testing.T) {
+		s, err := Open(ctx, file)
+		require.NoError(t, err)
+		defer s.Close()
+
+		id, err := s.Write(ctx, Run{Scenario: testScenario()})
+		require.NoError(t, err)
+		assert.EqualT(t, int64(2), id)
+	})
+
+	db, err := sql.Open(driverName, file)
+	require.NoError(t, err)
+	defer db.Close()
+
+	t.Run("should record runs", func(t *testing.T) {
+		var scenario, environment, stamp, branch string
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT created, scenario, environment, branch FROM runs WHERE id = 1").Scan(&stamp, &scenario, &environment, &branch))
+		assert.EqualT(t, "2026-10-01T12:00:00Z", stamp)
+		assert.EqualT(t, "test", scenario)
+		assert.EqualT(t, "linux amd64", environment)
+		assert.EqualT(t, "main", branch)
+	})
+
+	t.Run("should record parsed benchmarks and their samples", func(t *testing.T) {
+		rows, err := db.QueryContext(ctx, `
+SELECT b.name, b.file, b.iterations, s.metric, s.value
+FROM benchmarks b JOIN samples s ON s.benchmark_id = b.id
+WHERE b.run_id = 1
+ORDER BY b.id, s.metric`)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		type row struct {
+			name, file, metric string
+			iterations         int
+			value              float64
+		}
+		var found []row
+		for rows.Next() {
+			var r row
+			require.NoError(t, rows.Scan(&r.name, &r.file, &r.iterations, &r.metric, &r.value))
+			found = append(found, r)
+		}
+		require.NoError(t, rows.Err())
+
+		assert.Equal(t, []row{
+			{name: "BenchmarkGreater/generics/int", file: "bench.json", iterations: 1000, metric: "allocsPerOp", value: 2},
+			{name: "BenchmarkGreater/generics/int", file: "bench.json", iterations: 1000, metric: "nsPerOp", value: 10},
+			{name: "BenchmarkGreater/reflect/int", file: "bench.json", iterations: 500, metric: "nsPerOp", value: 100},
+		}, found)
+	})
+
+	t.Run("should record organized metrics", func(t *testing.T) {
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT count(*) FROM metrics").Scan(&count))
+		assert.EqualT(t, 4, count)
+
+		var value float64
+		require.NoError(t, db.QueryRowContext(ctx,
+			"SELECT value FROM metrics WHERE run_id = 1 AND function = 'greater' AND version = 'reflect' AND metric = 'nsPerOp'",
+		).Scan(&value))
+		assert.InDeltaT(t, 100, value, 1e-9)
+	})
+}
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "bench.db")
+
+	db, err := sql.Open(driverName, file)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "PRAGMA user_version = 99")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = Open(ctx, file)
+	require.ErrorContains(t, err, "unsupported schema version 99")
+
+	t.Run("should upgrade a database with a previous schema", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "v1.db")
+
+		db, err := sql.Open(driverName, file)
+		require.NoError(t, err)
+		_, err = db.ExecContext(ctx, `
+CREATE TABLE runs (id INTEGER PRIMARY KEY, created TEXT NOT NULL, scenario TEXT NOT NULL DEFAULT '', environment TEXT NOT NULL DEFAULT '');
+INSERT INTO runs (created) VALUES ('2026-10-01T12:00:00Z');
+PRAGMA user_version = 1;`)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		s, err := Open(ctx, file)
+		require.NoError(t, err)
+		defer s.Close()
+
+		var branch string
+		require.NoError(t, s.db.QueryRowContext(ctx, "SELECT branch FROM runs WHERE id = 1").Scan(&branch))
+		assert.Empty(t, branch)
+	})
+}
+
+func testSets() []parser.Set {
+	return []parser.Set{
+		{
+			Set: parse.Set{
+				"BenchmarkGreater/reflect/int": {
+					{Name: "BenchmarkGreater/reflect/int", N: 500, NsPerOp: 100, Measured: parse.NsPerOp},
+				},
+				"BenchmarkGreater/generics/int": {
+					{Name: "BenchmarkGreater/generics/int", N: 1000, NsPerOp: 10, AllocsPerOp: 2, Measured: parse.NsPerOp | parse.AllocsPerOp},
+				},
+			},
+			File:        "bench.json",
+			Environment: "linux amd64",
+		},
+	}
+}
+
+func testScenario() *model.Scenario {
+	point := func(version string, value float64) model.MetricPoint {
+		return model.MetricPoint{
+			SeriesKey: model.SeriesKey{Function: "greater", Version: version, Context: "int", Metric: config.MetricNsPerOp},
+			Name:      "greater - int - " + version,
+			Label:     "int",
+			Value:     value,
+		}
+	}
+
+	return &model.Scenario{
+		Name: "test",
+		Categories: []model.Category{
+			{
+				ID: "greater",
+				Data: []model.CategoryData{
+					{
+						Metric: config.Metric{ID: config.MetricNsPerOp, Title: "Timings", Axis: "ns/op"},
+						Series: []model.MetricSeries{
+							{Title: "reflect", Points: []model.MetricPoint{point("reflect", 100)}},
+							{Title: "generics", Points: []model.MetricPoint{point("generics", 10)}},
+						},
+					},
+				},
+			},
+		},
+	}
+}